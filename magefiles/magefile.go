@@ -97,12 +97,15 @@ func Install() error { return newOrch().Install() }
 // Clean removes build artifacts.
 func Clean() error { return newOrch().Clean() }
 
-// Credentials extracts Claude credentials from the macOS Keychain.
+// Credentials extracts Claude credentials from the macOS Keychain or Linux Secret Service.
 func Credentials() error { return newOrch().ExtractCredentials() }
 
 // Analyze performs cross-artifact consistency checks (PRDs, use cases, test suites, roadmap).
 func Analyze() error { return newOrch().Analyze() }
 
+// VerifySchemas reports YAML keys that the typed doc structs drop on load.
+func VerifySchemas() error { return newOrch().VerifySchemas() }
+
 // Status reports code implementation status per use case and release,
 // comparing road-map.yaml spec status with test file presence.
 func Status() error { return newOrch().CodeStatus() }
@@ -175,6 +178,15 @@ func (Scaffold) Pop(target string) error {
 	return newOrch().Uninstall(target)
 }
 
+// PopDryRun lists what Pop would remove from the target repository and the
+// go.mod edits it would make, without changing anything.
+func (Scaffold) PopDryRun(target string) error { return newOrch().UninstallDryRun(target) }
+
+// PushDryRun prints the configuration Push would detect for a local target
+// directory, the files it would remove and write, and the go.mod edits it
+// would make, without changing anything.
+func (Scaffold) PushDryRun(target string) error { return newOrch().ScaffoldDryRun(target) }
+
 // rejectSelfTarget returns an error if target resolves to orchRoot.
 // Running push or pop against the orchestrator repo itself is destructive:
 // push replaces the dev magefile with the template, pop deletes source
@@ -258,9 +270,21 @@ func (Cobbler) Measure() error { return newOrch().Measure() }
 // Stitch picks ready tasks and invokes Claude to execute them.
 func (Cobbler) Stitch() error { return newOrch().Stitch() }
 
+// StitchTask stitches one ready task by id (an issue number) instead of the next ready task.
+func (Cobbler) StitchTask(id string) error { return newOrch().StitchTask(id) }
+
+// Review lists stitched tasks awaiting review when defer_merge is enabled.
+func (Cobbler) Review() error { return newOrch().ListForReview() }
+
+// Accept merges a task awaiting review and closes its issue.
+func (Cobbler) Accept(id string) error { return newOrch().AcceptTask(id) }
+
 // Reset removes the cobbler scratch directory.
 func (Cobbler) Reset() error { return newOrch().CobblerReset() }
 
+// PruneHistory removes old history files per cobbler.history_retention.
+func (Cobbler) PruneHistory() error { return newOrch().PruneHistory() }
+
 // --- Generator targets ---
 
 // Start begins a new generation trail.
@@ -283,15 +307,21 @@ func (Generator) Stop() error { return newOrch().GeneratorStop() }
 // List shows active branches and past generations.
 func (Generator) List() error { return newOrch().GeneratorList() }
 
+// Status reports the current generation's issue counts, progress, and cost.
+func (Generator) Status() error { return newOrch().GeneratorStatus() }
+
 // Switch commits current work and checks out another generation branch.
 func (Generator) Switch() error { return newOrch().GeneratorSwitch() }
 
 // Reset destroys generation branches, worktrees, and Go source directories.
 func (Generator) Reset() error { return newOrch().GeneratorReset() }
 
+// Replay re-runs the saved prompts of the current generation and reports divergence from the originals.
+func (Generator) Replay() error { return newOrch().ReplayGeneration("") }
+
 // --- Stats targets ---
 
-// Loc prints Go lines of code and documentation word counts.
+// Loc prints lines of code per language and documentation word counts.
 func (Stats) Loc() error { return newOrch().Stats() }
 
 // Tokens enumerates prompt-attached files and counts tokens via the Anthropic API.
@@ -306,6 +336,15 @@ func (Stats) Generator() error { return newOrch().GeneratorStats() }
 // Releases prints a table of roadmap releases with PRD and requirement counts.
 func (Stats) Releases() error { return newOrch().ReleaseStats() }
 
+// Failures groups failed invocations in the generation history by error message.
+func (Stats) Failures() error { return newOrch().FailureReport() }
+
+// Heatmap ranks files by lines changed across the generation's stitch reports.
+func (Stats) Heatmap() error { return newOrch().StitchHeatmap() }
+
+// Estimate projects the remaining cost and time of the current generation's backlog.
+func (Stats) Estimate() error { return newOrch().EstimateRemaining() }
+
 // --- Prompt targets ---
 
 // Measure prints the assembled measure prompt to stdout.
@@ -314,6 +353,9 @@ func (Prompt) Measure() error { return newOrch().DumpMeasurePrompt() }
 // Stitch prints the assembled stitch prompt to stdout.
 func (Prompt) Stitch() error { return newOrch().DumpStitchPrompt() }
 
+// Task prints the stitch prompt for task id (an issue number), or for the next ready task when id is empty.
+func (Prompt) Task(id string) error { return newOrch().StitchPrompt(id) }
+
 // Files lists all files that will be appended to the Claude prompt with sizes and token estimates.
 func (Prompt) Files() error { return newOrch().PrintContextFiles() }
 
@@ -325,6 +367,9 @@ func (Podman) Build() error { return newOrch().BuildImage() }
 // Clean removes all podman containers created from the configured image.
 func (Podman) Clean() error { return newOrch().PodmanClean() }
 
+// Prewarm ensures the image and credentials are ready and optionally starts a no-op container.
+func (Podman) Prewarm() error { return newOrch().Prewarm() }
+
 // --- Compare targets ---
 
 // Run builds binaries from two sources and runs differential comparison.
@@ -599,7 +599,7 @@ func (o *Orchestrator) validateDocSchemas() []string {
 	var errs []string
 
 	// Validate standard documentation files.
-	for _, path := range resolveStandardFiles() {
+	for _, path := range resolveStandardFiles(".") {
 		switch classifyContextFile(path) {
 		case "vision":
 			errs = append(errs, validateYAMLStrict[VisionDoc](path)...)
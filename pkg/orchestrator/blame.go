@@ -0,0 +1,119 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Blame age buckets used in SourceFile.Blame summaries (GH-487).
+const (
+	blameRecent = "recent" // changed within the last 30 days
+	blameActive = "active" // changed within the last 180 days
+	blameStable = "stable" // unchanged for 180 days or more
+)
+
+// blameBucket classifies a line by the age of its last commit.
+func blameBucket(age time.Duration) string {
+	switch {
+	case age < 30*24*time.Hour:
+		return blameRecent
+	case age < 180*24*time.Hour:
+		return blameActive
+	default:
+		return blameStable
+	}
+}
+
+// summarizeBlame collapses git blame --line-porcelain output into line
+// ranges of equal age bucket, one per line in the form
+// "12-40 stable (2023-01-05)", where the date is the newest commit in the
+// range. Line numbers match the numbered source in SourceFile.Lines.
+// Returns "" when the output has no lines.
+func summarizeBlame(porcelain []byte, now time.Time) string {
+	type blameLine struct {
+		line int
+		when time.Time
+	}
+	var lines []blameLine
+	sc := bufio.NewScanner(bytes.NewReader(porcelain))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		text := sc.Text()
+		if strings.HasPrefix(text, "\t") {
+			continue // source content
+		}
+		fields := strings.Fields(text)
+		if len(fields) >= 3 && len(fields[0]) == 40 {
+			n, err := strconv.Atoi(fields[2])
+			if err == nil {
+				lines = append(lines, blameLine{line: n})
+			}
+			continue
+		}
+		if len(fields) == 2 && fields[0] == "committer-time" && len(lines) > 0 {
+			if ts, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+				lines[len(lines)-1].when = time.Unix(ts, 0)
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	start, newest := lines[0].line, lines[0].when
+	bucket := blameBucket(now.Sub(newest))
+	flush := func(end int) {
+		fmt.Fprintf(&b, "%d-%d %s (%s)\n", start, end, bucket, newest.UTC().Format("2006-01-02"))
+	}
+	for i := 1; i < len(lines); i++ {
+		l := lines[i]
+		if bk := blameBucket(now.Sub(l.when)); bk != bucket || l.line != lines[i-1].line+1 {
+			flush(lines[i-1].line)
+			start, newest, bucket = l.line, l.when, bk
+			continue
+		}
+		if l.when.After(newest) {
+			newest = l.when
+		}
+	}
+	flush(lines[len(lines)-1].line)
+	return b.String()
+}
+
+// annotateBlame adds a blame summary to each source file in files whose
+// path is in required, so the stitch agent can tell recently churned
+// regions from long-stable ones (GH-487). Files longer than maxLines are
+// skipped to bound the token cost; maxLines <= 0 means no cap. Blame
+// failures (untracked files, no git) are logged and leave the file
+// unannotated.
+func annotateBlame(files []SourceFile, required []string, maxLines int, dir string) {
+	want := make(map[string]bool, len(required))
+	for _, p := range required {
+		want[p] = true
+	}
+	now := time.Now()
+	for i := range files {
+		sf := &files[i]
+		if !want[sf.File] {
+			continue
+		}
+		if n := strings.Count(sf.Lines, "\n") + 1; maxLines > 0 && n > maxLines {
+			logf("annotateBlame: skipping %s: %d numbered lines exceed blame_max_lines=%d", sf.File, n, maxLines)
+			continue
+		}
+		out, err := gitBlameLinePorcelain(sf.File, dir)
+		if err != nil {
+			logf("annotateBlame: git blame %s: %v", sf.File, err)
+			continue
+		}
+		sf.Blame = summarizeBlame(out, now)
+	}
+}
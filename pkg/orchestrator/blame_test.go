@@ -0,0 +1,99 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// porcelainLine renders one git blame --line-porcelain entry.
+func porcelainLine(sha string, line int, when time.Time) string {
+	return fmt.Sprintf("%s %d %d\ncommitter-time %d\nfilename f.go\n\tcontent\n", sha, line, line, when.Unix())
+}
+
+func TestSummarizeBlame_CollapsesRangesByAge(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.AddDate(-2, 0, 0)
+	recent := now.AddDate(0, 0, -3)
+	shaOld := strings.Repeat("a", 40)
+	shaNew := strings.Repeat("b", 40)
+
+	var in strings.Builder
+	in.WriteString(porcelainLine(shaOld, 1, old))
+	in.WriteString(porcelainLine(shaOld, 2, old))
+	in.WriteString(porcelainLine(shaNew, 3, recent))
+	in.WriteString(porcelainLine(shaOld, 4, old))
+
+	got := summarizeBlame([]byte(in.String()), now)
+	want := "1-2 stable (2024-06-01)\n3-3 recent (2026-05-29)\n4-4 stable (2024-06-01)\n"
+	if got != want {
+		t.Errorf("summarizeBlame =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSummarizeBlame_Empty(t *testing.T) {
+	t.Parallel()
+	if got := summarizeBlame(nil, time.Now()); got != "" {
+		t.Errorf("summarizeBlame(nil) = %q, want empty", got)
+	}
+}
+
+func TestBlameBucket(t *testing.T) {
+	t.Parallel()
+	day := 24 * time.Hour
+	cases := []struct {
+		age  time.Duration
+		want string
+	}{
+		{time.Hour, blameRecent},
+		{29 * day, blameRecent},
+		{30 * day, blameActive},
+		{179 * day, blameActive},
+		{180 * day, blameStable},
+	}
+	for _, c := range cases {
+		if got := blameBucket(c.age); got != c.want {
+			t.Errorf("blameBucket(%s) = %q, want %q", c.age, got, c.want)
+		}
+	}
+}
+
+func TestAnnotateBlame_RequiredFilesOnly(t *testing.T) {
+	dir := initTestGitRepo(t)
+	for _, name := range []string{"a.go", "b.go"} {
+		if err := os.WriteFile(name, []byte("package x\n\nvar _ = 1\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{{"add", "."}, {"commit", "-m", "add files"}} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	files := []SourceFile{
+		{File: "a.go", Lines: "1 | package x\n3 | var _ = 1"},
+		{File: "b.go", Lines: "1 | package x\n3 | var _ = 1"},
+	}
+	annotateBlame(files, []string{"a.go"}, 800, dir)
+
+	if !strings.HasPrefix(files[0].Blame, "1-3 recent (") {
+		t.Errorf("a.go Blame = %q, want a single recent range", files[0].Blame)
+	}
+	if files[1].Blame != "" {
+		t.Errorf("b.go is not required, Blame = %q, want empty", files[1].Blame)
+	}
+
+	files[0].Blame = ""
+	annotateBlame(files, []string{"a.go"}, 1, dir)
+	if files[0].Blame != "" {
+		t.Errorf("a.go exceeds maxLines, Blame = %q, want empty", files[0].Blame)
+	}
+}
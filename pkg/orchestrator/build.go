@@ -4,10 +4,12 @@
 package orchestrator
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 )
 
 // Build compiles the project binary. If MainPackage is empty, the
@@ -168,18 +170,44 @@ func (o *Orchestrator) DumpStitchPrompt() error {
 	return nil
 }
 
-// ExtractCredentials reads Claude credentials from the macOS Keychain
-// and writes them to SecretsDir/TokenFile.
+// claudeCredentialService is the service name Claude Code stores its
+// credentials under in the platform secret store.
+const claudeCredentialService = "Claude Code-credentials"
+
+// credentialStore returns the name of the secret store ExtractCredentials
+// reads on goos and the command that prints the stored credentials: the
+// macOS Keychain via security, or the freedesktop Secret Service via
+// secret-tool on Linux. ok is false on other platforms.
+func credentialStore(goos string) (name string, argv []string, ok bool) {
+	switch goos {
+	case "darwin":
+		return "keychain", []string{binSecurity, "find-generic-password", "-s", claudeCredentialService, "-w"}, true
+	case "linux":
+		return "secret-service", []string{binSecretTool, "lookup", "service", claudeCredentialService}, true
+	default:
+		return "", nil, false
+	}
+}
+
+// ExtractCredentials reads Claude credentials from the platform secret
+// store (the macOS Keychain or the Linux Secret Service) and writes them
+// to SecretsDir/TokenFile.
 func (o *Orchestrator) ExtractCredentials() error {
+	store, argv, ok := credentialStore(runtime.GOOS)
+	if !ok {
+		return fmt.Errorf("no credential store supported on %s", runtime.GOOS)
+	}
 	outPath := filepath.Join(o.cfg.Claude.SecretsDir, o.cfg.EffectiveTokenFile())
-	logf("credentials: extracting to %s", outPath)
+	logf("credentials: extracting from %s to %s", store, outPath)
 	if err := os.MkdirAll(o.cfg.Claude.SecretsDir, 0o700); err != nil {
 		return fmt.Errorf("creating secrets directory: %w", err)
 	}
-	out, err := exec.Command(binSecurity, "find-generic-password",
-		"-s", "Claude Code-credentials", "-w").Output()
+	out, err := exec.Command(argv[0], argv[1:]...).Output()
 	if err != nil {
-		return fmt.Errorf("extracting credentials from keychain: %w", err)
+		return fmt.Errorf("extracting credentials from %s: %w", store, err)
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return fmt.Errorf("extracting credentials from %s: no %q entry", store, claudeCredentialService)
 	}
 	if err := os.WriteFile(outPath, out, 0o600); err != nil {
 		return fmt.Errorf("writing credentials: %w", err)
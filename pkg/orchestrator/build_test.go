@@ -6,6 +6,7 @@ package orchestrator
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -244,3 +245,28 @@ func TestDumpStitchPrompt_ProducesOutput(t *testing.T) {
 		t.Errorf("DumpStitchPrompt() unexpected error: %v", err)
 	}
 }
+
+func TestCredentialStore_PlatformSelection(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		goos, wantName, wantBin string
+		wantOK                  bool
+	}{
+		{"darwin", "keychain", binSecurity, true},
+		{"linux", "secret-service", binSecretTool, true},
+		{"windows", "", "", false},
+	}
+	for _, tc := range cases {
+		name, argv, ok := credentialStore(tc.goos)
+		if ok != tc.wantOK || name != tc.wantName {
+			t.Errorf("credentialStore(%q) = %q, ok=%v; want %q, ok=%v", tc.goos, name, ok, tc.wantName, tc.wantOK)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if argv[0] != tc.wantBin || !slices.Contains(argv, claudeCredentialService) {
+			t.Errorf("credentialStore(%q) argv = %v, want %s ... %q", tc.goos, argv, tc.wantBin, claudeCredentialService)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ClaudeBackend executes one Claude invocation. extraArgs are Claude CLI
+// arguments (e.g. "--max-turns", "1") appended after Claude.Args; backends
+// that do not run the CLI interpret the ones they support and ignore the
+// rest. The context carries the invocation's max time (GH-529).
+type ClaudeBackend interface {
+	Run(ctx context.Context, prompt string, extraArgs []string) (ClaudeResult, error)
+}
+
+// claudeBackend returns the backend selected by Claude.Backend and, for
+// the podman backend, Cobbler.Mode. workDir is the directory Claude
+// operates in; silence suppresses Claude's output on stdout.
+func (o *Orchestrator) claudeBackend(workDir string, silence bool) ClaudeBackend {
+	if o.cfg.Claude.effectiveBackend() == ClaudeBackendAPI {
+		return &apiBackend{cfg: o.cfg.Claude, silence: silence, client: http.DefaultClient}
+	}
+	switch o.cfg.Cobbler.effectiveMode() {
+	case ExecutionModeSDK:
+		return &sdkBackend{o: o, workDir: workDir, silence: silence}
+	case ExecutionModeCLI:
+		return &podmanBackend{o: o, workDir: workDir, silence: silence, direct: true}
+	default:
+		return &podmanBackend{o: o, workDir: workDir, silence: silence}
+	}
+}
+
+// sdkBackend runs Claude through the Go Agent SDK (ExecutionModeSDK).
+type sdkBackend struct {
+	o       *Orchestrator
+	workDir string
+	silence bool
+}
+
+// Run implements ClaudeBackend.
+func (b *sdkBackend) Run(ctx context.Context, prompt string, extraArgs []string) (ClaudeResult, error) {
+	return b.o.runClaudeSDK(ctx, prompt, b.workDir, b.silence, extraArgs...)
+}
+
+// apiBackend sends the prompt to the Anthropic Messages API as a single
+// user message. The response is rewritten as stream-json assistant and
+// result events so RawOutput, history logs, and parseClaudeTokens work the
+// same as for the CLI backends. The API reports no cost, so CostUSD is 0.
+type apiBackend struct {
+	cfg     ClaudeConfig
+	silence bool
+	client  *http.Client
+}
+
+// apiContentBlock is a content block in a Messages API response.
+type apiContentBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
+// apiUsage is the usage object shared by the Messages API response and
+// the stream-json result event.
+type apiUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+// Run implements ClaudeBackend.
+func (b *apiBackend) Run(ctx context.Context, prompt string, extraArgs []string) (ClaudeResult, error) {
+	if b.cfg.APIKey == "" {
+		return ClaudeResult{}, fmt.Errorf("claude.api_key is required for the %s backend", ClaudeBackendAPI)
+	}
+	timeout := ctxTimeout(ctx)
+
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	type request struct {
+		Model       string    `json:"model"`
+		MaxTokens   int       `json:"max_tokens"`
+		Messages    []message `json:"messages"`
+		Temperature *float64  `json:"temperature,omitempty"`
+	}
+	req := request{
+		Model:     apiModel(b.cfg.APIModel, extraArgs),
+		MaxTokens: b.cfg.APIMaxTokens,
+		Messages:  []message{{Role: "user", Content: prompt}},
+	}
+	if b.cfg.Temperature != 0 {
+		t := b.cfg.Temperature
+		req.Temperature = &t
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return ClaudeResult{}, fmt.Errorf("marshalling request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, b.cfg.APIURL, bytes.NewReader(body))
+	if err != nil {
+		return ClaudeResult{}, fmt.Errorf("creating request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", b.cfg.APIKey)
+	httpReq.Header.Set("anthropic-version", "2023-06-01")
+
+	logf("runClaude: POST %s model=%s max_tokens=%d (backend=api timeout=%s)",
+		b.cfg.APIURL, req.Model, req.MaxTokens, timeout)
+	start := time.Now()
+	resp, err := b.client.Do(httpReq)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			logf("runClaude: killed after %s (max time %s exceeded)", time.Since(start).Round(time.Second), timeout)
+			return ClaudeResult{}, fmt.Errorf("claude max time exceeded (%s)", timeout)
+		}
+		return ClaudeResult{}, fmt.Errorf("API request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ClaudeResult{}, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return ClaudeResult{}, apiResponseError(resp.StatusCode, respBody)
+	}
+
+	var msg struct {
+		Content []apiContentBlock `json:"content"`
+		Usage   apiUsage          `json:"usage"`
+	}
+	if err := json.Unmarshal(respBody, &msg); err != nil {
+		return ClaudeResult{}, fmt.Errorf("parsing response: %w", err)
+	}
+
+	rawOutput, err := apiStreamJSON(msg.Content, msg.Usage)
+	if err != nil {
+		return ClaudeResult{}, err
+	}
+	if !b.silence {
+		fmt.Print(extractTextFromStreamJSON(rawOutput))
+	}
+	result := parseClaudeTokens(rawOutput)
+	result.RawOutput = rawOutput
+	logf("runClaude: finished in %s in=%d (cache_create=%d cache_read=%d) out=%d",
+		time.Since(start).Round(time.Second), result.InputTokens,
+		result.CacheCreationTokens, result.CacheReadTokens, result.OutputTokens)
+	return result, nil
+}
+
+// apiModel returns the value of a --model argument in extraArgs, or
+// fallback when there is none.
+func apiModel(fallback string, extraArgs []string) string {
+	model := fallback
+	for i := 0; i+1 < len(extraArgs); i++ {
+		if extraArgs[i] == "--model" {
+			model = extraArgs[i+1]
+		}
+	}
+	return model
+}
+
+// apiStreamJSON renders a Messages API response as the two stream-json
+// lines the claude CLI would print for it: an assistant event with the
+// content blocks and a result event with the usage.
+func apiStreamJSON(content []apiContentBlock, usage apiUsage) ([]byte, error) {
+	var text bytes.Buffer
+	for _, c := range content {
+		if c.Type == "text" {
+			text.WriteString(c.Text)
+		}
+	}
+	assistant := map[string]any{
+		"type":    "assistant",
+		"message": map[string]any{"role": "assistant", "content": content},
+	}
+	result := map[string]any{
+		"type":   "result",
+		"result": text.String(),
+		"usage":  usage,
+	}
+	var out bytes.Buffer
+	for _, ev := range []any{assistant, result} {
+		line, err := json.Marshal(ev)
+		if err != nil {
+			return nil, fmt.Errorf("encoding stream-json event: %w", err)
+		}
+		out.Write(line)
+		out.WriteByte('\n')
+	}
+	return out.Bytes(), nil
+}
+
+// checkAPIBackend verifies the api backend is usable: it needs an API key
+// but neither podman nor the claude binary.
+func (o *Orchestrator) checkAPIBackend() error {
+	if o.cfg.Claude.APIKey == "" {
+		return fmt.Errorf("claude.api_key is required when claude.backend is %q", ClaudeBackendAPI)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClaudeBackend_Selection(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		backend, mode string
+		check         func(ClaudeBackend) bool
+	}{
+		{"", "", func(b ClaudeBackend) bool { p, ok := b.(*podmanBackend); return ok && !p.direct }},
+		{ClaudeBackendPodman, ExecutionModeCLI, func(b ClaudeBackend) bool { p, ok := b.(*podmanBackend); return ok && p.direct }},
+		{"", ExecutionModeSDK, func(b ClaudeBackend) bool { _, ok := b.(*sdkBackend); return ok }},
+		{ClaudeBackendAPI, ExecutionModeCLI, func(b ClaudeBackend) bool { _, ok := b.(*apiBackend); return ok }},
+		{"bogus", "", func(b ClaudeBackend) bool { _, ok := b.(*podmanBackend); return ok }},
+	}
+	for _, tc := range cases {
+		o := &Orchestrator{cfg: Config{Claude: ClaudeConfig{Backend: tc.backend}, Cobbler: CobblerConfig{Mode: tc.mode}}}
+		if b := o.claudeBackend(t.TempDir(), true); !tc.check(b) {
+			t.Errorf("backend=%q mode=%q: got %T", tc.backend, tc.mode, b)
+		}
+	}
+}
+
+func TestAPIBackend_RunMapsUsageAndText(t *testing.T) {
+	t.Parallel()
+	var gotReq struct {
+		Model     string `json:"model"`
+		MaxTokens int    `json:"max_tokens"`
+		Messages  []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+	var gotKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("x-api-key")
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotReq)
+		resp := map[string]any{
+			"content": []map[string]string{{"type": "text", "text": "```yaml\na: 1\n```"}},
+			"usage": map[string]int{
+				"input_tokens": 10, "output_tokens": 5,
+				"cache_creation_input_tokens": 2, "cache_read_input_tokens": 3,
+			},
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	b := &apiBackend{
+		cfg:     ClaudeConfig{APIKey: "k", APIURL: srv.URL, APIModel: "default-model", APIMaxTokens: 100},
+		silence: true,
+		client:  srv.Client(),
+	}
+	res, err := b.Run(context.Background(), "hello", []string{"--max-turns", "1", "--model", "other"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if gotKey != "k" || gotReq.Model != "other" || gotReq.MaxTokens != 100 {
+		t.Errorf("request key=%q model=%q max_tokens=%d", gotKey, gotReq.Model, gotReq.MaxTokens)
+	}
+	if len(gotReq.Messages) != 1 || gotReq.Messages[0].Content != "hello" {
+		t.Errorf("request messages = %+v", gotReq.Messages)
+	}
+	if res.InputTokens != 15 || res.OutputTokens != 5 || res.CacheCreationTokens != 2 || res.CacheReadTokens != 3 {
+		t.Errorf("result tokens = %+v", res)
+	}
+	yml, err := extractYAMLBlock(extractTextFromStreamJSON(res.RawOutput))
+	if err != nil || string(yml) != "a: 1" {
+		t.Errorf("YAML from RawOutput = %q, %v", yml, err)
+	}
+}
+
+func TestAPIBackend_RunErrors(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	b := &apiBackend{cfg: ClaudeConfig{APIKey: "k", APIURL: srv.URL}, silence: true, client: srv.Client()}
+	if _, err := b.Run(context.Background(), "p", nil); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Run with 503 = %v, want status error", err)
+	}
+
+	b.cfg.APIKey = ""
+	if _, err := b.Run(context.Background(), "p", nil); err == nil || !strings.Contains(err.Error(), "api_key") {
+		t.Errorf("Run without key = %v, want api_key error", err)
+	}
+}
+
+func TestApplyDefaults_ClaudeAPIBackend(t *testing.T) {
+	t.Parallel()
+	var cfg Config
+	cfg.applyDefaults()
+	if cfg.Claude.effectiveBackend() != ClaudeBackendPodman {
+		t.Errorf("effectiveBackend = %q, want %q", cfg.Claude.effectiveBackend(), ClaudeBackendPodman)
+	}
+	if cfg.Claude.APIURL == "" || cfg.Claude.APIModel == "" || cfg.Claude.APIMaxTokens == 0 {
+		t.Errorf("api defaults not applied: %+v", cfg.Claude)
+	}
+}
+
+func TestCheckClaude_APIBackendRejectsStitch(t *testing.T) {
+	t.Parallel()
+	o := New(Config{Claude: ClaudeConfig{Backend: ClaudeBackendAPI, APIKey: "k"}})
+	if err := o.checkClaude(false); err != nil {
+		t.Errorf("checkClaude(false) = %v, want nil", err)
+	}
+	if err := o.checkClaude(true); err == nil || !strings.Contains(err.Error(), "stitch") {
+		t.Errorf("checkClaude(true) = %v, want stitch rejection", err)
+	}
+}
+
+func TestLoadConfig_ClaudeBackend(t *testing.T) {
+	t.Parallel()
+	cfg, err := LoadConfig(writeTemp(t, "claude:\n  backend: api\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Claude.effectiveBackend() != ClaudeBackendAPI {
+		t.Errorf("effectiveBackend = %q, want %q", cfg.Claude.effectiveBackend(), ClaudeBackendAPI)
+	}
+	if _, err := LoadConfig(writeTemp(t, "claude:\n  backend: API\n")); err == nil {
+		t.Error("expected an error for an unknown claude.backend")
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Claude error kinds returned by ClaudeError.Kind.
+const (
+	ClaudeErrorAuth          = "auth"
+	ClaudeErrorOverloaded    = "overloaded"
+	ClaudeErrorContextLength = "context_length"
+	ClaudeErrorOther         = "other"
+)
+
+// ClaudeError is a Claude failure reported in the session's result event
+// (or, for the api backend, the Messages API error body) rather than by
+// the process exit status alone (GH-533). Subtype is the result subtype
+// (e.g. "error_during_execution") or API error type, Message is the
+// reason Claude gave, and Err is the underlying process or request
+// error, if any.
+type ClaudeError struct {
+	Subtype string
+	Message string
+	Err     error
+}
+
+// Error implements error.
+func (e *ClaudeError) Error() string {
+	msg := "claude error"
+	if e.Subtype != "" {
+		msg += " (" + e.Subtype + ")"
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying process or request error.
+func (e *ClaudeError) Unwrap() error { return e.Err }
+
+// claudeAuthMarkers identify authentication failures, matched
+// case-insensitively against the subtype and message.
+var claudeAuthMarkers = []string{
+	"authentication_error",
+	"invalid api key",
+	"please run /login",
+	"oauth token has expired",
+	"unauthorized",
+}
+
+// claudeContextLengthMarkers identify prompts that exceed the model's
+// context window.
+var claudeContextLengthMarkers = []string{
+	"prompt is too long",
+	"context length",
+	"context window",
+}
+
+// Kind classifies the error as ClaudeErrorAuth, ClaudeErrorOverloaded
+// (rate limits included), ClaudeErrorContextLength, or ClaudeErrorOther.
+// A 529 counts only as "API Error: 529"; a bare "529" also appears in
+// line numbers, token counts, and issue numbers.
+func (e *ClaudeError) Kind() string {
+	text := strings.ToLower(e.Subtype + " " + e.Message)
+	switch {
+	case containsAny(text, claudeAuthMarkers):
+		return ClaudeErrorAuth
+	case hasRateLimitMarker(text) || strings.Contains(text, "api error: 529"):
+		return ClaudeErrorOverloaded
+	case containsAny(text, claudeContextLengthMarkers):
+		return ClaudeErrorContextLength
+	default:
+		return ClaudeErrorOther
+	}
+}
+
+// Retryable reports whether running the same prompt again may succeed:
+// true for overload and rate-limit errors, false for everything else.
+func (e *ClaudeError) Retryable() bool { return e.Kind() == ClaudeErrorOverloaded }
+
+// containsAny reports whether s contains any of subs.
+func containsAny(s string, subs []string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// claudeResultError returns the error for a CLI run given its stream-json
+// output and the process error. When the last result event has is_error
+// set, or the process failed and the result event carries a non-success
+// subtype, the reason is wrapped in a *ClaudeError. Otherwise runErr is
+// returned unchanged (nil for a clean run).
+func claudeResultError(rawOutput []byte, runErr error) error {
+	lines := bytes.Split(bytes.TrimSpace(rawOutput), []byte("\n"))
+	for i := len(lines) - 1; i >= 0; i-- {
+		var ev struct {
+			Type    string   `json:"type"`
+			Subtype string   `json:"subtype"`
+			IsError bool     `json:"is_error"`
+			Result  string   `json:"result"`
+			Errors  []string `json:"errors"`
+		}
+		if json.Unmarshal(lines[i], &ev) != nil || ev.Type != "result" {
+			continue
+		}
+		failed := ev.IsError || (runErr != nil && ev.Subtype != "" && ev.Subtype != "success")
+		if !failed {
+			return runErr
+		}
+		msg := ev.Result
+		if msg == "" {
+			msg = strings.Join(ev.Errors, "; ")
+		}
+		return &ClaudeError{Subtype: ev.Subtype, Message: msg, Err: runErr}
+	}
+	return runErr
+}
+
+// apiResponseError converts a non-200 Messages API response into a
+// *ClaudeError using the error type and message from the body, falling
+// back to the raw body when it is not an API error object.
+func apiResponseError(status int, body []byte) error {
+	var apiErr struct {
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	statusErr := fmt.Errorf("API returned %d", status)
+	if json.Unmarshal(body, &apiErr) != nil || apiErr.Error.Type == "" {
+		return &ClaudeError{Message: strings.TrimSpace(string(body)), Err: statusErr}
+	}
+	return &ClaudeError{Subtype: apiErr.Error.Type, Message: apiErr.Error.Message, Err: statusErr}
+}
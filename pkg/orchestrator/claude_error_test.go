@@ -0,0 +1,132 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestClaudeResultError_SampleEvents(t *testing.T) {
+	t.Parallel()
+	exit1 := errors.New("exit status 1")
+	cases := []struct {
+		name     string
+		raw      string
+		runErr   error
+		wantKind string // "" means no *ClaudeError
+		wantMsg  string
+	}{
+		{
+			name:     "context length",
+			raw:      `{"type":"assistant","message":{"content":[]}}` + "\n" + `{"type":"result","subtype":"success","is_error":true,"result":"Prompt is too long"}`,
+			runErr:   exit1,
+			wantKind: ClaudeErrorContextLength,
+			wantMsg:  "Prompt is too long",
+		},
+		{
+			name:     "auth failure",
+			raw:      `{"type":"result","subtype":"success","is_error":true,"result":"Invalid API key · Please run /login"}`,
+			runErr:   exit1,
+			wantKind: ClaudeErrorAuth,
+			wantMsg:  "Invalid API key · Please run /login",
+		},
+		{
+			name:     "overloaded",
+			raw:      `{"type":"result","subtype":"success","is_error":true,"result":"API Error: 529 {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}"}`,
+			runErr:   exit1,
+			wantKind: ClaudeErrorOverloaded,
+		},
+		{
+			name:     "error subtype with errors list",
+			raw:      `{"type":"result","subtype":"error_during_execution","is_error":false,"errors":["tool crashed"]}`,
+			runErr:   exit1,
+			wantKind: ClaudeErrorOther,
+			wantMsg:  "tool crashed",
+		},
+		{
+			name:     "is_error with clean exit",
+			raw:      `{"type":"result","subtype":"error_max_turns","is_error":true,"result":""}`,
+			wantKind: ClaudeErrorOther,
+		},
+		{
+			name:   "exit error without result event",
+			raw:    "not json",
+			runErr: exit1,
+		},
+		{
+			name: "success",
+			raw:  `{"type":"result","subtype":"success","is_error":false,"result":"done"}`,
+		},
+	}
+	for _, tc := range cases {
+		err := claudeResultError([]byte(tc.raw), tc.runErr)
+		var ce *ClaudeError
+		if !errors.As(err, &ce) {
+			if tc.wantKind != "" {
+				t.Errorf("%s: err = %v, want *ClaudeError", tc.name, err)
+			} else if err != tc.runErr {
+				t.Errorf("%s: err = %v, want %v unchanged", tc.name, err, tc.runErr)
+			}
+			continue
+		}
+		if tc.wantKind == "" {
+			t.Errorf("%s: got %v, want no ClaudeError", tc.name, ce)
+			continue
+		}
+		if ce.Kind() != tc.wantKind {
+			t.Errorf("%s: Kind = %q, want %q", tc.name, ce.Kind(), tc.wantKind)
+		}
+		if tc.wantMsg != "" && ce.Message != tc.wantMsg {
+			t.Errorf("%s: Message = %q, want %q", tc.name, ce.Message, tc.wantMsg)
+		}
+		if tc.runErr != nil && !errors.Is(err, tc.runErr) {
+			t.Errorf("%s: error does not wrap the process error", tc.name)
+		}
+	}
+}
+
+func TestClaudeError_Retryable(t *testing.T) {
+	t.Parallel()
+	if !(&ClaudeError{Message: "Overloaded"}).Retryable() {
+		t.Error("overloaded error should be retryable")
+	}
+	if (&ClaudeError{Subtype: "authentication_error", Message: "invalid x-api-key"}).Retryable() {
+		t.Error("auth error should not be retryable")
+	}
+}
+
+func TestClaudeError_Kind529(t *testing.T) {
+	t.Parallel()
+	if k := (&ClaudeError{Message: "API Error: 529 upstream busy"}).Kind(); k != ClaudeErrorOverloaded {
+		t.Errorf("API Error: 529 kind = %q, want %q", k, ClaudeErrorOverloaded)
+	}
+	for _, msg := range []string{
+		"syntax error at main.go:529",
+		"used 15290 tokens",
+		"issue #529 is blocked",
+	} {
+		if k := (&ClaudeError{Message: msg}).Kind(); k != ClaudeErrorOther {
+			t.Errorf("%q kind = %q, want %q", msg, k, ClaudeErrorOther)
+		}
+	}
+}
+
+func TestAPIResponseError(t *testing.T) {
+	t.Parallel()
+	err := apiResponseError(401, []byte(`{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`))
+	var ce *ClaudeError
+	if !errors.As(err, &ce) || ce.Kind() != ClaudeErrorAuth || ce.Message != "invalid x-api-key" {
+		t.Errorf("apiResponseError(401) = %#v, want auth ClaudeError", err)
+	}
+	if !strings.Contains(err.Error(), "401") {
+		t.Errorf("error %q does not mention the status", err)
+	}
+
+	err = apiResponseError(500, []byte("upstream broke"))
+	if !errors.As(err, &ce) || ce.Message != "upstream broke" {
+		t.Errorf("apiResponseError(500) = %#v, want raw body as message", err)
+	}
+}
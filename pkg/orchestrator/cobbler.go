@@ -8,11 +8,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
@@ -40,10 +42,14 @@ type ClaudeResult struct {
 	RawOutput           []byte
 }
 
-// LocSnapshot holds a point-in-time LOC count.
+// LocSnapshot holds a point-in-time LOC count. Production and Test are
+// the Go totals; the ByLang maps break LOC down per source extension
+// (GH-539).
 type LocSnapshot struct {
-	Production int `json:"production"`
-	Test       int `json:"test"`
+	Production       int            `json:"production" yaml:"production"`
+	Test             int            `json:"test" yaml:"test"`
+	ProductionByLang map[string]int `json:"production_by_lang,omitempty" yaml:"production_by_lang,omitempty"`
+	TestByLang       map[string]int `json:"test_by_lang,omitempty" yaml:"test_by_lang,omitempty"`
 }
 
 // sdkEnvMu serialises temporary process-env mutations in runClaudeSDK.
@@ -90,7 +96,12 @@ func (o *Orchestrator) captureLOC() LocSnapshot {
 		logf("captureLOC: collectStats error: %v", err)
 		return LocSnapshot{}
 	}
-	return LocSnapshot{Production: rec.GoProdLOC, Test: rec.GoTestLOC}
+	return LocSnapshot{
+		Production:       rec.GoProdLOC,
+		Test:             rec.GoTestLOC,
+		ProductionByLang: rec.ProdLOCByLang,
+		TestByLang:       rec.TestLOCByLang,
+	}
 }
 
 // captureLOCAt returns Go LOC counts measured in dir. It temporarily changes
@@ -144,10 +155,14 @@ type diffRecord struct {
 // and log artifacts in the history directory.
 type HistoryStats struct {
 	Caller        string        `yaml:"caller"`
+	Generation    string        `yaml:"generation,omitempty"`
 	TaskID        string        `yaml:"task_id,omitempty"`
 	TaskTitle     string        `yaml:"task_title,omitempty"`
 	Status        string        `yaml:"status,omitempty"`
 	Error         string        `yaml:"error,omitempty"`
+	Attempt       int           `yaml:"attempt,omitempty"`
+	Model         string        `yaml:"model,omitempty"`
+	DoneCheck     string        `yaml:"done_check,omitempty"`
 	StartedAt     string        `yaml:"started_at"`
 	Duration      string        `yaml:"duration"`
 	DurationS     int           `yaml:"duration_s"`
@@ -159,6 +174,20 @@ type HistoryStats struct {
 	LOCBefore     LocSnapshot   `yaml:"loc_before"`
 	LOCAfter      LocSnapshot   `yaml:"loc_after"`
 	Diff          historyDiff   `yaml:"diff"`
+
+	// MissingRequiredReading counts required_reading source paths that
+	// did not exist in the worktree (GH-541).
+	MissingRequiredReading int `yaml:"missing_required_reading,omitempty"`
+
+	// PromptBytes and PromptTokensEstimate size the prompt sent to Claude,
+	// the estimate using ContextCharsPerToken, so context growth across a
+	// generation shows up in the history (GH-561).
+	PromptBytes          int `yaml:"prompt_bytes,omitempty"`
+	PromptTokensEstimate int `yaml:"prompt_tokens_estimate,omitempty"`
+
+	// ContextTrimmed counts the source files and docs the context budget
+	// removed while building the prompt; non-zero means the budget was hit.
+	ContextTrimmed int `yaml:"context_trimmed,omitempty"`
 }
 
 type historyTokens struct {
@@ -186,6 +215,7 @@ type StitchReport struct {
 	Files     []FileChange `yaml:"files"`
 	LOCBefore LocSnapshot  `yaml:"loc_before"`
 	LOCAfter  LocSnapshot  `yaml:"loc_after"`
+	Rebased   bool         `yaml:"rebased,omitempty"` // merge needed a rebase onto the base branch (GH-515)
 }
 
 // historyDir returns the resolved history directory path. When HistoryDir is
@@ -308,16 +338,30 @@ func formatOutcomeTrailers(rec InvocationRecord) []string {
 	}
 }
 
+// requirementsTrailer returns the trailer listing the requirement IDs a
+// stitch commit covers (GH-489), e.g. "Requirements: prd003 R2.1, R4".
+// Returns "" when key is empty or "none", or when there are no IDs.
+func requirementsTrailer(key string, ids []string) string {
+	if key == "" || key == "none" || len(ids) == 0 {
+		return ""
+	}
+	return key + ": " + strings.Join(ids, ", ")
+}
+
 // appendOutcomeTrailers amends the last commit in the given git worktree
-// directory with outcome trailers from rec. All trailers are added in a
+// directory with outcome trailers from rec followed by any extra trailers
+// ("Key: Value"; empty strings are skipped). All trailers are added in a
 // single git commit --amend invocation (requires git >= 2.38.0).
 //
 // This function must be called before the worktree branch is merged so that
 // the trailers travel with the commit into the generation branch history.
 // Errors are returned but treated as non-fatal by callers.
-func appendOutcomeTrailers(worktreeDir string, rec InvocationRecord) error {
+func appendOutcomeTrailers(worktreeDir string, rec InvocationRecord, extra ...string) error {
 	args := []string{"-C", worktreeDir, "commit", "--amend", "--no-edit"}
-	for _, t := range formatOutcomeTrailers(rec) {
+	for _, t := range append(formatOutcomeTrailers(rec), extra...) {
+		if t == "" {
+			continue
+		}
 		args = append(args, "--trailer", t)
 	}
 	cmd := exec.Command(binGit, args...)
@@ -519,11 +563,20 @@ func parseClaudeTokens(output []byte) ClaudeResult {
 	return ClaudeResult{}
 }
 
-// checkClaude verifies that Claude can be invoked. In podman mode it
-// confirms podman is available and the container image exists. In CLI and
-// SDK modes it confirms the claude binary is on PATH. All modes verify
-// credentials.
-func (o *Orchestrator) checkClaude() error {
+// checkClaude verifies that Claude can be invoked. With the api backend it
+// only requires an API key, and fails when needsTools is set: stitch edits
+// files through Claude's tools, which the api backend does not provide.
+// Otherwise, in podman mode it confirms podman is available and the
+// container image exists, and in CLI and SDK modes it confirms the claude
+// binary is on PATH. All CLI modes verify credentials.
+func (o *Orchestrator) checkClaude(needsTools bool) error {
+	if o.cfg.Claude.effectiveBackend() == ClaudeBackendAPI {
+		if needsTools {
+			return fmt.Errorf("claude.backend %q has no file tools and cannot run stitch; use %q",
+				ClaudeBackendAPI, ClaudeBackendPodman)
+		}
+		return o.checkAPIBackend()
+	}
 	switch o.cfg.Cobbler.effectiveMode() {
 	case ExecutionModeCLI, ExecutionModeSDK:
 		if _, err := exec.LookPath(binClaude); err != nil {
@@ -537,34 +590,97 @@ func (o *Orchestrator) checkClaude() error {
 	return o.ensureCredentials()
 }
 
-// ensureCredentials checks that the credential file exists in SecretsDir.
-// If missing, it attempts to extract credentials from the macOS Keychain.
-// Returns an error if the file still does not exist after the attempt.
+// ensureCredentials checks that Claude credentials are available: the
+// credential file in SecretsDir, else the Claude.TokenEnvVar environment
+// variable, else (on macOS) the Keychain, which is extracted to the
+// credential file. Returns an error if none of them yields credentials.
+// On Linux the Secret Service takes the Keychain's place.
 func (o *Orchestrator) ensureCredentials() error {
 	credPath := filepath.Join(o.cfg.Claude.SecretsDir, o.cfg.EffectiveTokenFile())
 	if _, err := os.Stat(credPath); err == nil {
 		return nil
 	}
+	if o.credentialsFromEnv() {
+		logf("ensureCredentials: %s not found, using $%s", credPath, o.cfg.Claude.TokenEnvVar)
+		return nil
+	}
 
-	logf("ensureCredentials: %s not found, attempting keychain extraction", credPath)
-	if err := o.ExtractCredentials(); err != nil {
-		logf("ensureCredentials: keychain extraction failed: %v", err)
+	if store, _, ok := credentialStore(runtime.GOOS); ok {
+		logf("ensureCredentials: %s not found, attempting %s extraction", credPath, store)
+		if err := o.ExtractCredentials(); err != nil {
+			logf("ensureCredentials: %s extraction failed: %v", store, err)
+		}
 	}
 
 	if _, err := os.Stat(credPath); err != nil {
 		return fmt.Errorf("claude credentials not found at %s; "+
-			"run 'mage credentials' on the host or place a valid credential file at %s",
-			credPath, credPath)
+			"run 'mage credentials' on the host, set $%s, or place a valid credential file at %s",
+			credPath, o.cfg.Claude.TokenEnvVar, credPath)
 	}
 	return nil
 }
 
-// checkPodman verifies that podman is available and that the configured
-// image exists locally. If the image is missing, it builds it from the
-// embedded Dockerfile.
+// credentialsFromEnv reports whether Claude should authenticate with the
+// token in Claude.TokenEnvVar: the variable is set and no credential file
+// exists in SecretsDir, which takes precedence.
+func (o *Orchestrator) credentialsFromEnv() bool {
+	if o.cfg.Claude.TokenEnvVar == "" || os.Getenv(o.cfg.Claude.TokenEnvVar) == "" {
+		return false
+	}
+	credPath := filepath.Join(o.cfg.Claude.SecretsDir, o.cfg.EffectiveTokenFile())
+	_, err := os.Stat(credPath)
+	return err != nil
+}
+
+// prewarmRunTimeout bounds the no-op container started by Prewarm.
+const prewarmRunTimeout = 2 * time.Minute
+
+// Prewarm readies the Claude backend before a batch: it runs checkClaude
+// (podman present, image built or pulled, credential file present),
+// refreshes credentials, and, in podman mode with Podman.PrewarmRun set,
+// starts a throwaway container so image layers and the container runtime
+// are warm. Each step's duration is logged. RunStitchN calls it before any
+// task is claimed so container and credential problems surface early.
+//
+// Exposed as a mage target (e.g., mage podman:prewarm).
+func (o *Orchestrator) Prewarm() error {
+	start := time.Now()
+	if err := o.checkClaude(true); err != nil {
+		return err
+	}
+	logf("prewarm: backend check (mode=%s) took %s", o.cfg.Cobbler.effectiveMode(), time.Since(start).Round(time.Millisecond))
+
+	credStart := time.Now()
+	if o.credentialsFromEnv() {
+		logf("prewarm: using credentials from $%s, skipping keychain refresh", o.cfg.Claude.TokenEnvVar)
+	} else if err := o.ExtractCredentials(); err != nil {
+		logf("prewarm: credential refresh warning: %v", err)
+	} else {
+		logf("prewarm: credential refresh took %s", time.Since(credStart).Round(time.Millisecond))
+	}
+
+	if o.cfg.Cobbler.effectiveMode() == ExecutionModePodman && o.cfg.Podman.PrewarmRun {
+		runStart := time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), prewarmRunTimeout)
+		defer cancel()
+		args := append([]string{"run", "--rm"}, o.cfg.Podman.Args...)
+		args = append(args, o.cfg.Podman.Image, binClaude, "--version")
+		if out, err := exec.CommandContext(ctx, o.cfg.Podman.runtimeBin(), args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("prewarm container run: %w\n%s", err, out)
+		}
+		logf("prewarm: no-op container run took %s", time.Since(runStart).Round(time.Millisecond))
+	}
+
+	logf("prewarm: done in %s", time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// checkPodman verifies that the container runtime (podman or docker) is
+// available and that the configured image exists locally. If the image is
+// missing, it builds it from the embedded Dockerfile.
 func (o *Orchestrator) checkPodman() error {
-	if _, err := exec.LookPath(binPodman); err != nil {
-		return fmt.Errorf("podman not found on PATH; see README.md")
+	if _, err := exec.LookPath(o.cfg.Podman.runtimeBin()); err != nil {
+		return fmt.Errorf("%s not found on PATH; see README.md", o.cfg.Podman.runtimeBin())
 	}
 	return o.ensureImage()
 }
@@ -641,22 +757,45 @@ func extractYAMLBlock(text string) ([]byte, error) {
 	return []byte(strings.TrimSpace(content[:end])), nil
 }
 
-// runClaude executes Claude inside a podman container and returns token
-// usage. The process is killed if ClaudeMaxTimeSec is exceeded.
+// runClaude executes Claude through the configured ClaudeBackend and
+// returns token usage. The invocation is cancelled if ClaudeMaxTimeSec is
+// exceeded.
 // Extra Claude CLI arguments (e.g., "--max-turns", "1") are appended
 // after the default args.
 func (o *Orchestrator) runClaude(prompt, dir string, silence bool, extraClaudeArgs ...string) (ClaudeResult, error) {
-	logf("runClaude: promptLen=%d dir=%q silence=%v", len(prompt), dir, silence)
+	return o.runClaudeWithTimeout(prompt, dir, o.cfg.ClaudeTimeout(), silence, extraClaudeArgs...)
+}
 
-	if o.cfg.Claude.Temperature != 0 {
-		logf("runClaude: warning: temperature=%.2f configured but Claude CLI does not support --temperature; parameter ignored", o.cfg.Claude.Temperature)
+// modelArgs returns the Claude CLI arguments selecting model, or nil when
+// model is empty so the CLI default applies.
+func modelArgs(model string) []string {
+	if model == "" {
+		return nil
 	}
+	return []string{"--model", model}
+}
+
+// runClaudeWithTimeout is runClaude with an explicit max time in place of
+// ClaudeMaxTimeSec, used for per-task timeouts (GH-517).
+func (o *Orchestrator) runClaudeWithTimeout(prompt, dir string, timeout time.Duration, silence bool, extraClaudeArgs ...string) (ClaudeResult, error) {
+	logf("runClaude: promptLen=%d dir=%q silence=%v", len(prompt), dir, silence)
+
+	if o.cfg.Claude.effectiveBackend() == ClaudeBackendPodman {
+		if o.cfg.Claude.Temperature != 0 {
+			logf("runClaude: warning: temperature=%.2f configured but Claude CLI does not support --temperature; parameter ignored", o.cfg.Claude.Temperature)
+		}
 
-	// Refresh credentials from macOS Keychain before each invocation.
-	// OAuth tokens expire periodically; extracting just before launch
-	// ensures the container always gets a valid token.
-	if err := o.ExtractCredentials(); err != nil {
-		logf("runClaude: credential refresh warning: %v", err)
+		// Refresh credentials from the platform secret store (macOS
+		// Keychain or Linux Secret Service) before each invocation. OAuth
+		// tokens expire periodically; extracting just before launch
+		// ensures the container always gets a valid token. A token from
+		// Claude.TokenEnvVar needs no refresh. ExtractCredentials logs
+		// the store it read.
+		if !o.credentialsFromEnv() {
+			if err := o.ExtractCredentials(); err != nil {
+				logf("runClaude: credential refresh warning: %v", err)
+			}
+		}
 	}
 
 	workDir := dir
@@ -668,19 +807,34 @@ func (o *Orchestrator) runClaude(prompt, dir string, silence bool, extraClaudeAr
 		}
 	}
 
-	timeout := o.cfg.ClaudeTimeout()
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+	result, err := o.runWithRateLimitRetry(o.claudeBackend(workDir, silence), prompt, timeout, extraClaudeArgs)
+	o.spend.add(result.CostUSD)
+	return result, err
+}
 
-	if o.cfg.Cobbler.effectiveMode() == ExecutionModeSDK {
-		return o.runClaudeSDK(ctx, prompt, workDir, silence, extraClaudeArgs...)
-	}
+// podmanBackend runs the claude CLI as a subprocess, inside a podman
+// container or, when direct is set (ExecutionModeCLI), as the host binary.
+// It streams stdout for history, enforces the idle watchdog and the command
+// guard, and parses token usage from the stream-json result event.
+type podmanBackend struct {
+	o       *Orchestrator
+	workDir string
+	silence bool
+	direct  bool
+}
+
+// Run implements ClaudeBackend.
+func (b *podmanBackend) Run(ctx context.Context, prompt string, extraClaudeArgs []string) (ClaudeResult, error) {
+	o := b.o
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	timeout := ctxTimeout(ctx)
 
 	var cmd *exec.Cmd
-	if o.cfg.Cobbler.effectiveMode() == ExecutionModeCLI {
-		cmd = o.buildDirectCmd(ctx, workDir, extraClaudeArgs...)
+	if b.direct {
+		cmd = o.buildDirectCmd(ctx, b.workDir, extraClaudeArgs...)
 	} else {
-		cmd = o.buildPodmanCmd(ctx, workDir, extraClaudeArgs...)
+		cmd = o.buildPodmanCmd(ctx, b.workDir, extraClaudeArgs...)
 	}
 
 	cmd.Stdin = strings.NewReader(prompt)
@@ -692,12 +846,17 @@ func (o *Orchestrator) runClaude(prompt, dir string, silence bool, extraClaudeAr
 
 	var stdoutBuf bytes.Buffer
 	var outputWriter io.Writer
-	if silence {
+	if b.silence {
 		outputWriter = newProgressWriter(&stdoutBuf, time.Now())
 	} else {
 		outputWriter = io.MultiWriter(os.Stdout, &stdoutBuf)
 		cmd.Stderr = os.Stderr
 	}
+	// Scan tool calls for denied shell commands (GH-467).
+	guard := newCommandGuard(deniedCommandsFromArgs(extraClaudeArgs), cancel)
+	if guard != nil {
+		outputWriter = io.MultiWriter(outputWriter, guard)
+	}
 	// Wrap the output writer to update idleAt on every write.
 	cmd.Stdout = &idleTrackingWriter{w: outputWriter, lastWrite: &idleAt}
 
@@ -747,24 +906,32 @@ func (o *Orchestrator) runClaude(prompt, dir string, silence bool, extraClaudeAr
 		time.Since(start).Round(time.Second), result.InputTokens,
 		result.CacheCreationTokens, result.CacheReadTokens,
 		result.OutputTokens, result.CostUSD, err)
-	return result, err
+	if denied := guard.Triggered(); denied != "" {
+		return result, fmt.Errorf("claude aborted: denied command %q", denied)
+	}
+	return result, claudeResultError(rawOutput, err)
 }
 
 // buildPodmanCmd constructs the exec.Cmd for running Claude inside a
-// podman container. It mounts the working directory and the credential
-// file so Claude Code can authenticate.
+// container with the configured runtime (podman or docker). It mounts the
+// working directory and the credential file so Claude Code can
+// authenticate.
 func (o *Orchestrator) buildPodmanCmd(ctx context.Context, workDir string, extraClaudeArgs ...string) *exec.Cmd {
 	args := []string{"run", "--rm", "-i",
 		"-v", workDir + ":" + workDir,
 		"-w", workDir,
 	}
 
-	// Mount credentials into the container at the path Claude Code expects.
+	// Mount credentials into the container at the path Claude Code expects,
+	// or pass the token variable through by name so its value stays out
+	// of the command line.
 	credPath := filepath.Join(o.cfg.Claude.SecretsDir, o.cfg.EffectiveTokenFile())
 	if absCredPath, err := filepath.Abs(credPath); err == nil {
 		if _, err := os.Stat(absCredPath); err == nil {
 			args = append(args,
 				"-v", absCredPath+":"+o.cfg.Claude.ContainerCredentialsPath+":ro")
+		} else if o.credentialsFromEnv() {
+			args = append(args, "-e", o.cfg.Claude.TokenEnvVar)
 		}
 	}
 
@@ -774,8 +941,19 @@ func (o *Orchestrator) buildPodmanCmd(ctx context.Context, workDir string, extra
 	args = append(args, o.cfg.Claude.Args...)
 	args = append(args, extraClaudeArgs...)
 
-	logf("runClaude: exec %s %v (timeout=%s)", binPodman, args, o.cfg.ClaudeTimeout())
-	return exec.CommandContext(ctx, binPodman, args...)
+	bin := o.cfg.Podman.runtimeBin()
+	logf("runClaude: exec %s %v (timeout=%s)", bin, args, ctxTimeout(ctx))
+	return exec.CommandContext(ctx, bin, args...)
+}
+
+// ctxTimeout describes the time left before ctx's deadline for logging,
+// or "none" when it has no deadline.
+func ctxTimeout(ctx context.Context) string {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return "none"
+	}
+	return time.Until(deadline).Round(time.Second).String()
 }
 
 // buildDirectCmd constructs the exec.Cmd for running the claude binary
@@ -789,7 +967,7 @@ func (o *Orchestrator) buildPodmanCmd(ctx context.Context, workDir string, extra
 func (o *Orchestrator) buildDirectCmd(ctx context.Context, workDir string, extraClaudeArgs ...string) *exec.Cmd {
 	args := append([]string{}, o.cfg.Claude.Args...)
 	args = append(args, extraClaudeArgs...)
-	logf("runClaude: exec %s %v (mode=cli timeout=%s)", binClaude, args, o.cfg.ClaudeTimeout())
+	logf("runClaude: exec %s %v (mode=cli timeout=%s)", binClaude, args, ctxTimeout(ctx))
 	cmd := exec.CommandContext(ctx, binClaude, args...)
 	cmd.Dir = workDir
 	// Inherit the full environment but remove CLAUDECODE so that the claude
@@ -829,16 +1007,28 @@ func (o *Orchestrator) runClaudeSDK(ctx context.Context, prompt, workDir string,
 		Preset: "claude_code",
 	})
 
-	// Map --max-turns from extraClaudeArgs into the options struct.
+	// Map --max-turns and --model from extraClaudeArgs into the options struct.
 	for i := 0; i+1 < len(extraClaudeArgs); i++ {
-		if extraClaudeArgs[i] == "--max-turns" {
+		switch extraClaudeArgs[i] {
+		case "--max-turns":
 			if n, err := strconv.Atoi(extraClaudeArgs[i+1]); err == nil {
 				opts = opts.WithMaxTurns(n)
 			}
 			i++ // skip the value token
+		case "--model":
+			opts = opts.WithModel(extraClaudeArgs[i+1])
+			i++
+		case disallowedToolsFlag:
+			opts.DisallowedTools = append(opts.DisallowedTools, extraClaudeArgs[i+1])
+			i++
 		}
 	}
 
+	// Abort the session if a denied shell command slips past the CLI rules.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	guard := newCommandGuard(deniedCommandsFromArgs(extraClaudeArgs), cancel)
+
 	start := time.Now()
 
 	// Redirect os.Stderr through a filter pipe for the full duration of this
@@ -860,7 +1050,7 @@ func (o *Orchestrator) runClaudeSDK(ctx context.Context, prompt, workDir string,
 		go filterSDKStderr(pr, origStderr, stderrDone)
 	}
 	// Log after os.Stderr is redirected so the message flows through the filter.
-	logf("runClaude: SDK query workDir=%q (timeout=%s)", workDir, o.cfg.ClaudeTimeout())
+	logf("runClaude: SDK query workDir=%q (timeout=%s)", workDir, ctxTimeout(ctx))
 
 	// Cleanup: restore stderr and drain the filter goroutine after the call.
 	// Defined here so all return paths below trigger it via the defer.
@@ -910,6 +1100,11 @@ func (o *Orchestrator) runClaudeSDK(ctx context.Context, prompt, workDir string,
 						fmt.Print(b.Text)
 					}
 					textBuf.WriteString(b.Text)
+				case *claudetypes.ToolUseBlock:
+					if guard != nil {
+						command, _ := b.Input["command"].(string)
+						guard.checkToolUse(b.Name, command)
+					}
 				}
 			}
 		case *claudetypes.ResultMessage:
@@ -925,11 +1120,18 @@ func (o *Orchestrator) runClaudeSDK(ctx context.Context, prompt, workDir string,
 			result.DurationAPIMs = m.DurationAPIMs
 			result.SessionID = m.SessionID
 			if m.IsError {
-				return result, fmt.Errorf("claude SDK session returned error result")
+				msg := ""
+				if m.Result != nil {
+					msg = *m.Result
+				}
+				return result, &ClaudeError{Subtype: m.Subtype, Message: msg, Err: errors.New("claude SDK session returned error result")}
 			}
 		}
 	}
 
+	if denied := guard.Triggered(); denied != "" {
+		return result, fmt.Errorf("claude aborted: denied command %q", denied)
+	}
 	if !gotResult {
 		return ClaudeResult{}, fmt.Errorf("claude SDK session produced no result (subprocess may have exited early)")
 	}
@@ -972,14 +1174,25 @@ func (o *Orchestrator) logConfig(target string) {
 	if o.cfg.Cobbler.UserPrompt != "" {
 		logf("%s config: userPrompt=%q", target, o.cfg.Cobbler.UserPrompt)
 	}
+	if o.cfg.Cobbler.FocusArea != "" {
+		logf("%s config: focusArea=%q", target, o.cfg.Cobbler.FocusArea)
+	}
 }
 
-// worktreeBasePath returns the directory used for stitch worktrees.
+// worktreeBasePath returns the directory used for stitch worktrees: a
+// per-repo "<repo>-worktrees" directory under parent, or under os.TempDir()
+// when parent is empty (Cobbler.WorktreeDir, GH-552). A relative parent is
+// resolved against the working directory.
 // It uses git rev-parse --git-common-dir to resolve the shared .git directory
 // so the path is identical whether the orchestrator is invoked from the main
 // repo root or from a git worktree of the same repository (prd003 R3.16).
 // Falls back to filepath.Base(os.Getwd()) when git is unavailable.
-func worktreeBasePath() string {
+func worktreeBasePath(parent string) string {
+	if parent == "" {
+		parent = os.TempDir()
+	} else if abs, err := filepath.Abs(parent); err == nil {
+		parent = abs
+	}
 	out, err := exec.Command("git", "rev-parse", "--git-common-dir").Output()
 	if err == nil {
 		gitDir := filepath.Clean(strings.TrimSpace(string(out)))
@@ -988,10 +1201,10 @@ func worktreeBasePath() string {
 			gitDir = filepath.Join(cwd, gitDir)
 		}
 		repoRoot := filepath.Dir(gitDir)
-		return filepath.Join(os.TempDir(), filepath.Base(repoRoot)+"-worktrees")
+		return filepath.Join(parent, filepath.Base(repoRoot)+"-worktrees")
 	}
 	repoRoot, _ := os.Getwd()
-	return filepath.Join(os.TempDir(), filepath.Base(repoRoot)+"-worktrees")
+	return filepath.Join(parent, filepath.Base(repoRoot)+"-worktrees")
 }
 
 // hasOpenIssues returns true if there are open orchestrator issues for the
@@ -13,6 +13,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -506,7 +507,7 @@ func TestHistoryDir_Relative(t *testing.T) {
 
 func TestWorktreeBasePath(t *testing.T) {
 	t.Parallel()
-	got := worktreeBasePath()
+	got := worktreeBasePath("")
 	if got == "" {
 		t.Fatal("worktreeBasePath() returned empty string")
 	}
@@ -554,11 +555,11 @@ func TestWorktreeBasePath_FromWorktree(t *testing.T) {
 
 	// Call from main repo.
 	os.Chdir(mainDir)
-	fromMain := worktreeBasePath()
+	fromMain := worktreeBasePath("")
 
 	// Call from inside the worktree.
 	os.Chdir(wtDir)
-	fromWorktree := worktreeBasePath()
+	fromWorktree := worktreeBasePath("")
 
 	if fromMain != expected {
 		t.Errorf("from main: got %q, want %q", fromMain, expected)
@@ -579,7 +580,7 @@ func TestWorktreeBasePath_FallbackOutsideGit(t *testing.T) {
 	os.Chdir(dir)
 	defer os.Chdir(orig)
 
-	got := worktreeBasePath()
+	got := worktreeBasePath("")
 	if got == "" {
 		t.Fatal("worktreeBasePath() returned empty string in fallback")
 	}
@@ -592,6 +593,27 @@ func TestWorktreeBasePath_FallbackOutsideGit(t *testing.T) {
 	}
 }
 
+// TestWorktreeBasePath_ConfiguredDir verifies that Cobbler.WorktreeDir
+// replaces os.TempDir() as the parent and keeps the per-repo suffix
+// (GH-552).
+func TestWorktreeBasePath_ConfiguredDir(t *testing.T) {
+	dir := t.TempDir()
+	parent := t.TempDir()
+	orig, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(orig)
+
+	want := filepath.Join(parent, filepath.Base(dir)+"-worktrees")
+	if got := worktreeBasePath(parent); got != want {
+		t.Errorf("worktreeBasePath(%q) = %q, want %q", parent, got, want)
+	}
+	cwd, _ := os.Getwd()
+	want = filepath.Join(cwd, "wt", filepath.Base(dir)+"-worktrees")
+	if got := worktreeBasePath("wt"); got != want {
+		t.Errorf("worktreeBasePath(\"wt\") = %q, want %q", got, want)
+	}
+}
+
 // --- saveHistoryStats ---
 
 func TestSaveHistoryStats_WritesFile(t *testing.T) {
@@ -762,6 +784,99 @@ func TestBuildPodmanCmd_ExtraArgsAppended(t *testing.T) {
 	}
 }
 
+func TestBuildPodmanCmd_UsesSelectedRuntime(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		runtime, want string
+	}{
+		{"", binPodman},
+		{ContainerRuntimePodman, binPodman},
+		{ContainerRuntimeDocker, binDocker},
+	}
+	for _, tc := range cases {
+		cfg := Config{}
+		cfg.Podman.Runtime = tc.runtime
+		cfg.Podman.Image = "img"
+		o := New(cfg)
+		cmd := o.buildPodmanCmd(context.TODO(), "/work")
+		if filepath.Base(cmd.Args[0]) != tc.want {
+			t.Errorf("runtime %q: binary = %q, want %q", tc.runtime, cmd.Args[0], tc.want)
+		}
+		want := []string{"run", "--rm", "-i", "-v", "/work:/work", "-w", "/work"}
+		if !slices.Equal(cmd.Args[1:len(want)+1], want) {
+			t.Errorf("runtime %q: args = %v, want prefix %v", tc.runtime, cmd.Args[1:], want)
+		}
+	}
+}
+
+func TestLoadConfig_PodmanRuntime(t *testing.T) {
+	t.Parallel()
+	cfg, err := LoadConfig(writeTemp(t, "podman:\n  runtime: docker\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Podman.Runtime != ContainerRuntimeDocker {
+		t.Errorf("podman.runtime = %q, want %q", cfg.Podman.Runtime, ContainerRuntimeDocker)
+	}
+	if _, err := LoadConfig(writeTemp(t, "podman:\n  runtime: dokcer\n")); err == nil {
+		t.Error("expected an error for an unknown podman.runtime")
+	}
+}
+
+func TestBuildPodmanCmd_PassesTokenEnvVarWithoutCredentialFile(t *testing.T) {
+	t.Setenv("COBBLER_TEST_OAUTH_TOKEN", "secret-token")
+	cfg := Config{}
+	cfg.Claude.SecretsDir = t.TempDir()
+	cfg.Claude.TokenEnvVar = "COBBLER_TEST_OAUTH_TOKEN"
+	o := New(cfg)
+
+	cmd := o.buildPodmanCmd(context.TODO(), "/work")
+	joined := strings.Join(cmd.Args, " ")
+	if !strings.Contains(joined, "-e COBBLER_TEST_OAUTH_TOKEN") {
+		t.Errorf("args missing -e for the token variable; args=%v", cmd.Args)
+	}
+	if strings.Contains(joined, "secret-token") {
+		t.Errorf("token value leaked into args; args=%v", cmd.Args)
+	}
+	if err := o.ensureCredentials(); err != nil {
+		t.Errorf("ensureCredentials with token variable set: %v", err)
+	}
+}
+
+func TestBuildPodmanCmd_CredentialFileTakesPrecedenceOverEnv(t *testing.T) {
+	t.Setenv("COBBLER_TEST_OAUTH_TOKEN", "secret-token")
+	cfg := Config{}
+	cfg.Claude.SecretsDir = t.TempDir()
+	cfg.Claude.TokenEnvVar = "COBBLER_TEST_OAUTH_TOKEN"
+	o := New(cfg)
+	credPath := filepath.Join(cfg.Claude.SecretsDir, o.cfg.EffectiveTokenFile())
+	if err := os.WriteFile(credPath, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if o.credentialsFromEnv() {
+		t.Error("credentialsFromEnv = true with a credential file present")
+	}
+	cmd := o.buildPodmanCmd(context.TODO(), "/work")
+	joined := strings.Join(cmd.Args, " ")
+	if strings.Contains(joined, "-e COBBLER_TEST_OAUTH_TOKEN") {
+		t.Errorf("token variable passed despite credential file; args=%v", cmd.Args)
+	}
+	if !strings.Contains(joined, credPath+":"+o.cfg.Claude.ContainerCredentialsPath+":ro") {
+		t.Errorf("credential file not mounted; args=%v", cmd.Args)
+	}
+}
+
+func TestModelArgs(t *testing.T) {
+	t.Parallel()
+	if got := modelArgs(""); got != nil {
+		t.Errorf("modelArgs(\"\") = %v, want nil", got)
+	}
+	if got := modelArgs("haiku"); !slices.Equal(got, []string{"--model", "haiku"}) {
+		t.Errorf("modelArgs(haiku) = %v", got)
+	}
+}
+
 // --- buildDirectCmd ---
 
 func TestBuildDirectCmd_UsesClaudeBinary(t *testing.T) {
@@ -1211,7 +1326,7 @@ func TestAppendOutcomeTrailers_AmendsLastCommit(t *testing.T) {
 		LOCBefore: LocSnapshot{Production: 100, Test: 20},
 		LOCAfter:  LocSnapshot{Production: 150, Test: 30},
 	}
-	if err := appendOutcomeTrailers(dir, rec); err != nil {
+	if err := appendOutcomeTrailers(dir, rec, "", requirementsTrailer("Requirements", []string{"prd003 R2.1", "R4"})); err != nil {
 		// git commit --amend --trailer requires git >= 2.38; skip if unsupported.
 		t.Skipf("appendOutcomeTrailers: %v", err)
 	}
@@ -1228,6 +1343,7 @@ func TestAppendOutcomeTrailers_AmendsLastCommit(t *testing.T) {
 		"Duration-Seconds:",
 		"Loc-Prod-Before:",
 		"Loc-Prod-After:",
+		"Requirements: prd003 R2.1, R4",
 	} {
 		if !strings.Contains(trailerStr, wantKey) {
 			t.Errorf("trailer output missing key %q\ngot:\n%s", wantKey, trailerStr)
@@ -1235,6 +1351,21 @@ func TestAppendOutcomeTrailers_AmendsLastCommit(t *testing.T) {
 	}
 }
 
+func TestRequirementsTrailer(t *testing.T) {
+	t.Parallel()
+	if got := requirementsTrailer("Requirements", []string{"R1", "R2"}); got != "Requirements: R1, R2" {
+		t.Errorf("requirementsTrailer = %q", got)
+	}
+	for _, key := range []string{"", "none"} {
+		if got := requirementsTrailer(key, []string{"R1"}); got != "" {
+			t.Errorf("requirementsTrailer(%q) = %q, want empty", key, got)
+		}
+	}
+	if got := requirementsTrailer("Requirements", nil); got != "" {
+		t.Errorf("requirementsTrailer with no IDs = %q, want empty", got)
+	}
+}
+
 // --- newProgressWriter ---
 
 func TestNewProgressWriter(t *testing.T) {
@@ -1967,3 +2098,15 @@ func TestNew_IdleTimeoutZeroDisablesWatchdog(t *testing.T) {
 		t.Error("default idle timeout should not be 0 (use 60)")
 	}
 }
+
+// --- Prewarm ---
+
+func TestPrewarm_FailsWhenClaudeMissing(t *testing.T) {
+	// Not parallel: modifies PATH.
+	t.Setenv("PATH", t.TempDir())
+	o := &Orchestrator{cfg: Config{Cobbler: CobblerConfig{Mode: ExecutionModeCLI}}}
+	err := o.Prewarm()
+	if err == nil || !strings.Contains(err.Error(), "claude not found") {
+		t.Errorf("expected claude not found error, got %v", err)
+	}
+}
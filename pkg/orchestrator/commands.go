@@ -4,23 +4,27 @@
 package orchestrator
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
 
 // Binary names.
 const (
-	binGit      = "git"
-	binClaude   = "claude"
-	binGh       = "gh"
-	binGo       = "go"
-	binLint     = "golangci-lint"
-	binMage     = "mage"
-	binPodman   = "podman"
-	binSecurity = "security"
+	binGit        = "git"
+	binClaude     = "claude"
+	binDocker     = "docker"
+	binGh         = "gh"
+	binGo         = "go"
+	binLint       = "golangci-lint"
+	binMage       = "mage"
+	binPodman     = "podman"
+	binSecurity   = "security"
+	binSecretTool = "secret-tool"
 )
 
 // Directory and file path constants.
@@ -98,6 +102,10 @@ func gitForceDeleteBranch(name, dir string) error {
 	return cmdGit(dir, "branch", "-D", name).Run()
 }
 
+func gitRenameBranch(oldName, newName, dir string) error {
+	return cmdGit(dir, "branch", "-m", oldName, newName).Run()
+}
+
 func gitBranchExists(name, dir string) bool {
 	return cmdGit(dir, "show-ref", "--verify", "--quiet", "refs/heads/"+name).Run() == nil
 }
@@ -147,6 +155,40 @@ func gitLsFiles(dir string) []string {
 	return parseBranchList(string(out))
 }
 
+// gitCheckIgnored returns the subset of paths that .gitignore rules
+// (including nested .gitignore files, .git/info/exclude, and the global
+// excludes file) ignore, as reported by git check-ignore. Tracked files
+// are never reported. Returns an error outside a git repository.
+func gitCheckIgnored(dir string, paths []string) (map[string]bool, error) {
+	ignored := make(map[string]bool)
+	if len(paths) == 0 {
+		return ignored, nil
+	}
+	cmd := cmdGit(dir, "check-ignore", "--stdin")
+	cmd.Stdin = strings.NewReader(strings.Join(paths, "\n") + "\n")
+	out, err := cmd.Output()
+	if err != nil {
+		// Exit status 1 means no path is ignored.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return ignored, nil
+		}
+		return nil, fmt.Errorf("git check-ignore: %w", err)
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			ignored[line] = true
+		}
+	}
+	return ignored, nil
+}
+
+// gitBlameLinePorcelain returns git blame --line-porcelain output for path,
+// which repeats the full commit header for every line.
+func gitBlameLinePorcelain(path, dir string) ([]byte, error) {
+	return cmdGit(dir, "blame", "--line-porcelain", "--", path).Output()
+}
+
 func gitStageAll(dir string) error {
 	return cmdGit(dir, "add", "-A").Run()
 }
@@ -196,6 +238,38 @@ func gitMergeCmd(branch, dir string) *exec.Cmd {
 	return cmdGit(dir, "merge", branch, "--no-edit")
 }
 
+func gitMergeAbort(dir string) error {
+	return cmdGit(dir, "merge", "--abort").Run()
+}
+
+func gitMergeSquash(branch, dir string) ([]byte, error) {
+	return cmdGit(dir, "merge", "--squash", branch).CombinedOutput()
+}
+
+// gitResetMerge discards a conflicted merge --squash, which leaves no
+// MERGE_HEAD for merge --abort to act on.
+func gitResetMerge(dir string) error {
+	return cmdGit(dir, "reset", "--merge").Run()
+}
+
+// gitCommitTrailers returns the trailer block of the commit at ref, one
+// "Key: Value" per line.
+func gitCommitTrailers(ref, dir string) (string, error) {
+	out, err := cmdGit(dir, "log", "-1", "--format=%(trailers:only,unfold)", ref).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func gitRebase(onto, dir string) ([]byte, error) {
+	return cmdGit(dir, "rebase", onto).CombinedOutput()
+}
+
+func gitRebaseAbort(dir string) error {
+	return cmdGit(dir, "rebase", "--abort").Run()
+}
+
 func gitWorktreePrune(dir string) error {
 	return cmdGit(dir, "worktree", "prune").Run()
 }
@@ -206,12 +280,56 @@ func gitWorktreeAdd(worktreeDir, branch, dir string) *exec.Cmd {
 	return cmdGit(dir, "worktree", "add", worktreeDir, branch)
 }
 
+// addSparseWorktree adds a worktree for branch at worktreeDir that checks
+// out only the given directories (cone mode) plus top-level files. The
+// worktree is created without a checkout, the sparse cone is set, and then
+// the branch is checked out, so excluded directories are never written.
+// dir is the repository root used as cmd.Dir (empty means process CWD).
+func addSparseWorktree(worktreeDir, branch string, paths []string, dir string) error {
+	if out, err := cmdGit(dir, "worktree", "add", "--no-checkout", worktreeDir, branch).CombinedOutput(); err != nil {
+		return fmt.Errorf("worktree add: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	args := append([]string{"sparse-checkout", "set", "--cone"}, paths...)
+	if out, err := cmdGit(worktreeDir, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("sparse-checkout set: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	if out, err := cmdGit(worktreeDir, "checkout", branch).CombinedOutput(); err != nil {
+		return fmt.Errorf("checkout: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
 // gitWorktreeRemove removes the worktree at worktreeDir.
 // dir is the repository root used as cmd.Dir (empty means process CWD).
 func gitWorktreeRemove(worktreeDir, dir string) error {
 	return cmdGit(dir, "worktree", "remove", worktreeDir, "--force").Run()
 }
 
+func gitWorktreeMove(worktreeDir, newDir, dir string) error {
+	return cmdGit(dir, "worktree", "move", worktreeDir, newDir).Run()
+}
+
+// gitWorktreeBranches returns the branch checked out in each worktree of
+// the repository at dir, keyed by the worktree directory's base name;
+// git may report the directory through a resolved symlink, so callers
+// match on names. Detached worktrees and list failures are omitted.
+func gitWorktreeBranches(dir string) map[string]string {
+	branches := make(map[string]string)
+	out, err := cmdGit(dir, "worktree", "list", "--porcelain").Output()
+	if err != nil {
+		return branches
+	}
+	var current string
+	for _, line := range strings.Split(string(out), "\n") {
+		if p, ok := strings.CutPrefix(line, "worktree "); ok {
+			current = filepath.Base(p)
+		} else if b, ok := strings.CutPrefix(line, "branch refs/heads/"); ok {
+			branches[current] = b
+		}
+	}
+	return branches
+}
+
 func gitCurrentBranch(dir string) (string, error) {
 	out, err := cmdGit(dir, "rev-parse", "--abbrev-ref", "HEAD").Output()
 	if err != nil {
@@ -377,15 +495,16 @@ func parseNumstat(output string) map[string]numstatEntry {
 
 // Podman helpers.
 
-// podmanBuild builds a container image from a Dockerfile, applying one or
-// more image tags. Each tag is a full image reference (e.g., "name:v1").
-func podmanBuild(dockerfile string, tags ...string) error {
+// podmanBuild builds a container image from a Dockerfile with the given
+// container runtime binary (podman or docker), applying one or more image
+// tags. Each tag is a full image reference (e.g., "name:v1").
+func podmanBuild(runtime, dockerfile string, tags ...string) error {
 	args := []string{"build", "-f", dockerfile}
 	for _, t := range tags {
 		args = append(args, "-t", t)
 	}
 	args = append(args, ".")
-	cmd := exec.Command(binPodman, args...)
+	cmd := exec.Command(runtime, args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
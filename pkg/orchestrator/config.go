@@ -6,6 +6,8 @@ package orchestrator
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -32,6 +34,11 @@ type ProjectConfig struct {
 	// VersionFile is the path to the version file.
 	VersionFile string `yaml:"version_file"`
 
+	// VersionFiles lists additional version files for repositories with
+	// several binaries under cmd/, each with its own Version constant.
+	// Tag updates VersionFile and every entry here (GH-525).
+	VersionFiles []string `yaml:"version_files"`
+
 	// MagefilesDir is the directory skipped when deleting Go files
 	// (default "magefiles").
 	MagefilesDir string `yaml:"magefiles_dir"`
@@ -56,9 +63,25 @@ type ProjectConfig struct {
 	// ContextExclude is a newline-delimited list of glob patterns. Files
 	// matching any pattern (or under a matching directory) are excluded
 	// from the project context. Applied to docs, context sources, and
-	// source code. Use "." to exclude everything.
+	// source code. Use "." to exclude everything. Patterns in
+	// ContextSources, ContextInclude, and ContextExclude are relative to
+	// the repository root, or the task worktree's root during stitch,
+	// whatever the working directory (GH-563).
 	ContextExclude string `yaml:"context_exclude"`
 
+	// ExcludeContains lists markers, e.g. "DO NOT EDIT", that exclude a
+	// source file from the project context when any of them appears in
+	// its first excludeContainsHeaderBytes bytes. It catches generated
+	// files that share a header but no path prefix; ContextExclude still
+	// applies by path (GH-554).
+	ExcludeContains []string `yaml:"exclude_contains"`
+
+	// MaxSourceFileBytes skips source files larger than this many bytes
+	// when loading the project context, so a checked-in minified or
+	// generated blob cannot crowd out the rest of the prompt. Files that
+	// are not valid UTF-8 are always skipped. 0 disables the cap (GH-557).
+	MaxSourceFileBytes int64 `yaml:"max_source_file_bytes"`
+
 	// Release is the target release version (e.g., "01.0"). When set,
 	// use cases and test suites are filtered to only include files whose
 	// release version is <= this value. PRDs are filtered to only those
@@ -75,6 +98,55 @@ type ProjectConfig struct {
 	// An empty list disables release-based filtering and includes all files.
 	Releases []string `yaml:"releases"`
 
+	// MaxSourceFiles caps the number of source files included in the
+	// project context, independent of MaxContextBytes. Required-reading
+	// files are kept first, then files under the task's package-scoped
+	// directories, then the rest in load order. Dropped files are logged.
+	// Zero means unlimited.
+	MaxSourceFiles int `yaml:"max_source_files"`
+
+	// SourceLoadWorkers bounds the number of goroutines that read and
+	// number source files when building the project context. Zero uses
+	// runtime.NumCPU() (GH-504).
+	SourceLoadWorkers int `yaml:"source_load_workers"`
+
+	// RespectGitignore skips untracked source files matched by .gitignore
+	// rules (including nested .gitignore files) when loading source into
+	// the project context, so ignored vendored code, generated mocks, and
+	// caches stay out of prompts. ContextExclude still applies on top.
+	// Default true; a pointer so nil (absent) is treated as true and an
+	// explicit false opts out (GH-505).
+	RespectGitignore *bool `yaml:"respect_gitignore"`
+
+	// SourceExtensions lists the file suffixes loaded as source into the
+	// project context from GoSourceDirs, e.g. [".go", ".proto", ".sql"].
+	// All are line-numbered the same way. LOC stats count every listed
+	// suffix per language (GH-539); the measure P7 file-naming check stays
+	// Go-only. Default [".go"] (GH-506).
+	SourceExtensions []string `yaml:"source_extensions"`
+
+	// StripComments removes // and /* */ comments from .go source before
+	// it enters the project context, including the copyright header.
+	// Line numbers are preserved, so {number} | {line} references still
+	// match the file on disk; //go: directives are kept. Other source
+	// extensions are loaded unchanged. Default false (GH-507).
+	StripComments bool `yaml:"strip_comments"`
+
+	// FollowSymlinks descends into symlinked directories under
+	// GoSourceDirs, for monorepos that link shared packages into pkg/.
+	// Only links resolving inside the repository root are followed, and
+	// each real directory is walked at most once, so circular links
+	// cannot loop. Default false: symlinked directories are skipped
+	// (GH-558).
+	FollowSymlinks bool `yaml:"follow_symlinks"`
+
+	// SourceOrder sets the order of source files in the project context:
+	// SourceOrderPath (default) sorts by path; SourceOrderDependency groups
+	// files by package and lists imported packages before their importers,
+	// so Claude reads types before the code that uses them. Imports are
+	// read from .go files without type-checking (GH-550).
+	SourceOrder string `yaml:"source_order"`
+
 	// TargetRepo is the GitHub repository (owner/repo) of the project being
 	// analyzed and developed. It is used to file defect issues (schema errors,
 	// constitution drift) discovered by RunPreCycleAnalysis in the target repo
@@ -117,6 +189,38 @@ type GenerationConfig struct {
 	// Default false; all existing behaviour is unchanged when false.
 	// See prd002 R10.
 	PreserveSources bool `yaml:"preserve_sources"`
+
+	// MaxDuration caps the wall-clock time of a generator run, as a Go
+	// duration string (e.g. "4h", "90m"). RunCycles checks it at each
+	// cycle boundary and stops cleanly once the deadline has passed; the
+	// cycle in progress always finishes. Combines with Cycles and the
+	// stitch limits, whichever triggers first. Empty means no cap; zero
+	// and negative durations are rejected (GH-490).
+	MaxDuration string `yaml:"max_duration"`
+
+	// MaxCostUSD caps the Claude spend of a generation, summed over every
+	// measure and stitch invocation. Once the total reaches the cap the
+	// stitch loop stops after the current task and RunCycles returns
+	// without error. The running total is kept in the cobbler directory so
+	// generator:resume continues the same budget. 0 means no cap (GH-532).
+	MaxCostUSD float64 `yaml:"max_cost_usd"`
+
+	// OutputJSON makes generator:status and generator:list print JSON
+	// instead of human-readable text, for consumption by CI and dashboards
+	// (GH-520).
+	OutputJSON bool `yaml:"output_json"`
+
+	// DryRun makes generator:stop print the operations it would perform
+	// (tags, checkouts, commits, the merge, files restored from the start
+	// tag, branch deletion) and return without changing the repository
+	// (GH-521). Default false.
+	DryRun bool `yaml:"dry_run"`
+
+	// BaseBranch retargets generator:stop: when set, the generation merges
+	// into this branch instead of the base recorded at generator:start or
+	// the caller's branch. Use it when the recorded base was deleted or
+	// renamed during the generation (GH-562).
+	BaseBranch string `yaml:"base_branch"`
 }
 
 // CobblerConfig holds settings for the measure and stitch workflows.
@@ -132,7 +236,8 @@ type CobblerConfig struct {
 	IssuesRepo string `yaml:"issues_repo"`
 
 	// MaxStitchIssues is the total maximum number of stitch iterations for
-	// an entire run (default 0, meaning unlimited).
+	// an entire run (default 0, meaning unlimited). generator:resume counts
+	// the tasks the interrupted run already stitched (GH-549).
 	MaxStitchIssues int `yaml:"max_stitch_issues"`
 
 	// MaxStitchIssuesPerCycle is the maximum number of tasks stitch
@@ -146,6 +251,14 @@ type CobblerConfig struct {
 	// UserPrompt provides additional context for the measure prompt.
 	UserPrompt string `yaml:"user_prompt"`
 
+	// FocusArea directs a measure pass at one part of the codebase: a
+	// directory or file path, optionally followed by a parenthetical note,
+	// e.g. "pkg/orchestrator/context.go (context budgeting)". Measure adds
+	// it as a hard constraint and narrows the source code in the project
+	// context to files under that path. Composes with MaxMeasureIssues
+	// (GH-559).
+	FocusArea string `yaml:"focus_area"`
+
 	// MeasurePrompt is a file path to a custom measure prompt template.
 	// During LoadConfig the file is read and its content stored here.
 	// If empty, the embedded default is used.
@@ -191,29 +304,109 @@ type CobblerConfig struct {
 	GoldenExample string `yaml:"golden_example"`
 
 	// MaxContextBytes is the maximum serialized size (in bytes) of the
-	// ProjectContext injected into the stitch prompt. When the context
-	// exceeds this budget, non-required source files are progressively
-	// removed. Recommended value: 200000 (~50K tokens at 4 bytes/token).
+	// ProjectContext injected into the measure and stitch prompts. When
+	// the context exceeds this budget, non-required source files are
+	// progressively removed. Recommended value: 200000 (~50K tokens at 4 bytes/token).
 	// When 0 (the default), budget enforcement is skipped.
 	MaxContextBytes int `yaml:"max_context_bytes"`
 
+	// MaxContextTokens is the context budget in estimated tokens
+	// (serialized characters divided by ContextCharsPerToken). When set it
+	// replaces MaxContextBytes: non-required source files are removed
+	// until the estimate fits. When 0 (the default), MaxContextBytes
+	// applies (GH-502).
+	MaxContextTokens int `yaml:"max_context_tokens"`
+
+	// ContextCharsPerToken is the divisor used to estimate tokens from
+	// characters for MaxContextTokens. Default 4.
+	ContextCharsPerToken float64 `yaml:"context_chars_per_token"`
+
+	// ContextBudgetShedDocs lets the context budget remove extra
+	// and then engineering docs, last loaded first, once no removable
+	// source files remain. Docs named in the task's required_reading are
+	// never removed. Default false (GH-503).
+	ContextBudgetShedDocs bool `yaml:"context_budget_shed_docs"`
+
 	// EnforceMeasureValidation enables strict validation of measure output.
 	// When true, issues that violate P9 granularity ranges or P7 file naming
 	// are rejected and measure retries. When false (default), violations are
 	// logged as advisory warnings and import proceeds.
 	EnforceMeasureValidation bool `yaml:"enforce_measure_validation"`
 
+	// EnforceIssueSchema rejects measure output whose descriptions break
+	// the issue-format constitution's field_specs: wrong field types,
+	// values outside an enum such as deliverable_type, or missing required
+	// fields and sub-fields. Measure then retries as for other validation
+	// errors. When false (default), violations are logged as warnings.
+	// Stitch always logs them when it picks a task (GH-556).
+	EnforceIssueSchema bool `yaml:"enforce_issue_schema"`
+
 	// MaxMeasureRetries is the maximum number of retry attempts per iteration
 	// when EnforceMeasureValidation rejects the output. When 0 (default),
 	// no retries are attempted. A value of 2-3 is recommended.
 	MaxMeasureRetries int `yaml:"max_measure_retries"`
 
+	// MaxMeasureRetriesTotal caps the retries summed across all iterations
+	// of one measure run. Once exhausted, remaining iterations get no
+	// retries and accept their first result with warnings. Bounds the cost
+	// of a pathologically flaky run while MaxMeasureRetries stays generous
+	// (GH-470). When 0 (default), the total is unlimited.
+	MaxMeasureRetriesTotal int `yaml:"max_measure_retries_total"`
+
+	// MeasureRetryBackoff is the delay before a measure retry, as a Go
+	// duration string (e.g. "5s"). It doubles with each retry of the same
+	// iteration and gets up to 50% random jitter, so projects measuring
+	// concurrently do not retry in lockstep. The first attempt is never
+	// delayed. Empty (default) retries immediately.
+	MeasureRetryBackoff string `yaml:"measure_retry_backoff"`
+
+	// MaxMeasureCostUSD caps the Claude cost of one measure iteration,
+	// summed over its attempts. Once reached, the iteration stops retrying
+	// and accepts its last result with warnings, bounding the spend on a
+	// stubborn issue (GH-547). When 0 (default), there is no cap.
+	MaxMeasureCostUSD float64 `yaml:"max_measure_cost_usd"`
+
+	// MeasurePreviewNumber is a GitHub PR or issue number. When set, the
+	// issues created by each measure run are posted there as one comment
+	// with titles, dependency tree, and descriptions, so reviewers can see
+	// the plan in GitHub (GH-471). Posting is best-effort. 0 disables it.
+	MeasurePreviewNumber int `yaml:"measure_preview_number"`
+
+	// MeasurePreviewRepo is the owner/repo holding MeasurePreviewNumber.
+	// Defaults to the repo where cobbler issues are created.
+	MeasurePreviewRepo string `yaml:"measure_preview_repo"`
+
+	// IncludeIssueDescriptions adds each open issue's description, not
+	// just its title, to the measure context so the agent can judge scope
+	// overlap with existing work (GH-476). Adds tokens; default false.
+	IncludeIssueDescriptions bool `yaml:"include_issue_descriptions"`
+
+	// IssueDescriptionMaxBytes caps each included issue description.
+	// Longer descriptions are truncated. Default 2000.
+	IssueDescriptionMaxBytes int `yaml:"issue_description_max_bytes"`
+
 	// MaxRequirementsPerTask is the maximum number of requirements a single
 	// proposed task may contain. When exceeded the task is rejected and the
 	// measure agent is re-prompted to split it. When 0 (default), the limit
 	// is disabled and requirement count is governed only by P9 range rules.
 	MaxRequirementsPerTask int `yaml:"max_requirements_per_task"`
 
+	// GranularityRules sets the P9 ranges measure enforces on the
+	// requirement, acceptance criteria, and design decision counts of
+	// proposed code and documentation tasks. A range left unset keeps its
+	// default: code 5-8/5-8/3-5, documentation 2-4/3-5 with design
+	// decisions unchecked. MaxRequirementsPerTask still applies on top
+	// (GH-542).
+	GranularityRules GranularityRules `yaml:"granularity_rules"`
+
+	// MaxDescriptionBytes and MaxDescriptionLines cap the raw size of a
+	// proposed issue description. Oversized descriptions are reported as
+	// validation errors, so under EnforceMeasureValidation measure retries
+	// with a tighter plan; otherwise they are logged (GH-477). This catches
+	// prose bloat that the requirement-count checks miss. 0 disables a cap.
+	MaxDescriptionBytes int `yaml:"max_description_bytes"`
+	MaxDescriptionLines int `yaml:"max_description_lines"`
+
 	// MaxConsecutiveZeroLOCCycles is the number of consecutive stitch cycles
 	// that may produce zero LOC change before the generator stops with a
 	// warning. This prevents runaway refinement loops where measure keeps
@@ -225,6 +418,19 @@ type CobblerConfig struct {
 	// issues YAML, stream-json log) per iteration. Default "history".
 	HistoryDir string `yaml:"history_dir"`
 
+	// HistoryRetention bounds the history directory when
+	// cobbler:pruneHistory runs. Runs never prune on their own because the
+	// current generation's stats drive model escalation and reports
+	// (GH-537).
+	HistoryRetention HistoryRetentionConfig `yaml:"history_retention"`
+
+	// LOCIgnore lists glob patterns, relative to the repository root, of
+	// files and directories left out of LOC counts, e.g. "third_party" or
+	// "internal/mocks/*". A pattern matching a directory excludes the tree
+	// under it. Files with a "Code generated ... DO NOT EDIT." header are
+	// always excluded (GH-540).
+	LOCIgnore []string `yaml:"loc_ignore"`
+
 	// DocTagPrefix is the prefix used when creating documentation release
 	// tags (default "v0."). Tags are formed as <DocTagPrefix><YYYYMMDD>.<N>.
 	DocTagPrefix string `yaml:"doc_tag_prefix"`
@@ -265,6 +471,14 @@ type CobblerConfig struct {
 	// Default false; existing behaviour is preserved when false.
 	MeasureRoadmapSource bool `yaml:"measure_roadmap_source"`
 
+	// IncrementalSince makes measure favour source files changed since the
+	// generation's -start tag: they are treated as required, so unchanged
+	// files are dropped first by MaxSourceFiles and by the context budget
+	// (MaxContextTokens or MaxContextBytes). Spec documents are always
+	// included in full. Falls back to the full context when the start tag
+	// is missing. Default false (GH-564).
+	IncrementalSince bool `yaml:"incremental_since"`
+
 	// MeasureExcludeTests excludes *_test.go files from the measure prompt
 	// context. Test files are consumers of the API, not providers; the measure
 	// agent needs to know what is tested but not how tests are implemented.
@@ -286,6 +500,248 @@ type CobblerConfig struct {
 	// On non-zero exit or empty output, the full file content is used.
 	MeasureSummarizeCommand string `yaml:"measure_summarize_command"`
 
+	// StitchObservedConventions enables detection of emergent project
+	// conventions (error-wrapping style, logging helper, test layout) from
+	// a deterministic sample of Go source files. The resulting summary is
+	// added to the stitch prompt as observed_conventions so generated code
+	// matches house style. Default false because it adds prompt tokens
+	// (GH-458).
+	StitchObservedConventions bool `yaml:"stitch_observed_conventions"`
+
+	// MeasureStreamingImport makes importIssues create each proposed issue
+	// as soon as its YAML list item parses, instead of parsing the whole
+	// batch first. A malformed item no longer discards the issues before
+	// it; dependencies on items that failed to import are cleared in a
+	// deferred link pass. Default false (all-or-nothing batch import).
+	MeasureStreamingImport bool `yaml:"measure_streaming_import"`
+
+	// MeasureOutputOnly runs measure without GitHub: no repo detection,
+	// labels, placeholder, or issue creation. Each run writes its
+	// validated proposals to its own file, measure-output/<timestamp>.yaml
+	// in Cobbler.Dir, which is the sole output; later iterations of the
+	// run read it as their existing-issues list and deduplicate against
+	// it. Indices and dependencies are renumbered to stay unique across
+	// iterations. For planning a backlog to review in another tracker.
+	// Streaming import and the measure preview do not apply. Default
+	// false (GH-545).
+	MeasureOutputOnly bool `yaml:"measure_output_only"`
+
+	// MeasureMode selects what measure proposes. MeasureModeBuild (default)
+	// plans code and documentation work toward the roadmap.
+	// MeasureModeSpecMaintenance uses a separate prompt that compares the
+	// docs against the source and proposes documentation-only updates to
+	// existing files under docs/; proposals of any other deliverable type
+	// or path fail validation (GH-551).
+	MeasureMode string `yaml:"measure_mode"`
+
+	// PriorityByDepth gives each imported issue a cobbler_priority equal to
+	// one plus its depth in the batch's dependency graph, so roots get 1.
+	// Stitch picks ready issues by priority before issue number, building
+	// foundational tasks before the features that use them. Default false
+	// (GH-546).
+	PriorityByDepth bool `yaml:"priority_by_depth"`
+
+	// DuplicateTitleThreshold is the title similarity (0-1, the Dice
+	// coefficient of normalized title words) at or above which importIssues
+	// skips a proposed issue as a duplicate of an open issue or of an
+	// earlier issue in the same batch. Default 0.85; negative disables the
+	// check (GH-543).
+	DuplicateTitleThreshold float64 `yaml:"duplicate_title_threshold"`
+
+	// DeferMerge makes stitch commit each task on its branch without
+	// merging it into the generation branch. The branch is renamed to
+	// review/<base>-<id>, its worktree is kept, and the issue is labelled
+	// cobbler-review instead of being closed. Use cobbler:review to list
+	// pending tasks and cobbler:accept to merge one (GH-465). Default false.
+	DeferMerge bool `yaml:"defer_merge"`
+
+	// SquashMerge makes stitch merge each task branch with git merge
+	// --squash and record it as a single "Task <id>: <title>" commit
+	// carrying the task's outcome trailers, instead of a merge commit on
+	// top of the worktree commit. Keeps the generation branch linear and
+	// bisectable (GH-518). Has no effect with DeferMerge. Default false.
+	SquashMerge bool `yaml:"squash_merge"`
+
+	// CommitMessageTemplate is a Go text/template for the commit message of
+	// each stitch task, rendered with CommitMessageData; for example
+	// "feat({{.IssueType}}): {{.Title}}". Empty, or a template that fails
+	// to parse or execute, falls back to "Task <id>: <title>" (GH-519).
+	CommitMessageTemplate string `yaml:"commit_message_template"`
+
+	// StitchEscalationModel is the Claude model (passed as --model) used
+	// for a stitch task once it has failed StitchEscalationAfter times.
+	// Earlier attempts run on the default model, so the more expensive
+	// model is only paid for on tasks that have proven hard (GH-466).
+	// Empty disables escalation.
+	StitchEscalationModel string `yaml:"stitch_escalation_model"`
+
+	// StitchEscalationAfter is the number of failed stitch attempts on a
+	// task, counted from the history directory, after which the task is
+	// retried with StitchEscalationModel. Only Claude and verify failures
+	// count. Default 1.
+	StitchEscalationAfter int `yaml:"stitch_escalation_after"`
+
+	// StitchDeniedCommands lists shell command prefixes (e.g. "curl",
+	// "rm -rf") that stitch sessions may not run. Each entry is passed to
+	// Claude as a --disallowedTools Bash(<cmd>:*) rule; as a second line of
+	// defense, runClaude scans Bash tool calls in the output stream and
+	// aborts the session on the first match, recording the command in the
+	// failure (GH-467). Empty means no restrictions.
+	StitchDeniedCommands []string `yaml:"stitch_denied_commands"`
+
+	// DoneChecks maps a task's deliverable_type to a definition-of-done
+	// check that must pass before the task is merged: DoneCheckBuildTest
+	// ("build_test"), DoneCheckDocsYAML ("docs_yaml"), or DoneCheckNone.
+	// A failing check resets the task like any other stitch failure, and
+	// the outcome is recorded in the history stats (GH-474). Types without
+	// an entry are not checked; LoadConfig rejects unknown check names.
+	DoneChecks map[string]string `yaml:"done_checks"`
+
+	// WorktreeSetup maps a task's deliverable_type to worktree options
+	// (GH-478). With sparse enabled, the task worktree checks out only the
+	// directories of the task's files plus Paths, which speeds worktree
+	// creation on large repos (e.g. documentation tasks that need only
+	// docs/). Types without an entry get a full worktree.
+	WorktreeSetup map[string]WorktreeOptions `yaml:"worktree_setup"`
+
+	// ExcludeReleases lists releases whose use cases and test suites are
+	// dropped from the project context regardless of Project.Releases or
+	// Project.Release (GH-480), e.g. an abandoned "01.5" between "01.0"
+	// and "02.0". PRD loading is unaffected unless a release set or
+	// ceiling is also configured.
+	ExcludeReleases []string `yaml:"exclude_releases"`
+
+	// MeasureSettleMode controls how the iterative measure loop waits for
+	// just-created issues to become visible before the next iteration
+	// refreshes the issue list (GH-481): MeasureSettleDelay (default)
+	// sleeps MeasureSettleSeconds after the issue-count wait importIssues
+	// already performs, since the search index can lag the count;
+	// MeasureSettleCount relies on that count wait alone;
+	// MeasureSettleConsistent re-queries every MeasureSettleSeconds until
+	// each created issue is listed by number or MeasureSettleTimeoutSeconds
+	// elapses, for eventually-consistent trackers.
+	MeasureSettleMode string `yaml:"measure_settle_mode"`
+
+	// MeasureSettleSeconds is the settle delay, or the poll interval in
+	// consistent mode. Default 2. A negative value disables settling.
+	MeasureSettleSeconds int `yaml:"measure_settle_seconds"`
+
+	// MeasureSettleTimeoutSeconds bounds the consistent-mode wait.
+	// Default 30.
+	MeasureSettleTimeoutSeconds int `yaml:"measure_settle_timeout_seconds"`
+
+	// MaxWorktrees caps the number of stitch worktree directories that may
+	// exist under the worktree base path. Before creating a task worktree
+	// the existing ones are counted, and stitch refuses to proceed when
+	// the cap is reached so leaked worktrees are cleaned up before they
+	// fill the disk (GH-483). Worktrees awaiting review under DeferMerge
+	// are not counted. When 0 (default), the count is only logged.
+	MaxWorktrees int `yaml:"max_worktrees"`
+
+	// KeepFailedWorktrees is a debugging aid: when true, a failed stitch
+	// task's worktree and branch are kept for inspection instead of being
+	// removed. They are moved to <worktree base>/failed/<id>-<timestamp>
+	// on branch failed/<generation>-<id>-<timestamp> so the task can be
+	// retried; the issue is still reset to ready. Kept worktrees older
+	// than KeepFailedWorktreesMaxAge are pruned at the start of each
+	// stitch. Do not enable it in unattended runs. Default false (GH-516).
+	KeepFailedWorktrees bool `yaml:"keep_failed_worktrees"`
+
+	// WorktreeDir is the parent directory for stitch worktrees. Worktrees
+	// go in a per-repo "<repo>-worktrees" subdirectory of it, so several
+	// repositories can share one WorktreeDir. Set it when the temp
+	// filesystem is small or cleaned mid-run. A relative path is resolved
+	// against the current working directory (the directory mage runs in),
+	// not the repository root. Default "" uses os.TempDir() (GH-552).
+	WorktreeDir string `yaml:"worktree_dir"`
+
+	// KeepFailedWorktreesMaxAge is how long kept failed worktrees survive,
+	// as a Go duration string. Default "72h" (GH-516).
+	KeepFailedWorktreesMaxAge string `yaml:"keep_failed_worktrees_max_age"`
+
+	// IncludeBlame adds a git blame summary to each required_reading source
+	// file in the stitch prompt: line ranges bucketed as recent (<30 days),
+	// active (<180 days), or stable, so the agent can avoid churning
+	// long-stable code (GH-487). Costs tokens; default false.
+	IncludeBlame bool `yaml:"include_blame"`
+
+	// BlameMaxLines skips the blame summary for required files with more
+	// lines than this. Default 800.
+	BlameMaxLines int `yaml:"blame_max_lines"`
+
+	// StrictRequiredReading resets a task to ready instead of stitching it
+	// when a required_reading source path does not exist in the worktree.
+	// Missing paths are always logged and counted in history stats
+	// (GH-541). Default false.
+	StrictRequiredReading bool `yaml:"strict_required_reading"`
+
+	// MinTaskLines is the lower bound on a proposed task's estimated_lines.
+	// Before import, consecutive tasks that both fall below it, share a
+	// deliverable type, and touch the same directory are merged into one
+	// task with the combined requirements and acceptance criteria, saving
+	// a stitch invocation per fragment (GH-488). Not applied with
+	// MeasureStreamingImport. When 0 (default), no tasks are merged.
+	MinTaskLines int `yaml:"min_task_lines"`
+
+	// RequirementsTrailer is the git trailer key used to record the
+	// requirement IDs a stitch commit covers, e.g.
+	// "Requirements: prd003 R2.1, prd003 R2.2", so commits can be found
+	// with git log --grep by requirement (GH-489). Default "Requirements";
+	// set to "none" to omit the trailer.
+	RequirementsTrailer string `yaml:"requirements_trailer"`
+
+	// TrackingIssueOnFailure controls what happens to the measuring
+	// placeholder issue when a measure iteration fails before completing
+	// (GH-492): TrackingIssueClose (default) comments and closes it,
+	// TrackingIssueKeepOpen comments and leaves it open, and
+	// TrackingIssueMarkFailed also adds the cobbler-measure-failed label so
+	// failures stay visible on the board until acknowledged. Successful
+	// iterations close or upgrade the placeholder regardless. LoadConfig
+	// rejects other values.
+	TrackingIssueOnFailure string `yaml:"tracking_issue_on_failure"`
+
+	// StitchBuildPlan adds a build_plan section to the stitch prompt
+	// describing how many open tasks depend on the task, directly and
+	// transitively, and their titles, so foundations get extensible
+	// interfaces and leaves stay minimal. Default false because it adds
+	// prompt tokens and a GitHub query per task (GH-493).
+	StitchBuildPlan bool `yaml:"stitch_build_plan"`
+
+	// StitchParseCheck parses every new or modified .go file in the task
+	// worktree with go/parser before the worktree is committed, and resets
+	// the task on a syntax error so invalid code never reaches the
+	// generation branch. Default true; a pointer so nil (absent) is treated
+	// as true and an explicit false opts out (GH-494).
+	StitchParseCheck *bool `yaml:"stitch_parse_check"`
+
+	// FormatOnStitch formats the new and modified .go files in the task
+	// worktree after Claude finishes and before the worktree is verified
+	// and committed, so parallel tasks do not conflict over import order
+	// or whitespace. Runs gofmt -w, or goimports -w when
+	// FormatWithGoimports is set; when the formatter is not installed the
+	// step is skipped. Default false.
+	FormatOnStitch bool `yaml:"format_on_stitch"`
+
+	// FormatWithGoimports makes FormatOnStitch run goimports instead of
+	// gofmt, which also adds and removes imports. Falls back to gofmt when
+	// goimports is not on PATH. Default false (GH-567).
+	FormatWithGoimports bool `yaml:"format_with_goimports"`
+
+	// VerifyBuild runs go build ./... in the task worktree after Claude
+	// finishes and before the worktree is committed and merged; a failure
+	// resets the task so broken code never lands on the generation branch.
+	// VerifyTests does the same with go test ./.... Both default false
+	// (GH-512). Unlike DoneChecks, they apply to every deliverable type.
+	VerifyBuild bool `yaml:"verify_build"`
+	VerifyTests bool `yaml:"verify_tests"`
+
+	// MaxStitchRepairs is how many times a task that fails the
+	// VerifyBuild/VerifyTests gate is sent back to Claude, in the same
+	// worktree, with the failure output before the task is reset. Each
+	// repair is recorded in history under the stitch-repair phase.
+	// Default 0 (no repairs) (GH-513).
+	MaxStitchRepairs int `yaml:"max_stitch_repairs"`
+
 	// Mode selects the Claude execution backend. Valid values are
 	// ExecutionModePodman (default, run Claude inside a podman container),
 	// ExecutionModeCLI (run the claude binary directly on the host), and
@@ -295,6 +751,26 @@ type CobblerConfig struct {
 	Mode string `yaml:"mode"`
 }
 
+// Measure mode constants for CobblerConfig.MeasureMode.
+const (
+	// MeasureModeBuild plans implementation work (default).
+	MeasureModeBuild = "build"
+
+	// MeasureModeSpecMaintenance proposes documentation-only updates that
+	// bring existing specification documents in line with the code.
+	MeasureModeSpecMaintenance = "spec-maintenance"
+)
+
+// Source order constants for ProjectConfig.SourceOrder.
+const (
+	// SourceOrderPath sorts project context source files by path (default).
+	SourceOrderPath = "path"
+
+	// SourceOrderDependency groups source files by package and orders
+	// packages so that imported ones come before their importers.
+	SourceOrderDependency = "dependency"
+)
+
 // Execution mode constants for CobblerConfig.Mode.
 const (
 	// ExecutionModePodman runs Claude inside a podman container (default).
@@ -312,6 +788,35 @@ const (
 	ExecutionModeSDK = "sdk"
 )
 
+// Settle mode constants for CobblerConfig.MeasureSettleMode.
+const (
+	// MeasureSettleDelay sleeps a short fixed delay between measure
+	// iterations.
+	MeasureSettleDelay = "delay"
+
+	// MeasureSettleCount adds no wait of its own: importIssues already
+	// polls until the number of open issues covers those it created.
+	MeasureSettleCount = "count"
+
+	// MeasureSettleConsistent re-queries the issue list until every issue
+	// created by the iteration appears.
+	MeasureSettleConsistent = "consistent"
+)
+
+// Failure policies for CobblerConfig.TrackingIssueOnFailure.
+const (
+	// TrackingIssueClose closes the placeholder with a failure comment.
+	TrackingIssueClose = "close"
+
+	// TrackingIssueKeepOpen leaves the placeholder open with a failure
+	// comment.
+	TrackingIssueKeepOpen = "open"
+
+	// TrackingIssueMarkFailed leaves the placeholder open, comments, and
+	// labels it cobbler-measure-failed.
+	TrackingIssueMarkFailed = "failed"
+)
+
 // effectiveMode returns the execution mode, defaulting to ExecutionModePodman
 // when Mode is empty or unrecognised.
 func (c *CobblerConfig) effectiveMode() string {
@@ -325,6 +830,156 @@ func (c *CobblerConfig) effectiveMode() string {
 	}
 }
 
+// HistoryRetentionConfig limits how many invocations the history directory
+// keeps. An invocation is the group of files sharing one timestamp prefix
+// (prompt, log, stats, report). See CobblerConfig.HistoryRetention.
+type HistoryRetentionConfig struct {
+	// MaxAge removes invocations older than this Go duration string
+	// (e.g. "720h"). Empty means no age limit.
+	MaxAge string `yaml:"max_age"`
+
+	// KeepN keeps only the newest N invocations. 0 means no count limit.
+	KeepN int `yaml:"keep_n"`
+}
+
+// GranularityRules holds the P9 count ranges for each deliverable type.
+// See CobblerConfig.GranularityRules.
+type GranularityRules struct {
+	Code          DeliverableGranularity `yaml:"code"`
+	Documentation DeliverableGranularity `yaml:"documentation"`
+}
+
+// DeliverableGranularity holds the allowed count range for each list in
+// a task description.
+type DeliverableGranularity struct {
+	Requirements       CountRange `yaml:"requirements"`
+	AcceptanceCriteria CountRange `yaml:"acceptance_criteria"`
+	DesignDecisions    CountRange `yaml:"design_decisions"`
+}
+
+// CountRange is an inclusive [Min, Max] bound. Max 0 means no upper
+// bound; the zero CountRange checks nothing.
+type CountRange struct {
+	Min int `yaml:"min"`
+	Max int `yaml:"max"`
+}
+
+// isZero reports whether r is unset.
+func (r CountRange) isZero() bool { return r.Min == 0 && r.Max == 0 }
+
+// contains reports whether n lies within r.
+func (r CountRange) contains(n int) bool {
+	return n >= r.Min && (r.Max == 0 || n <= r.Max)
+}
+
+// String formats r as "min-max", or "min+" without an upper bound.
+func (r CountRange) String() string {
+	if r.Max == 0 {
+		return fmt.Sprintf("%d+", r.Min)
+	}
+	return fmt.Sprintf("%d-%d", r.Min, r.Max)
+}
+
+// defaultGranularityRules returns the built-in P9 ranges.
+func defaultGranularityRules() GranularityRules {
+	return GranularityRules{
+		Code: DeliverableGranularity{
+			Requirements:       CountRange{Min: 5, Max: 8},
+			AcceptanceCriteria: CountRange{Min: 5, Max: 8},
+			DesignDecisions:    CountRange{Min: 3, Max: 5},
+		},
+		Documentation: DeliverableGranularity{
+			Requirements:       CountRange{Min: 2, Max: 4},
+			AcceptanceCriteria: CountRange{Min: 3, Max: 5},
+		},
+	}
+}
+
+// withDefaults fills each unset range of g from def.
+func (g DeliverableGranularity) withDefaults(def DeliverableGranularity) DeliverableGranularity {
+	if g.Requirements.isZero() {
+		g.Requirements = def.Requirements
+	}
+	if g.AcceptanceCriteria.isZero() {
+		g.AcceptanceCriteria = def.AcceptanceCriteria
+	}
+	if g.DesignDecisions.isZero() {
+		g.DesignDecisions = def.DesignDecisions
+	}
+	return g
+}
+
+// validate reports a range whose Min exceeds its Max.
+func (g DeliverableGranularity) validate(kind string) error {
+	fields := []struct {
+		name string
+		r    CountRange
+	}{
+		{"requirements", g.Requirements},
+		{"acceptance_criteria", g.AcceptanceCriteria},
+		{"design_decisions", g.DesignDecisions},
+	}
+	for _, f := range fields {
+		if f.r.Min < 0 || f.r.Max < 0 || (f.r.Max > 0 && f.r.Min > f.r.Max) {
+			return fmt.Errorf("cobbler.granularity_rules.%s.%s: invalid range min=%d max=%d", kind, f.name, f.r.Min, f.r.Max)
+		}
+	}
+	return nil
+}
+
+// targets lists the set ranges of g as prompt text, e.g. "5-8
+// requirements and 3-5 acceptance criteria". Unset ranges are omitted.
+func (g DeliverableGranularity) targets() string {
+	var parts []string
+	for _, f := range []struct {
+		name string
+		r    CountRange
+	}{
+		{"requirements", g.Requirements},
+		{"acceptance criteria", g.AcceptanceCriteria},
+		{"design decisions", g.DesignDecisions},
+	} {
+		if !f.r.isZero() {
+			parts = append(parts, f.r.String()+" "+f.name)
+		}
+	}
+	switch len(parts) {
+	case 0:
+		return ""
+	case 1:
+		return parts[0]
+	case 2:
+		return parts[0] + " and " + parts[1]
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + ", and " + parts[len(parts)-1]
+}
+
+// p9Targets renders r as the sentences substituted for
+// {granularity_targets} in the planning constitution, so the P9 rule
+// Claude reads matches the ranges validateMeasureOutput enforces.
+func (r GranularityRules) p9Targets() string {
+	var sentences []string
+	if t := r.Code.targets(); t != "" {
+		sentences = append(sentences, "Code tasks target "+t+".")
+	}
+	if t := r.Documentation.targets(); t != "" {
+		sentences = append(sentences, "Documentation tasks target "+t+".")
+	}
+	return strings.Join(sentences, " ")
+}
+
+// WorktreeOptions configures how the stitch worktree for one deliverable
+// type is created. See CobblerConfig.WorktreeSetup.
+type WorktreeOptions struct {
+	// Sparse enables a cone-mode sparse checkout limited to the task's
+	// file directories and Paths.
+	Sparse bool `yaml:"sparse"`
+
+	// Paths are extra directories always included in a sparse checkout
+	// (e.g. "docs", "magefiles").
+	Paths []string `yaml:"paths"`
+}
+
 // PodmanConfig holds settings for the podman container runtime.
 type PodmanConfig struct {
 	// Image is the container image for Claude execution (default "claude-cli").
@@ -333,6 +988,16 @@ type PodmanConfig struct {
 
 	// Args are additional arguments passed to podman run before the image name.
 	Args []string `yaml:"args"`
+
+	// Runtime is the container runtime binary used to build images and run
+	// Claude: ContainerRuntimePodman (default) or ContainerRuntimeDocker.
+	// The run flags are the same for both (GH-530).
+	Runtime string `yaml:"runtime"`
+
+	// PrewarmRun makes Prewarm start a throwaway container (claude
+	// --version) after the image and credentials are ready, so the first
+	// stitch task does not pay container cold-start latency. Default false.
+	PrewarmRun bool `yaml:"prewarm_run"`
 }
 
 // ClaudeConfig holds settings for the Claude CLI.
@@ -359,6 +1024,20 @@ type ClaudeConfig struct {
 	// process is killed and the task is reset to ready in the issue tracker.
 	MaxTimeSec int `yaml:"max_time_sec"`
 
+	// MaxTaskTimeSec caps the timeout_sec a stitch task description may
+	// request in place of MaxTimeSec, so a bad estimate cannot hang the
+	// pipeline (default 3600). Tasks without timeout_sec use MaxTimeSec
+	// (GH-517).
+	MaxTaskTimeSec int `yaml:"max_task_time_sec"`
+
+	// TokenEnvVar names an environment variable holding a Claude OAuth
+	// token, for hosts without a credential file or secret store such as
+	// Linux CI (default "CLAUDE_CODE_OAUTH_TOKEN"). Credentials are taken
+	// from, in order: the credential file in SecretsDir, this variable
+	// (passed into the container with -e, inherited in cli and sdk
+	// modes), and the platform secret store.
+	TokenEnvVar string `yaml:"token_env_var"`
+
 	// ContainerCredentialsPath is the absolute path inside the container
 	// where the Claude CLI expects its credentials file.
 	// Default: /home/crumbs/.claude/.credentials.json
@@ -373,6 +1052,84 @@ type ClaudeConfig struct {
 	// value, the orchestrator logs a warning that the parameter cannot be
 	// passed through to the CLI.
 	Temperature float64 `yaml:"temperature"`
+
+	// MeasureModel is the model passed as --model to measure invocations,
+	// e.g. a cheaper model than stitch uses. Empty passes no --model and
+	// leaves the choice to the Claude CLI.
+	MeasureModel string `yaml:"measure_model"`
+
+	// StitchModel is the model passed as --model to stitch invocations.
+	// Cobbler.StitchEscalationModel replaces it once a task is escalated.
+	// Empty passes no --model.
+	StitchModel string `yaml:"stitch_model"`
+
+	// MaxRateLimitRetries is how many times runClaude re-invokes Claude
+	// after a run ends on a rate-limit or overloaded error, with
+	// exponential backoff and jitter between attempts. Token usage is
+	// summed across attempts. Default 0 (no retries) (GH-531).
+	MaxRateLimitRetries int `yaml:"max_rate_limit_retries"`
+
+	// Backend selects how Claude is invoked. ClaudeBackendPodman (default)
+	// runs the claude CLI as chosen by Cobbler.Mode; ClaudeBackendAPI calls
+	// the Anthropic Messages API over HTTP, for CI runners without podman
+	// or the claude binary (GH-529).
+	Backend string `yaml:"backend"`
+
+	// APIKey authenticates requests made by the api backend.
+	APIKey string `yaml:"api_key"`
+
+	// APIURL is the Messages API endpoint used by the api backend
+	// (default "https://api.anthropic.com/v1/messages").
+	APIURL string `yaml:"api_url"`
+
+	// APIModel is the model requested by the api backend when no --model
+	// argument is passed (default tokenCountModel).
+	APIModel string `yaml:"api_model"`
+
+	// APIMaxTokens caps the response length of an api backend request
+	// (default 8192).
+	APIMaxTokens int `yaml:"api_max_tokens"`
+}
+
+// Claude backend constants for ClaudeConfig.Backend.
+const (
+	// ClaudeBackendPodman runs the claude CLI as a subprocess (default).
+	// Cobbler.Mode decides whether it runs in a podman container, on the
+	// host, or through the Go Agent SDK.
+	ClaudeBackendPodman = "podman"
+
+	// ClaudeBackendAPI sends the prompt to the Anthropic Messages API.
+	// There is no tool use, so it suits single-turn phases such as measure;
+	// stitch refuses to run with it.
+	ClaudeBackendAPI = "api"
+)
+
+// Container runtime constants for PodmanConfig.Runtime.
+const (
+	// ContainerRuntimePodman runs containers with podman (default).
+	ContainerRuntimePodman = "podman"
+
+	// ContainerRuntimeDocker runs containers with docker.
+	ContainerRuntimeDocker = "docker"
+)
+
+// runtimeBin returns the container runtime binary, defaulting to podman
+// when Runtime is empty. LoadConfig rejects unrecognised runtimes.
+func (c *PodmanConfig) runtimeBin() string {
+	if c.Runtime == ContainerRuntimeDocker {
+		return binDocker
+	}
+	return binPodman
+}
+
+// effectiveBackend returns the Claude backend, defaulting to
+// ClaudeBackendPodman when Backend is empty. LoadConfig rejects
+// unrecognised values.
+func (c *ClaudeConfig) effectiveBackend() string {
+	if c.Backend == ClaudeBackendAPI {
+		return ClaudeBackendAPI
+	}
+	return ClaudeBackendPodman
 }
 
 // Config holds all orchestrator settings. Consuming repos either
@@ -399,6 +1156,33 @@ func (c *CobblerConfig) effectiveMeasureExcludeTests() bool {
 	return *c.MeasureExcludeTests
 }
 
+// effectiveRespectGitignore returns whether gitignored files are skipped
+// when loading source. Nil defaults to true.
+func (p *ProjectConfig) effectiveRespectGitignore() bool {
+	if p.RespectGitignore == nil {
+		return true
+	}
+	return *p.RespectGitignore
+}
+
+// effectiveStitchParseCheck returns whether changed .go files are parsed
+// before the worktree commit. Nil defaults to true.
+func (c *CobblerConfig) effectiveStitchParseCheck() bool {
+	if c.StitchParseCheck == nil {
+		return true
+	}
+	return *c.StitchParseCheck
+}
+
+// issueDescriptionLimit returns the per-issue description byte limit for
+// the measure context, or 0 when IncludeIssueDescriptions is off.
+func (c *CobblerConfig) issueDescriptionLimit() int {
+	if !c.IncludeIssueDescriptions {
+		return 0
+	}
+	return c.IssueDescriptionMaxBytes
+}
+
 // DefaultConfig returns a Config populated with all default values.
 // Project-specific fields (ModulePath, BinaryName, etc.) are left empty;
 // the caller fills them in or the user edits the generated file.
@@ -435,6 +1219,26 @@ type SeedData struct {
 	ModulePath string
 }
 
+// CommitMessageData is the template data passed to
+// Cobbler.CommitMessageTemplate.
+type CommitMessageData struct {
+	ID        string
+	Title     string
+	IssueType string
+}
+
+// versionFiles returns VersionFile followed by the VersionFiles entries,
+// skipping empty paths and duplicates.
+func (c *ProjectConfig) versionFiles() []string {
+	var files []string
+	for _, f := range append([]string{c.VersionFile}, c.VersionFiles...) {
+		if f != "" && !slices.Contains(files, f) {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
 // Silence returns true when Claude output should be suppressed.
 // Handles the nil-pointer case for the default (true).
 func (c *Config) Silence() bool {
@@ -453,6 +1257,49 @@ func (c *Config) EffectiveTokenFile() string {
 	return c.Claude.DefaultTokenFile
 }
 
+// GenerationMaxDuration returns Generation.MaxDuration parsed as a
+// Duration, or 0 when it is empty, invalid, or not positive. LoadConfig
+// rejects such values, so 0 only means "no cap" for loaded configurations.
+func (c *Config) GenerationMaxDuration() time.Duration {
+	d, err := time.ParseDuration(c.Generation.MaxDuration)
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return d
+}
+
+// KeptWorktreeMaxAge returns Cobbler.KeepFailedWorktreesMaxAge parsed as
+// a duration, or 72h when it is empty, invalid, or not positive.
+func (c *Config) KeptWorktreeMaxAge() time.Duration {
+	d, err := time.ParseDuration(c.Cobbler.KeepFailedWorktreesMaxAge)
+	if err != nil || d <= 0 {
+		return 72 * time.Hour
+	}
+	return d
+}
+
+// MeasureRetryBackoffBase returns Cobbler.MeasureRetryBackoff parsed as a
+// duration, or 0 (retry immediately) when it is empty, invalid, or not
+// positive.
+func (c *Config) MeasureRetryBackoffBase() time.Duration {
+	d, err := time.ParseDuration(c.Cobbler.MeasureRetryBackoff)
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return d
+}
+
+// HistoryRetentionMaxAge returns Cobbler.HistoryRetention.MaxAge parsed as
+// a duration, or 0 (no age limit) when it is empty, invalid, or not
+// positive.
+func (c *Config) HistoryRetentionMaxAge() time.Duration {
+	d, err := time.ParseDuration(c.Cobbler.HistoryRetention.MaxAge)
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return d
+}
+
 // ClaudeTimeout returns the max Claude invocation time as a Duration.
 func (c *Config) ClaudeTimeout() time.Duration {
 	return time.Duration(c.Claude.MaxTimeSec) * time.Second
@@ -476,6 +1323,9 @@ func (c *Config) applyDefaults() {
 	if c.Project.BinaryDir == "" {
 		c.Project.BinaryDir = "bin"
 	}
+	if len(c.Project.SourceExtensions) == 0 {
+		c.Project.SourceExtensions = defaultSourceExtensions
+	}
 	if c.Generation.Prefix == "" {
 		c.Generation.Prefix = "generation-"
 	}
@@ -518,12 +1368,60 @@ func (c *Config) applyDefaults() {
 	if c.Cobbler.IdleTimeoutSeconds == 0 {
 		c.Cobbler.IdleTimeoutSeconds = 60
 	}
+	if c.Cobbler.IssueDescriptionMaxBytes == 0 {
+		c.Cobbler.IssueDescriptionMaxBytes = 2000
+	}
+	if c.Cobbler.StitchEscalationAfter == 0 {
+		c.Cobbler.StitchEscalationAfter = 1
+	}
+	if c.Cobbler.MeasureSettleMode == "" {
+		c.Cobbler.MeasureSettleMode = MeasureSettleDelay
+	}
+	if c.Cobbler.DuplicateTitleThreshold == 0 {
+		c.Cobbler.DuplicateTitleThreshold = 0.85
+	}
+	if c.Cobbler.MeasureSettleSeconds == 0 {
+		c.Cobbler.MeasureSettleSeconds = 2
+	}
+	if c.Cobbler.MeasureSettleTimeoutSeconds == 0 {
+		c.Cobbler.MeasureSettleTimeoutSeconds = 30
+	}
+	def := defaultGranularityRules()
+	c.Cobbler.GranularityRules.Code = c.Cobbler.GranularityRules.Code.withDefaults(def.Code)
+	c.Cobbler.GranularityRules.Documentation = c.Cobbler.GranularityRules.Documentation.withDefaults(def.Documentation)
+	if c.Cobbler.BlameMaxLines == 0 {
+		c.Cobbler.BlameMaxLines = 800
+	}
+	if c.Cobbler.RequirementsTrailer == "" {
+		c.Cobbler.RequirementsTrailer = "Requirements"
+	}
+	if c.Cobbler.ContextCharsPerToken <= 0 {
+		c.Cobbler.ContextCharsPerToken = defaultCharsPerToken
+	}
+	if c.Cobbler.TrackingIssueOnFailure == "" {
+		c.Cobbler.TrackingIssueOnFailure = TrackingIssueClose
+	}
 	if c.Cobbler.MaxConsecutiveZeroLOCCycles == 0 {
 		c.Cobbler.MaxConsecutiveZeroLOCCycles = 3
 	}
 	if c.Claude.MaxTimeSec == 0 {
 		c.Claude.MaxTimeSec = 300
 	}
+	if c.Claude.MaxTaskTimeSec == 0 {
+		c.Claude.MaxTaskTimeSec = 3600
+	}
+	if c.Claude.APIURL == "" {
+		c.Claude.APIURL = "https://api.anthropic.com/v1/messages"
+	}
+	if c.Claude.APIModel == "" {
+		c.Claude.APIModel = tokenCountModel
+	}
+	if c.Claude.APIMaxTokens == 0 {
+		c.Claude.APIMaxTokens = 8192
+	}
+	if c.Claude.TokenEnvVar == "" {
+		c.Claude.TokenEnvVar = "CLAUDE_CODE_OAUTH_TOKEN"
+	}
 	if c.Claude.ContainerCredentialsPath == "" {
 		c.Claude.ContainerCredentialsPath = "/home/crumbs/.claude/.credentials.json"
 	}
@@ -547,6 +1445,73 @@ func LoadConfig(path string) (Config, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return Config{}, fmt.Errorf("parsing config file: %w", err)
 	}
+	if cfg.Generation.MaxDuration != "" {
+		d, err := time.ParseDuration(cfg.Generation.MaxDuration)
+		if err != nil {
+			return Config{}, fmt.Errorf("parsing generation.max_duration: %w", err)
+		}
+		if d <= 0 {
+			return Config{}, fmt.Errorf("parsing generation.max_duration: %q is not positive (leave it empty for no cap)",
+				cfg.Generation.MaxDuration)
+		}
+	}
+	if cfg.Cobbler.KeepFailedWorktreesMaxAge != "" {
+		if _, err := time.ParseDuration(cfg.Cobbler.KeepFailedWorktreesMaxAge); err != nil {
+			return Config{}, fmt.Errorf("parsing cobbler.keep_failed_worktrees_max_age: %w", err)
+		}
+	}
+	if cfg.Cobbler.MeasureRetryBackoff != "" {
+		if _, err := time.ParseDuration(cfg.Cobbler.MeasureRetryBackoff); err != nil {
+			return Config{}, fmt.Errorf("parsing cobbler.measure_retry_backoff: %w", err)
+		}
+	}
+	switch cfg.Project.SourceOrder {
+	case "", SourceOrderPath, SourceOrderDependency:
+	default:
+		return Config{}, fmt.Errorf("parsing project.source_order: unknown order %q (want %q or %q)",
+			cfg.Project.SourceOrder, SourceOrderPath, SourceOrderDependency)
+	}
+	switch cfg.Cobbler.MeasureMode {
+	case "", MeasureModeBuild, MeasureModeSpecMaintenance:
+	default:
+		return Config{}, fmt.Errorf("parsing cobbler.measure_mode: unknown mode %q (want %q or %q)",
+			cfg.Cobbler.MeasureMode, MeasureModeBuild, MeasureModeSpecMaintenance)
+	}
+	switch cfg.Claude.Backend {
+	case "", ClaudeBackendPodman, ClaudeBackendAPI:
+	default:
+		return Config{}, fmt.Errorf("parsing claude.backend: unknown backend %q (want %q or %q)",
+			cfg.Claude.Backend, ClaudeBackendPodman, ClaudeBackendAPI)
+	}
+	switch cfg.Cobbler.TrackingIssueOnFailure {
+	case "", TrackingIssueClose, TrackingIssueKeepOpen, TrackingIssueMarkFailed:
+	default:
+		return Config{}, fmt.Errorf("parsing cobbler.tracking_issue_on_failure: unknown policy %q (want %q, %q, or %q)",
+			cfg.Cobbler.TrackingIssueOnFailure, TrackingIssueClose, TrackingIssueKeepOpen, TrackingIssueMarkFailed)
+	}
+	switch cfg.Podman.Runtime {
+	case "", ContainerRuntimePodman, ContainerRuntimeDocker:
+	default:
+		return Config{}, fmt.Errorf("parsing podman.runtime: unknown runtime %q (want %q or %q)",
+			cfg.Podman.Runtime, ContainerRuntimePodman, ContainerRuntimeDocker)
+	}
+	for dtype, name := range cfg.Cobbler.DoneChecks {
+		if _, ok := doneCheckFuncs[name]; !ok {
+			return Config{}, fmt.Errorf("parsing cobbler.done_checks.%s: unknown check %q (want %q, %q, or %q)",
+				dtype, name, DoneCheckBuildTest, DoneCheckDocsYAML, DoneCheckNone)
+		}
+	}
+	if err := cfg.Cobbler.GranularityRules.Code.validate("code"); err != nil {
+		return Config{}, err
+	}
+	if err := cfg.Cobbler.GranularityRules.Documentation.validate("documentation"); err != nil {
+		return Config{}, err
+	}
+	if cfg.Cobbler.HistoryRetention.MaxAge != "" {
+		if _, err := time.ParseDuration(cfg.Cobbler.HistoryRetention.MaxAge); err != nil {
+			return Config{}, fmt.Errorf("parsing cobbler.history_retention.max_age: %w", err)
+		}
+	}
 
 	// Read seed file templates from disk.
 	for dest, src := range cfg.Project.SeedFiles {
@@ -6,6 +6,8 @@ package orchestrator
 import (
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -156,6 +158,53 @@ func TestLoadConfig_MissingConstitutionFile(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_GranularityRules(t *testing.T) {
+	f := writeTemp(t, "cobbler:\n  granularity_rules:\n    code:\n      requirements: {min: 3, max: 10}\n")
+	cfg, err := LoadConfig(f)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	def := defaultGranularityRules()
+	got := cfg.Cobbler.GranularityRules
+	if got.Code.Requirements != (CountRange{Min: 3, Max: 10}) {
+		t.Errorf("Code.Requirements = %+v, want {3 10}", got.Code.Requirements)
+	}
+	if got.Code.AcceptanceCriteria != def.Code.AcceptanceCriteria || got.Documentation != def.Documentation {
+		t.Errorf("unset ranges not defaulted: %+v", got)
+	}
+
+	bad := writeTemp(t, "cobbler:\n  granularity_rules:\n    documentation:\n      acceptance_criteria: {min: 6, max: 2}\n")
+	if _, err := LoadConfig(bad); err == nil || !strings.Contains(err.Error(), "documentation.acceptance_criteria") {
+		t.Errorf("LoadConfig with min > max = %v, want range error", err)
+	}
+}
+
+func TestLoadConfig_SourceOrder(t *testing.T) {
+	cfg, err := LoadConfig(writeTemp(t, "project:\n  source_order: dependency\n"))
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Project.SourceOrder != SourceOrderDependency {
+		t.Errorf("SourceOrder = %q, want %q", cfg.Project.SourceOrder, SourceOrderDependency)
+	}
+	if _, err := LoadConfig(writeTemp(t, "project:\n  source_order: alphabetical\n")); err == nil || !strings.Contains(err.Error(), "project.source_order") {
+		t.Errorf("LoadConfig with unknown order = %v, want source_order error", err)
+	}
+}
+
+func TestLoadConfig_MeasureMode(t *testing.T) {
+	cfg, err := LoadConfig(writeTemp(t, "cobbler:\n  measure_mode: spec-maintenance\n"))
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Cobbler.MeasureMode != MeasureModeSpecMaintenance {
+		t.Errorf("MeasureMode = %q, want %q", cfg.Cobbler.MeasureMode, MeasureModeSpecMaintenance)
+	}
+	if _, err := LoadConfig(writeTemp(t, "cobbler:\n  measure_mode: refactor\n")); err == nil || !strings.Contains(err.Error(), "cobbler.measure_mode") {
+		t.Errorf("LoadConfig with unknown mode = %v, want measure_mode error", err)
+	}
+}
+
 func TestLoadConfig_MissingFile(t *testing.T) {
 	_, err := LoadConfig("/nonexistent/configuration.yaml")
 	if err == nil {
@@ -211,6 +260,85 @@ func TestConfig_ClaudeTimeout(t *testing.T) {
 	}
 }
 
+func TestConfig_GenerationMaxDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"4h", 4 * time.Hour},
+		{"90m", 90 * time.Minute},
+		{"-1h", 0},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		cfg := Config{Generation: GenerationConfig{MaxDuration: tt.in}}
+		if got := cfg.GenerationMaxDuration(); got != tt.want {
+			t.Errorf("GenerationMaxDuration(%q): got %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLoadConfig_InvalidMaxDuration(t *testing.T) {
+	f := writeTemp(t, "generation:\n  max_duration: four hours\n")
+	if _, err := LoadConfig(f); err == nil {
+		t.Fatal("expected error for invalid generation.max_duration")
+	}
+}
+
+func TestLoadConfig_NonPositiveMaxDuration(t *testing.T) {
+	for _, in := range []string{"0s", "-1h"} {
+		f := writeTemp(t, "generation:\n  max_duration: "+in+"\n")
+		if _, err := LoadConfig(f); err == nil {
+			t.Errorf("expected error for generation.max_duration %q", in)
+		}
+	}
+}
+
+func TestConfig_KeptWorktreeMaxAge(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 72 * time.Hour},
+		{"24h", 24 * time.Hour},
+		{"0s", 72 * time.Hour},
+		{"later", 72 * time.Hour},
+	}
+	for _, tt := range tests {
+		cfg := Config{Cobbler: CobblerConfig{KeepFailedWorktreesMaxAge: tt.in}}
+		if got := cfg.KeptWorktreeMaxAge(); got != tt.want {
+			t.Errorf("KeptWorktreeMaxAge(%q): got %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	f := writeTemp(t, "cobbler:\n  keep_failed_worktrees_max_age: a week\n")
+	if _, err := LoadConfig(f); err == nil {
+		t.Error("expected error for invalid cobbler.keep_failed_worktrees_max_age")
+	}
+}
+
+func TestConfig_MeasureRetryBackoffBase(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"5s", 5 * time.Second},
+		{"-1s", 0},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		cfg := Config{Cobbler: CobblerConfig{MeasureRetryBackoff: tt.in}}
+		if got := cfg.MeasureRetryBackoffBase(); got != tt.want {
+			t.Errorf("MeasureRetryBackoffBase(%q): got %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	f := writeTemp(t, "cobbler:\n  measure_retry_backoff: a bit\n")
+	if _, err := LoadConfig(f); err == nil {
+		t.Error("expected error for invalid cobbler.measure_retry_backoff")
+	}
+}
+
 func TestLoadConfig_TemperatureFromYAML(t *testing.T) {
 	yaml := `claude:
   temperature: 0.7
@@ -380,3 +508,39 @@ func TestWriteDefaultConfig_ExistingFileError(t *testing.T) {
 		t.Error("expected error when file already exists, got nil")
 	}
 }
+
+func TestProjectConfigVersionFiles(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		project ProjectConfig
+		want    []string
+	}{
+		{ProjectConfig{}, nil},
+		{ProjectConfig{VersionFile: "cmd/a/version.go"}, []string{"cmd/a/version.go"}},
+		{ProjectConfig{VersionFiles: []string{"cmd/a/version.go", "cmd/b/version.go"}}, []string{"cmd/a/version.go", "cmd/b/version.go"}},
+		{ProjectConfig{VersionFile: "cmd/a/version.go", VersionFiles: []string{"", "cmd/a/version.go", "cmd/b/version.go"}}, []string{"cmd/a/version.go", "cmd/b/version.go"}},
+	}
+	for _, tc := range cases {
+		if got := tc.project.versionFiles(); !slices.Equal(got, tc.want) {
+			t.Errorf("versionFiles(%+v) = %v, want %v", tc.project, got, tc.want)
+		}
+	}
+}
+
+func TestGranularityRules_P9Targets(t *testing.T) {
+	t.Parallel()
+	got := defaultGranularityRules().p9Targets()
+	want := "Code tasks target 5-8 requirements, 5-8 acceptance criteria, and 3-5 design decisions. " +
+		"Documentation tasks target 2-4 requirements and 3-5 acceptance criteria."
+	if got != want {
+		t.Errorf("p9Targets() =\n%q\nwant\n%q", got, want)
+	}
+
+	open := GranularityRules{Code: DeliverableGranularity{Requirements: CountRange{Min: 4}}}
+	if got := open.p9Targets(); got != "Code tasks target 4+ requirements." {
+		t.Errorf("p9Targets() = %q", got)
+	}
+	if got := (GranularityRules{}).p9Targets(); got != "" {
+		t.Errorf("zero rules p9Targets() = %q, want empty", got)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// renumberedLists maps description list keys whose entries carry
+// sequential ids to the id prefix used after a merge.
+var renumberedLists = map[string]string{
+	"requirements":        "R",
+	"acceptance_criteria": "AC",
+	"design_decisions":    "D",
+}
+
+// taskEstimatedLines returns the estimated_lines declared in a task
+// description, or 0 when absent.
+func taskEstimatedLines(description string) int {
+	var desc issueDescription
+	if err := yaml.Unmarshal([]byte(description), &desc); err != nil {
+		return 0
+	}
+	return desc.EstimatedLines
+}
+
+// taskDirs returns the set of directories of the task's declared files.
+func taskDirs(description string) map[string]bool {
+	dirs := make(map[string]bool)
+	for _, p := range parseTaskFiles(description) {
+		dirs[filepath.Dir(p)] = true
+	}
+	return dirs
+}
+
+// shareDir reports whether two directory sets intersect.
+func shareDir(a, b map[string]bool) bool {
+	for d := range a {
+		if b[d] {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeTinyTasks consolidates over-split measure output (GH-488). Walking
+// the issues in order, an issue is folded into the task before it when
+// both declare estimated_lines below minLines (the accumulated task
+// counts as tiny until its combined estimate reaches minLines), both have
+// the same deliverable_type, their files share a directory, and the later
+// issue depends on nothing, on the earlier task, or on the same task as
+// the earlier one. Merged descriptions keep the union of required
+// reading and files and the concatenated requirements, acceptance
+// criteria, design decisions, and test cases; dependencies on a folded
+// issue are redirected to the task it was folded into. Tasks without
+// estimated_lines are never merged. When fits is non-nil, a merge whose
+// result fits rejects (for example, one that exceeds the P9 ranges) is
+// skipped. Returns the consolidated issues and one log line per merge.
+// minLines <= 0 returns issues unchanged.
+func mergeTinyTasks(issues []proposedIssue, minLines int, fits func(proposedIssue) bool) ([]proposedIssue, []string) {
+	if minLines <= 0 || len(issues) < 2 {
+		return issues, nil
+	}
+	var out []proposedIssue
+	var merges []string
+	redirect := make(map[int]int) // folded index -> surviving index
+	for _, iss := range issues {
+		if len(out) > 0 {
+			prev := &out[len(out)-1]
+			prevLines, lines := taskEstimatedLines(prev.Description), taskEstimatedLines(iss.Description)
+			dep := iss.Dependency
+			if r, ok := redirect[dep]; ok {
+				dep = r
+			}
+			if prevLines > 0 && prevLines < minLines && lines > 0 && lines < minLines &&
+				parseDeliverableType(prev.Description) == parseDeliverableType(iss.Description) &&
+				shareDir(taskDirs(prev.Description), taskDirs(iss.Description)) &&
+				(dep < 0 || dep == prev.Index || dep == prev.Dependency) {
+				merged, err := mergeTaskDescriptions(prev.Description, iss.Description)
+				candidate := *prev
+				candidate.Title = prev.Title + " + " + iss.Title
+				candidate.Description = merged
+				switch {
+				case err != nil:
+					logf("mergeTinyTasks: cannot merge [%d] into [%d]: %v", iss.Index, prev.Index, err)
+				case fits != nil && !fits(candidate):
+					logf("mergeTinyTasks: not merging [%d] into [%d], the merged task would fail validation", iss.Index, prev.Index)
+				default:
+					merges = append(merges, fmt.Sprintf("merged [%d] %q (%d lines) into [%d] %q (%d lines)",
+						iss.Index, iss.Title, lines, prev.Index, prev.Title, prevLines))
+					*prev = candidate
+					redirect[iss.Index] = prev.Index
+					continue
+				}
+			}
+		}
+		out = append(out, iss)
+	}
+	for i := range out {
+		if r, ok := redirect[out[i].Dependency]; ok {
+			out[i].Dependency = r
+		}
+	}
+	return out, merges
+}
+
+// mergeTaskDescriptions folds description b into a, preserving a's key
+// order. Lists are concatenated (files and required_reading skip entries
+// already present), estimated_lines is summed, other scalars keep a's
+// value, and keys only in b are appended. Ids in requirements,
+// acceptance_criteria, and design_decisions are renumbered.
+func mergeTaskDescriptions(a, b string) (string, error) {
+	var docA, docB yaml.Node
+	if err := yaml.Unmarshal([]byte(a), &docA); err != nil {
+		return "", fmt.Errorf("parsing first description: %w", err)
+	}
+	if err := yaml.Unmarshal([]byte(b), &docB); err != nil {
+		return "", fmt.Errorf("parsing second description: %w", err)
+	}
+	if len(docA.Content) == 0 || len(docB.Content) == 0 ||
+		docA.Content[0].Kind != yaml.MappingNode || docB.Content[0].Kind != yaml.MappingNode {
+		return "", fmt.Errorf("descriptions are not YAML mappings")
+	}
+	ma, mb := docA.Content[0], docB.Content[0]
+
+	for i := 0; i+1 < len(mb.Content); i += 2 {
+		key, val := mb.Content[i], mb.Content[i+1]
+		existing := mappingValue(ma, key.Value)
+		switch {
+		case existing == nil:
+			ma.Content = append(ma.Content, key, val)
+		case key.Value == "estimated_lines":
+			x, _ := strconv.Atoi(existing.Value)
+			y, _ := strconv.Atoi(val.Value)
+			existing.Value = strconv.Itoa(x + y)
+		case existing.Kind == yaml.SequenceNode && val.Kind == yaml.SequenceNode:
+			dedupe := key.Value == "files" || key.Value == "required_reading"
+			for _, item := range val.Content {
+				if dedupe && sequenceHasEntry(existing, item) {
+					continue
+				}
+				existing.Content = append(existing.Content, item)
+			}
+		}
+	}
+
+	for key, prefix := range renumberedLists {
+		seq := mappingValue(ma, key)
+		if seq == nil || seq.Kind != yaml.SequenceNode {
+			continue
+		}
+		for n, item := range seq.Content {
+			if id := mappingValue(item, "id"); id != nil {
+				id.Value = prefix + strconv.Itoa(n+1)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&docA); err != nil {
+		return "", fmt.Errorf("encoding merged description: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// sequenceHasEntry reports whether seq already holds item, comparing
+// scalars by value and mappings by their path field.
+func sequenceHasEntry(seq, item *yaml.Node) bool {
+	for _, e := range seq.Content {
+		switch {
+		case item.Kind == yaml.ScalarNode && e.Kind == yaml.ScalarNode:
+			if e.Value == item.Value {
+				return true
+			}
+		case item.Kind == yaml.MappingNode && e.Kind == yaml.MappingNode:
+			p, q := mappingValue(item, "path"), mappingValue(e, "path")
+			if p != nil && q != nil && p.Value == q.Value {
+				return true
+			}
+		}
+	}
+	return false
+}
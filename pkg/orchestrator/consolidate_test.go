@@ -0,0 +1,208 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"strconv"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func tinyTaskDesc(lines int, file, req string) string {
+	return "deliverable_type: code\n" +
+		"estimated_lines: " + strconv.Itoa(lines) + "\n" +
+		"required_reading:\n  - docs/ARCHITECTURE.yaml\n" +
+		"files:\n  - path: " + file + "\n    action: create\n" +
+		"requirements:\n  - id: R1\n    text: " + req + "\n" +
+		"acceptance_criteria:\n  - id: AC1\n    text: " + req + " works\n"
+}
+
+func TestMergeTinyTasks_MergesSamePackage(t *testing.T) {
+	t.Parallel()
+	issues := []proposedIssue{
+		{Index: 0, Title: "Add Get", Dependency: -1, Description: tinyTaskDesc(40, "pkg/store/get.go", "Get")},
+		{Index: 1, Title: "Add Set", Dependency: 0, Description: tinyTaskDesc(50, "pkg/store/set.go", "Set")},
+		{Index: 2, Title: "Use store", Dependency: 1, Description: tinyTaskDesc(400, "cmd/app/main.go", "Wire")},
+	}
+	got, merges := mergeTinyTasks(issues, 200, nil)
+	if len(got) != 2 || len(merges) != 1 {
+		t.Fatalf("got %d issue(s), %d merge(s); want 2 and 1: %v", len(got), len(merges), merges)
+	}
+	if got[0].Title != "Add Get + Add Set" {
+		t.Errorf("merged title = %q", got[0].Title)
+	}
+	if got[1].Dependency != 0 {
+		t.Errorf("dependency on folded issue = %d, want redirected to 0", got[1].Dependency)
+	}
+
+	var desc struct {
+		EstimatedLines  int             `yaml:"estimated_lines"`
+		RequiredReading []string        `yaml:"required_reading"`
+		Files           []issueDescFile `yaml:"files"`
+		Requirements    []issueDescItem `yaml:"requirements"`
+		Acceptance      []issueDescItem `yaml:"acceptance_criteria"`
+	}
+	if err := yaml.Unmarshal([]byte(got[0].Description), &desc); err != nil {
+		t.Fatalf("merged description does not parse: %v\n%s", err, got[0].Description)
+	}
+	if desc.EstimatedLines != 90 {
+		t.Errorf("estimated_lines = %d, want 90", desc.EstimatedLines)
+	}
+	if len(desc.RequiredReading) != 1 {
+		t.Errorf("required_reading = %v, want duplicates removed", desc.RequiredReading)
+	}
+	if len(desc.Files) != 2 {
+		t.Errorf("files = %v, want both files", desc.Files)
+	}
+	if len(desc.Requirements) != 2 || desc.Requirements[1].ID != "R2" || desc.Requirements[1].Text != "Set" {
+		t.Errorf("requirements = %+v, want R1 Get, R2 Set", desc.Requirements)
+	}
+	if len(desc.Acceptance) != 2 || desc.Acceptance[1].ID != "AC2" {
+		t.Errorf("acceptance_criteria = %+v, want renumbered AC1, AC2", desc.Acceptance)
+	}
+}
+
+func TestMergeTinyTasks_KeepsSeparate(t *testing.T) {
+	t.Parallel()
+	cases := map[string][]proposedIssue{
+		"different package": {
+			{Index: 0, Title: "A", Dependency: -1, Description: tinyTaskDesc(40, "pkg/a/a.go", "A")},
+			{Index: 1, Title: "B", Dependency: -1, Description: tinyTaskDesc(40, "pkg/b/b.go", "B")},
+		},
+		"above threshold": {
+			{Index: 0, Title: "A", Dependency: -1, Description: tinyTaskDesc(300, "pkg/a/a.go", "A")},
+			{Index: 1, Title: "B", Dependency: -1, Description: tinyTaskDesc(40, "pkg/a/b.go", "B")},
+		},
+		"unrelated dependency": {
+			{Index: 0, Title: "X", Dependency: -1, Description: tinyTaskDesc(300, "pkg/x/x.go", "X")},
+			{Index: 1, Title: "A", Dependency: -1, Description: tinyTaskDesc(40, "pkg/a/a.go", "A")},
+			{Index: 2, Title: "B", Dependency: 0, Description: tinyTaskDesc(40, "pkg/a/b.go", "B")},
+		},
+		"no estimate": {
+			{Index: 0, Title: "A", Dependency: -1, Description: "deliverable_type: code\nfiles:\n  - path: pkg/a/a.go\n"},
+			{Index: 1, Title: "B", Dependency: -1, Description: "deliverable_type: code\nfiles:\n  - path: pkg/a/b.go\n"},
+		},
+	}
+	for name, issues := range cases {
+		got, merges := mergeTinyTasks(issues, 200, nil)
+		if len(got) != len(issues) || len(merges) != 0 {
+			t.Errorf("%s: merged unexpectedly: %v", name, merges)
+		}
+	}
+}
+
+func TestMergeTinyTasks_Disabled(t *testing.T) {
+	t.Parallel()
+	issues := []proposedIssue{
+		{Index: 0, Title: "A", Dependency: -1, Description: tinyTaskDesc(10, "pkg/a/a.go", "A")},
+		{Index: 1, Title: "B", Dependency: -1, Description: tinyTaskDesc(10, "pkg/a/b.go", "B")},
+	}
+	if got, _ := mergeTinyTasks(issues, 0, nil); len(got) != 2 {
+		t.Errorf("minLines 0 merged issues: %+v", got)
+	}
+}
+
+func TestMergeTinyTasks_SkipsMergesThatFailValidation(t *testing.T) {
+	t.Parallel()
+	issues := []proposedIssue{
+		{Index: 0, Title: "A", Dependency: -1, Description: tinyTaskDesc(40, "pkg/a/a.go", "A")},
+		{Index: 1, Title: "B", Dependency: -1, Description: tinyTaskDesc(40, "pkg/a/b.go", "B")},
+	}
+	rules := GranularityRules{Code: DeliverableGranularity{Requirements: CountRange{Min: 1, Max: 1}}}
+	fits := func(iss proposedIssue) bool {
+		return !validateMeasureOutput([]proposedIssue{iss}, 0, nil, rules).HasErrors()
+	}
+	got, merges := mergeTinyTasks(issues, 200, fits)
+	if len(got) != 2 || len(merges) != 0 {
+		t.Errorf("merge exceeding the requirement range was applied: %v", merges)
+	}
+}
+
+// measureShapedOutput is Claude measure output in the shape the measure
+// prompt and issue-format constitution ask for.
+const measureShapedOutput = "Proposed tasks:\n\n```yaml\n" + `- index: 0
+  title: Add Crumb type (prd003 R1.1)
+  dependency: -1
+  description: |
+    deliverable_type: code
+    estimated_lines: 60
+
+    required_reading:
+      - docs/specs/product-requirements/prd003-crumbs-interface.yaml
+
+    files:
+      - path: pkg/crumbs/crumb.go
+        action: create
+        note: Crumb struct
+
+    requirements:
+      - id: R1
+        text: Implement Crumb per prd003 R1.1
+
+    acceptance_criteria:
+      - id: AC1
+        text: Crumb fields match the PRD
+
+- index: 1
+  title: Add Crumb validation (prd003 R1.2)
+  dependency: 0
+  description: |
+    deliverable_type: code
+    estimated_lines: 80
+
+    required_reading:
+      - docs/specs/product-requirements/prd003-crumbs-interface.yaml
+      - pkg/crumbs/crumb.go (Crumb from task 0)
+
+    files:
+      - path: pkg/crumbs/validate.go
+        action: create
+        note: Validate method
+
+    requirements:
+      - id: R1
+        text: Implement Crumb.Validate per prd003 R1.2
+
+    acceptance_criteria:
+      - id: AC1
+        text: Validate rejects a crumb without an ID
+
+    test_cases:
+      - name: TestCrumb_ValidateMissingID
+        description: Validate returns an error for an empty ID
+` + "```\n"
+
+func TestMergeTinyTasks_MeasureShapedOutput(t *testing.T) {
+	t.Parallel()
+	block, err := extractYAMLBlock(measureShapedOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var issues []proposedIssue
+	if err := yaml.Unmarshal(block, &issues); err != nil {
+		t.Fatalf("parsing measure output: %v", err)
+	}
+	if msgs := validateIssueSchemas(issues); len(msgs) > 0 {
+		t.Fatalf("measure-shaped output violates the issue format: %v", msgs)
+	}
+
+	got, merges := mergeTinyTasks(issues, 200, nil)
+	if len(got) != 1 || len(merges) != 1 {
+		t.Fatalf("got %d issue(s), %d merge(s); want 1 and 1: %v", len(got), len(merges), merges)
+	}
+	var desc issueDescription
+	if err := yaml.Unmarshal([]byte(got[0].Description), &desc); err != nil {
+		t.Fatalf("merged description does not parse: %v\n%s", err, got[0].Description)
+	}
+	if desc.EstimatedLines != 140 {
+		t.Errorf("estimated_lines = %d, want 140", desc.EstimatedLines)
+	}
+	if len(desc.Files) != 2 || len(desc.Requirements) != 2 || len(desc.TestCases) != 1 {
+		t.Errorf("merged description lost entries: %+v", desc)
+	}
+	if msgs := validateIssueSchemas(got); len(msgs) > 0 {
+		t.Errorf("merged description violates the issue format: %v", msgs)
+	}
+}
@@ -5,19 +5,29 @@ package orchestrator
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/printer"
+	"go/scanner"
 	"go/token"
 	"io/fs"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	"gopkg.in/yaml.v3"
 )
@@ -52,6 +62,22 @@ type PhaseContext struct {
 	// SummarizeCommand overrides CobblerConfig.MeasureSummarizeCommand
 	// for this invocation. Used when SourceMode is "custom" (GH-617).
 	SummarizeCommand string `yaml:"summarize_command"`
+	// GoSourceDirs is a newline-delimited list of directories that
+	// replaces ProjectConfig.GoSourceDirs for this phase, so stitch can
+	// see one package while measure sees the whole tree (GH-555).
+	GoSourceDirs string `yaml:"go_source_dirs"`
+}
+
+// sourceDirs returns the phase's GoSourceDirs when set, else fallback.
+// Safe to call on a nil PhaseContext.
+func (pc *PhaseContext) sourceDirs(fallback []string) []string {
+	if pc == nil {
+		return fallback
+	}
+	if dirs := parseContextSources(pc.GoSourceDirs); len(dirs) > 0 {
+		return dirs
+	}
+	return fallback
 }
 
 // loadPhaseContext reads a phase context YAML file. Returns (nil, nil)
@@ -89,6 +115,10 @@ const defaultMeasureContext = `# Phase context for the measure workflow.
 # sources: |
 #   docs/constitutions/*.yaml
 
+# go_source_dirs: |
+#   pkg/
+#   cmd/
+
 # release: "01.0"
 `
 
@@ -108,6 +138,9 @@ const defaultStitchContext = `# Phase context for the stitch workflow.
 # sources: |
 #   docs/constitutions/*.yaml
 
+# go_source_dirs: |
+#   pkg/orchestrator/
+
 # release: "01.0"
 `
 
@@ -129,13 +162,33 @@ type ProjectContext struct {
 	Issues         []ContextIssue     `yaml:"issues,omitempty"`
 	CompletedWork  []string           `yaml:"completed_work,omitempty"`
 	Extra          []*NamedDoc        `yaml:"extra,omitempty"`
+	ReadErrors     []string           `yaml:"read_errors,omitempty"` // files dropped after repeated read failures (GH-485)
+
+	// RequiredDocOverrides lists docs from the task's required_reading that
+	// the include/exclude rules or release filter had dropped and that were
+	// re-included; MissingRequiredDocs lists those that could not be
+	// loaded at all (GH-491).
+	RequiredDocOverrides []string `yaml:"required_doc_overrides,omitempty"`
+	MissingRequiredDocs  []string `yaml:"missing_required_docs,omitempty"`
+
+	// ParseErrors lists documents that were read but failed to parse and
+	// are therefore absent from the context (GH-510).
+	ParseErrors []ContextLoadError `yaml:"parse_errors,omitempty"`
+}
+
+// ContextLoadError records a context document that could not be parsed.
+type ContextLoadError struct {
+	File  string `yaml:"file"`
+	Error string `yaml:"error"`
 }
 
 // SourceFile holds a source file for inclusion in the project context.
 // Lines are formatted as "{number} | {content}", with blank lines omitted.
+// Blame, when set, summarizes last-commit age per line range (GH-487).
 type SourceFile struct {
 	File  string `yaml:"file"`
 	Lines string `yaml:"lines"`
+	Blame string `yaml:"blame,omitempty"`
 }
 
 // ---------------------------------------------------------------------------
@@ -470,15 +523,15 @@ type OODPackageContractRef struct {
 //   - sharedProtocols: the shared_protocols from docs/ARCHITECTURE.yaml
 //     (used in the stitch prompt only).
 //
-// Missing files and parse errors are silently skipped; the function
-// always returns non-nil slices.
-func loadOODPromptContext() (contracts []OODPackageContractRef, sharedProtocols []ArchSharedProtocol) {
+// Paths are resolved against root (GH-563). Missing files and parse
+// errors are silently skipped; the function always returns non-nil slices.
+func loadOODPromptContext(root string) (contracts []OODPackageContractRef, sharedProtocols []ArchSharedProtocol) {
 	contracts = []OODPackageContractRef{}
 	sharedProtocols = []ArchSharedProtocol{}
 
-	prdFiles, _ := filepath.Glob("docs/specs/product-requirements/prd*.yaml")
+	prdFiles, _ := globFromRoot(root, "docs/specs/product-requirements/prd*.yaml")
 	for _, path := range prdFiles {
-		prd := loadYAML[PRDDoc](path)
+		prd := loadYAMLFrom[PRDDoc](root, path, nil)
 		if prd == nil || prd.PackageContract == nil || len(prd.PackageContract.Exports) == 0 {
 			continue
 		}
@@ -488,7 +541,7 @@ func loadOODPromptContext() (contracts []OODPackageContractRef, sharedProtocols
 		})
 	}
 
-	if data, err := os.ReadFile("docs/ARCHITECTURE.yaml"); err == nil {
+	if data, err := os.ReadFile(rootedPath(root, "docs/ARCHITECTURE.yaml")); err == nil {
 		var arch ArchitectureDoc
 		if yaml.Unmarshal(data, &arch) == nil {
 			sharedProtocols = arch.SharedProtocols
@@ -850,10 +903,11 @@ type Risk struct {
 // It captures the fields needed for Claude to avoid creating duplicate
 // issues during measure.
 type ContextIssue struct {
-	ID     string `yaml:"id"     json:"id"`
-	Title  string `yaml:"title"  json:"title"`
-	Status string `yaml:"status" json:"status"`
-	Type   string `yaml:"type"   json:"type"`
+	ID          string `yaml:"id"                    json:"id"`
+	Title       string `yaml:"title"                 json:"title"`
+	Status      string `yaml:"status"                json:"status"`
+	Type        string `yaml:"type"                  json:"type"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
 }
 
 // NamedDoc wraps project-specific YAML files that don't have a fixed
@@ -909,76 +963,438 @@ func filterSourceFiles(sources []SourceFile, requiredPaths []string) []SourceFil
 	return filtered
 }
 
+// lineRange is an inclusive 1-based line range of a source file.
+type lineRange struct {
+	Start, End int
+}
+
+// parseLineRange splits an optional ":start-end" suffix from a
+// required_reading path. "pkg/x/stitch.go:120-180" returns
+// "pkg/x/stitch.go" and {120, 180}. Without a suffix, or when the suffix
+// is not a valid range (start < 1 or end < start), the input is returned
+// unchanged with ok false.
+func parseLineRange(s string) (path string, r lineRange, ok bool) {
+	idx := strings.LastIndex(s, ":")
+	if idx <= 0 {
+		return s, lineRange{}, false
+	}
+	lo, hi, found := strings.Cut(s[idx+1:], "-")
+	if !found {
+		return s, lineRange{}, false
+	}
+	start, err1 := strconv.Atoi(strings.TrimSpace(lo))
+	end, err2 := strconv.Atoi(strings.TrimSpace(hi))
+	if err1 != nil || err2 != nil || start < 1 || end < start {
+		return s, lineRange{}, false
+	}
+	return s[:idx], lineRange{Start: start, End: end}, true
+}
+
+// sliceSourceLines narrows each source file matching a key of ranges (by
+// suffix, as in filterSourceFiles) to the numbered lines inside any of
+// its ranges. Files without ranges keep their full content.
+// Lines keep their original numbers, so a sliced file still reads as
+// "{number} | {line}".
+func sliceSourceLines(sources []SourceFile, ranges map[string][]lineRange) []SourceFile {
+	if len(ranges) == 0 {
+		return sources
+	}
+	out := make([]SourceFile, len(sources))
+	for i, sf := range sources {
+		out[i] = sf
+		var want []lineRange
+		for path, rs := range ranges {
+			if strings.HasSuffix(sf.File, path) {
+				want = append(want, rs...)
+			}
+		}
+		if len(want) == 0 {
+			continue
+		}
+		var kept []string
+		for _, line := range strings.Split(sf.Lines, "\n") {
+			numStr, _, _ := strings.Cut(line, " | ")
+			n, err := strconv.Atoi(numStr)
+			if err != nil {
+				continue
+			}
+			for _, r := range want {
+				if n >= r.Start && n <= r.End {
+					kept = append(kept, line)
+					break
+				}
+			}
+		}
+		out[i].Lines = strings.Join(kept, "\n")
+	}
+	return out
+}
+
+// packageScopeFiles derives a package scope from a task's files list and
+// returns only the source files in that scope. The scope is the directory
+// of every listed file plus each project-local package imported by source
+// files in those directories; imports are read from disk with go/parser,
+// relative to root. modulePath maps import paths to directories. Returns
+// sources unchanged and a nil scope when taskFiles is empty or the scope
+// matches no source file (for example, a task that creates a new package).
+func packageScopeFiles(root string, sources []SourceFile, taskFiles []string, modulePath string) ([]SourceFile, []string) {
+	targets := make(map[string]bool)
+	for _, f := range taskFiles {
+		f = strings.TrimPrefix(stripParenthetical(f), "./")
+		if f == "" {
+			continue
+		}
+		targets[filepath.Dir(f)] = true
+	}
+	if len(targets) == 0 {
+		return sources, nil
+	}
+
+	scope := make(map[string]bool, len(targets))
+	for d := range targets {
+		scope[d] = true
+	}
+	if modulePath != "" {
+		fset := token.NewFileSet()
+		for _, sf := range sources {
+			if !targets[filepath.Dir(sf.File)] {
+				continue
+			}
+			f, err := parser.ParseFile(fset, rootedPath(root, sf.File), nil, parser.ImportsOnly)
+			if err != nil {
+				logf("packageScopeFiles: parse imports of %s: %v", sf.File, err)
+				continue
+			}
+			for _, imp := range f.Imports {
+				path := strings.Trim(imp.Path.Value, `"`)
+				switch {
+				case path == modulePath:
+					scope["."] = true
+				case strings.HasPrefix(path, modulePath+"/"):
+					scope[strings.TrimPrefix(path, modulePath+"/")] = true
+				}
+			}
+		}
+	}
+
+	var kept []SourceFile
+	for _, sf := range sources {
+		if scope[filepath.Dir(sf.File)] {
+			kept = append(kept, sf)
+		}
+	}
+	if len(kept) == 0 {
+		return sources, nil
+	}
+	dirs := make([]string, 0, len(scope))
+	for d := range scope {
+		dirs = append(dirs, d)
+	}
+	sort.Strings(dirs)
+	return kept, dirs
+}
+
 // applyContextBudget measures the YAML-serialized size of ctx and, if it
 // exceeds budget, progressively removes SourceCode entries not in
 // requiredPaths until within budget. Files are removed in reverse order
 // (last loaded first) to preserve files closer to the top of the directory
-// tree. When budget is 0 or negative, this function is a no-op.
-func applyContextBudget(ctx *ProjectContext, budget int, requiredPaths []string) {
-	if budget <= 0 || ctx == nil {
+// tree. When shedDocs is set and removing source is not enough, Extra and
+// then Engineering docs not in requiredDocs are removed the same way
+// (GH-503). When budget is 0 or negative, this function is a no-op.
+func applyContextBudget(ctx *ProjectContext, budget int, requiredPaths, requiredDocs []string, shedDocs bool) budgetTrim {
+	trim, ok := trimContextToBudget(ctx, budget, requiredPaths, requiredDocs, shedDocs, func(data []byte) int { return len(data) })
+	if !ok {
+		return trim
+	}
+	if trim.Before <= budget {
+		logf("applyContextBudget: context size %d <= budget %d, no truncation needed", trim.Before, budget)
+		return trim
+	}
+	logf("applyContextBudget: context size %d -> %d, removed %d source file(s) and %d doc(s)",
+		trim.Before, trim.After, trim.Sources, trim.Docs)
+	return trim
+}
+
+// defaultCharsPerToken is the chars-per-token divisor used by
+// tokensForBytes when CobblerConfig.ContextCharsPerToken is unset.
+const defaultCharsPerToken = 4.0
+
+// tokensForBytes approximates the token count of serialized context as
+// its character count divided by charsPerToken (defaultCharsPerToken
+// when <= 0).
+func tokensForBytes(data []byte, charsPerToken float64) int {
+	if charsPerToken <= 0 {
+		charsPerToken = defaultCharsPerToken
+	}
+	return int(math.Ceil(float64(utf8.RuneCount(data)) / charsPerToken))
+}
+
+// applyContextTokenBudget is applyContextBudget measured in estimated
+// tokens (see tokensForBytes) instead of bytes (GH-502). Both the
+// estimated token count and byte size before and after trimming are
+// logged so operators can tune charsPerToken. When maxTokens is 0 or
+// negative, this function is a no-op.
+func applyContextTokenBudget(ctx *ProjectContext, maxTokens int, charsPerToken float64, requiredPaths, requiredDocs []string, shedDocs bool) budgetTrim {
+	if charsPerToken <= 0 {
+		charsPerToken = defaultCharsPerToken
+	}
+	var bytesBefore, bytesAfter int
+	measure := func(data []byte) int {
+		if bytesBefore == 0 {
+			bytesBefore = len(data)
+		}
+		bytesAfter = len(data)
+		return tokensForBytes(data, charsPerToken)
+	}
+	trim, ok := trimContextToBudget(ctx, maxTokens, requiredPaths, requiredDocs, shedDocs, measure)
+	if !ok {
+		return trim
+	}
+	logf("applyContextTokenBudget: estimated tokens %d -> %d (bytes %d -> %d, budget %d tokens, %.1f chars/token), removed %d source file(s) and %d doc(s)",
+		trim.Before, trim.After, bytesBefore, bytesAfter, maxTokens, charsPerToken, trim.Sources, trim.Docs)
+	return trim
+}
+
+// applyContextBudgets applies the configured context budget to ctx:
+// MaxContextTokens when set, else MaxContextBytes, shedding docs when
+// ContextBudgetShedDocs is set. Measure and stitch both call it so the
+// budget behaves the same in either phase. The result is kept in
+// o.lastTrim for the prompt's history stats.
+func (o *Orchestrator) applyContextBudgets(ctx *ProjectContext, requiredPaths, requiredDocs []string) {
+	shedDocs := o.cfg.Cobbler.ContextBudgetShedDocs
+	if o.cfg.Cobbler.MaxContextTokens > 0 {
+		o.lastTrim = applyContextTokenBudget(ctx, o.cfg.Cobbler.MaxContextTokens, o.cfg.Cobbler.ContextCharsPerToken, requiredPaths, requiredDocs, shedDocs)
 		return
 	}
+	o.lastTrim = applyContextBudget(ctx, o.cfg.Cobbler.MaxContextBytes, requiredPaths, requiredDocs, shedDocs)
+}
+
+// budgetTrim reports what trimContextToBudget did: the measured size
+// before and after and the number of source files and docs removed.
+type budgetTrim struct {
+	Before, After int
+	Sources, Docs int
+}
+
+// removed returns the number of entries the trim dropped.
+func (t budgetTrim) removed() int { return t.Sources + t.Docs }
+
+// trimContextToBudget removes entries from ctx until measure(serialized
+// ctx) <= budget. Non-required SourceCode entries go first, last loaded
+// first; when shedDocs is set, Extra and then Engineering docs whose File
+// is not in requiredDocs follow, also last first. Returns false when the
+// budget is disabled or marshalling fails.
+func trimContextToBudget(ctx *ProjectContext, budget int, requiredPaths, requiredDocs []string, shedDocs bool, measure func([]byte) int) (budgetTrim, bool) {
+	var trim budgetTrim
+	if budget <= 0 || ctx == nil {
+		return trim, false
+	}
 
 	data, err := yaml.Marshal(ctx)
 	if err != nil {
-		logf("applyContextBudget: marshal error: %v", err)
-		return
+		logf("trimContextToBudget: marshal error: %v", err)
+		return trim, false
 	}
-	before := len(data)
-	if before <= budget {
-		logf("applyContextBudget: context size %d <= budget %d, no truncation needed", before, budget)
-		return
+	trim.Before = measure(data)
+	trim.After = trim.Before
+
+	protectedDoc := make(map[string]bool, len(requiredDocs))
+	for _, d := range requiredDocs {
+		protectedDoc[filepath.Clean(d)] = true
 	}
 
-	removed := 0
-	for len(ctx.SourceCode) > 0 && len(data) > budget {
-		// Find the last non-required source file.
-		idx := -1
+	// Each remover drops the last unprotected entry of its list and
+	// returns false when none is left.
+	removeSource := func() bool {
 		for i := len(ctx.SourceCode) - 1; i >= 0; i-- {
 			if !sourceFileMatchesAny(ctx.SourceCode[i], requiredPaths) {
-				idx = i
-				break
+				ctx.SourceCode = append(ctx.SourceCode[:i], ctx.SourceCode[i+1:]...)
+				trim.Sources++
+				return true
+			}
+		}
+		return false // all remaining files are required
+	}
+	removeExtra := func() bool {
+		for i := len(ctx.Extra) - 1; i >= 0; i-- {
+			if !protectedDoc[filepath.Clean(ctx.Extra[i].File)] {
+				logf("trimContextToBudget: dropping extra doc %s", ctx.Extra[i].File)
+				ctx.Extra = append(ctx.Extra[:i], ctx.Extra[i+1:]...)
+				trim.Docs++
+				return true
 			}
 		}
-		if idx < 0 {
-			break // all remaining files are required
+		return false
+	}
+	removeEngineering := func() bool {
+		for i := len(ctx.Engineering) - 1; i >= 0; i-- {
+			if !protectedDoc[filepath.Clean(ctx.Engineering[i].File)] {
+				logf("trimContextToBudget: dropping engineering doc %s", ctx.Engineering[i].File)
+				ctx.Engineering = append(ctx.Engineering[:i], ctx.Engineering[i+1:]...)
+				trim.Docs++
+				return true
+			}
 		}
-		ctx.SourceCode = append(ctx.SourceCode[:idx], ctx.SourceCode[idx+1:]...)
-		removed++
+		return false
+	}
+	passes := []func() bool{removeSource}
+	if shedDocs {
+		passes = append(passes, removeExtra, removeEngineering)
+	}
 
-		data, err = yaml.Marshal(ctx)
-		if err != nil {
-			logf("applyContextBudget: re-marshal error: %v", err)
-			return
+	for _, remove := range passes {
+		for trim.After > budget && remove() {
+			data, err = yaml.Marshal(ctx)
+			if err != nil {
+				logf("trimContextToBudget: re-marshal error: %v", err)
+				return trim, false
+			}
+			trim.After = measure(data)
 		}
 	}
+	return trim, true
+}
+
+// capSourceFiles keeps at most max source files. Files matching
+// requiredPaths (suffix match) are kept first, then files under any of
+// preferredDirs, then the remaining files in their original order. The
+// result preserves the original relative order. Dropped files are logged.
+// When max is 0 or negative, files is returned unchanged.
+func capSourceFiles(files []SourceFile, max int, requiredPaths, preferredDirs []string) []SourceFile {
+	if max <= 0 || len(files) <= max {
+		return files
+	}
 
-	logf("applyContextBudget: context size %d -> %d, removed %d source file(s)", before, len(data), removed)
+	tier := func(sf SourceFile) int {
+		if sourceFileMatchesAny(sf, requiredPaths) {
+			return 0
+		}
+		for _, d := range preferredDirs {
+			if strings.HasPrefix(sf.File, strings.TrimSuffix(d, "/")+"/") {
+				return 1
+			}
+		}
+		return 2
+	}
+
+	keep := make([]bool, len(files))
+	kept := 0
+	for t := 0; t <= 2 && kept < max; t++ {
+		for i, sf := range files {
+			if kept >= max {
+				break
+			}
+			if !keep[i] && tier(sf) == t {
+				keep[i] = true
+				kept++
+			}
+		}
+	}
+
+	var result []SourceFile
+	var dropped []string
+	for i, sf := range files {
+		if keep[i] {
+			result = append(result, sf)
+		} else {
+			dropped = append(dropped, sf.File)
+		}
+	}
+	logf("capSourceFiles: max_source_files=%d, kept %d, dropped %d: %v", max, len(result), len(dropped), dropped)
+	return result
 }
 
 // ---------------------------------------------------------------------------
 // Helper functions
 // ---------------------------------------------------------------------------
 
+// Transient read handling for context files (GH-485). readFileFn is
+// swapped in tests to simulate flaky filesystems.
+var (
+	readFileFn            = os.ReadFile
+	contextReadRetries    = 2
+	contextReadRetryDelay = 200 * time.Millisecond
+)
+
+// contextLoadErrors collects the files one buildProjectContext call
+// dropped: those that could not be read for a reason other than not
+// existing (GH-485) and documents whose YAML did not parse (GH-510). Each
+// build owns its collector, so concurrent builds never see each other's
+// failures. Methods on a nil collector record nothing.
+type contextLoadErrors struct {
+	sync.Mutex
+	read  []string
+	parse []ContextLoadError
+}
+
+// addRead records path as dropped after a read error.
+func (e *contextLoadErrors) addRead(path string, err error) {
+	if e == nil {
+		return
+	}
+	e.Lock()
+	e.read = append(e.read, fmt.Sprintf("%s: %v", path, err))
+	e.Unlock()
+}
+
+// readContextFile reads a file for the project context. A missing file is
+// returned immediately, since absence is legitimate. Any other error is
+// treated as transient and retried contextReadRetries times; if it still
+// fails, the file is recorded as dropped in errs and the last error is
+// returned.
+func readContextFile(path string, errs *contextLoadErrors) ([]byte, error) {
+	data, err := readFileFn(path)
+	for attempt := 1; err != nil && !os.IsNotExist(err) && attempt <= contextReadRetries; attempt++ {
+		logf("readContextFile: read error for %s (retry %d/%d): %v", path, attempt, contextReadRetries, err)
+		time.Sleep(contextReadRetryDelay)
+		data, err = readFileFn(path)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		logf("readContextFile: warning: dropping %s from context: %v", path, err)
+		errs.addRead(path, err)
+	}
+	return data, err
+}
+
+// addParse logs a parse failure reported by fn and records path as
+// dropped.
+func (e *contextLoadErrors) addParse(fn, path string, err error) {
+	logf("%s: parse error for %s: %v", fn, path, err)
+	if e == nil {
+		return
+	}
+	e.Lock()
+	e.parse = append(e.parse, ContextLoadError{File: path, Error: err.Error()})
+	e.Unlock()
+}
+
 // loadYAML reads a YAML file and unmarshals it into T.
-// Returns nil if the file does not exist or cannot be parsed.
+// Returns nil if the file does not exist or cannot be parsed; parse
+// failures are logged.
 func loadYAML[T any](path string) *T {
-	data, err := os.ReadFile(path)
+	return loadYAMLFrom[T]("", path, nil)
+}
+
+// loadYAMLFrom is loadYAML for a path relative to root (GH-563). Read
+// and parse failures are recorded in errs, parse failures under path.
+func loadYAMLFrom[T any](root, path string, errs *contextLoadErrors) *T {
+	data, err := readContextFile(rootedPath(root, path), errs)
 	if err != nil {
 		return nil
 	}
 	var v T
 	if err := yaml.Unmarshal(data, &v); err != nil {
-		logf("loadYAML: parse error for %s: %v", path, err)
+		errs.addParse("loadYAML", path, err)
 		return nil
 	}
 	return &v
 }
 
-// loadNamedDoc reads a YAML file into a NamedDoc, using the filename
-// stem (without extension) as the Name.
-func loadNamedDoc(path string) *NamedDoc {
-	data, err := os.ReadFile(path)
+// loadNamedDoc reads a YAML file, relative to root, into a NamedDoc, using
+// the filename stem (without extension) as the Name. Read and parse
+// failures are recorded in errs.
+func loadNamedDoc(root, path string, errs *contextLoadErrors) *NamedDoc {
+	data, err := readContextFile(rootedPath(root, path), errs)
 	if err != nil {
 		return nil
 	}
@@ -999,7 +1415,7 @@ func loadNamedDoc(path string) *NamedDoc {
 				},
 			}
 		}
-		logf("loadNamedDoc: parse error for %s: %v", path, err)
+		errs.addParse("loadNamedDoc", path, err)
 		return nil
 	}
 	node := &content
@@ -1046,6 +1462,46 @@ func numberLines(content string) string {
 	return strings.Join(result, "\n")
 }
 
+// stripGoComments removes every comment from Go source except //go:
+// directives (GH-507). A comment's newlines are kept so the remaining code
+// stays on its original line numbers; trailing whitespace left behind is
+// trimmed, and lines that become empty are then dropped by numberLines.
+// Content that does not scan as Go is returned unchanged.
+func stripGoComments(content string) string {
+	src := []byte(content)
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	failed := false
+	s.Init(file, src, func(token.Position, string) { failed = true }, scanner.ScanComments)
+
+	var b strings.Builder
+	last := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok != token.COMMENT || strings.HasPrefix(lit, "//go:") {
+			continue
+		}
+		start := file.Offset(pos)
+		b.Write(src[last:start])
+		b.WriteString(strings.Repeat("\n", strings.Count(lit, "\n")))
+		last = start + len(lit)
+	}
+	if failed {
+		return content
+	}
+	b.Write(src[last:])
+
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.Join(lines, "\n")
+}
+
 // ---------------------------------------------------------------------------
 // Source summarization (GH-617, prd003 R12)
 // ---------------------------------------------------------------------------
@@ -1109,16 +1565,19 @@ func summarizeGoHeaders(content string) string {
 	return buf.String()
 }
 
-// summarizeCustom runs command with filePath appended as the last argument
-// and returns stdout as the summarized content (prd003 R12.4). Falls back to
-// fullContent when the command exits non-zero or produces empty output.
-func summarizeCustom(command, filePath, fullContent string) string {
+// summarizeCustom runs command in dir (the working directory when empty)
+// with filePath appended as the last argument and returns stdout as the
+// summarized content (prd003 R12.4). Falls back to fullContent when the
+// command exits non-zero or produces empty output.
+func summarizeCustom(command, dir, filePath, fullContent string) string {
 	if command == "" {
 		return fullContent
 	}
 	parts := strings.Fields(command)
 	parts = append(parts, filePath)
-	out, err := exec.Command(parts[0], parts[1:]...).Output() //nolint:gosec
+	cmd := exec.Command(parts[0], parts[1:]...) //nolint:gosec
+	cmd.Dir = dir
+	out, err := cmd.Output()
 	if err != nil || len(strings.TrimSpace(string(out))) == 0 {
 		logf("summarizeCustom: command %q failed for %s (%v), using full content", command, filePath, err)
 		return fullContent
@@ -1126,41 +1585,446 @@ func summarizeCustom(command, filePath, fullContent string) string {
 	return string(out)
 }
 
-// loadSourceFiles walks the given directories and reads all .go files,
-// returning them sorted by path for deterministic prompt output.
-func loadSourceFiles(dirs []string) []SourceFile {
-	var files []SourceFile
-	for _, dir := range dirs {
-		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+// LoadSourceFiles reads every .go file under dirs using up to workers
+// concurrent readers (runtime.NumCPU() when workers <= 0) and returns
+// them numbered and sorted by path. It is the loader buildProjectContext
+// uses, without .gitignore filtering, exported for benchmarks and tooling.
+func LoadSourceFiles(dirs []string, workers int) []SourceFile {
+	return loadSourceFiles(dirs, sourceLoadOptions{Workers: workers})
+}
+
+// defaultSourceExtensions is used when ProjectConfig.SourceExtensions is
+// empty.
+var defaultSourceExtensions = []string{".go"}
+
+// sourceLoadOptions configures loadSourceFiles from ProjectConfig.
+type sourceLoadOptions struct {
+	Workers          int                // concurrent readers; <= 0 means runtime.NumCPU()
+	RespectGitignore bool               // skip untracked files matched by .gitignore (GH-505)
+	Extensions       []string           // file suffixes to load; empty means .go only (GH-506)
+	StripComments    bool               // remove comments from .go files (GH-507)
+	Order            string             // SourceOrderPath (default) or SourceOrderDependency (GH-550)
+	ExcludeContains  []string           // skip files whose header contains any marker (GH-554)
+	MaxFileBytes     int64              // skip files larger than this; 0 means no cap (GH-557)
+	FollowSymlinks   bool               // descend into symlinked directories (GH-558)
+	Root             string             // dirs and file paths are relative to Root; empty means the working directory (GH-563)
+	Errs             *contextLoadErrors // collects files dropped after read errors (GH-485); nil records nothing
+}
+
+// walkFollowingSymlinks calls visit for every non-directory under dirs,
+// which are relative to root (the working directory when empty),
+// descending into symlinked directories whose target resolves inside root.
+// Paths passed to visit are relative to root. Links leaving root or failing to
+// resolve are logged and skipped. Each directory is tracked by its real
+// path and walked at most once, so circular links terminate and a package
+// reachable through several links is loaded once (GH-558).
+func walkFollowingSymlinks(dirs []string, root string, visit func(path string, info os.FileInfo)) {
+	if root == "" {
+		root = "."
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err == nil {
+		realRoot, err = filepath.Abs(realRoot)
+	}
+	if err != nil {
+		logf("loadSourceFiles: resolving root %s: %v", root, err)
+		return
+	}
+
+	visited := make(map[string]bool)
+	var walk func(dir string)
+	walk = func(dir string) {
+		realDir, err := filepath.EvalSymlinks(dir)
+		if err == nil {
+			realDir, err = filepath.Abs(realDir)
+		}
+		if err != nil {
+			logf("loadSourceFiles: resolving %s: %v", dir, err)
+			return
+		}
+		// Walk the resolved directory, since WalkDir does not descend
+		// through a symlinked root, and report paths under dir.
+		err = filepath.WalkDir(realDir, func(real string, d fs.DirEntry, err error) error {
 			if err != nil {
 				return nil
 			}
-			if info.IsDir() {
+			rel, _ := filepath.Rel(realDir, real)
+			path := filepath.Join(dir, rel)
+			if d.IsDir() {
+				if visited[real] {
+					logf("loadSourceFiles: skipping %s (already walked %s)", path, real)
+					return filepath.SkipDir
+				}
+				visited[real] = true
 				return nil
 			}
-			if !strings.HasSuffix(path, ".go") {
+			if d.Type()&fs.ModeSymlink == 0 {
+				if info, err := d.Info(); err == nil {
+					visit(relToRoot(root, path), info)
+				}
+				return nil
+			}
+			target, err := filepath.EvalSymlinks(path)
+			if err == nil {
+				target, err = filepath.Abs(target)
+			}
+			if err != nil {
+				logf("loadSourceFiles: skipping symlink %s: %v", path, err)
 				return nil
 			}
-			data, readErr := os.ReadFile(path)
-			if readErr != nil {
-				logf("loadSourceFiles: read error for %s: %v", path, readErr)
+			if rel, err := filepath.Rel(realRoot, target); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				logf("loadSourceFiles: skipping symlink %s (target %s is outside %s)", path, target, realRoot)
 				return nil
 			}
-			files = append(files, SourceFile{
-				File:  path,
-				Lines: numberLines(string(data)),
-			})
+			info, err := os.Stat(path)
+			if err != nil {
+				return nil
+			}
+			if info.IsDir() {
+				walk(path)
+				return nil
+			}
+			visit(relToRoot(root, path), info)
 			return nil
 		})
 		if err != nil {
 			logf("loadSourceFiles: walk error for %s: %v", dir, err)
 		}
 	}
+	for _, dir := range dirs {
+		walk(rootedPath(root, dir))
+	}
+}
+
+// excludeContainsHeaderBytes is how much of a source file's start is
+// searched for ExcludeContains markers.
+const excludeContainsHeaderBytes = 2048
+
+// headerContainsAny returns the first of markers found in the first
+// excludeContainsHeaderBytes bytes of data, or "" when none is.
+func headerContainsAny(data []byte, markers []string) string {
+	header := data[:min(len(data), excludeContainsHeaderBytes)]
+	for _, m := range markers {
+		if m != "" && bytes.Contains(header, []byte(m)) {
+			return m
+		}
+	}
+	return ""
+}
+
+// sourceLoadOptionsFor derives the loader options from project settings.
+func sourceLoadOptionsFor(project ProjectConfig) sourceLoadOptions {
+	return sourceLoadOptions{
+		Workers:          project.SourceLoadWorkers,
+		RespectGitignore: project.effectiveRespectGitignore(),
+		Extensions:       project.SourceExtensions,
+		StripComments:    project.StripComments,
+		Order:            project.SourceOrder,
+		ExcludeContains:  project.ExcludeContains,
+		MaxFileBytes:     project.MaxSourceFileBytes,
+		FollowSymlinks:   project.FollowSymlinks,
+	}
+}
+
+// hasSourceExtension reports whether path ends with one of extensions,
+// or with .go when extensions is empty.
+func hasSourceExtension(path string, extensions []string) bool {
+	if len(extensions) == 0 {
+		extensions = defaultSourceExtensions
+	}
+	for _, ext := range extensions {
+		if ext != "" && strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadSourceFiles walks the given directories, relative to opts.Root
+// (GH-563), and reads all files whose suffix is one of opts.Extensions
+// (.go by default, GH-506), returning them sorted by path for
+// deterministic prompt output. Files are read and
+// line-numbered by a bounded pool of workers (runtime.NumCPU() when
+// opts.Workers <= 0, GH-504); unreadable files are logged and skipped.
+// When opts.RespectGitignore is set, untracked files that .gitignore
+// rules match are skipped (GH-505). When opts.StripComments is set, .go
+// files pass through stripGoComments first (GH-507). When opts.Order is
+// SourceOrderDependency, the path-sorted files are regrouped by package
+// in dependency order (GH-550). Files whose header contains one of
+// opts.ExcludeContains are skipped; the check reuses the bytes already
+// read, so no file is read twice (GH-554).
+func loadSourceFiles(dirs []string, opts sourceLoadOptions) []SourceFile {
+	var paths []string
+	addPath := func(path string, info os.FileInfo) {
+		if !hasSourceExtension(path, opts.Extensions) {
+			return
+		}
+		if opts.MaxFileBytes > 0 && info.Size() > opts.MaxFileBytes {
+			logf("loadSourceFiles: skipping %s (%d bytes exceeds max %d)", path, info.Size(), opts.MaxFileBytes)
+			return
+		}
+		paths = append(paths, path)
+	}
+	if opts.FollowSymlinks {
+		walkFollowingSymlinks(dirs, opts.Root, addPath)
+	} else {
+		for _, dir := range dirs {
+			err := filepath.Walk(rootedPath(opts.Root, dir), func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return nil
+				}
+				if info.IsDir() {
+					return nil
+				}
+				addPath(relToRoot(opts.Root, path), info)
+				return nil
+			})
+			if err != nil {
+				logf("loadSourceFiles: walk error for %s: %v", dir, err)
+			}
+		}
+	}
+
+	if opts.RespectGitignore {
+		paths = dropGitignored(opts.Root, paths)
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+	results := make([]*SourceFile, len(paths))
+	excluded := make([]bool, len(paths))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				data, readErr := readContextFile(rootedPath(opts.Root, paths[i]), opts.Errs)
+				if readErr != nil {
+					logf("loadSourceFiles: read error for %s: %v", paths[i], readErr)
+					continue
+				}
+				if m := headerContainsAny(data, opts.ExcludeContains); m != "" {
+					logf("loadSourceFiles: skipping %s (header contains %q)", paths[i], m)
+					excluded[i] = true
+					continue
+				}
+				if !utf8.Valid(data) {
+					logf("loadSourceFiles: skipping %s (binary or invalid UTF-8)", paths[i])
+					continue
+				}
+				content := string(data)
+				if opts.StripComments && strings.HasSuffix(paths[i], ".go") {
+					content = stripGoComments(content)
+				}
+				results[i] = &SourceFile{
+					File:  paths[i],
+					Lines: numberLines(content),
+				}
+			}
+		}()
+	}
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var files []SourceFile
+	for _, sf := range results {
+		if sf != nil {
+			files = append(files, *sf)
+		}
+	}
+	skipped := 0
+	for _, e := range excluded {
+		if e {
+			skipped++
+		}
+	}
+	if skipped > 0 {
+		logf("loadSourceFiles: skipped %d file(s) by exclude_contains", skipped)
+	}
 	sort.Slice(files, func(i, j int) bool { return files[i].File < files[j].File })
-	logf("loadSourceFiles: %d file(s) from %d dir(s)", len(files), len(dirs))
+	if opts.Order == SourceOrderDependency {
+		files = orderSourcesByDependency(opts.Root, files, dirs)
+	}
+	logf("loadSourceFiles: %d file(s) from %d dir(s) with %d worker(s)", len(files), len(dirs), workers)
 	return files
 }
 
+// orderSourcesByDependency groups files by directory (package) and orders
+// the packages so each comes after the local packages it imports (GH-550).
+// Imports are read from the .go files on disk with parser.ImportsOnly; an
+// import path refers to a loaded package when it ends with that package's
+// directory, either as loaded or relative to the root in roots it was
+// walked from, at a path-segment boundary. Standard-library paths (no dot
+// in the first element) never match unless they fall under the module in
+// root's go.mod, so a local pkg/errors is not mistaken for errors. Packages
+// are visited in path order and import cycles are broken at the first back
+// edge, so the result is deterministic. Within a package, files keep their
+// path order. File paths are relative to root.
+func orderSourcesByDependency(root string, files []SourceFile, roots []string) []SourceFile {
+	byDir := make(map[string][]SourceFile)
+	keys := make(map[string][]string)
+	var dirs []string
+	for _, sf := range files {
+		d := filepath.Dir(sf.File)
+		if _, seen := byDir[d]; !seen {
+			dirs = append(dirs, d)
+			keys[d] = packageImportKeys(d, roots)
+		}
+		byDir[d] = append(byDir[d], sf)
+	}
+	sort.Strings(dirs)
+
+	modulePath := goModModulePath(root)
+	deps := make(map[string][]string, len(dirs))
+	fset := token.NewFileSet()
+	for _, d := range dirs {
+		seen := make(map[string]bool)
+		for _, sf := range byDir[d] {
+			if !strings.HasSuffix(sf.File, ".go") {
+				continue
+			}
+			f, err := parser.ParseFile(fset, rootedPath(root, sf.File), nil, parser.ImportsOnly)
+			if err != nil {
+				logf("orderSourcesByDependency: parse imports of %s: %v", sf.File, err)
+				continue
+			}
+			for _, imp := range f.Imports {
+				if dep := localImportDir(strings.Trim(imp.Path.Value, `"`), modulePath, dirs, keys); dep != "" && dep != d && !seen[dep] {
+					seen[dep] = true
+					deps[d] = append(deps[d], dep)
+				}
+			}
+		}
+		sort.Strings(deps[d])
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(dirs))
+	ordered := make([]SourceFile, 0, len(files))
+	var visit func(d string)
+	visit = func(d string) {
+		if state[d] != 0 {
+			return
+		}
+		state[d] = visiting
+		for _, dep := range deps[d] {
+			visit(dep)
+		}
+		state[d] = done
+		ordered = append(ordered, byDir[d]...)
+	}
+	for _, d := range dirs {
+		visit(d)
+	}
+	return ordered
+}
+
+// packageImportKeys returns the slash-separated forms of dir that an
+// import path may end with: dir itself and dir relative to each root
+// containing it. The root itself yields no relative key.
+func packageImportKeys(dir string, roots []string) []string {
+	keys := []string{filepath.ToSlash(filepath.Clean(dir))}
+	for _, root := range roots {
+		rel, err := filepath.Rel(filepath.Clean(root), dir)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		keys = append(keys, filepath.ToSlash(rel))
+	}
+	return keys
+}
+
+// localImportDir returns the entry of dirs that importPath refers to: the
+// one with the longest key (from packageImportKeys) equal to importPath or
+// ending it after a slash. Returns "" for imports outside the loaded
+// sources and for standard-library imports, whose first path element has
+// no dot, unless they fall under modulePath.
+func localImportDir(importPath, modulePath string, dirs []string, keys map[string][]string) string {
+	inModule := modulePath != "" && (importPath == modulePath || strings.HasPrefix(importPath, modulePath+"/"))
+	if first, _, _ := strings.Cut(importPath, "/"); !inModule && !strings.Contains(first, ".") {
+		return ""
+	}
+	best, bestLen := "", 0
+	for _, d := range dirs {
+		for _, k := range keys[d] {
+			if k == "." || len(k) <= bestLen {
+				continue
+			}
+			if importPath == k || strings.HasSuffix(importPath, "/"+k) {
+				best, bestLen = d, len(k)
+			}
+		}
+	}
+	return best
+}
+
+// dropGitignored removes the paths, relative to root, that git reports as
+// ignored. Outside a git repository the paths are returned unchanged.
+// Paths reached through a followed symlink are checked at their resolved
+// location, since git refuses pathspecs beyond a symbolic link (GH-558).
+func dropGitignored(root string, paths []string) []string {
+	checked := make([]string, len(paths))
+	for i, p := range paths {
+		checked[i] = gitignoreCheckPath(root, p)
+	}
+	ignored, err := gitCheckIgnored(root, checked)
+	if err != nil {
+		logf("loadSourceFiles: gitignore filtering skipped: %v", err)
+		return paths
+	}
+	if len(ignored) == 0 {
+		return paths
+	}
+	kept := paths[:0]
+	dropped := 0
+	for i, p := range paths {
+		if ignored[checked[i]] {
+			dropped++
+			continue
+		}
+		kept = append(kept, p)
+	}
+	logf("loadSourceFiles: skipped %d gitignored file(s)", dropped)
+	return kept
+}
+
+// gitignoreCheckPath returns the root-relative real path of path when a
+// symlink lies on the way to it, and path unchanged otherwise or when
+// the real path is outside root.
+func gitignoreCheckPath(root, path string) string {
+	if root == "" {
+		root = "."
+	}
+	real, err := filepath.EvalSymlinks(rootedPath(root, path))
+	if err != nil {
+		return path
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return path
+	}
+	realRoot, _ = filepath.Abs(realRoot)
+	real, _ = filepath.Abs(real)
+	rel, err := filepath.Rel(realRoot, real)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
+
 // ---------------------------------------------------------------------------
 // Context source resolution
 // ---------------------------------------------------------------------------
@@ -1180,22 +2044,63 @@ func parseContextSources(text string) []string {
 	return patterns
 }
 
-// resolveContextSources expands glob patterns from ContextSources into
-// a deduplicated, sorted list of real file paths. Duplicate files
-// (matched by multiple patterns) are logged and removed.
-func resolveContextSources(sources string) []string {
+// globFromRoot expands pattern against root rather than the working
+// directory and returns the matches relative to root, so they compare
+// equal to the root-relative paths used throughout the project context
+// (GH-563). Absolute patterns are expanded as given and returned
+// unchanged.
+func globFromRoot(root, pattern string) ([]string, error) {
+	if filepath.IsAbs(pattern) {
+		return filepath.Glob(pattern)
+	}
+	matches, err := filepath.Glob(filepath.Join(root, pattern))
+	for i, m := range matches {
+		if rel, relErr := filepath.Rel(root, m); relErr == nil {
+			matches[i] = rel
+		}
+	}
+	return matches, err
+}
+
+// rootedPath returns path as seen from the working directory when it is
+// relative to root.
+func rootedPath(root, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(root, path)
+}
+
+// relToRoot is the inverse of rootedPath: it returns path relative to
+// root, or path unchanged when root is empty or path lies outside it.
+func relToRoot(root, path string) string {
+	if root == "" {
+		return path
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
+
+// resolveContextSources expands glob patterns from ContextSources,
+// relative to root, into a deduplicated, sorted list of real file paths
+// relative to root. Duplicate files (matched by multiple patterns) are
+// logged and removed.
+func resolveContextSources(root, sources string) []string {
 	patterns := parseContextSources(sources)
 	seen := make(map[string]string) // path -> first pattern that matched
 	var files []string
 
 	for _, pattern := range patterns {
-		matches, err := filepath.Glob(pattern)
+		matches, err := globFromRoot(root, pattern)
 		if err != nil {
 			logf("resolveContextSources: bad glob %q: %v", pattern, err)
 			continue
 		}
 		for _, path := range matches {
-			if _, err := os.Stat(path); err != nil {
+			if _, err := os.Stat(rootedPath(root, path)); err != nil {
 				continue
 			}
 			if prev, dup := seen[path]; dup {
@@ -1212,28 +2117,35 @@ func resolveContextSources(sources string) []string {
 	return files
 }
 
-// resolveFileSet expands newline-delimited glob patterns into a set of
-// file paths. Directory matches are walked recursively so that excluding
-// a directory excludes all files underneath it.
-func resolveFileSet(text string) map[string]bool {
+// resolveFileSet expands newline-delimited glob patterns, relative to
+// root, into a set of file paths relative to root. Directory matches are
+// walked recursively so that excluding a directory excludes all files
+// underneath it.
+func resolveFileSet(root, text string) map[string]bool {
 	patterns := parseContextSources(text)
 	set := make(map[string]bool)
 	for _, pattern := range patterns {
-		matches, err := filepath.Glob(pattern)
+		matches, err := globFromRoot(root, pattern)
 		if err != nil {
 			logf("resolveFileSet: bad glob %q: %v", pattern, err)
 			continue
 		}
 		for _, m := range matches {
-			info, err := os.Stat(m)
+			info, err := os.Stat(rootedPath(root, m))
 			if err != nil {
 				continue
 			}
 			if info.IsDir() {
-				filepath.WalkDir(m, func(p string, d fs.DirEntry, err error) error {
-					if err == nil && !d.IsDir() {
-						set[p] = true
+				filepath.WalkDir(rootedPath(root, m), func(p string, d fs.DirEntry, err error) error {
+					if err != nil || d.IsDir() {
+						return nil
 					}
+					if !filepath.IsAbs(m) {
+						if rel, relErr := filepath.Rel(root, p); relErr == nil {
+							p = rel
+						}
+					}
+					set[p] = true
 					return nil
 				})
 			} else {
@@ -1252,6 +2164,11 @@ func classifyContextFile(path string) string {
 	dir := filepath.Dir(path)
 	base := filepath.Base(path)
 
+	// .yml is equivalent to .yaml for the typed top-level docs (GH-501).
+	if ext := filepath.Ext(base); ext == ".yml" {
+		base = strings.TrimSuffix(base, ext) + ".yaml"
+	}
+
 	switch {
 	case dir == "docs" && base == "VISION.yaml":
 		return "vision"
@@ -1286,7 +2203,8 @@ func classifyContextFile(path string) string {
 // documentation structure. These are loaded automatically by
 // resolveStandardFiles. Does NOT include docs/constitutions/*.yaml
 // (constitutions are injected separately as top-level prompt keys)
-// or docs/*.yaml (catchall that pulled in utilities.yaml).
+// or docs/*.yaml (catchall that pulled in utilities.yaml). Each pattern
+// also matches the .yml spelling (GH-501).
 var standardContextPatterns = []string{
 	"docs/VISION.yaml",
 	"docs/ARCHITECTURE.yaml",
@@ -1299,6 +2217,34 @@ var standardContextPatterns = []string{
 	"docs/specs/sources.yaml",
 }
 
+// ymlVariant returns path with its .yaml extension replaced by .yml, or
+// path unchanged when it does not end in .yaml.
+func ymlVariant(path string) string {
+	if stem, ok := strings.CutSuffix(path, ".yaml"); ok {
+		return stem + ".yml"
+	}
+	return path
+}
+
+// dedupeYAMLExtensions drops a .yml file when the same path with a .yaml
+// extension is also in files, so a project mixing both spellings never
+// loads one logical document twice. Order is preserved.
+func dedupeYAMLExtensions(files []string) []string {
+	present := make(map[string]bool, len(files))
+	for _, f := range files {
+		present[f] = true
+	}
+	var out []string
+	for _, f := range files {
+		if stem, ok := strings.CutSuffix(f, ".yml"); ok && present[stem+".yaml"] {
+			logf("dedupeYAMLExtensions: skipping %s, %s takes precedence", f, stem+".yaml")
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
 // typedDocPaths lists documents that must always be loaded through their
 // dedicated typed parsers (VisionDoc, ArchitectureDoc, etc.), even when
 // context_include replaces the standard discovery. If context_include
@@ -1312,37 +2258,42 @@ var typedDocPaths = []string{
 }
 
 // ensureTypedDocs merges the always-load typed document paths into the
-// file list if they exist on disk but are not already present.
-func ensureTypedDocs(files []string) []string {
+// file list if they exist under root but are not already present.
+func ensureTypedDocs(root string, files []string) []string {
 	present := make(map[string]bool, len(files))
 	for _, f := range files {
 		present[f] = true
 	}
 	for _, path := range typedDocPaths {
-		if present[path] {
+		if present[path] || present[ymlVariant(path)] {
 			continue
 		}
-		if _, err := os.Stat(path); err == nil {
-			files = append(files, path)
-			logf("ensureTypedDocs: added missing typed doc %s", path)
+		for _, candidate := range []string{path, ymlVariant(path)} {
+			if _, err := os.Stat(rootedPath(root, candidate)); err == nil {
+				files = append(files, candidate)
+				logf("ensureTypedDocs: added missing typed doc %s", candidate)
+				break
+			}
 		}
 	}
 	return files
 }
 
-// resolveStandardFiles expands standardContextPatterns into a
-// deduplicated, sorted list of real file paths.
-func resolveStandardFiles() []string {
+// resolveStandardFiles expands standardContextPatterns against root into
+// a deduplicated, sorted list of real file paths relative to root.
+func resolveStandardFiles(root string) []string {
 	seen := make(map[string]bool)
 	var files []string
 	for _, pattern := range standardContextPatterns {
-		matches, err := filepath.Glob(pattern)
+		matches, err := globFromRoot(root, pattern)
 		if err != nil {
 			logf("resolveStandardFiles: bad glob %q: %v", pattern, err)
 			continue
 		}
+		ymlMatches, _ := globFromRoot(root, ymlVariant(pattern))
+		matches = append(matches, ymlMatches...)
 		for _, path := range matches {
-			if _, err := os.Stat(path); err != nil {
+			if _, err := os.Stat(rootedPath(root, path)); err != nil {
 				continue
 			}
 			if seen[path] {
@@ -1353,6 +2304,7 @@ func resolveStandardFiles() []string {
 		}
 	}
 	sort.Strings(files)
+	files = dedupeYAMLExtensions(files)
 	logf("resolveStandardFiles: %d pattern(s) -> %d file(s)", len(standardContextPatterns), len(files))
 	return files
 }
@@ -1361,10 +2313,12 @@ func resolveStandardFiles() []string {
 // based on their release version. When ReleaseSet is non-nil, only files
 // whose extracted release is in the set pass. When ReleaseSet is nil but
 // MaxRelease is non-empty, the legacy <= comparison is used. When both are
-// empty/nil, all files pass (no filtering).
+// empty/nil, all files pass (no filtering). Releases in Excluded never
+// pass, regardless of the set or ceiling.
 type releaseFilter struct {
 	ReleaseSet map[string]bool // explicit set of in-scope releases
 	MaxRelease string          // legacy: include files with release <= this value
+	Excluded   map[string]bool // releases dropped on top of the set or ceiling
 }
 
 // newReleaseFilter builds a releaseFilter from the effective config fields.
@@ -1380,7 +2334,24 @@ func newReleaseFilter(releases []string, release string) releaseFilter {
 	return releaseFilter{MaxRelease: release}
 }
 
-// active reports whether any release filtering is in effect.
+// excluding returns a copy of rf that also rejects the given releases.
+// Blank entries are ignored.
+func (rf releaseFilter) excluding(releases []string) releaseFilter {
+	for _, r := range releases {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+		if rf.Excluded == nil {
+			rf.Excluded = make(map[string]bool, len(releases))
+		}
+		rf.Excluded[r] = true
+	}
+	return rf
+}
+
+// active reports whether release set or ceiling filtering is in effect.
+// Exclusions alone do not activate it, so PRD loading is unaffected.
 func (rf releaseFilter) active() bool {
 	return len(rf.ReleaseSet) > 0 || rf.MaxRelease != ""
 }
@@ -1397,27 +2368,34 @@ func extractFileRelease(path string) string {
 			return rest[:idx]
 		}
 	case strings.HasPrefix(base, "test-rel"):
-		// test-rel01.0.yaml → extract "01.0"
+		// test-rel01.0.yaml (or .yml) → extract "01.0"
 		rest := strings.TrimPrefix(base, "test-rel")
-		return strings.TrimSuffix(rest, ".yaml")
+		return strings.TrimSuffix(strings.TrimSuffix(rest, ".yaml"), ".yml")
 	}
 	return ""
 }
 
 // fileMatchesRelease returns true if the file's release passes the filter.
-// Returns true if no filter is active or if the file's release cannot be
-// determined. String comparison works for the zero-padded "NN.N" format.
+// Excluded releases are rejected first. Returns true if no filter is
+// active or if the file's release cannot be determined. String comparison
+// works for the zero-padded "NN.N" format.
 func fileMatchesRelease(path string, rf releaseFilter) bool {
-	if !rf.active() {
+	if !rf.active() && len(rf.Excluded) == 0 {
 		return true
 	}
 	fileRelease := extractFileRelease(path)
 	if fileRelease == "" {
 		return true
 	}
+	if rf.Excluded[fileRelease] {
+		return false
+	}
 	if rf.ReleaseSet != nil {
 		return rf.ReleaseSet[fileRelease]
 	}
+	if rf.MaxRelease == "" {
+		return true
+	}
 	return fileRelease <= rf.MaxRelease
 }
 
@@ -1606,26 +2584,27 @@ func parseTouchpointPackages(touchpoints []map[string]string) []string {
 // loadContextFileInto loads a single file into the appropriate field
 // of ctx based on its classified category. Applies release filtering
 // for use_case and test_suite categories. Does not handle constitution
-// or extra categories.
-func loadContextFileInto(ctx *ProjectContext, path string, rf releaseFilter) {
+// or extra categories. path is relative to root; read failures are
+// recorded in errs.
+func loadContextFileInto(ctx *ProjectContext, root, path string, rf releaseFilter, errs *contextLoadErrors) {
 	switch classifyContextFile(path) {
 	case "vision":
-		if v := loadYAML[VisionDoc](path); v != nil {
+		if v := loadYAMLFrom[VisionDoc](root, path, errs); v != nil {
 			v.File = path
 			ctx.Vision = v
 		}
 	case "architecture":
-		if v := loadYAML[ArchitectureDoc](path); v != nil {
+		if v := loadYAMLFrom[ArchitectureDoc](root, path, errs); v != nil {
 			v.File = path
 			ctx.Architecture = v
 		}
 	case "specifications":
-		if v := loadYAML[SpecificationsDoc](path); v != nil {
+		if v := loadYAMLFrom[SpecificationsDoc](root, path, errs); v != nil {
 			v.File = path
 			ctx.Specifications = v
 		}
 	case "roadmap":
-		if v := loadYAML[RoadmapDoc](path); v != nil {
+		if v := loadYAMLFrom[RoadmapDoc](root, path, errs); v != nil {
 			v.File = path
 			ctx.Roadmap = v
 		}
@@ -1633,7 +2612,7 @@ func loadContextFileInto(ctx *ProjectContext, path string, rf releaseFilter) {
 		if !fileMatchesRelease(path, rf) {
 			return
 		}
-		if v := loadYAML[UseCaseDoc](path); v != nil {
+		if v := loadYAMLFrom[UseCaseDoc](root, path, errs); v != nil {
 			v.File = path
 			ctx.Specs.UseCases = append(ctx.Specs.UseCases, v)
 		}
@@ -1641,12 +2620,12 @@ func loadContextFileInto(ctx *ProjectContext, path string, rf releaseFilter) {
 		if !fileMatchesRelease(path, rf) {
 			return
 		}
-		if v := loadYAML[TestSuiteDoc](path); v != nil {
+		if v := loadYAMLFrom[TestSuiteDoc](root, path, errs); v != nil {
 			v.File = path
 			ctx.Specs.TestSuites = append(ctx.Specs.TestSuites, v)
 		}
 	case "spec_aux":
-		if v := loadNamedDoc(path); v != nil {
+		if v := loadNamedDoc(root, path, errs); v != nil {
 			v.File = path
 			switch filepath.Base(path) {
 			case "dependency-map.yaml":
@@ -1658,18 +2637,134 @@ func loadContextFileInto(ctx *ProjectContext, path string, rf releaseFilter) {
 			}
 		}
 	case "engineering":
-		if v := loadYAML[EngineeringDoc](path); v != nil {
+		if v := loadYAMLFrom[EngineeringDoc](root, path, errs); v != nil {
 			v.File = path
 			ctx.Engineering = append(ctx.Engineering, v)
 		}
 	case "extra":
-		if v := loadNamedDoc(path); v != nil {
+		if v := loadNamedDoc(root, path, errs); v != nil {
 			v.File = path
 			ctx.Extra = append(ctx.Extra, v)
 		}
 	}
 }
 
+// contextDocFiles returns the paths of every document loaded into ctx.
+func contextDocFiles(ctx *ProjectContext) map[string]bool {
+	files := make(map[string]bool)
+	add := func(path string) {
+		if path != "" {
+			files[filepath.Clean(path)] = true
+		}
+	}
+	if ctx.Vision != nil {
+		add(ctx.Vision.File)
+	}
+	if ctx.Architecture != nil {
+		add(ctx.Architecture.File)
+	}
+	if ctx.Specifications != nil {
+		add(ctx.Specifications.File)
+	}
+	if ctx.Roadmap != nil {
+		add(ctx.Roadmap.File)
+	}
+	if ctx.Specs != nil {
+		for _, d := range ctx.Specs.ProductRequirements {
+			add(d.File)
+		}
+		for _, d := range ctx.Specs.UseCases {
+			add(d.File)
+		}
+		for _, d := range ctx.Specs.TestSuites {
+			add(d.File)
+		}
+		if ctx.Specs.DependencyMap != nil {
+			add(ctx.Specs.DependencyMap.File)
+		}
+		if ctx.Specs.Sources != nil {
+			add(ctx.Specs.Sources.File)
+		}
+	}
+	for _, d := range ctx.Engineering {
+		add(d.File)
+	}
+	for _, d := range ctx.Extra {
+		add(d.File)
+	}
+	return files
+}
+
+// requiredDocPath extracts a documentation path from a required_reading
+// entry, dropping parenthetical notes, trailing words, and #fragments.
+// Returns "" for source files and entries that do not name a .yaml,
+// .yml, or .md file.
+func requiredDocPath(entry string) string {
+	fields := strings.Fields(stripParenthetical(entry))
+	if len(fields) == 0 {
+		return ""
+	}
+	path, _, _ := strings.Cut(fields[0], "#")
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml", ".md":
+		return filepath.Clean(path)
+	}
+	return ""
+}
+
+// includeRequiredDocs cross-checks the docs named in a task's
+// required_reading against what ctx actually holds (GH-491). A doc that
+// was filtered out (context exclude, context_include, or release scoping)
+// is loaded into ctx regardless of those rules and recorded in
+// RequiredDocOverrides; a doc that cannot be loaded is recorded in
+// MissingRequiredDocs and logged as a warning, since the agent will be
+// told to read something its prompt does not contain. Docs are read
+// relative to root.
+func includeRequiredDocs(ctx *ProjectContext, root string, requiredReading []string) {
+	if ctx == nil {
+		return
+	}
+	present := contextDocFiles(ctx)
+	for _, entry := range requiredReading {
+		path := requiredDocPath(entry)
+		if path == "" || present[path] {
+			continue
+		}
+		present[path] = true
+
+		if ctx.Specs == nil {
+			ctx.Specs = &SpecsCollection{}
+		}
+		switch classifyContextFile(path) {
+		case "prd":
+			if v := loadYAMLFrom[PRDDoc](root, path, nil); v != nil {
+				v.File = path
+				ctx.Specs.ProductRequirements = append(ctx.Specs.ProductRequirements, v)
+			}
+		case "constitution":
+			if v := loadNamedDoc(root, path, nil); v != nil {
+				v.File = path
+				ctx.Extra = append(ctx.Extra, v)
+			}
+		default:
+			loadContextFileInto(ctx, root, path, releaseFilter{}, nil)
+		}
+		if ctx.Specs.ProductRequirements == nil && ctx.Specs.UseCases == nil &&
+			ctx.Specs.TestSuites == nil && ctx.Specs.DependencyMap == nil &&
+			ctx.Specs.Sources == nil {
+			ctx.Specs = nil
+		}
+
+		if contextDocFiles(ctx)[path] {
+			logf("includeRequiredDocs: re-included %s from required_reading", path)
+			ctx.RequiredDocOverrides = append(ctx.RequiredDocOverrides, path)
+		} else {
+			logf("includeRequiredDocs: WARNING: required_reading doc %s is not in the context and could not be loaded", path)
+			ctx.MissingRequiredDocs = append(ctx.MissingRequiredDocs, path)
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Assembly
 // ---------------------------------------------------------------------------
@@ -1679,8 +2774,12 @@ func loadContextFileInto(ctx *ProjectContext, path string, rf releaseFilter) {
 // existing issues, and assembles them into a ProjectContext struct.
 // The project config controls include/exclude filtering and release scoping.
 // When phaseCtx is non-nil, its non-empty fields override the corresponding
-// ProjectConfig fields (prd003 R9.5-R9.7).
-func buildProjectContext(existingIssuesJSON string, project ProjectConfig, phaseCtx *PhaseContext) (*ProjectContext, error) {
+// ProjectConfig fields (prd003 R9.5-R9.7). Use cases and test suites whose
+// release is in excludeReleases (Cobbler.ExcludeReleases) are dropped on
+// top of the release set or ceiling. Include, exclude, and source patterns
+// are resolved against root, not the working directory (GH-563).
+func buildProjectContext(root, existingIssuesJSON string, project ProjectConfig, phaseCtx *PhaseContext, excludeReleases []string) (*ProjectContext, error) {
+	errs := &contextLoadErrors{}
 	ctx := &ProjectContext{}
 	ctx.Specs = &SpecsCollection{}
 
@@ -1706,12 +2805,15 @@ func buildProjectContext(existingIssuesJSON string, project ProjectConfig, phase
 			release = phaseCtx.Release
 		}
 	}
-	rf := newReleaseFilter(releases, release)
+	rf := newReleaseFilter(releases, release).excluding(excludeReleases)
+	if len(rf.Excluded) > 0 {
+		logf("buildProjectContext: excluding release(s) %v", excludeReleases)
+	}
 
 	// Compute exclude set when configured.
 	var excludeSet map[string]bool
 	if strings.TrimSpace(ctxExclude) != "" {
-		excludeSet = resolveFileSet(ctxExclude)
+		excludeSet = resolveFileSet(root, ctxExclude)
 		logf("buildProjectContext: exclude set has %d file(s)", len(excludeSet))
 	}
 
@@ -1719,14 +2821,14 @@ func buildProjectContext(existingIssuesJSON string, project ProjectConfig, phase
 	// fall back to the standard document discovery.
 	var docFiles []string
 	if strings.TrimSpace(ctxInclude) != "" {
-		docFiles = resolveContextSources(ctxInclude)
+		docFiles = resolveContextSources(root, ctxInclude)
 		// Ensure core typed documents (Vision, Architecture, Roadmap) are
 		// always present so they go through dedicated parsers rather than
 		// falling into the generic loadNamedDoc path.
-		docFiles = ensureTypedDocs(docFiles)
+		docFiles = dedupeYAMLExtensions(ensureTypedDocs(root, docFiles))
 		logf("buildProjectContext: using context_include (%d file(s))", len(docFiles))
 	} else {
-		docFiles = resolveStandardFiles()
+		docFiles = resolveStandardFiles(root)
 	}
 
 	// Filter through exclude set.
@@ -1749,14 +2851,14 @@ func buildProjectContext(existingIssuesJSON string, project ProjectConfig, phase
 			prdPaths = append(prdPaths, path)
 			continue
 		}
-		loadContextFileInto(ctx, path, rf)
+		loadContextFileInto(ctx, root, path, rf, errs)
 	}
 
 	// Load PRDs filtered by release: when a release filter is active, only
 	// include PRDs referenced by the loaded (release-scoped) use cases.
 	if !rf.active() {
 		for _, path := range prdPaths {
-			if v := loadYAML[PRDDoc](path); v != nil {
+			if v := loadYAMLFrom[PRDDoc](root, path, errs); v != nil {
 				v.File = path
 				ctx.Specs.ProductRequirements = append(ctx.Specs.ProductRequirements, v)
 			}
@@ -1766,7 +2868,7 @@ func buildProjectContext(existingIssuesJSON string, project ProjectConfig, phase
 		for _, path := range prdPaths {
 			stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
 			if referencedPRDs[stem] {
-				if v := loadYAML[PRDDoc](path); v != nil {
+				if v := loadYAMLFrom[PRDDoc](root, path, errs); v != nil {
 					v.File = path
 					ctx.Specs.ProductRequirements = append(ctx.Specs.ProductRequirements, v)
 				}
@@ -1777,7 +2879,7 @@ func buildProjectContext(existingIssuesJSON string, project ProjectConfig, phase
 	// Load extras from contextSources (if non-empty), skipping files
 	// already in the standard set and files in the exclude set.
 	if ctxSources != "" {
-		extras := resolveContextSources(ctxSources)
+		extras := resolveContextSources(root, ctxSources)
 		for _, path := range extras {
 			if standardSet[path] {
 				continue
@@ -1785,7 +2887,7 @@ func buildProjectContext(existingIssuesJSON string, project ProjectConfig, phase
 			if excludeSet != nil && excludeSet[path] {
 				continue
 			}
-			if v := loadNamedDoc(path); v != nil {
+			if v := loadNamedDoc(root, path, errs); v != nil {
 				v.File = path
 				ctx.Extra = append(ctx.Extra, v)
 			}
@@ -1804,11 +2906,18 @@ func buildProjectContext(existingIssuesJSON string, project ProjectConfig, phase
 	if excludeSource {
 		logf("buildProjectContext: source excluded (exclude_source=true)")
 	} else {
-		ctx.SourceCode = loadSourceFiles(project.GoSourceDirs)
+		sourceDirs := phaseCtx.sourceDirs(project.GoSourceDirs)
+		if phaseCtx != nil && phaseCtx.GoSourceDirs != "" {
+			logf("buildProjectContext: go_source_dirs %v from phase context", sourceDirs)
+		}
+		opts := sourceLoadOptionsFor(project)
+		opts.Root = root
+		opts.Errs = errs
+		ctx.SourceCode = loadSourceFiles(sourceDirs, opts)
 
 		// Apply glob-pattern source filter when SourcePatterns is set (GH-565).
 		if phaseCtx != nil && phaseCtx.SourcePatterns != "" {
-			allowSet := resolveFileSet(phaseCtx.SourcePatterns)
+			allowSet := resolveFileSet(root, phaseCtx.SourcePatterns)
 			logf("buildProjectContext: source_patterns allow set has %d file(s)", len(allowSet))
 			var filtered []SourceFile
 			for _, sf := range ctx.SourceCode {
@@ -1852,7 +2961,7 @@ func buildProjectContext(existingIssuesJSON string, project ProjectConfig, phase
 		if phaseCtx != nil && phaseCtx.SourceMode != "" && phaseCtx.SourceMode != "full" {
 			var summarized []SourceFile
 			for _, sf := range ctx.SourceCode {
-				raw, readErr := os.ReadFile(sf.File)
+				raw, readErr := os.ReadFile(rootedPath(root, sf.File))
 				if readErr != nil {
 					logf("buildProjectContext: cannot re-read %s for summarization: %v, using full", sf.File, readErr)
 					summarized = append(summarized, sf)
@@ -1863,7 +2972,7 @@ func buildProjectContext(existingIssuesJSON string, project ProjectConfig, phase
 				case "headers":
 					content = summarizeGoHeaders(string(raw))
 				case "custom":
-					content = summarizeCustom(phaseCtx.SummarizeCommand, sf.File, string(raw))
+					content = summarizeCustom(phaseCtx.SummarizeCommand, root, sf.File, string(raw))
 				default:
 					logf("buildProjectContext: unknown source_mode %q for %s, using full", phaseCtx.SourceMode, sf.File)
 					summarized = append(summarized, sf)
@@ -1884,6 +2993,17 @@ func buildProjectContext(existingIssuesJSON string, project ProjectConfig, phase
 	// Load pre-cycle analysis results if present in the scratch directory.
 	ctx.Analysis = loadAnalysisDoc(dirCobbler)
 
+	// Surface files lost to read errors so neither the operator nor the
+	// agent mistakes an incomplete context for a complete one (GH-485).
+	ctx.ReadErrors = errs.read
+	if len(ctx.ReadErrors) > 0 {
+		logf("buildProjectContext: warning: %d file(s) dropped due to read errors: %v", len(ctx.ReadErrors), ctx.ReadErrors)
+	}
+	ctx.ParseErrors = errs.parse
+	for _, pe := range ctx.ParseErrors {
+		logf("buildProjectContext: warning: %s dropped, does not parse: %s", pe.File, pe.Error)
+	}
+
 	logf("buildProjectContext: vision=%v arch=%v roadmap=%v specs=%v eng=%d analysis=%v issues=%d extra=%d src=%d files=%d",
 		ctx.Vision != nil,
 		ctx.Architecture != nil,
@@ -1899,6 +3019,98 @@ func buildProjectContext(existingIssuesJSON string, project ProjectConfig, phase
 	return ctx, nil
 }
 
+// projectContextCache holds one built ProjectContext for reuse across
+// measure iterations (GH-509). Only the Issues field changes between
+// iterations, so documents and source are loaded once per key and each
+// lookup returns a shallow copy with freshly parsed issues.
+type projectContextCache struct {
+	key string
+	ctx *ProjectContext
+}
+
+// projectContextCacheKey identifies the inputs buildProjectContext reads:
+// the repository HEAD, the project and phase context settings, the
+// excluded releases, and the resolved include and exclude file sets.
+// Returns "" when HEAD cannot be resolved, which disables caching.
+func projectContextCacheKey(root string, project ProjectConfig, phaseCtx *PhaseContext, excludeReleases []string) string {
+	head, err := gitRevParseHEAD(".")
+	if err != nil {
+		return ""
+	}
+	settings, err := yaml.Marshal(struct {
+		Project         ProjectConfig
+		Phase           *PhaseContext
+		ExcludeReleases []string
+	}{project, phaseCtx, excludeReleases})
+	if err != nil {
+		return ""
+	}
+	include, exclude := project.ContextInclude, project.ContextExclude
+	if phaseCtx != nil {
+		if phaseCtx.Include != "" {
+			include = phaseCtx.Include
+		}
+		if phaseCtx.Exclude != "" {
+			exclude = phaseCtx.Exclude
+		}
+	}
+	var excluded []string
+	for f := range resolveFileSet(root, exclude) {
+		excluded = append(excluded, f)
+	}
+	sort.Strings(excluded)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n", head, settings)
+	fmt.Fprintf(h, "include:%s\n", strings.Join(resolveContextSources(root, include), "\n"))
+	fmt.Fprintf(h, "exclude:%s\n", strings.Join(excluded, "\n"))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// build returns the cached context for the current inputs, rebuilding it
+// when the key changed (for example, HEAD moved mid-run) or nothing is
+// cached yet. A nil cache always builds. The returned context is a copy,
+// so callers may replace or trim its fields without touching the cached
+// one.
+func (c *projectContextCache) build(root, existingIssuesJSON string, project ProjectConfig, phaseCtx *PhaseContext, excludeReleases []string) (*ProjectContext, error) {
+	if c == nil {
+		return buildProjectContext(root, existingIssuesJSON, project, phaseCtx, excludeReleases)
+	}
+	key := projectContextCacheKey(root, project, phaseCtx, excludeReleases)
+	if key == "" || key != c.key || c.ctx == nil {
+		ctx, err := buildProjectContext(root, existingIssuesJSON, project, phaseCtx, excludeReleases)
+		if err != nil || key == "" {
+			return ctx, err
+		}
+		if c.ctx != nil {
+			logf("projectContextCache: inputs changed (HEAD or context files), rebuilt project context")
+		}
+		c.key, c.ctx = key, ctx
+		return ctx.clone(), nil
+	}
+	cp := c.ctx.clone()
+	cp.Issues = parseIssuesJSON(existingIssuesJSON)
+	logf("projectContextCache: reusing project context, refreshed %d issue(s)", len(cp.Issues))
+	return cp, nil
+}
+
+// clone returns a copy of ctx whose slice fields have their own backing
+// arrays, so trimming the copy in place (trimContextToBudget) leaves the
+// cached context intact. Document pointers are shared.
+func (ctx *ProjectContext) clone() *ProjectContext {
+	cp := *ctx
+	cp.Engineering = slices.Clone(ctx.Engineering)
+	cp.SourceCode = slices.Clone(ctx.SourceCode)
+	cp.Issues = slices.Clone(ctx.Issues)
+	cp.CompletedWork = slices.Clone(ctx.CompletedWork)
+	cp.Extra = slices.Clone(ctx.Extra)
+	cp.ReadErrors = slices.Clone(ctx.ReadErrors)
+	cp.RequiredDocOverrides = slices.Clone(ctx.RequiredDocOverrides)
+	cp.MissingRequiredDocs = slices.Clone(ctx.MissingRequiredDocs)
+	cp.ParseErrors = slices.Clone(ctx.ParseErrors)
+	return &cp
+}
+
 // ---------------------------------------------------------------------------
 // Required-field validation
 //
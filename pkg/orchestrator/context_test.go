@@ -4,8 +4,13 @@
 package orchestrator
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
 	"strings"
 	"testing"
 
@@ -111,7 +116,7 @@ func TestBuildProjectContext_PhaseContextOverride(t *testing.T) {
 		Include: "docs/custom.yaml",
 	}
 
-	ctx, err := buildProjectContext("", project, phaseCtx)
+	ctx, err := buildProjectContext(".", "", project, phaseCtx, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -131,7 +136,7 @@ func TestBuildProjectContext_NilPhaseContextUsesConfig(t *testing.T) {
 		GoSourceDirs: []string{"pkg/"},
 	}
 
-	ctx, err := buildProjectContext("", project, nil)
+	ctx, err := buildProjectContext(".", "", project, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -156,7 +161,7 @@ func TestBuildProjectContext_PhaseContextPartialOverride(t *testing.T) {
 		Include: "docs/VISION.yaml",
 	}
 
-	ctx, err := buildProjectContext("", project, phaseCtx)
+	ctx, err := buildProjectContext(".", "", project, phaseCtx, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -296,6 +301,38 @@ func TestFileMatchesRelease_ReleaseSet(t *testing.T) {
 	}
 }
 
+func TestFileMatchesRelease_Excluded(t *testing.T) {
+	tests := []struct {
+		name string
+		rf   releaseFilter
+		path string
+		want bool
+	}{
+		{"exclusion only", releaseFilter{}.excluding([]string{"01.5"}), "rel01.5-uc002-x.yaml", false},
+		{"exclusion only, other release", releaseFilter{}.excluding([]string{"01.5"}), "rel02.0-uc003-y.yaml", true},
+		{"ceiling and exclusion", releaseFilter{MaxRelease: "02.0"}.excluding([]string{"01.5"}), "rel01.5-uc002-x.yaml", false},
+		{"ceiling and exclusion, below ceiling", releaseFilter{MaxRelease: "02.0"}.excluding([]string{"01.5"}), "rel01.0-uc001-z.yaml", true},
+		{"ceiling and exclusion, above ceiling", releaseFilter{MaxRelease: "02.0"}.excluding([]string{"01.5"}), "test-rel03.0.yaml", false},
+		{"set and exclusion", releaseFilter{ReleaseSet: map[string]bool{"01.5": true}}.excluding([]string{"01.5"}), "test-rel01.5.yaml", false},
+		{"unknown format passes", releaseFilter{}.excluding([]string{"01.5"}), "sources.yaml", true},
+	}
+	for _, tt := range tests {
+		if got := fileMatchesRelease(tt.path, tt.rf); got != tt.want {
+			t.Errorf("%s: fileMatchesRelease(%q) = %v, want %v", tt.name, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestReleaseFilterExcludingKeepsActive(t *testing.T) {
+	rf := releaseFilter{}.excluding([]string{"01.5", " "})
+	if rf.active() {
+		t.Error("exclusions alone should not activate set/ceiling filtering")
+	}
+	if len(rf.Excluded) != 1 {
+		t.Errorf("Excluded = %v, want only 01.5", rf.Excluded)
+	}
+}
+
 func TestNewReleaseFilter(t *testing.T) {
 	// Releases list takes precedence over Release string.
 	rf := newReleaseFilter([]string{"01.0", "02.0"}, "03.0")
@@ -334,6 +371,8 @@ func TestExtractFileRelease(t *testing.T) {
 		{"rel02.0-uc003-future.yaml", "02.0"},
 		{"test-rel01.0.yaml", "01.0"},
 		{"test-rel03.0.yaml", "03.0"},
+		{"test-rel04.0.yml", "04.0"},
+		{"rel05.0-uc002-other.yml", "05.0"},
 		{"docs/specs/use-cases/rel01.0-uc001-feature.yaml", "01.0"},
 		{"something-else.yaml", ""},
 		{"prd001-core.yaml", ""},
@@ -393,7 +432,7 @@ func TestResolveStandardFiles(t *testing.T) {
 		os.WriteFile(f, []byte("id: test"), 0o644)
 	}
 
-	resolved := resolveStandardFiles()
+	resolved := resolveStandardFiles(".")
 
 	// All standard files should be included.
 	resolvedSet := make(map[string]bool)
@@ -427,9 +466,9 @@ func TestLoadContextFileIntoSetsFilePath(t *testing.T) {
 
 	ctx := &ProjectContext{Specs: &SpecsCollection{}}
 	noFilter := releaseFilter{}
-	loadContextFileInto(ctx, "docs/VISION.yaml", noFilter)
-	loadContextFileInto(ctx, "docs/ARCHITECTURE.yaml", noFilter)
-	loadContextFileInto(ctx, "docs/road-map.yaml", noFilter)
+	loadContextFileInto(ctx, ".", "docs/VISION.yaml", noFilter, nil)
+	loadContextFileInto(ctx, ".", "docs/ARCHITECTURE.yaml", noFilter, nil)
+	loadContextFileInto(ctx, ".", "docs/road-map.yaml", noFilter, nil)
 
 	if ctx.Vision == nil || ctx.Vision.File != "docs/VISION.yaml" {
 		t.Errorf("Vision.File = %q, want %q", ctx.Vision.File, "docs/VISION.yaml")
@@ -520,9 +559,9 @@ func TestLoadContextFileInto_SpecAux(t *testing.T) {
 
 	ctx := &ProjectContext{Specs: &SpecsCollection{}}
 	noFilter := releaseFilter{}
-	loadContextFileInto(ctx, filepath.Join("docs", "specs", "dependency-map.yaml"), noFilter)
-	loadContextFileInto(ctx, filepath.Join("docs", "specs", "sources.yaml"), noFilter)
-	loadContextFileInto(ctx, filepath.Join("docs", "specs", "utilities.yaml"), noFilter)
+	loadContextFileInto(ctx, ".", filepath.Join("docs", "specs", "dependency-map.yaml"), noFilter, nil)
+	loadContextFileInto(ctx, ".", filepath.Join("docs", "specs", "sources.yaml"), noFilter, nil)
+	loadContextFileInto(ctx, ".", filepath.Join("docs", "specs", "utilities.yaml"), noFilter, nil)
 
 	if ctx.Specs.DependencyMap == nil {
 		t.Error("Specs.DependencyMap should be set for dependency-map.yaml")
@@ -552,7 +591,7 @@ func TestLoadContextFileInto_Engineering(t *testing.T) {
 
 	ctx := &ProjectContext{Specs: &SpecsCollection{}}
 	noFilter := releaseFilter{}
-	loadContextFileInto(ctx, filepath.Join("docs", "engineering", "eng01-testing.yaml"), noFilter)
+	loadContextFileInto(ctx, ".", filepath.Join("docs", "engineering", "eng01-testing.yaml"), noFilter, nil)
 
 	if len(ctx.Engineering) != 1 {
 		t.Fatalf("Engineering len = %d, want 1", len(ctx.Engineering))
@@ -572,7 +611,7 @@ func TestLoadContextFileInto_Extra(t *testing.T) {
 
 	ctx := &ProjectContext{Specs: &SpecsCollection{}}
 	noFilter := releaseFilter{}
-	loadContextFileInto(ctx, "notes.yaml", noFilter)
+	loadContextFileInto(ctx, ".", "notes.yaml", noFilter, nil)
 
 	if len(ctx.Extra) != 1 {
 		t.Fatalf("Extra len = %d, want 1", len(ctx.Extra))
@@ -683,7 +722,7 @@ func TestContextExclude(t *testing.T) {
 		ContextExclude: "docs/extra.yaml\npkg/app/util.go",
 	}
 
-	ctx, err := buildProjectContext("", project, nil)
+	ctx, err := buildProjectContext(".", "", project, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -725,7 +764,7 @@ func TestContextIncludeReplacesStandard(t *testing.T) {
 		ContextInclude: "docs/custom.yaml",
 	}
 
-	ctx, err := buildProjectContext("", project, nil)
+	ctx, err := buildProjectContext(".", "", project, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -765,7 +804,7 @@ func TestContextExcludeDirectory(t *testing.T) {
 		ContextExclude: "pkg/sub",
 	}
 
-	ctx, err := buildProjectContext("", project, nil)
+	ctx, err := buildProjectContext(".", "", project, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -802,7 +841,7 @@ func TestContextIncludeWithExclude(t *testing.T) {
 		ContextExclude: "docs/inc2.yaml",
 	}
 
-	ctx, err := buildProjectContext("", project, nil)
+	ctx, err := buildProjectContext(".", "", project, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -855,7 +894,7 @@ func TestBuildProjectContext_ReleasesFilter(t *testing.T) {
 		Releases: []string{"01.0", "03.0"},
 	}
 
-	ctx, err := buildProjectContext("", project, nil)
+	ctx, err := buildProjectContext(".", "", project, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -876,6 +915,43 @@ func TestBuildProjectContext_ReleasesFilter(t *testing.T) {
 	}
 }
 
+func TestBuildProjectContext_ExcludeReleasesWithCeiling(t *testing.T) {
+	_, cleanup := setupContextTestDir(t)
+	defer cleanup()
+
+	os.WriteFile("docs/specs/use-cases/rel01.0-uc001-init.yaml",
+		[]byte("id: rel01.0-uc001-init\ntitle: Init"), 0o644)
+	os.WriteFile("docs/specs/use-cases/rel01.5-uc002-abandoned.yaml",
+		[]byte("id: rel01.5-uc002-abandoned\ntitle: Abandoned"), 0o644)
+	os.WriteFile("docs/specs/use-cases/rel02.0-uc003-next.yaml",
+		[]byte("id: rel02.0-uc003-next\ntitle: Next"), 0o644)
+	os.WriteFile("docs/specs/test-suites/test-rel01.5.yaml",
+		[]byte("id: test-rel01.5\ntitle: Abandoned suite"), 0o644)
+
+	project := ProjectConfig{Release: "02.0"}
+
+	ctx, err := buildProjectContext(".", "", project, nil, []string{"01.5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ucIDs := make(map[string]bool)
+	for _, uc := range ctx.Specs.UseCases {
+		ucIDs[uc.ID] = true
+	}
+	if !ucIDs["rel01.0-uc001-init"] || !ucIDs["rel02.0-uc003-next"] {
+		t.Errorf("expected rel01.0 and rel02.0 use cases within the ceiling, got %v", ucIDs)
+	}
+	if ucIDs["rel01.5-uc002-abandoned"] {
+		t.Error("expected rel01.5-uc002-abandoned to be dropped by ExcludeReleases")
+	}
+	for _, ts := range ctx.Specs.TestSuites {
+		if ts.File == "docs/specs/test-suites/test-rel01.5.yaml" {
+			t.Error("expected test-rel01.5.yaml to be dropped by ExcludeReleases")
+		}
+	}
+}
+
 func TestBuildProjectContext_ReleaseLegacyBackwardCompat(t *testing.T) {
 	_, cleanup := setupContextTestDir(t)
 	defer cleanup()
@@ -891,7 +967,7 @@ func TestBuildProjectContext_ReleaseLegacyBackwardCompat(t *testing.T) {
 		Release: "01.0",
 	}
 
-	ctx, err := buildProjectContext("", project, nil)
+	ctx, err := buildProjectContext(".", "", project, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -923,7 +999,7 @@ func TestBuildProjectContext_ReleasesOverridesRelease(t *testing.T) {
 		Releases: []string{"01.0"},
 	}
 
-	ctx, err := buildProjectContext("", project, nil)
+	ctx, err := buildProjectContext(".", "", project, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -952,7 +1028,7 @@ func TestBuildProjectContext_EmptyReleasesIncludesAll(t *testing.T) {
 	// No release filtering: both should be included.
 	project := ProjectConfig{}
 
-	ctx, err := buildProjectContext("", project, nil)
+	ctx, err := buildProjectContext(".", "", project, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -977,7 +1053,7 @@ func TestBuildProjectContext_PhaseContextOverridesReleases(t *testing.T) {
 	}
 	phase := &PhaseContext{Release: "01.0"}
 
-	ctx, err := buildProjectContext("", project, phase)
+	ctx, err := buildProjectContext(".", "", project, phase, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1004,7 +1080,7 @@ func TestEnsureTypedDocs_AddsMissingDocs(t *testing.T) {
 
 	// Start with an empty file list — ensureTypedDocs should add typed docs
 	// that exist on disk.
-	files := ensureTypedDocs(nil)
+	files := ensureTypedDocs(".", nil)
 
 	// VISION, ARCHITECTURE, and road-map.yaml exist in the test fixture.
 	found := make(map[string]bool)
@@ -1028,7 +1104,7 @@ func TestEnsureTypedDocs_DoesNotDuplicate(t *testing.T) {
 
 	// Start with VISION already in the list.
 	files := []string{"docs/VISION.yaml"}
-	result := ensureTypedDocs(files)
+	result := ensureTypedDocs(".", files)
 
 	count := 0
 	for _, f := range result {
@@ -1048,7 +1124,7 @@ func TestEnsureTypedDocs_SkipsMissingFiles(t *testing.T) {
 	os.Chdir(dir)
 	defer os.Chdir(origDir)
 
-	files := ensureTypedDocs(nil)
+	files := ensureTypedDocs(".", nil)
 	if len(files) != 0 {
 		t.Errorf("got %d files, want 0 (no typed docs exist in temp dir)", len(files))
 	}
@@ -1066,7 +1142,7 @@ func TestLoadNamedDoc_MarkdownFile(t *testing.T) {
 	content := "# Do Work\n\nUse this command:\n\n```bash\ncurl http://example.com\n```\n"
 	os.WriteFile(mdPath, []byte(content), 0o644)
 
-	doc := loadNamedDoc(mdPath)
+	doc := loadNamedDoc(".", mdPath, nil)
 	if doc == nil {
 		t.Fatal("loadNamedDoc returned nil for markdown file")
 	}
@@ -1086,7 +1162,7 @@ func TestLoadNamedDoc_TextFile(t *testing.T) {
 	txtPath := filepath.Join(dir, "readme.txt")
 	os.WriteFile(txtPath, []byte("plain text"), 0o644)
 
-	doc := loadNamedDoc(txtPath)
+	doc := loadNamedDoc(".", txtPath, nil)
 	if doc == nil {
 		t.Fatal("loadNamedDoc returned nil for .txt file")
 	}
@@ -1100,7 +1176,7 @@ func TestLoadNamedDoc_YAMLFileStillWorks(t *testing.T) {
 	yamlPath := filepath.Join(dir, "config.yaml")
 	os.WriteFile(yamlPath, []byte("id: test\ntitle: Test Doc"), 0o644)
 
-	doc := loadNamedDoc(yamlPath)
+	doc := loadNamedDoc(".", yamlPath, nil)
 	if doc == nil {
 		t.Fatal("loadNamedDoc returned nil for YAML file")
 	}
@@ -1126,7 +1202,12 @@ func TestClassifyContextFile_AllTypes(t *testing.T) {
 		{"docs/ARCHITECTURE.yaml", "architecture"},
 		{"docs/SPECIFICATIONS.yaml", "specifications"},
 		{"docs/road-map.yaml", "roadmap"},
+		{"docs/VISION.yml", "vision"},
+		{"docs/ARCHITECTURE.yml", "architecture"},
+		{"docs/SPECIFICATIONS.yml", "specifications"},
+		{"docs/road-map.yml", "roadmap"},
 		{filepath.Join("docs", "specs", "product-requirements", "prd001-feature.yaml"), "prd"},
+		{filepath.Join("docs", "specs", "product-requirements", "prd002-other.yml"), "prd"},
 		{filepath.Join("docs", "specs", "use-cases", "rel01.0-uc001-init.yaml"), "use_case"},
 		{filepath.Join("docs", "specs", "test-suites", "test-rel-01.0.yaml"), "test_suite"},
 		{filepath.Join("docs", "specs", "dependency-map.yaml"), "spec_aux"},
@@ -1302,7 +1383,7 @@ func TestApplyContextBudget_RemovesNonRequired(t *testing.T) {
 	fullSize := len(data)
 	budget := fullSize / 2
 
-	applyContextBudget(ctx, budget, required)
+	applyContextBudget(ctx, budget, required, nil, false)
 
 	// a.go must be preserved (it's required).
 	found := false
@@ -1329,7 +1410,7 @@ func TestApplyContextBudget_ZeroBudget(t *testing.T) {
 		},
 	}
 
-	applyContextBudget(ctx, 0, nil)
+	applyContextBudget(ctx, 0, nil, nil, false)
 
 	if len(ctx.SourceCode) != 2 {
 		t.Errorf("zero budget should not remove files, got %d", len(ctx.SourceCode))
@@ -1346,13 +1427,83 @@ func TestApplyContextBudget_PreservesRequired(t *testing.T) {
 	}
 	required := []string{"pkg/a.go", "pkg/b.go"}
 
-	applyContextBudget(ctx, 1, required) // impossibly small budget
+	applyContextBudget(ctx, 1, required, nil, false) // impossibly small budget
 
 	if len(ctx.SourceCode) != 2 {
 		t.Errorf("all-required: expected 2 files preserved, got %d", len(ctx.SourceCode))
 	}
 }
 
+func TestCapSourceFiles_ZeroIsUnlimited(t *testing.T) {
+	files := []SourceFile{{File: "pkg/a.go"}, {File: "pkg/b.go"}}
+	if got := capSourceFiles(files, 0, nil, nil); len(got) != 2 {
+		t.Errorf("zero cap should keep all files, got %d", len(got))
+	}
+}
+
+func TestCapSourceFiles_PrefersRequiredThenScoped(t *testing.T) {
+	files := []SourceFile{
+		{File: "pkg/a/a.go"},
+		{File: "pkg/b/b.go"},
+		{File: "pkg/c/c.go"},
+		{File: "pkg/d/d.go"},
+	}
+	got := capSourceFiles(files, 2, []string{"pkg/d/d.go"}, []string{"pkg/c"})
+	if len(got) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(got))
+	}
+	// Original order is preserved among kept files.
+	if got[0].File != "pkg/c/c.go" || got[1].File != "pkg/d/d.go" {
+		t.Errorf("kept %v, want [pkg/c/c.go pkg/d/d.go]", got)
+	}
+}
+
+func TestCapSourceFiles_FillsFromLoadOrder(t *testing.T) {
+	files := []SourceFile{{File: "pkg/a.go"}, {File: "pkg/b.go"}, {File: "pkg/c.go"}}
+	got := capSourceFiles(files, 2, nil, nil)
+	if len(got) != 2 || got[0].File != "pkg/a.go" || got[1].File != "pkg/b.go" {
+		t.Errorf("kept %v, want first two files", got)
+	}
+}
+
+func TestPackageScopeFiles_TargetAndImports(t *testing.T) {
+	// Not parallel: chdirTemp changes the working directory.
+	chdirTemp(t)
+	files := map[string]string{
+		"pkg/a/a.go":    "package a\n\nimport (\n\t\"fmt\"\n\t\"example.com/m/pkg/b\"\n)\n",
+		"pkg/b/b.go":    "package b\n",
+		"pkg/c/c.go":    "package c\n",
+		"cmd/x/main.go": "package main\n",
+	}
+	var sources []SourceFile
+	for path, content := range files {
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		os.WriteFile(path, []byte(content), 0o644)
+		sources = append(sources, SourceFile{File: path})
+	}
+
+	got, scope := packageScopeFiles(".", sources, []string{"pkg/a/new.go", "./pkg/a/a.go (extend)"}, "example.com/m")
+	if !reflect.DeepEqual(scope, []string{"pkg/a", "pkg/b"}) {
+		t.Errorf("scope = %v, want [pkg/a pkg/b]", scope)
+	}
+	if len(got) != 2 {
+		t.Errorf("got %d files, want 2: %v", len(got), got)
+	}
+}
+
+func TestPackageScopeFiles_NoMatchKeepsAll(t *testing.T) {
+	t.Parallel()
+	sources := []SourceFile{{File: "pkg/a/a.go"}, {File: "pkg/b/b.go"}}
+	got, scope := packageScopeFiles(".", sources, []string{"pkg/new/new.go"}, "")
+	if scope != nil || len(got) != 2 {
+		t.Errorf("expected fallback to all files, got scope=%v files=%d", scope, len(got))
+	}
+	got, scope = packageScopeFiles(".", sources, nil, "")
+	if scope != nil || len(got) != 2 {
+		t.Errorf("expected all files without task files, got scope=%v files=%d", scope, len(got))
+	}
+}
+
 func TestApplyContextBudget_UnderBudget(t *testing.T) {
 	ctx := &ProjectContext{
 		SourceCode: []SourceFile{
@@ -1360,7 +1511,7 @@ func TestApplyContextBudget_UnderBudget(t *testing.T) {
 		},
 	}
 
-	applyContextBudget(ctx, 1000000, nil)
+	applyContextBudget(ctx, 1000000, nil, nil, false)
 
 	if len(ctx.SourceCode) != 1 {
 		t.Errorf("under budget should not remove files, got %d", len(ctx.SourceCode))
@@ -1376,7 +1527,7 @@ func TestApplyContextBudget_ExactlyAtLimit(t *testing.T) {
 	data, _ := yaml.Marshal(ctx)
 	exactSize := len(data)
 
-	applyContextBudget(ctx, exactSize, nil)
+	applyContextBudget(ctx, exactSize, nil, nil, false)
 
 	if len(ctx.SourceCode) != 1 {
 		t.Errorf("at-limit: expected 1 file, got %d", len(ctx.SourceCode))
@@ -1385,7 +1536,7 @@ func TestApplyContextBudget_ExactlyAtLimit(t *testing.T) {
 
 func TestApplyContextBudget_NilContext(t *testing.T) {
 	// Should not panic.
-	applyContextBudget(nil, 100, nil)
+	applyContextBudget(nil, 100, nil, nil, false)
 }
 
 func TestContextExcludeEverything(t *testing.T) {
@@ -1398,7 +1549,7 @@ func TestContextExcludeEverything(t *testing.T) {
 		ContextExclude: ".",
 	}
 
-	ctx, err := buildProjectContext("", project, nil)
+	ctx, err := buildProjectContext(".", "", project, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1432,7 +1583,7 @@ func TestBuildProjectContext_ExcludeSource(t *testing.T) {
 	project := ProjectConfig{GoSourceDirs: []string{"pkg/"}}
 	phaseCtx := &PhaseContext{ExcludeSource: true}
 
-	ctx, err := buildProjectContext("", project, phaseCtx)
+	ctx, err := buildProjectContext(".", "", project, phaseCtx, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1456,7 +1607,7 @@ func TestBuildProjectContext_SourcePatterns(t *testing.T) {
 	// Only include main.go, not util.go.
 	phaseCtx := &PhaseContext{SourcePatterns: "pkg/app/main.go"}
 
-	ctx, err := buildProjectContext("", project, phaseCtx)
+	ctx, err := buildProjectContext(".", "", project, phaseCtx, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1478,7 +1629,7 @@ func TestBuildProjectContext_SourcePatternsEmpty(t *testing.T) {
 	project := ProjectConfig{GoSourceDirs: []string{"pkg/"}}
 	phaseCtx := &PhaseContext{SourcePatterns: ""}
 
-	ctx, err := buildProjectContext("", project, phaseCtx)
+	ctx, err := buildProjectContext(".", "", project, phaseCtx, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1489,6 +1640,38 @@ func TestBuildProjectContext_SourcePatternsEmpty(t *testing.T) {
 	}
 }
 
+// TestBuildProjectContext_PhaseGoSourceDirs verifies that
+// PhaseContext.GoSourceDirs replaces ProjectConfig.GoSourceDirs and that
+// an empty value falls back to the config (GH-555).
+func TestBuildProjectContext_PhaseGoSourceDirs(t *testing.T) {
+	_, cleanup := setupContextTestDir(t)
+	defer cleanup()
+	os.MkdirAll("cmd/tool", 0o755)
+	os.WriteFile("cmd/tool/main.go", []byte("package main\n"), 0o644)
+
+	project := ProjectConfig{GoSourceDirs: []string{"pkg/", "cmd/"}}
+	files := func(phaseCtx *PhaseContext) []string {
+		t.Helper()
+		ctx, err := buildProjectContext(".", "", project, phaseCtx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, sf := range ctx.SourceCode {
+			out = append(out, sf.File)
+		}
+		return out
+	}
+
+	if got := files(&PhaseContext{GoSourceDirs: "# tools only\ncmd/\n"}); !slices.Equal(got, []string{"cmd/tool/main.go"}) {
+		t.Errorf("phase go_source_dirs: got %v, want [cmd/tool/main.go]", got)
+	}
+	all := files(nil)
+	if got := files(&PhaseContext{GoSourceDirs: "  \n"}); !slices.Equal(got, all) || len(all) < 3 {
+		t.Errorf("empty phase go_source_dirs: got %v, want config dirs %v", got, all)
+	}
+}
+
 // --- test file exclusion (GH-616) ---
 
 // TestBuildProjectContext_ExcludeTests_True verifies that _test.go files are
@@ -1502,7 +1685,7 @@ func TestBuildProjectContext_ExcludeTests_True(t *testing.T) {
 	project := ProjectConfig{GoSourceDirs: []string{"pkg/"}}
 	phaseCtx := &PhaseContext{ExcludeTests: true}
 
-	ctx, err := buildProjectContext("", project, phaseCtx)
+	ctx, err := buildProjectContext(".", "", project, phaseCtx, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1529,7 +1712,7 @@ func TestBuildProjectContext_ExcludeTests_False(t *testing.T) {
 	project := ProjectConfig{GoSourceDirs: []string{"pkg/"}}
 	phaseCtx := &PhaseContext{ExcludeTests: false}
 
-	ctx, err := buildProjectContext("", project, phaseCtx)
+	ctx, err := buildProjectContext(".", "", project, phaseCtx, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1654,7 +1837,7 @@ func TestSummarizeCustom_Output(t *testing.T) {
 	os.WriteFile(filePath, []byte("package foo\n"), 0o644)
 
 	// "echo hello" appended with filePath → output contains "hello".
-	out := summarizeCustom("echo hello", filePath, "original content")
+	out := summarizeCustom("echo hello", "", filePath, "original content")
 	if !strings.Contains(out, "hello") {
 		t.Errorf("expected command output, got: %q", out)
 	}
@@ -1669,7 +1852,7 @@ func TestSummarizeCustom_FallbackOnFailure(t *testing.T) {
 	os.WriteFile(filePath, []byte("package foo\n"), 0o644)
 
 	fullContent := "original full content"
-	got := summarizeCustom("false", filePath, fullContent)
+	got := summarizeCustom("false", "", filePath, fullContent)
 	if got != fullContent {
 		t.Errorf("failing command should fall back to full content, got: %q", got)
 	}
@@ -1680,7 +1863,7 @@ func TestSummarizeCustom_FallbackOnFailure(t *testing.T) {
 func TestSummarizeCustom_EmptyCommand(t *testing.T) {
 	t.Parallel()
 	full := "original content"
-	got := summarizeCustom("", "any/path.go", full)
+	got := summarizeCustom("", "", "any/path.go", full)
 	if got != full {
 		t.Errorf("empty command should return full content, got: %q", got)
 	}
@@ -1698,7 +1881,7 @@ func TestBuildProjectContext_SourceMode_Headers(t *testing.T) {
 	project := ProjectConfig{GoSourceDirs: []string{"pkg/"}}
 	phaseCtx := &PhaseContext{SourceMode: "headers"}
 
-	ctx, err := buildProjectContext("", project, phaseCtx)
+	ctx, err := buildProjectContext(".", "", project, phaseCtx, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1724,11 +1907,11 @@ func TestBuildProjectContext_SourceMode_Full(t *testing.T) {
 
 	project := ProjectConfig{GoSourceDirs: []string{"pkg/"}}
 
-	ctxFull, err := buildProjectContext("", project, &PhaseContext{SourceMode: "full"})
+	ctxFull, err := buildProjectContext(".", "", project, &PhaseContext{SourceMode: "full"}, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	ctxNil, err := buildProjectContext("", project, nil)
+	ctxNil, err := buildProjectContext(".", "", project, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2197,3 +2380,1007 @@ touchpoints: []
 		t.Errorf("expected nil (rel01.0 in_progress but all UCs done, no auto-advance), got %s", uc.ID)
 	}
 }
+
+// stubReadFile replaces readFileFn for the duration of a test, failing the
+// first failures calls with err before delegating to os.ReadFile.
+func stubReadFile(t *testing.T, failures int, err error) *int {
+	t.Helper()
+	calls := 0
+	origRead, origDelay := readFileFn, contextReadRetryDelay
+	readFileFn = func(path string) ([]byte, error) {
+		calls++
+		if calls <= failures {
+			return nil, err
+		}
+		return os.ReadFile(path)
+	}
+	contextReadRetryDelay = 0
+	t.Cleanup(func() { readFileFn, contextReadRetryDelay = origRead, origDelay })
+	return &calls
+}
+
+func TestReadContextFile_RetriesTransientError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.yaml")
+	if err := os.WriteFile(path, []byte("id: x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	calls := stubReadFile(t, 1, errors.New("nfs: server not responding"))
+
+	var errs contextLoadErrors
+	data, err := readContextFile(path, &errs)
+	if err != nil {
+		t.Fatalf("readContextFile: %v", err)
+	}
+	if string(data) != "id: x\n" {
+		t.Errorf("data = %q, want file content", data)
+	}
+	if *calls != 2 {
+		t.Errorf("read calls = %d, want 2 (one failure, one retry)", *calls)
+	}
+	if len(errs.read) != 0 {
+		t.Errorf("dropped = %v, want none after a successful retry", errs.read)
+	}
+}
+
+func TestReadContextFile_RecordsPersistentError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.yaml")
+	if err := os.WriteFile(path, []byte("id: x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	calls := stubReadFile(t, 100, errors.New("input/output error"))
+
+	var errs contextLoadErrors
+	if _, err := readContextFile(path, &errs); err == nil {
+		t.Fatal("expected error after retries are exhausted")
+	}
+	if *calls != 1+contextReadRetries {
+		t.Errorf("read calls = %d, want %d", *calls, 1+contextReadRetries)
+	}
+	if len(errs.read) != 1 || !strings.HasPrefix(errs.read[0], path+":") {
+		t.Errorf("dropped = %v, want one entry for %s", errs.read, path)
+	}
+}
+
+func TestReadContextFile_MissingFileNotRetried(t *testing.T) {
+	calls := stubReadFile(t, 0, nil)
+
+	var errs contextLoadErrors
+	if _, err := readContextFile(filepath.Join(t.TempDir(), "missing.yaml"), &errs); !os.IsNotExist(err) {
+		t.Fatalf("err = %v, want not-exist", err)
+	}
+	if *calls != 1 {
+		t.Errorf("read calls = %d, want 1 (no retry for missing file)", *calls)
+	}
+	if len(errs.read) != 0 {
+		t.Errorf("dropped = %v, want none for a missing file", errs.read)
+	}
+}
+
+func TestBuildProjectContext_SurfacesReadErrors(t *testing.T) {
+	_, cleanup := setupContextTestDir(t)
+	defer cleanup()
+
+	origRead, origDelay := readFileFn, contextReadRetryDelay
+	defer func() { readFileFn, contextReadRetryDelay = origRead, origDelay }()
+	contextReadRetryDelay = 0
+	readFileFn = func(path string) ([]byte, error) {
+		if path == "docs/VISION.yaml" {
+			return nil, errors.New("stale file handle")
+		}
+		return os.ReadFile(path)
+	}
+
+	ctx, err := buildProjectContext(".", "", ProjectConfig{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Vision != nil {
+		t.Error("Vision should be dropped when its read keeps failing")
+	}
+	if len(ctx.ReadErrors) != 1 || !strings.HasPrefix(ctx.ReadErrors[0], "docs/VISION.yaml:") {
+		t.Errorf("ReadErrors = %v, want docs/VISION.yaml", ctx.ReadErrors)
+	}
+}
+
+func TestRequiredDocPath(t *testing.T) {
+	tests := []struct {
+		entry, want string
+	}{
+		{"docs/specs/product-requirements/prd001-core.yaml", "docs/specs/product-requirements/prd001-core.yaml"},
+		{"docs/ARCHITECTURE.yaml (components section)", "docs/ARCHITECTURE.yaml"},
+		{"docs/engineering/eng01-x.yaml#guidelines", "docs/engineering/eng01-x.yaml"},
+		{"docs/notes.md R3", "docs/notes.md"},
+		{"pkg/app/main.go", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := requiredDocPath(tt.entry); got != tt.want {
+			t.Errorf("requiredDocPath(%q) = %q, want %q", tt.entry, got, tt.want)
+		}
+	}
+}
+
+func TestBuildProjectContext_SurfacesParseErrors(t *testing.T) {
+	_, cleanup := setupContextTestDir(t)
+	defer cleanup()
+
+	os.WriteFile("docs/specs/product-requirements/prd001-good.yaml",
+		[]byte("id: prd001-good\ntitle: Good"), 0o644)
+	os.WriteFile("docs/specs/product-requirements/prd002-bad.yaml",
+		[]byte("id: prd002-bad\ntitle: [unclosed\n"), 0o644)
+
+	ctx, err := buildProjectContext(".", "", ProjectConfig{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Specs == nil || len(ctx.Specs.ProductRequirements) != 1 || ctx.Specs.ProductRequirements[0].ID != "prd001-good" {
+		t.Fatalf("want only prd001-good loaded, got %+v", ctx.Specs)
+	}
+	if len(ctx.ParseErrors) != 1 || ctx.ParseErrors[0].File != "docs/specs/product-requirements/prd002-bad.yaml" {
+		t.Fatalf("ParseErrors = %+v, want prd002-bad.yaml", ctx.ParseErrors)
+	}
+	if ctx.ParseErrors[0].Error == "" {
+		t.Error("ParseErrors entry has no message")
+	}
+}
+
+func TestIncludeRequiredDocs_ReincludesFilteredDocs(t *testing.T) {
+	_, cleanup := setupContextTestDir(t)
+	defer cleanup()
+
+	os.WriteFile("docs/specs/use-cases/rel01.0-uc001-init.yaml",
+		[]byte("id: rel01.0-uc001-init\ntitle: Init"), 0o644)
+	os.WriteFile("docs/specs/use-cases/rel02.0-uc002-later.yaml",
+		[]byte("id: rel02.0-uc002-later\ntitle: Later"), 0o644)
+	os.WriteFile("docs/specs/product-requirements/prd002-later.yaml",
+		[]byte("id: prd002-later\ntitle: Later"), 0o644)
+
+	project := ProjectConfig{
+		Release:        "01.0",
+		ContextExclude: "docs/ARCHITECTURE.yaml",
+	}
+	ctx, err := buildProjectContext(".", "", project, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Architecture != nil {
+		t.Fatal("precondition: ARCHITECTURE.yaml should be excluded")
+	}
+
+	includeRequiredDocs(ctx, ".", []string{
+		"docs/ARCHITECTURE.yaml (interfaces)",
+		"docs/specs/use-cases/rel02.0-uc002-later.yaml",
+		"docs/specs/product-requirements/prd002-later.yaml",
+		"docs/VISION.yaml",
+		"docs/engineering/eng09-missing.yaml",
+		"pkg/app/main.go",
+	})
+
+	if ctx.Architecture == nil {
+		t.Error("ARCHITECTURE.yaml should be re-included")
+	}
+	var ucIDs []string
+	for _, uc := range ctx.Specs.UseCases {
+		ucIDs = append(ucIDs, uc.ID)
+	}
+	if !slices.Contains(ucIDs, "rel02.0-uc002-later") {
+		t.Errorf("use cases = %v, want rel02.0-uc002-later re-included", ucIDs)
+	}
+	if len(ctx.Specs.ProductRequirements) != 1 || ctx.Specs.ProductRequirements[0].ID != "prd002-later" {
+		t.Errorf("PRDs = %v, want prd002-later re-included", ctx.Specs.ProductRequirements)
+	}
+	wantOverrides := []string{
+		"docs/ARCHITECTURE.yaml",
+		"docs/specs/use-cases/rel02.0-uc002-later.yaml",
+		"docs/specs/product-requirements/prd002-later.yaml",
+	}
+	if !slices.Equal(ctx.RequiredDocOverrides, wantOverrides) {
+		t.Errorf("RequiredDocOverrides = %v, want %v", ctx.RequiredDocOverrides, wantOverrides)
+	}
+	if !slices.Equal(ctx.MissingRequiredDocs, []string{"docs/engineering/eng09-missing.yaml"}) {
+		t.Errorf("MissingRequiredDocs = %v, want the missing engineering doc", ctx.MissingRequiredDocs)
+	}
+}
+
+func TestResolvePatterns_RelativeToRootFromSubdirectory(t *testing.T) {
+	root := t.TempDir()
+	for _, f := range []string{"docs/a.yaml", "docs/b.yaml", "internal/gen/x.go", "sub/docs/c.yaml"} {
+		if err := os.MkdirAll(filepath.Join(root, filepath.Dir(f)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, f), []byte("id: x\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	orig, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join(root, "sub")); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(orig)
+
+	got := resolveContextSources(root, "docs/*.yaml")
+	if want := []string{"docs/a.yaml", "docs/b.yaml"}; !slices.Equal(got, want) {
+		t.Errorf("resolveContextSources from subdirectory = %v, want %v", got, want)
+	}
+	set := resolveFileSet(root, "internal\ndocs/b.yaml")
+	if len(set) != 2 || !set["internal/gen/x.go"] || !set["docs/b.yaml"] {
+		t.Errorf("resolveFileSet from subdirectory = %v, want internal/gen/x.go and docs/b.yaml", set)
+	}
+}
+
+func TestBuildProjectContext_RootOtherThanCwd(t *testing.T) {
+	root, cleanup := setupContextTestDir(t)
+	defer cleanup()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	project := ProjectConfig{GoSourceDirs: []string{"pkg/"}}
+	ctx, err := buildProjectContext(root, "", project, nil, nil)
+	if err != nil {
+		t.Fatalf("buildProjectContext: %v", err)
+	}
+	if ctx.Vision == nil || ctx.Vision.ID != "v1" {
+		t.Errorf("Vision = %+v, want id v1 loaded from root", ctx.Vision)
+	}
+	var paths []string
+	for _, sf := range ctx.SourceCode {
+		paths = append(paths, sf.File)
+	}
+	if want := []string{"pkg/app/main.go", "pkg/app/util.go"}; !slices.Equal(paths, want) {
+		t.Errorf("SourceCode files = %v, want root-relative %v", paths, want)
+	}
+}
+
+func TestResolveStandardFiles_YmlExtension(t *testing.T) {
+	_, cleanup := setupContextTestDir(t)
+	defer cleanup()
+
+	// setupContextTestDir writes VISION.yaml; add a duplicate .yml spelling
+	// plus .yml-only docs in each typed category.
+	for _, f := range []string{
+		"docs/VISION.yml",
+		"docs/SPECIFICATIONS.yml",
+		"docs/specs/product-requirements/prd001-core.yml",
+		"docs/specs/use-cases/rel01.0-uc001-init.yml",
+		"docs/specs/test-suites/test-rel01.0.yml",
+	} {
+		os.WriteFile(f, []byte("id: test"), 0o644)
+	}
+
+	got := resolveStandardFiles(".")
+	for _, want := range []string{
+		"docs/VISION.yaml",
+		"docs/SPECIFICATIONS.yml",
+		"docs/specs/product-requirements/prd001-core.yml",
+		"docs/specs/use-cases/rel01.0-uc001-init.yml",
+		"docs/specs/test-suites/test-rel01.0.yml",
+	} {
+		if !slices.Contains(got, want) {
+			t.Errorf("resolveStandardFiles missing %s: %v", want, got)
+		}
+	}
+	if slices.Contains(got, "docs/VISION.yml") {
+		t.Errorf("docs/VISION.yml should be skipped when VISION.yaml exists: %v", got)
+	}
+
+	ctx, err := buildProjectContext(".", "", ProjectConfig{Release: "01.0"}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Vision == nil || ctx.Vision.File != "docs/VISION.yaml" {
+		t.Errorf("Vision = %+v, want loaded from docs/VISION.yaml", ctx.Vision)
+	}
+	if ctx.Specifications == nil {
+		t.Error("SPECIFICATIONS.yml should load as typed specifications")
+	}
+	if ctx.Specs == nil || len(ctx.Specs.UseCases) != 1 || len(ctx.Specs.TestSuites) != 1 {
+		t.Errorf("Specs = %+v, want one .yml use case and test suite", ctx.Specs)
+	}
+}
+
+func TestEnsureTypedDocs_YmlVariant(t *testing.T) {
+	_, cleanup := setupContextTestDir(t)
+	defer cleanup()
+	os.Remove("docs/road-map.yaml")
+	os.WriteFile("docs/road-map.yml", []byte("id: r1"), 0o644)
+
+	got := dedupeYAMLExtensions(ensureTypedDocs(".", []string{"docs/VISION.yml"}))
+	if !slices.Contains(got, "docs/road-map.yml") {
+		t.Errorf("expected road-map.yml to be added: %v", got)
+	}
+	if slices.Contains(got, "docs/VISION.yaml") {
+		t.Errorf("VISION.yaml should not be added when VISION.yml is listed: %v", got)
+	}
+}
+
+// estimateTestTokens is the token estimate applyContextTokenBudget
+// measures ctx with.
+func estimateTestTokens(t *testing.T, ctx *ProjectContext, charsPerToken float64) int {
+	t.Helper()
+	data, err := yaml.Marshal(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokensForBytes(data, charsPerToken)
+}
+
+func TestTokensForBytes(t *testing.T) {
+	if got := tokensForBytes(nil, 4); got != 0 {
+		t.Errorf("empty data: got %d, want 0", got)
+	}
+	data := []byte(strings.Repeat("x", 4001))
+	if got, want := tokensForBytes(data, 4), 1001; got != want {
+		t.Errorf("tokensForBytes(4) = %d, want %d", got, want)
+	}
+	if got, want := tokensForBytes(data, 0), tokensForBytes(data, defaultCharsPerToken); got != want {
+		t.Errorf("divisor 0 should fall back to default: got %d, want %d", got, want)
+	}
+	if tokensForBytes(data, 2) <= tokensForBytes(data, 4) {
+		t.Error("a smaller divisor should estimate more tokens")
+	}
+}
+
+func TestApplyContextTokenBudget_TrimsAgainstTokens(t *testing.T) {
+	ctx := &ProjectContext{
+		SourceCode: []SourceFile{
+			{File: "pkg/a.go", Lines: strings.Repeat("x", 4000)},
+			{File: "pkg/b.go", Lines: strings.Repeat("y", 4000)},
+			{File: "pkg/c.go", Lines: strings.Repeat("z", 4000)},
+		},
+	}
+	full := estimateTestTokens(t, ctx, 4)
+
+	// A token budget that fits about two files keeps required a.go and b.go.
+	applyContextTokenBudget(ctx, full*2/3+10, 4, []string{"pkg/a.go"}, nil, false)
+	var files []string
+	for _, sf := range ctx.SourceCode {
+		files = append(files, sf.File)
+	}
+	if !slices.Equal(files, []string{"pkg/a.go", "pkg/b.go"}) {
+		t.Errorf("files = %v, want [pkg/a.go pkg/b.go]", files)
+	}
+	if got := estimateTestTokens(t, ctx, 4); got > full*2/3+10 {
+		t.Errorf("estimate after trim %d exceeds budget %d", got, full*2/3+10)
+	}
+
+	// The same number as a byte budget would trim far more aggressively.
+	byteCtx := &ProjectContext{SourceCode: []SourceFile{
+		{File: "pkg/a.go", Lines: strings.Repeat("x", 4000)},
+		{File: "pkg/b.go", Lines: strings.Repeat("y", 4000)},
+	}}
+	applyContextBudget(byteCtx, full*2/3+10, nil, nil, false)
+	if len(byteCtx.SourceCode) != 0 {
+		t.Errorf("byte budget kept %d file(s), want 0", len(byteCtx.SourceCode))
+	}
+}
+
+func TestApplyContextTokenBudget_ZeroIsNoOp(t *testing.T) {
+	ctx := &ProjectContext{SourceCode: []SourceFile{{File: "pkg/a.go", Lines: "package a"}}}
+	applyContextTokenBudget(ctx, 0, 4, nil, nil, false)
+	if len(ctx.SourceCode) != 1 {
+		t.Errorf("zero token budget should not remove files, got %d", len(ctx.SourceCode))
+	}
+}
+
+func TestApplyContextBudget_ShedsDocsAfterSource(t *testing.T) {
+	big := func(file string) *NamedDoc {
+		return &NamedDoc{File: file, Name: file, Content: yaml.Node{Kind: yaml.ScalarNode, Value: strings.Repeat("d", 3000)}}
+	}
+	newCtx := func() *ProjectContext {
+		return &ProjectContext{
+			SourceCode: []SourceFile{
+				{File: "pkg/a.go", Lines: strings.Repeat("x", 3000)},
+				{File: "pkg/b.go", Lines: strings.Repeat("y", 3000)},
+			},
+			Engineering: []*EngineeringDoc{
+				{File: "docs/engineering/eng01.yaml", Introduction: strings.Repeat("e", 3000)},
+				{File: "docs/engineering/eng02.yaml", Introduction: strings.Repeat("f", 3000)},
+			},
+			Extra: []*NamedDoc{big("docs/notes.yaml"), big("docs/keep.yaml")},
+		}
+	}
+
+	// Without shedDocs, docs survive even when source is exhausted.
+	ctx := newCtx()
+	applyContextBudget(ctx, 5000, []string{"pkg/a.go"}, nil, false)
+	if len(ctx.SourceCode) != 1 || len(ctx.Extra) != 2 || len(ctx.Engineering) != 2 {
+		t.Errorf("shedDocs=false: src=%d extra=%d eng=%d, want 1/2/2",
+			len(ctx.SourceCode), len(ctx.Extra), len(ctx.Engineering))
+	}
+
+	// With shedDocs, extras go first (last first), protected docs stay,
+	// then engineering docs in reverse order.
+	ctx = newCtx()
+	applyContextBudget(ctx, 5000, []string{"pkg/a.go"}, []string{"docs/keep.yaml"}, true)
+	if len(ctx.SourceCode) != 1 || ctx.SourceCode[0].File != "pkg/a.go" {
+		t.Errorf("source = %v, want only required pkg/a.go", ctx.SourceCode)
+	}
+	if len(ctx.Extra) != 1 || ctx.Extra[0].File != "docs/keep.yaml" {
+		t.Errorf("extra = %v, want only protected docs/keep.yaml", ctx.Extra)
+	}
+	if len(ctx.Engineering) != 0 {
+		t.Errorf("engineering = %d doc(s), want all shed", len(ctx.Engineering))
+	}
+
+	// A budget that fits after dropping one engineering doc keeps the first.
+	ctx = newCtx()
+	data, _ := yaml.Marshal(&ProjectContext{
+		SourceCode:  ctx.SourceCode[:1],
+		Engineering: ctx.Engineering[:1],
+		Extra:       ctx.Extra[1:],
+	})
+	applyContextBudget(ctx, len(data)+50, []string{"pkg/a.go"}, []string{"docs/keep.yaml"}, true)
+	if len(ctx.Engineering) != 1 || ctx.Engineering[0].File != "docs/engineering/eng01.yaml" {
+		t.Errorf("engineering = %v, want eng01 kept (reverse-order removal)", ctx.Engineering)
+	}
+}
+
+func TestLoadSourceFiles_ParallelMatchesSequential(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 40; i++ {
+		sub := filepath.Join(dir, fmt.Sprintf("p%02d", i%7))
+		os.MkdirAll(sub, 0o755)
+		os.WriteFile(filepath.Join(sub, fmt.Sprintf("f%02d.go", i)),
+			[]byte(fmt.Sprintf("package p\n\nvar V%d = %d\n", i, i)), 0o644)
+	}
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("not go"), 0o644)
+
+	seq := loadSourceFiles([]string{dir}, sourceLoadOptions{Workers: 1})
+	par := loadSourceFiles([]string{dir}, sourceLoadOptions{Workers: 8})
+	if len(seq) != 40 {
+		t.Fatalf("sequential loaded %d file(s), want 40", len(seq))
+	}
+	if !reflect.DeepEqual(seq, par) {
+		t.Error("parallel load differs from sequential load")
+	}
+	if !sort.SliceIsSorted(par, func(i, j int) bool { return par[i].File < par[j].File }) {
+		t.Error("parallel load is not sorted by path")
+	}
+	if !strings.HasPrefix(par[0].Lines, "1 | package p") {
+		t.Errorf("lines not numbered: %q", par[0].Lines)
+	}
+}
+
+func TestLoadSourceFiles_SkipsUnreadableFiles(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.go")
+	bad := filepath.Join(dir, "bad.go")
+	os.WriteFile(good, []byte("package x\n"), 0o644)
+	os.WriteFile(bad, []byte("package x\n"), 0o644)
+
+	origRead, origDelay := readFileFn, contextReadRetryDelay
+	defer func() { readFileFn, contextReadRetryDelay = origRead, origDelay }()
+	contextReadRetryDelay = 0
+	readFileFn = func(path string) ([]byte, error) {
+		if path == bad {
+			return nil, errors.New("input/output error")
+		}
+		return os.ReadFile(path)
+	}
+
+	var errs contextLoadErrors
+	files := loadSourceFiles([]string{dir}, sourceLoadOptions{Workers: 4, Errs: &errs})
+	if len(files) != 1 || files[0].File != good {
+		t.Errorf("files = %v, want only %s", files, good)
+	}
+	if len(errs.read) != 1 || !strings.HasPrefix(errs.read[0], bad+":") {
+		t.Errorf("dropped = %v, want one entry for %s", errs.read, bad)
+	}
+}
+
+func TestBuildProjectContext_RespectsGitignore(t *testing.T) {
+	initTestGitRepo(t)
+	for _, d := range []string{"pkg/app", "pkg/app/mocks", "pkg/vendored"} {
+		os.MkdirAll(d, 0o755)
+	}
+	os.WriteFile(".gitignore", []byte("pkg/vendored/\n"), 0o644)
+	os.WriteFile("pkg/app/.gitignore", []byte("mocks/\n"), 0o644)
+	os.WriteFile("pkg/app/main.go", []byte("package app\n"), 0o644)
+	os.WriteFile("pkg/app/util.go", []byte("package app\n"), 0o644)
+	os.WriteFile("pkg/app/mocks/mock.go", []byte("package mocks\n"), 0o644)
+	os.WriteFile("pkg/vendored/lib.go", []byte("package vendored\n"), 0o644)
+
+	sourceFiles := func(project ProjectConfig) []string {
+		t.Helper()
+		ctx, err := buildProjectContext(".", "", project, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var files []string
+		for _, sf := range ctx.SourceCode {
+			files = append(files, sf.File)
+		}
+		return files
+	}
+
+	// Default: root and nested .gitignore rules apply, and ContextExclude
+	// composes on top of them.
+	got := sourceFiles(ProjectConfig{GoSourceDirs: []string{"pkg"}, ContextExclude: "pkg/app/util.go"})
+	if !slices.Equal(got, []string{"pkg/app/main.go"}) {
+		t.Errorf("default: source files = %v, want [pkg/app/main.go]", got)
+	}
+
+	off := false
+	got = sourceFiles(ProjectConfig{GoSourceDirs: []string{"pkg"}, RespectGitignore: &off})
+	if len(got) != 4 {
+		t.Errorf("respect_gitignore=false: source files = %v, want all 4", got)
+	}
+}
+
+func TestDropGitignored_OutsideRepo(t *testing.T) {
+	chdirTemp(t)
+	paths := []string{"a.go", "b.go"}
+	if got := dropGitignored(".", paths); !slices.Equal(got, paths) {
+		t.Errorf("outside a repo: got %v, want paths unchanged", got)
+	}
+}
+
+func TestLoadSourceFiles_SourceExtensions(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "api.proto"), []byte("syntax = \"proto3\";\n\nmessage M {}\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "schema.sql"), []byte("CREATE TABLE t (id INT);\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored\n"), 0o644)
+
+	names := func(files []SourceFile) []string {
+		var out []string
+		for _, sf := range files {
+			out = append(out, filepath.Base(sf.File))
+		}
+		return out
+	}
+
+	if got := names(loadSourceFiles([]string{dir}, sourceLoadOptions{})); !slices.Equal(got, []string{"main.go"}) {
+		t.Errorf("default extensions: got %v, want [main.go]", got)
+	}
+	files := loadSourceFiles([]string{dir}, sourceLoadOptions{Extensions: []string{".go", ".proto", ".sql"}})
+	if got := names(files); !slices.Equal(got, []string{"api.proto", "main.go", "schema.sql"}) {
+		t.Errorf("configured extensions: got %v", got)
+	}
+	for _, sf := range files {
+		if filepath.Base(sf.File) == "api.proto" && sf.Lines != "1 | syntax = \"proto3\";\n3 | message M {}" {
+			t.Errorf("proto not line-numbered: %q", sf.Lines)
+		}
+	}
+}
+
+func TestLoadSourceFiles_DependencyOrder(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	write := func(rel, content string) {
+		p := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// app imports store, store imports model; cyc/a and cyc/b import each other.
+	write("app/main.go", "package main\n\nimport (\n\t\"fmt\"\n\t\"example.com/m/store\"\n)\n")
+	write("model/model.go", "package model\n")
+	write("model/types.go", "package model\n")
+	write("store/store.go", "package store\n\nimport \"example.com/m/model\"\n")
+	write("cyc/a/a.go", "package a\n\nimport \"example.com/m/cyc/b\"\n")
+	write("cyc/b/b.go", "package b\n\nimport \"example.com/m/cyc/a\"\n")
+
+	rel := func(files []SourceFile) []string {
+		var out []string
+		for _, sf := range files {
+			r, _ := filepath.Rel(root, sf.File)
+			out = append(out, filepath.ToSlash(r))
+		}
+		return out
+	}
+
+	byPath := rel(loadSourceFiles([]string{root}, sourceLoadOptions{Workers: 1}))
+	wantPath := []string{"app/main.go", "cyc/a/a.go", "cyc/b/b.go", "model/model.go", "model/types.go", "store/store.go"}
+	if !slices.Equal(byPath, wantPath) {
+		t.Errorf("path order = %v, want %v", byPath, wantPath)
+	}
+
+	byDep := rel(loadSourceFiles([]string{root}, sourceLoadOptions{Workers: 1, Order: SourceOrderDependency}))
+	wantDep := []string{"model/model.go", "model/types.go", "store/store.go", "app/main.go", "cyc/b/b.go", "cyc/a/a.go"}
+	if !slices.Equal(byDep, wantDep) {
+		t.Errorf("dependency order = %v, want %v", byDep, wantDep)
+	}
+}
+
+func TestLoadSourceFiles_ExcludeContains(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "gen.pb.go"), []byte("// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage main\n"), 0o644)
+	late := "package main\n\n" + strings.Repeat("// padding\n", excludeContainsHeaderBytes/10) + "// DO NOT EDIT\n"
+	os.WriteFile(filepath.Join(dir, "late.go"), []byte(late), 0o644)
+
+	names := func(files []SourceFile) []string {
+		var out []string
+		for _, sf := range files {
+			out = append(out, filepath.Base(sf.File))
+		}
+		return out
+	}
+	if got := names(loadSourceFiles([]string{dir}, sourceLoadOptions{})); len(got) != 3 {
+		t.Errorf("without markers: got %v, want all 3 files", got)
+	}
+	got := names(loadSourceFiles([]string{dir}, sourceLoadOptions{ExcludeContains: []string{"", "DO NOT EDIT"}}))
+	if !slices.Equal(got, []string{"late.go", "main.go"}) {
+		t.Errorf("with marker: got %v, want [late.go main.go] (marker past the header is not matched)", got)
+	}
+}
+
+func TestLoadSourceFiles_SkipsInvalidUTF8AndOversized(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "blob.go"), []byte("package main\n\xff\xfe\x00\x01\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "big.go"), []byte("package main\n"+strings.Repeat("var _ = 0\n", 100)), 0o644)
+
+	var got []string
+	for _, sf := range loadSourceFiles([]string{dir}, sourceLoadOptions{}) {
+		got = append(got, filepath.Base(sf.File))
+	}
+	if !slices.Equal(got, []string{"big.go", "main.go"}) {
+		t.Errorf("without cap: got %v, want [big.go main.go] (invalid UTF-8 skipped)", got)
+	}
+	got = nil
+	for _, sf := range loadSourceFiles([]string{dir}, sourceLoadOptions{MaxFileBytes: 512}) {
+		got = append(got, filepath.Base(sf.File))
+	}
+	if !slices.Equal(got, []string{"main.go"}) {
+		t.Errorf("with 512-byte cap: got %v, want [main.go]", got)
+	}
+}
+
+func TestLoadSourceFiles_FollowSymlinks(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	outside := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "pkg", "a"), 0o755)
+	os.MkdirAll(filepath.Join(root, "shared", "s"), 0o755)
+	os.WriteFile(filepath.Join(root, "pkg", "a", "a.go"), []byte("package a\n"), 0o644)
+	os.WriteFile(filepath.Join(root, "shared", "s", "s.go"), []byte("package s\n"), 0o644)
+	os.WriteFile(filepath.Join(outside, "x.go"), []byte("package x\n"), 0o644)
+	for link, target := range map[string]string{
+		"shared":  filepath.Join("..", "shared"),
+		"loop":    "..",
+		"outside": outside,
+	} {
+		if err := os.Symlink(target, filepath.Join(root, "pkg", link)); err != nil {
+			t.Skipf("symlinks unsupported: %v", err)
+		}
+	}
+
+	names := func(files []SourceFile) []string {
+		var out []string
+		for _, sf := range files {
+			out = append(out, filepath.Base(sf.File))
+		}
+		slices.Sort(out)
+		return out
+	}
+	dirs := []string{filepath.Join(root, "pkg")}
+	if got := names(loadSourceFiles(dirs, sourceLoadOptions{Root: root})); !slices.Equal(got, []string{"a.go"}) {
+		t.Errorf("default: got %v, want [a.go]", got)
+	}
+	got := names(loadSourceFiles(dirs, sourceLoadOptions{FollowSymlinks: true, Root: root}))
+	if !slices.Equal(got, []string{"a.go", "s.go"}) {
+		t.Errorf("following: got %v, want [a.go s.go] (each file once, outside link skipped)", got)
+	}
+}
+
+func TestLoadSourceFiles_FollowSymlinksRespectsGitignore(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	initTestGitRepoInDir(t, root)
+	os.MkdirAll(filepath.Join(root, "pkg", "a"), 0o755)
+	os.MkdirAll(filepath.Join(root, "shared", "s"), 0o755)
+	os.WriteFile(filepath.Join(root, ".gitignore"), []byte("pkg/a/gen.go\nshared/s/gen.go\n"), 0o644)
+	for _, f := range []string{"pkg/a/a.go", "pkg/a/gen.go", "shared/s/s.go", "shared/s/gen.go"} {
+		os.WriteFile(filepath.Join(root, f), []byte("package x\n"), 0o644)
+	}
+	if err := os.Symlink(filepath.Join("..", "shared"), filepath.Join(root, "pkg", "shared")); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	var got []string
+	opts := sourceLoadOptions{FollowSymlinks: true, RespectGitignore: true, Root: root}
+	for _, sf := range loadSourceFiles([]string{"pkg"}, opts) {
+		got = append(got, sf.File)
+	}
+	want := []string{"pkg/a/a.go", "pkg/shared/s/s.go"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v (ignored files dropped on both sides of the link)", got, want)
+	}
+}
+
+func TestLocalImportDir_SegmentMatch(t *testing.T) {
+	t.Parallel()
+	dirs := []string{"/src/pkg/errors", "/src/pkg/store"}
+	keys := map[string][]string{}
+	for _, d := range dirs {
+		keys[d] = packageImportKeys(d, []string{"/src", "/src/pkg"})
+	}
+	cases := []struct {
+		importPath, modulePath, want string
+	}{
+		{"errors", "", ""},
+		{"example.com/m/pkg/errors", "", "/src/pkg/errors"},
+		{"example.com/m/errors", "", "/src/pkg/errors"},
+		{"example.com/m/myerrors", "", ""},
+		{"example.com/m/pkg/store", "", "/src/pkg/store"},
+		{"m/pkg/store", "m", "/src/pkg/store"},
+		{"m/pkg/store", "", ""},
+	}
+	for _, tc := range cases {
+		if got := localImportDir(tc.importPath, tc.modulePath, dirs, keys); got != tc.want {
+			t.Errorf("localImportDir(%q, %q) = %q, want %q", tc.importPath, tc.modulePath, got, tc.want)
+		}
+	}
+}
+
+func TestStripGoComments_PreservesLineNumbers(t *testing.T) {
+	t.Parallel()
+	input := "// Copyright (c) 2026 Example. All rights reserved.\n" +
+		"// SPDX-License-Identifier: MIT\n" +
+		"\n" +
+		"//go:build linux\n" +
+		"\n" +
+		"// Package demo does things.\n" +
+		"package demo\n" +
+		"\n" +
+		"/*\n" +
+		"Block comment spanning\n" +
+		"several lines.\n" +
+		"*/\n" +
+		"var s = \"// not a comment\" // trailing\n" +
+		"func F() int { return 1 /* inline */ + 2 }\n"
+	got := numberLines(stripGoComments(input))
+	want := "4 | //go:build linux\n" +
+		"7 | package demo\n" +
+		"13 | var s = \"// not a comment\"\n" +
+		"14 | func F() int { return 1  + 2 }"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestStripGoComments_InvalidGoUnchanged(t *testing.T) {
+	t.Parallel()
+	input := "package x\n/* unterminated\n"
+	if got := stripGoComments(input); got != input {
+		t.Errorf("got %q, want input unchanged", got)
+	}
+}
+
+func TestLoadSourceFiles_StripCommentsReducesTokens(t *testing.T) {
+	dir := t.TempDir()
+	var b strings.Builder
+	b.WriteString("// Copyright (c) 2026 Example. All rights reserved.\n// SPDX-License-Identifier: MIT\n\npackage demo\n\n")
+	for i := 0; i < 20; i++ {
+		fmt.Fprintf(&b, "// F%d does something worth describing at length so that the\n", i)
+		b.WriteString("// documentation outweighs the code, as it often does in real\n")
+		b.WriteString("// packages with thorough doc comments.\n")
+		fmt.Fprintf(&b, "func F%d() int { return %d } // trailing note\n\n", i, i)
+	}
+	os.WriteFile(filepath.Join(dir, "demo.go"), []byte(b.String()), 0o644)
+	os.WriteFile(filepath.Join(dir, "api.proto"), []byte("// kept\nsyntax = \"proto3\";\n"), 0o644)
+	exts := []string{".go", ".proto"}
+
+	full := loadSourceFiles([]string{dir}, sourceLoadOptions{Workers: 1, Extensions: exts})
+	stripped := loadSourceFiles([]string{dir}, sourceLoadOptions{Workers: 1, Extensions: exts, StripComments: true})
+	if len(full) != 2 || len(stripped) != 2 {
+		t.Fatalf("got %d and %d files, want 2 each", len(full), len(stripped))
+	}
+	if stripped[0].Lines != full[0].Lines {
+		t.Errorf("non-Go file changed: %q", stripped[0].Lines)
+	}
+	if strings.Contains(stripped[1].Lines, "Copyright") {
+		t.Error("copyright header not dropped")
+	}
+	if !strings.Contains(stripped[1].Lines, "4 | package demo") {
+		t.Errorf("package line lost its number:\n%s", stripped[1].Lines)
+	}
+	before := tokensForBytes([]byte(full[1].Lines), 0)
+	after := tokensForBytes([]byte(stripped[1].Lines), 0)
+	t.Logf("tokens: %d -> %d (%.0f%% reduction)", before, after, 100*float64(before-after)/float64(before))
+	if after*2 > before {
+		t.Errorf("expected at least 50%% token reduction, got %d -> %d", before, after)
+	}
+}
+
+func TestParseLineRange(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		in    string
+		path  string
+		r     lineRange
+		valid bool
+	}{
+		{"pkg/x/stitch.go:120-180", "pkg/x/stitch.go", lineRange{120, 180}, true},
+		{"pkg/x/stitch.go: 5 - 5", "pkg/x/stitch.go", lineRange{5, 5}, true},
+		{"pkg/x/stitch.go", "pkg/x/stitch.go", lineRange{}, false},
+		{"pkg/x/stitch.go:120", "pkg/x/stitch.go:120", lineRange{}, false},
+		{"pkg/x/stitch.go:180-120", "pkg/x/stitch.go:180-120", lineRange{}, false},
+		{"pkg/x/stitch.go:0-10", "pkg/x/stitch.go:0-10", lineRange{}, false},
+		{"pkg/x/stitch.go:a-b", "pkg/x/stitch.go:a-b", lineRange{}, false},
+	}
+	for _, tc := range cases {
+		path, r, ok := parseLineRange(tc.in)
+		if path != tc.path || r != tc.r || ok != tc.valid {
+			t.Errorf("parseLineRange(%q) = %q, %v, %v; want %q, %v, %v", tc.in, path, r, ok, tc.path, tc.r, tc.valid)
+		}
+	}
+}
+
+func TestSliceSourceLines(t *testing.T) {
+	t.Parallel()
+	sources := []SourceFile{
+		{File: "pkg/a/a.go", Lines: "1 | package a\n3 | func A() {}\n5 | func B() {}\n7 | func C() {}"},
+		{File: "pkg/b/b.go", Lines: "1 | package b\n2 | func D() {}"},
+	}
+	got := sliceSourceLines(sources, map[string][]lineRange{
+		"a/a.go": {{3, 3}, {6, 9}},
+	})
+	if want := "3 | func A() {}\n7 | func C() {}"; got[0].Lines != want {
+		t.Errorf("sliced a.go = %q, want %q", got[0].Lines, want)
+	}
+	if got[1].Lines != sources[1].Lines {
+		t.Errorf("b.go without range changed: %q", got[1].Lines)
+	}
+	if sources[0].Lines == got[0].Lines {
+		t.Error("input slice was modified in place")
+	}
+	if same := sliceSourceLines(sources, nil); len(same) != 2 || same[0].Lines != sources[0].Lines {
+		t.Error("nil ranges should return sources unchanged")
+	}
+}
+
+func TestProjectContextCache_ReusesUntilHEADMoves(t *testing.T) {
+	dir := initTestGitRepo(t)
+	os.MkdirAll(filepath.Join(dir, "pkg"), 0o755)
+	os.WriteFile(filepath.Join(dir, "pkg", "a.go"), []byte("package pkg\n"), 0o644)
+	gitRun(t, "add", "-A")
+	gitRun(t, "commit", "--no-verify", "-m", "add a.go")
+
+	project := ProjectConfig{GoSourceDirs: []string{"pkg"}}
+	cache := &projectContextCache{}
+	first, err := cache.build(".", "[]", project, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.SourceCode) != 1 || first.Issues != nil {
+		t.Fatalf("first build: src=%d issues=%d", len(first.SourceCode), len(first.Issues))
+	}
+
+	// An uncommitted edit is not seen; the issue list is refreshed.
+	os.WriteFile(filepath.Join(dir, "pkg", "b.go"), []byte("package pkg\n"), 0o644)
+	second, err := cache.build(".", `[{"id":"7","title":"t"}]`, project, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second.SourceCode) != 1 {
+		t.Errorf("cached build: got %d source file(s), want 1", len(second.SourceCode))
+	}
+	if len(second.Issues) != 1 || second.Issues[0].ID != "7" {
+		t.Errorf("cached build: issues not refreshed: %+v", second.Issues)
+	}
+	if first.Issues != nil {
+		t.Error("refreshing issues modified an earlier result")
+	}
+
+	// Moving HEAD invalidates the cache.
+	gitRun(t, "add", "-A")
+	gitRun(t, "commit", "--no-verify", "-m", "add b.go")
+	third, err := cache.build(".", "[]", project, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(third.SourceCode) != 2 {
+		t.Errorf("after commit: got %d source file(s), want 2", len(third.SourceCode))
+	}
+}
+
+func TestProjectContextCache_TrimDoesNotTouchCache(t *testing.T) {
+	dir := initTestGitRepo(t)
+	if err := os.MkdirAll(filepath.Join(dir, "pkg"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		if err := os.WriteFile(filepath.Join(dir, "pkg", name), []byte("package pkg\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitRun(t, "add", "-A")
+	gitRun(t, "commit", "--no-verify", "-m", "add sources")
+
+	project := ProjectConfig{GoSourceDirs: []string{"pkg"}}
+	cache := &projectContextCache{}
+	first, err := cache.build(".", "[]", project, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make([]string, len(first.SourceCode))
+	for i, sf := range first.SourceCode {
+		want[i] = sf.File
+	}
+	// Drop the first file in place, as trimContextToBudget does.
+	first.SourceCode = append(first.SourceCode[:0], first.SourceCode[1:]...)
+
+	second, err := cache.build(".", "[]", project, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]string, len(second.SourceCode))
+	for i, sf := range second.SourceCode {
+		got[i] = sf.File
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("cached sources = %v, want %v", got, want)
+	}
+}
+
+func TestProjectContextCache_BudgetTrimLeavesCacheIntact(t *testing.T) {
+	dir := initTestGitRepo(t)
+	if err := os.MkdirAll(filepath.Join(dir, "pkg"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	body := "package pkg\n\n// " + strings.Repeat("x", 400) + "\n"
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		if err := os.WriteFile(filepath.Join(dir, "pkg", name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitRun(t, "add", "-A")
+	gitRun(t, "commit", "--no-verify", "-m", "add sources")
+
+	project := ProjectConfig{GoSourceDirs: []string{"pkg"}}
+	cache := &projectContextCache{}
+	for i := range 3 {
+		ctx, err := cache.build(".", "[]", project, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ctx.SourceCode) != 3 {
+			t.Fatalf("iteration %d: got %d source file(s), want 3", i, len(ctx.SourceCode))
+		}
+		// Incremental measure trims with the changed files protected.
+		applyContextBudget(ctx, 600, []string{"pkg/a.go"}, nil, false)
+		if len(ctx.SourceCode) == 3 {
+			t.Fatalf("iteration %d: budget did not trim", i)
+		}
+	}
+}
+
+func TestProjectContextCache_KeyTracksSettings(t *testing.T) {
+	initTestGitRepo(t)
+	a := projectContextCacheKey(".", ProjectConfig{}, nil, nil)
+	if a == "" {
+		t.Fatal("expected a key inside a git repo")
+	}
+	if b := projectContextCacheKey(".", ProjectConfig{}, &PhaseContext{ExcludeTests: true}, nil); b == a {
+		t.Error("phase context change did not change the key")
+	}
+	if c := projectContextCacheKey(".", ProjectConfig{}, nil, []string{"01.0"}); c == a {
+		t.Error("excluded releases change did not change the key")
+	}
+}
+
+func TestProjectContextCache_OutsideRepoDoesNotCache(t *testing.T) {
+	chdirTemp(t)
+	if key := projectContextCacheKey(".", ProjectConfig{}, nil, nil); key != "" {
+		t.Errorf("key outside a repo = %q, want empty", key)
+	}
+	cache := &projectContextCache{}
+	if _, err := cache.build(".", "[]", ProjectConfig{}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if cache.ctx != nil {
+		t.Error("context cached without a HEAD")
+	}
+}
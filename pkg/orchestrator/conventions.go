@@ -0,0 +1,171 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// observedConventionsSampleSize is the maximum number of production files
+// and, separately, test files sampled when detecting observed conventions.
+const observedConventionsSampleSize = 6
+
+// ObservedConventions summarizes the emergent coding conventions detected
+// in a deterministic sample of the project's Go source files. It is
+// injected into the stitch prompt when CobblerConfig.StitchObservedConventions
+// is enabled so generated code matches house style rather than the
+// constitution's generic advice (GH-458).
+type ObservedConventions struct {
+	SampledFiles []string `yaml:"sampled_files"`
+	Conventions  []string `yaml:"conventions"`
+}
+
+var (
+	convErrorfWrapRe  = regexp.MustCompile(`fmt\.Errorf\([^\n]*%w`)
+	convErrorfPlainRe = regexp.MustCompile(`fmt\.Errorf\(`)
+	convPkgErrorsRe   = regexp.MustCompile(`errors\.(Wrap|Wrapf|WithMessage)\(`)
+	convLogCallRe     = regexp.MustCompile(`\b((?:log|slog|logger|logrus|zap)\.\w+|logf|debugf|infof|warnf)\(`)
+	convTableTestRe   = regexp.MustCompile(`(?m)^\s*(tests|cases|tt|tcs|testCases)\s*:?=\s*\[\]struct`)
+	convSubtestRe     = regexp.MustCompile(`\bt\.Run\(`)
+	convParallelRe    = regexp.MustCompile(`\bt\.Parallel\(\)`)
+	convTestifyRe     = regexp.MustCompile(`"github\.com/stretchr/testify/`)
+	convExportedFnRe  = regexp.MustCompile(`(?m)^func (?:\([^)]*\) )?([A-Z]\w*)`)
+	convDocFnRe       = regexp.MustCompile(`(?m)^//[^\n]*\nfunc (?:\([^)]*\) )?[A-Z]\w*`)
+)
+
+// sampleConventionFiles walks dirs under root and returns a deterministic
+// sample of production and test Go files, as root-relative paths. Files
+// are sorted by path and picked at an even stride so the same tree always
+// yields the same sample, which keeps the stitch prompt stable for
+// caching.
+func sampleConventionFiles(root string, dirs []string, n int) (prod, tests []string) {
+	var allProd, allTests []string
+	for _, dir := range dirs {
+		filepath.Walk(rootedPath(root, dir), func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || !strings.HasSuffix(path, ".go") {
+				return nil
+			}
+			path = relToRoot(root, path)
+			if strings.HasSuffix(path, "_test.go") {
+				allTests = append(allTests, path)
+			} else {
+				allProd = append(allProd, path)
+			}
+			return nil
+		})
+	}
+	return strideSample(allProd, n), strideSample(allTests, n)
+}
+
+// strideSample sorts paths and returns at most n of them, evenly spaced.
+func strideSample(paths []string, n int) []string {
+	sort.Strings(paths)
+	if n <= 0 || len(paths) <= n {
+		return paths
+	}
+	out := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		out = append(out, paths[i*len(paths)/n])
+	}
+	return out
+}
+
+// detectObservedConventions samples Go files under dirs (relative to root)
+// and returns a short summary of the conventions they follow. Returns nil when no Go
+// files are found or no convention can be inferred.
+func detectObservedConventions(root string, dirs []string) *ObservedConventions {
+	prod, tests := sampleConventionFiles(root, dirs, observedConventionsSampleSize)
+	if len(prod) == 0 && len(tests) == 0 {
+		return nil
+	}
+
+	var prodSrc, testSrc strings.Builder
+	for _, p := range prod {
+		if data, err := os.ReadFile(rootedPath(root, p)); err == nil {
+			prodSrc.Write(data)
+			prodSrc.WriteByte('\n')
+		}
+	}
+	for _, p := range tests {
+		if data, err := os.ReadFile(rootedPath(root, p)); err == nil {
+			testSrc.Write(data)
+			testSrc.WriteByte('\n')
+		}
+	}
+
+	conv := summarizeConventions(prodSrc.String(), testSrc.String())
+	if len(conv) == 0 {
+		return nil
+	}
+	sampled := append(append([]string{}, prod...), tests...)
+	return &ObservedConventions{SampledFiles: sampled, Conventions: conv}
+}
+
+// summarizeConventions applies the convention heuristics to the
+// concatenated production and test sources.
+func summarizeConventions(prod, tests string) []string {
+	var conv []string
+
+	wrapped := len(convErrorfWrapRe.FindAllString(prod, -1))
+	errorf := len(convErrorfPlainRe.FindAllString(prod, -1))
+	pkgErrors := len(convPkgErrorsRe.FindAllString(prod, -1))
+	switch {
+	case pkgErrors > wrapped && pkgErrors > 0:
+		conv = append(conv, "Errors are wrapped with errors.Wrap/Wrapf; follow the same style.")
+	case wrapped > 0 && wrapped*2 >= errorf:
+		conv = append(conv, `Errors are wrapped with fmt.Errorf("context: %w", err); wrap, do not discard, the cause.`)
+	case errorf > 0:
+		conv = append(conv, "Errors are created with fmt.Errorf without %w wrapping.")
+	}
+
+	if helper, count := mostFrequentLogCall(prod); count >= 2 {
+		conv = append(conv, fmt.Sprintf("Logging goes through %s(...) (%d calls in sample); use it instead of introducing a new logger.", helper, count))
+	}
+
+	if exported := len(convExportedFnRe.FindAllString(prod, -1)); exported > 0 {
+		documented := len(convDocFnRe.FindAllString(prod, -1))
+		if documented*4 >= exported*3 {
+			conv = append(conv, "Exported functions carry doc comments starting with the function name.")
+		}
+	}
+
+	if tests != "" {
+		if n := len(convTableTestRe.FindAllString(tests, -1)); n > 0 {
+			conv = append(conv, fmt.Sprintf("Tests are table-driven ([]struct cases, %d in sample).", n))
+		}
+		if convSubtestRe.MatchString(tests) {
+			conv = append(conv, "Test cases run as subtests via t.Run.")
+		}
+		if convParallelRe.MatchString(tests) {
+			conv = append(conv, "Tests call t.Parallel() where safe.")
+		}
+		if convTestifyRe.MatchString(tests) {
+			conv = append(conv, "Tests use testify assertions.")
+		} else {
+			conv = append(conv, "Tests use the standard library only (t.Errorf/t.Fatalf, no assertion library).")
+		}
+	}
+	return conv
+}
+
+// mostFrequentLogCall returns the most frequently called logging function
+// in src and its call count. Ties are broken alphabetically.
+func mostFrequentLogCall(src string) (string, int) {
+	counts := make(map[string]int)
+	for _, m := range convLogCallRe.FindAllStringSubmatch(src, -1) {
+		counts[m[1]]++
+	}
+	best, bestN := "", 0
+	for name, n := range counts {
+		if n > bestN || (n == bestN && name < best) {
+			best, bestN = name, n
+		}
+	}
+	return best, bestN
+}
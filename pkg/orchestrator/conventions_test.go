@@ -0,0 +1,94 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStrideSample_Deterministic(t *testing.T) {
+	t.Parallel()
+	paths := []string{"e.go", "a.go", "d.go", "b.go", "c.go", "f.go"}
+	got := strideSample(append([]string{}, paths...), 3)
+	want := []string{"a.go", "c.go", "e.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("strideSample = %v, want %v", got, want)
+	}
+	again := strideSample(append([]string{}, paths...), 3)
+	if !reflect.DeepEqual(got, again) {
+		t.Errorf("strideSample not deterministic: %v vs %v", got, again)
+	}
+}
+
+func TestStrideSample_FewerThanN(t *testing.T) {
+	t.Parallel()
+	got := strideSample([]string{"b.go", "a.go"}, 5)
+	if !reflect.DeepEqual(got, []string{"a.go", "b.go"}) {
+		t.Errorf("strideSample = %v", got)
+	}
+}
+
+func TestSummarizeConventions_WrapAndLogf(t *testing.T) {
+	t.Parallel()
+	prod := `// Run does a thing.
+func Run() error {
+	logf("run: start")
+	logf("run: done")
+	return fmt.Errorf("running: %w", err)
+}
+`
+	tests := `func TestRun(t *testing.T) {
+	tests := []struct{ name string }{}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {})
+	}
+}
+`
+	conv := strings.Join(summarizeConventions(prod, tests), "\n")
+	for _, want := range []string{"%w", "logf(...)", "doc comments", "table-driven", "t.Run", "standard library only"} {
+		if !strings.Contains(conv, want) {
+			t.Errorf("conventions missing %q:\n%s", want, conv)
+		}
+	}
+	if strings.Contains(conv, "t.Parallel") {
+		t.Errorf("conventions should not mention t.Parallel:\n%s", conv)
+	}
+}
+
+func TestSummarizeConventions_Empty(t *testing.T) {
+	t.Parallel()
+	if conv := summarizeConventions("package x\n", ""); len(conv) != 0 {
+		t.Errorf("expected no conventions, got %v", conv)
+	}
+}
+
+func TestDetectObservedConventions_NoFiles(t *testing.T) {
+	t.Parallel()
+	if got := detectObservedConventions("", []string{t.TempDir()}); got != nil {
+		t.Errorf("expected nil, got %+v", got)
+	}
+}
+
+func TestDetectObservedConventions_SamplesDir(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\n\nfunc f() error { return fmt.Errorf(\"x: %w\", err) }\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "a_test.go"), []byte("package a\n\nfunc TestF(t *testing.T) { t.Parallel() }\n"), 0o644)
+
+	got := detectObservedConventions("", []string{dir})
+	if got == nil {
+		t.Fatal("expected conventions, got nil")
+	}
+	if len(got.SampledFiles) != 2 {
+		t.Errorf("SampledFiles = %v, want 2 entries", got.SampledFiles)
+	}
+	conv := strings.Join(got.Conventions, "\n")
+	if !strings.Contains(conv, "%w") || !strings.Contains(conv, "t.Parallel") {
+		t.Errorf("unexpected conventions:\n%s", conv)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// costLedgerFile is the per-generation file, under generationMetaDir,
+// holding the Claude spend accumulated against Generation.MaxCostUSD.
+const costLedgerFile = "cost-usd"
+
+// costLedger is the running Claude spend of one generation. Every add is
+// written through to path so an interrupted run resumes with its total.
+// Methods on a nil ledger are no-ops.
+type costLedger struct {
+	mu    sync.Mutex
+	path  string
+	spent float64
+}
+
+// loadCostLedger reads the total recorded at path. A missing or
+// unreadable file starts the ledger at zero.
+func loadCostLedger(path string) *costLedger {
+	l := &costLedger{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return l
+	}
+	if v, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64); err == nil {
+		l.spent = v
+	} else {
+		logf("costLedger: ignoring unparsable %s: %v", path, err)
+	}
+	return l
+}
+
+// add records usd of spend and persists the new total. Write failures
+// are logged; the in-memory total stays authoritative for this run.
+func (l *costLedger) add(usd float64) {
+	if l == nil || usd == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.spent += usd
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		logf("costLedger: creating %s: %v", filepath.Dir(l.path), err)
+		return
+	}
+	if err := os.WriteFile(l.path, []byte(strconv.FormatFloat(l.spent, 'f', 6, 64)+"\n"), 0o644); err != nil {
+		logf("costLedger: writing %s: %v", l.path, err)
+	}
+}
+
+// total returns the spend recorded so far.
+func (l *costLedger) total() float64 {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.spent
+}
+
+// costLedgerPath returns the path of the cost ledger for generation.
+func (o *Orchestrator) costLedgerPath(generation string) string {
+	return filepath.Join(o.cfg.Cobbler.Dir, generationMetaDir, generation, costLedgerFile)
+}
+
+// openCostLedger starts tracking Claude spend for generation, continuing
+// from the total persisted by an earlier run, and returns a func that
+// stops tracking. When a ledger is already open (generator:resume opens
+// one before RunCycles) it is kept and the returned func does nothing.
+func (o *Orchestrator) openCostLedger(generation string) func() {
+	if o.spend != nil {
+		return func() {}
+	}
+	o.spend = loadCostLedger(o.costLedgerPath(generation))
+	if spent := o.spend.total(); spent > 0 {
+		logf("costLedger: %s has spent $%.2f so far (max $%.2f)", generation, spent, o.cfg.Generation.MaxCostUSD)
+	}
+	return func() { o.spend = nil }
+}
+
+// costBudgetExhausted reports whether Generation.MaxCostUSD is set and
+// the open ledger has reached it.
+func (o *Orchestrator) costBudgetExhausted() bool {
+	ceiling := o.cfg.Generation.MaxCostUSD
+	return ceiling > 0 && o.spend.total() >= ceiling
+}
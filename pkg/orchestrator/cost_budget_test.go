@@ -0,0 +1,89 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCostLedger_PersistsAndResumes(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "meta", "gen", costLedgerFile)
+
+	l := loadCostLedger(path)
+	l.add(1.25)
+	l.add(0.5)
+	if got := l.total(); got != 1.75 {
+		t.Fatalf("total = %v, want 1.75", got)
+	}
+
+	resumed := loadCostLedger(path)
+	if got := resumed.total(); got != 1.75 {
+		t.Errorf("resumed total = %v, want 1.75", got)
+	}
+}
+
+func TestCostLedger_NilAndUnparsable(t *testing.T) {
+	t.Parallel()
+	var nilLedger *costLedger
+	nilLedger.add(3)
+	if got := nilLedger.total(); got != 0 {
+		t.Errorf("nil ledger total = %v, want 0", got)
+	}
+
+	path := filepath.Join(t.TempDir(), costLedgerFile)
+	if err := os.WriteFile(path, []byte("garbage\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := loadCostLedger(path).total(); got != 0 {
+		t.Errorf("unparsable ledger total = %v, want 0", got)
+	}
+}
+
+func TestCostBudgetExhausted(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	o := &Orchestrator{cfg: Config{Cobbler: CobblerConfig{Dir: dir}}}
+	closeLedger := o.openCostLedger("generation-a")
+	defer closeLedger()
+
+	o.spend.add(5)
+	if o.costBudgetExhausted() {
+		t.Error("exhausted with MaxCostUSD unset, want no cap")
+	}
+	o.cfg.Generation.MaxCostUSD = 10
+	if o.costBudgetExhausted() {
+		t.Error("exhausted at $5 of $10")
+	}
+	o.spend.add(5)
+	if !o.costBudgetExhausted() {
+		t.Error("not exhausted at $10 of $10")
+	}
+
+	if _, err := os.Stat(o.costLedgerPath("generation-a")); err != nil {
+		t.Errorf("ledger not persisted: %v", err)
+	}
+}
+
+func TestOpenCostLedger_KeepsOpenLedger(t *testing.T) {
+	t.Parallel()
+	o := &Orchestrator{cfg: Config{Cobbler: CobblerConfig{Dir: t.TempDir()}}}
+	closeOuter := o.openCostLedger("gen")
+	outer := o.spend
+
+	closeInner := o.openCostLedger("gen")
+	if o.spend != outer {
+		t.Error("nested openCostLedger replaced the open ledger")
+	}
+	closeInner()
+	if o.spend != outer {
+		t.Error("closing the nested ledger cleared the outer one")
+	}
+	closeOuter()
+	if o.spend != nil {
+		t.Error("ledger still open after close")
+	}
+}
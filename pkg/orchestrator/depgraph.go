@@ -0,0 +1,119 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// buildPlanMaxDependents caps how many dependent titles are listed in the
+// stitch prompt's build plan.
+const buildPlanMaxDependents = 10
+
+// StitchBuildPlan describes the task's position in the generation's
+// dependency graph. It is injected into the stitch prompt as build_plan
+// when CobblerConfig.StitchBuildPlan is enabled (GH-493).
+type StitchBuildPlan struct {
+	DirectDependents     int      `yaml:"direct_dependents"`
+	TransitiveDependents int      `yaml:"transitive_dependents"`
+	Dependents           []string `yaml:"dependents,omitempty"`
+	Guidance             string   `yaml:"guidance"`
+}
+
+// issueDependents maps each issue index to the issues that declare a
+// direct dependency on it. Self-dependencies are ignored.
+func issueDependents(issues []cobblerIssue) map[int][]cobblerIssue {
+	children := make(map[int][]cobblerIssue)
+	for _, iss := range issues {
+		if iss.DependsOn >= 0 && iss.DependsOn != iss.Index {
+			children[iss.DependsOn] = append(children[iss.DependsOn], iss)
+		}
+	}
+	for idx := range children {
+		sort.Slice(children[idx], func(i, j int) bool { return children[idx][i].Index < children[idx][j].Index })
+	}
+	return children
+}
+
+// transitiveDependents returns the issues that depend on index directly
+// or through other issues, in breadth-first order, and the number of
+// direct dependents. Cycles are tolerated: each issue is visited once.
+func transitiveDependents(issues []cobblerIssue, index int) ([]cobblerIssue, int) {
+	children := issueDependents(issues)
+	visited := map[int]bool{index: true}
+	queue := []int{index}
+	var out []cobblerIssue
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, c := range children[cur] {
+			if visited[c.Index] {
+				continue
+			}
+			visited[c.Index] = true
+			out = append(out, c)
+			queue = append(queue, c.Index)
+		}
+	}
+	return out, len(children[index])
+}
+
+// buildPlanFor summarizes how many open tasks build on the task with the
+// given index, lists their titles (direct dependents first, capped at
+// buildPlanMaxDependents), and adds guidance: leaves should stay minimal,
+// tasks with dependents should expose interfaces those tasks can extend.
+func buildPlanFor(issues []cobblerIssue, index int) *StitchBuildPlan {
+	deps, direct := transitiveDependents(issues, index)
+	plan := &StitchBuildPlan{DirectDependents: direct, TransitiveDependents: len(deps)}
+	for i, d := range deps {
+		if i == buildPlanMaxDependents {
+			plan.Dependents = append(plan.Dependents, fmt.Sprintf("... and %d more", len(deps)-i))
+			break
+		}
+		plan.Dependents = append(plan.Dependents, d.Title)
+	}
+	if len(deps) == 0 {
+		plan.Guidance = "No other task builds on this one. Implement exactly what the requirements ask; avoid speculative abstractions."
+	} else {
+		plan.Guidance = fmt.Sprintf("%d task(s) build on this one. Design the interfaces and types they will use to be general enough to extend without rework.", len(deps))
+	}
+	return plan
+}
+
+// issueIndexByNumber returns the cobbler_index of the issue with GitHub
+// number, which is what DependsOn refers to.
+func issueIndexByNumber(issues []cobblerIssue, number int) (int, bool) {
+	for _, iss := range issues {
+		if iss.Number == number {
+			return iss.Index, true
+		}
+	}
+	return 0, false
+}
+
+// stitchBuildPlan loads the generation's open issues and returns the
+// build plan for task. Returns nil when the task ID is not numeric, the
+// issues cannot be listed, or the task is not among them.
+func stitchBuildPlan(task stitchTask) *StitchBuildPlan {
+	number, err := strconv.Atoi(task.id)
+	if err != nil || task.repo == "" {
+		return nil
+	}
+	issues, err := listOpenCobblerIssues(task.repo, task.generation)
+	if err != nil {
+		logf("stitchBuildPlan: listing issues: %v", err)
+		return nil
+	}
+	index, ok := issueIndexByNumber(issues, number)
+	if !ok {
+		logf("stitchBuildPlan: task %s not among open issues", task.id)
+		return nil
+	}
+	plan := buildPlanFor(issues, index)
+	logf("stitchBuildPlan: task %s has %d direct, %d transitive dependent(s)",
+		task.id, plan.DirectDependents, plan.TransitiveDependents)
+	return plan
+}
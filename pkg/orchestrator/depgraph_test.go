@@ -0,0 +1,90 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTransitiveDependents(t *testing.T) {
+	t.Parallel()
+	issues := []cobblerIssue{
+		{Index: 1, Title: "types", DependsOn: -1},
+		{Index: 2, Title: "store", DependsOn: 1},
+		{Index: 3, Title: "cli", DependsOn: 2},
+		{Index: 4, Title: "api", DependsOn: 1},
+		{Index: 5, Title: "docs", DependsOn: -1},
+	}
+	deps, direct := transitiveDependents(issues, 1)
+	if direct != 2 {
+		t.Errorf("direct = %d, want 2", direct)
+	}
+	var titles []string
+	for _, d := range deps {
+		titles = append(titles, d.Title)
+	}
+	if got := strings.Join(titles, ","); got != "store,api,cli" {
+		t.Errorf("dependents = %s, want store,api,cli", got)
+	}
+	if deps, direct := transitiveDependents(issues, 5); len(deps) != 0 || direct != 0 {
+		t.Errorf("leaf: got %d dependents (%d direct), want 0", len(deps), direct)
+	}
+}
+
+func TestTransitiveDependents_ToleratesCycles(t *testing.T) {
+	t.Parallel()
+	issues := []cobblerIssue{
+		{Index: 1, DependsOn: 2},
+		{Index: 2, DependsOn: 1},
+		{Index: 3, DependsOn: 3},
+	}
+	if deps, _ := transitiveDependents(issues, 1); len(deps) != 1 || deps[0].Index != 2 {
+		t.Errorf("dependents = %+v, want only index 2", deps)
+	}
+	if deps, _ := transitiveDependents(issues, 3); len(deps) != 0 {
+		t.Errorf("self-dependency should be ignored, got %+v", deps)
+	}
+}
+
+func TestBuildPlanFor(t *testing.T) {
+	t.Parallel()
+	issues := []cobblerIssue{{Index: 0, Title: "base", DependsOn: -1}}
+	for i := 1; i <= buildPlanMaxDependents+2; i++ {
+		issues = append(issues, cobblerIssue{Index: i, Title: "child", DependsOn: 0})
+	}
+
+	plan := buildPlanFor(issues, 0)
+	if plan.DirectDependents != buildPlanMaxDependents+2 || plan.TransitiveDependents != buildPlanMaxDependents+2 {
+		t.Errorf("counts = %d/%d, want %d", plan.DirectDependents, plan.TransitiveDependents, buildPlanMaxDependents+2)
+	}
+	if len(plan.Dependents) != buildPlanMaxDependents+1 || plan.Dependents[buildPlanMaxDependents] != "... and 2 more" {
+		t.Errorf("dependents = %v, want capped list with overflow note", plan.Dependents)
+	}
+	if !strings.Contains(plan.Guidance, "extend") {
+		t.Errorf("foundation guidance = %q", plan.Guidance)
+	}
+
+	leaf := buildPlanFor(issues, 1)
+	if leaf.TransitiveDependents != 0 || len(leaf.Dependents) != 0 || !strings.Contains(leaf.Guidance, "speculative") {
+		t.Errorf("leaf plan = %+v", leaf)
+	}
+}
+
+func TestIssueIndexByNumber(t *testing.T) {
+	t.Parallel()
+	issues := []cobblerIssue{
+		{Number: 41, Index: 0, DependsOn: -1},
+		{Number: 42, Index: 1, DependsOn: 0},
+	}
+	if idx, ok := issueIndexByNumber(issues, 41); !ok || idx != 0 {
+		t.Errorf("issueIndexByNumber(41) = (%d, %v), want (0, true)", idx, ok)
+	}
+	if idx, ok := issueIndexByNumber(issues, 42); !ok || idx != 1 {
+		t.Errorf("issueIndexByNumber(42) = (%d, %v), want (1, true)", idx, ok)
+	}
+	if _, ok := issueIndexByNumber(issues, 1); ok {
+		t.Error("issueIndexByNumber(1) matched a cobbler_index, want no match")
+	}
+}
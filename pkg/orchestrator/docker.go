@@ -16,10 +16,11 @@ import (
 //go:embed Dockerfile.claude
 var embeddedDockerfile string
 
-// BuildImage builds the container image using podman from the embedded
+// BuildImage builds the container image using the configured container
+// runtime (podman or docker, see Podman.Runtime) from the embedded
 // Dockerfile. It reads the version from the consuming project's version
-// file (VersionFile in Config). If no version file is configured or it
-// has no Version constant, it falls back to the latest v* git tag.
+// files (VersionFile, then VersionFiles, in Config). If none is configured
+// or none has a Version constant, it falls back to the latest v* git tag.
 // Both a versioned tag and "latest" are applied to the built image.
 // The image name is taken from PodmanImage (stripped of any existing tag).
 //
@@ -31,7 +32,12 @@ func (o *Orchestrator) BuildImage() error {
 	}
 
 	// Prefer version from the project's version file; fall back to git tags.
-	tag := readVersionConst(o.cfg.Project.VersionFile)
+	var tag string
+	for _, f := range o.cfg.Project.versionFiles() {
+		if tag = readVersionConst(f); tag != "" {
+			break
+		}
+	}
 	if tag == "" {
 		tag = latestVersionTag()
 	}
@@ -43,8 +49,8 @@ func (o *Orchestrator) BuildImage() error {
 	latestImage := imageName + ":latest"
 
 	logf("buildImage: building %s", versionedImage)
-	if err := buildFromEmbeddedDockerfile(versionedImage, latestImage); err != nil {
-		return fmt.Errorf("podman build: %w", err)
+	if err := buildFromEmbeddedDockerfile(o.cfg.Podman.runtimeBin(), versionedImage, latestImage); err != nil {
+		return fmt.Errorf("%s build: %w", o.cfg.Podman.runtimeBin(), err)
 	}
 
 	logf("buildImage: done — %s and %s", versionedImage, latestImage)
@@ -66,7 +72,8 @@ func (o *Orchestrator) PodmanClean() error {
 	}
 
 	// Resolve to image ID so we catch containers from any name alias.
-	imageID, err := podmanImageID(image)
+	runtime := o.cfg.Podman.runtimeBin()
+	imageID, err := podmanImageID(runtime, image)
 	if err != nil {
 		return fmt.Errorf("resolving image ID for %s: %w", image, err)
 	}
@@ -76,7 +83,7 @@ func (o *Orchestrator) PodmanClean() error {
 	}
 
 	// List all containers (running + stopped) created from this image ID.
-	out, err := exec.Command(binPodman, "ps", "-a",
+	out, err := exec.Command(runtime, "ps", "-a",
 		"--filter", "ancestor="+imageID,
 		"--format", "{{.ID}} {{.Status}}",
 	).Output()
@@ -99,7 +106,7 @@ func (o *Orchestrator) PodmanClean() error {
 
 	logf("podmanClean: removing %d container(s) for image %s (%s)", len(ids), image, shortID(imageID))
 	args := append([]string{"rm", "-f"}, ids...)
-	cmd := exec.Command(binPodman, args...)
+	cmd := exec.Command(runtime, args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
@@ -113,12 +120,13 @@ func (o *Orchestrator) PodmanClean() error {
 // ensureImage checks whether the configured PodmanImage exists locally.
 // If missing, it builds it from the embedded Dockerfile.
 func (o *Orchestrator) ensureImage() error {
-	if podmanImageExists(o.cfg.Podman.Image) {
+	runtime := o.cfg.Podman.runtimeBin()
+	if podmanImageExists(runtime, o.cfg.Podman.Image) {
 		return nil
 	}
 
 	logf("ensureImage: %s not found locally, building from embedded Dockerfile", o.cfg.Podman.Image)
-	if err := buildFromEmbeddedDockerfile(o.cfg.Podman.Image); err != nil {
+	if err := buildFromEmbeddedDockerfile(runtime, o.cfg.Podman.Image); err != nil {
 		return fmt.Errorf("auto-building %s: %w", o.cfg.Podman.Image, err)
 	}
 	logf("ensureImage: built %s", o.cfg.Podman.Image)
@@ -126,8 +134,8 @@ func (o *Orchestrator) ensureImage() error {
 }
 
 // buildFromEmbeddedDockerfile writes the embedded Dockerfile to a temp
-// file and runs podman build with the given image tags.
-func buildFromEmbeddedDockerfile(tags ...string) error {
+// file and runs a build with the given container runtime and image tags.
+func buildFromEmbeddedDockerfile(runtime string, tags ...string) error {
 	tmp, err := os.CreateTemp("", "Dockerfile.claude-*")
 	if err != nil {
 		return fmt.Errorf("creating temp Dockerfile: %w", err)
@@ -144,33 +152,39 @@ func buildFromEmbeddedDockerfile(tags ...string) error {
 	}
 	tmp.Close()
 
-	return podmanBuild(tmp.Name(), tags...)
+	return podmanBuild(runtime, tmp.Name(), tags...)
 }
 
 // podmanImageExists returns true if the given image reference exists
-// in the local podman image store. A 15-second deadline prevents a
-// slow or unresponsive podman socket from blocking indefinitely.
-func podmanImageExists(image string) bool {
+// in the local image store of runtime. Docker has no "image exists", so
+// it uses "image inspect" instead. A 15-second deadline prevents a slow
+// or unresponsive runtime socket from blocking indefinitely.
+func podmanImageExists(runtime, image string) bool {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
-	if err := exec.CommandContext(ctx, binPodman, "image", "exists", image).Run(); err != nil {
+	args := []string{"image", "exists", image}
+	if runtime == binDocker {
+		args = []string{"image", "inspect", "--format", "{{.Id}}", image}
+	}
+	if err := exec.CommandContext(ctx, runtime, args...).Run(); err != nil {
 		if ctx.Err() != nil {
-			logf("podmanImageExists: timed out querying podman for %s", image)
+			logf("podmanImageExists: timed out querying %s for %s", runtime, image)
 		}
 		return false
 	}
 	return true
 }
 
-// podmanImageID resolves an image name/tag to its full image ID.
-// Returns "" if the image does not exist locally.
-func podmanImageID(image string) (string, error) {
-	out, err := exec.Command(binPodman, "image", "inspect", image,
+// podmanImageID resolves an image name/tag to its full image ID using
+// runtime. Returns "" if the image does not exist locally.
+func podmanImageID(runtime, image string) (string, error) {
+	out, err := exec.Command(runtime, "image", "inspect", image,
 		"--format", "{{.Id}}",
 	).Output()
 	if err != nil {
-		// image not found is not an error for our purposes
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 125 {
+		// image not found is not an error for our purposes; podman
+		// exits 125 and docker exits 1.
+		if exitErr, ok := err.(*exec.ExitError); ok && (exitErr.ExitCode() == 125 || (runtime == binDocker && exitErr.ExitCode() == 1)) {
 			return "", nil
 		}
 		return "", err
@@ -0,0 +1,312 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Built-in definition-of-done checks selectable in Cobbler.DoneChecks.
+const (
+	// DoneCheckBuildTest requires go build ./... and go test ./... to pass
+	// in the task worktree.
+	DoneCheckBuildTest = "build_test"
+
+	// DoneCheckDocsYAML requires every file declared in the task's files
+	// list (except deletions) to exist, and every .yaml/.yml file among
+	// them to parse.
+	DoneCheckDocsYAML = "docs_yaml"
+
+	// DoneCheckNone disables the check for a deliverable type.
+	DoneCheckNone = "none"
+)
+
+// doneCheckFuncs maps check names to their implementations. Each runs in
+// the task worktree with the task description and returns nil on pass.
+var doneCheckFuncs = map[string]func(worktreeDir, description string) error{
+	DoneCheckBuildTest: doneCheckBuildTest,
+	DoneCheckDocsYAML:  doneCheckDocsYAML,
+	DoneCheckNone:      func(string, string) error { return nil },
+}
+
+// parseDeliverableType extracts deliverable_type from a YAML task
+// description. Returns "" if absent or unparseable.
+func parseDeliverableType(description string) string {
+	var parsed struct {
+		DeliverableType string `yaml:"deliverable_type"`
+	}
+	if err := yaml.Unmarshal([]byte(description), &parsed); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(parsed.DeliverableType)
+}
+
+// runDoneCheck runs the definition-of-done check configured for the
+// task's deliverable type (GH-474). It returns the outcome recorded in
+// history ("" when no check applies, otherwise "<check>: passed" or
+// "<check>: failed") and a non-nil error when the check fails or names an
+// unknown check.
+func (o *Orchestrator) runDoneCheck(task stitchTask) (string, error) {
+	dtype := parseDeliverableType(task.description)
+	name := o.cfg.Cobbler.DoneChecks[dtype]
+	if dtype == "" || name == "" {
+		return "", nil
+	}
+	fn, ok := doneCheckFuncs[name]
+	if !ok {
+		return name + ": failed", fmt.Errorf("unknown done check %q for deliverable type %q", name, dtype)
+	}
+	logf("runDoneCheck: task %s deliverable_type=%s check=%s", task.id, dtype, name)
+	if err := fn(task.worktreeDir, task.description); err != nil {
+		logf("runDoneCheck: task %s check %s failed: %v", task.id, name, err)
+		return name + ": failed", fmt.Errorf("done check %s: %w", name, err)
+	}
+	logf("runDoneCheck: task %s check %s passed", task.id, name)
+	return name + ": passed", nil
+}
+
+// doneCheckBuildTest runs go build and go test over the worktree.
+func doneCheckBuildTest(worktreeDir, _ string) error {
+	return runGoCommands(worktreeDir, [][]string{{"build", "./..."}, {"test", "./..."}})
+}
+
+// runGoCommands runs each go command in dir and stops at the first
+// failure, returning the tail of its output.
+func runGoCommands(dir string, commands [][]string) error {
+	for _, args := range commands {
+		cmd := exec.Command(binGo, args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("go %s: %w\n%s", strings.Join(args, " "), err, tailLines(string(out), 20))
+		}
+	}
+	return nil
+}
+
+// verifyCommands returns the go commands the pre-merge verification gate
+// runs for the given settings (GH-512).
+func verifyCommands(build, tests bool) [][]string {
+	var commands [][]string
+	if build {
+		commands = append(commands, []string{"build", "./..."})
+	}
+	if tests {
+		commands = append(commands, []string{"test", "./..."})
+	}
+	return commands
+}
+
+// runVerifyGate runs go build and/or go test in the task worktree when
+// Cobbler.VerifyBuild or Cobbler.VerifyTests is set. Returns nil when
+// both are off or every command passes.
+func (o *Orchestrator) runVerifyGate(task stitchTask) error {
+	commands := verifyCommands(o.cfg.Cobbler.VerifyBuild, o.cfg.Cobbler.VerifyTests)
+	if len(commands) == 0 {
+		return nil
+	}
+	start := time.Now()
+	if err := runGoCommands(task.worktreeDir, commands); err != nil {
+		logf("runVerifyGate: task %s failed after %s: %v", task.id, time.Since(start).Round(time.Second), err)
+		return err
+	}
+	logf("runVerifyGate: task %s passed %d command(s) in %s", task.id, len(commands), time.Since(start).Round(time.Second))
+	return nil
+}
+
+// doneCheckDocsYAML verifies the files declared by a documentation task.
+func doneCheckDocsYAML(worktreeDir, description string) error {
+	var parsed struct {
+		Files []struct {
+			Path   string `yaml:"path"`
+			Action string `yaml:"action"`
+		} `yaml:"files"`
+	}
+	if err := yaml.Unmarshal([]byte(description), &parsed); err != nil {
+		return fmt.Errorf("parsing task files: %w", err)
+	}
+	var problems []string
+	for _, f := range parsed.Files {
+		if f.Path == "" || f.Action == "delete" {
+			continue
+		}
+		path := filepath.Join(worktreeDir, f.Path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: missing", f.Path))
+			continue
+		}
+		if ext := filepath.Ext(f.Path); ext == ".yaml" || ext == ".yml" {
+			var v any
+			if err := yaml.Unmarshal(data, &v); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: invalid YAML: %v", f.Path, err))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// changedGoFiles returns the new and modified .go files in the worktree
+// that still exist, relative to worktreeDir and sorted.
+func changedGoFiles(worktreeDir string) ([]string, error) {
+	out, err := cmdGit(worktreeDir, "ls-files", "--modified", "--others", "--exclude-standard").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files: %w", err)
+	}
+	seen := make(map[string]bool)
+	var files []string
+	for _, line := range strings.Split(string(out), "\n") {
+		path := strings.TrimSpace(line)
+		if !strings.HasSuffix(path, ".go") || seen[path] {
+			continue
+		}
+		seen[path] = true
+		if _, err := os.Stat(filepath.Join(worktreeDir, path)); err != nil {
+			continue // deleted
+		}
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// parseCheckGoFiles parses each file with go/parser and returns an error
+// listing every file that fails, with the parser's message (GH-494). It
+// catches syntax errors far faster than a full go build.
+func parseCheckGoFiles(worktreeDir string, files []string) error {
+	fset := token.NewFileSet()
+	var problems []string
+	for _, f := range files {
+		if _, err := parser.ParseFile(fset, filepath.Join(worktreeDir, f), nil, parser.SkipObjectResolution); err != nil {
+			problems = append(problems, strings.TrimPrefix(err.Error(), worktreeDir+string(filepath.Separator)))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d file(s) do not parse: %s", len(problems), strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// runParseCheck parses the task worktree's changed .go files when
+// Cobbler.StitchParseCheck is enabled. Returns nil when disabled, when no
+// .go files changed, or when all of them parse.
+func (o *Orchestrator) runParseCheck(task stitchTask) error {
+	if !o.cfg.Cobbler.effectiveStitchParseCheck() {
+		return nil
+	}
+	files, err := changedGoFiles(task.worktreeDir)
+	if err != nil {
+		logf("runParseCheck: task %s: %v; skipping", task.id, err)
+		return nil
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	if err := parseCheckGoFiles(task.worktreeDir, files); err != nil {
+		logf("runParseCheck: task %s failed: %v", task.id, err)
+		return err
+	}
+	logf("runParseCheck: task %s: %d .go file(s) parse", task.id, len(files))
+	return nil
+}
+
+// stitchRepairSection is appended to the original stitch prompt when a
+// task is sent back for repair (GH-513).
+type stitchRepairSection struct {
+	Attempt      int    `yaml:"attempt"`
+	Failure      string `yaml:"verification_failure"`
+	Instructions string `yaml:"instructions"`
+}
+
+// stitchRepairPrompt returns the original stitch prompt followed by a
+// repair section with the verification failure, so the repair session has
+// the full task context plus what went wrong.
+func stitchRepairPrompt(prompt string, attempt int, failure error) (string, error) {
+	out, err := yaml.Marshal(map[string]stitchRepairSection{"repair": {
+		Attempt: attempt,
+		Failure: failure.Error(),
+		Instructions: "Your previous attempt at this task is already in the working tree, " +
+			"but it failed verification with the output above. Fix the cause of the failure " +
+			"with the smallest change that makes it pass. Do not start over and do not " +
+			"weaken or delete tests to make them pass.",
+	}})
+	if err != nil {
+		return "", fmt.Errorf("marshaling repair section: %w", err)
+	}
+	return strings.TrimRight(prompt, "\n") + "\n" + string(out), nil
+}
+
+// repairTask re-invokes Claude in the task worktree with the verification
+// failure, re-formats the worktree, and re-runs the gate (GH-513). The
+// repair session is saved to history under the stitch-repair phase.
+// Returns nil when the gate passes after the repair, otherwise the new
+// failure.
+func (o *Orchestrator) repairTask(task stitchTask, prompt string, repair int, failure error, model string, timeout time.Duration, claudeArgs []string) error {
+	logf("repairTask: task %s repair %d/%d after: %v", task.id, repair, o.cfg.Cobbler.MaxStitchRepairs, failure)
+	repairPrompt, err := stitchRepairPrompt(prompt, repair, failure)
+	if err != nil {
+		return err
+	}
+	ts := time.Now().Format("2006-01-02-15-04-05")
+	o.saveHistoryPrompt(ts, "stitch-repair", repairPrompt)
+
+	start := time.Now()
+	tokens, claudeErr := o.runClaudeWithTimeout(repairPrompt, task.worktreeDir, timeout, o.cfg.Silence(), claudeArgs...)
+	o.saveHistoryLog(ts, "stitch-repair", tokens.RawOutput)
+
+	var result error
+	if claudeErr != nil {
+		result = fmt.Errorf("claude failure: %w", claudeErr)
+	} else {
+		// The repair session can reintroduce unformatted code, so normalize
+		// again before re-running the gate and committing.
+		o.runFormatOnStitch(task)
+		result = o.runVerifyGate(task)
+	}
+	stats := HistoryStats{
+		Caller:     "stitch-repair",
+		Generation: task.generation,
+		TaskID:     task.id,
+		TaskTitle:  task.title,
+		Attempt:    repair,
+		Model:      model,
+		Status:     "success",
+		StartedAt:  start.UTC().Format(time.RFC3339),
+		Duration:   time.Since(start).Round(time.Second).String(),
+		DurationS:  int(time.Since(start).Seconds()),
+		Tokens:     historyTokens{Input: tokens.InputTokens, Output: tokens.OutputTokens, CacheCreation: tokens.CacheCreationTokens, CacheRead: tokens.CacheReadTokens},
+		CostUSD:    tokens.CostUSD,
+		NumTurns:   tokens.NumTurns,
+	}
+	if result != nil {
+		stats.Status = "failed"
+		stats.Error = fmt.Sprintf("repair %d failure: %v", repair, result)
+	}
+	o.saveHistoryStats(ts, "stitch-repair", stats)
+	if result == nil {
+		logf("repairTask: task %s passed verification after repair %d", task.id, repair)
+	}
+	return result
+}
+
+// tailLines returns the last n lines of s.
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	claudetypes "github.com/schlunsen/claude-agent-sdk-go/types"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseDeliverableType(t *testing.T) {
+	t.Parallel()
+	if got := parseDeliverableType("deliverable_type: documentation\n"); got != "documentation" {
+		t.Errorf("parseDeliverableType = %q", got)
+	}
+	if got := parseDeliverableType("not: [valid"); got != "" {
+		t.Errorf("expected empty for invalid YAML, got %q", got)
+	}
+}
+
+func TestDoneCheckDocsYAML(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "docs"), 0o755)
+	os.WriteFile(filepath.Join(dir, "docs", "ok.yaml"), []byte("id: x\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "docs", "bad.yaml"), []byte("id: [x\n"), 0o644)
+
+	ok := "files:\n  - path: docs/ok.yaml\n    action: create\n  - path: docs/gone.yaml\n    action: delete\n"
+	if err := doneCheckDocsYAML(dir, ok); err != nil {
+		t.Errorf("expected pass, got %v", err)
+	}
+
+	bad := "files:\n  - path: docs/bad.yaml\n  - path: docs/missing.md\n"
+	err := doneCheckDocsYAML(dir, bad)
+	if err == nil {
+		t.Fatal("expected failure")
+	}
+	for _, want := range []string{"docs/bad.yaml: invalid YAML", "docs/missing.md: missing"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+}
+
+func TestRunDoneCheck_Selection(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	o := &Orchestrator{cfg: Config{Cobbler: CobblerConfig{DoneChecks: map[string]string{
+		"documentation": DoneCheckDocsYAML,
+		"code":          "bogus",
+	}}}}
+
+	outcome, err := o.runDoneCheck(stitchTask{id: "1", worktreeDir: dir, description: "deliverable_type: other\n"})
+	if outcome != "" || err != nil {
+		t.Errorf("unmapped type: outcome=%q err=%v, want no check", outcome, err)
+	}
+
+	outcome, err = o.runDoneCheck(stitchTask{id: "2", worktreeDir: dir,
+		description: "deliverable_type: documentation\nfiles:\n  - path: docs/x.yaml\n"})
+	if outcome != "docs_yaml: failed" || err == nil {
+		t.Errorf("missing doc: outcome=%q err=%v", outcome, err)
+	}
+
+	_, err = o.runDoneCheck(stitchTask{id: "3", worktreeDir: dir, description: "deliverable_type: code\n"})
+	if err == nil || !strings.Contains(err.Error(), "unknown done check") {
+		t.Errorf("expected unknown check error, got %v", err)
+	}
+}
+
+func TestDoneCheckBuildTest_FailsOnBrokenCode(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/x\n\ngo 1.21\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "x.go"), []byte("package x\n\nfunc F() int { return \"no\" }\n"), 0o644)
+	if err := doneCheckBuildTest(dir, ""); err == nil || !strings.Contains(err.Error(), "go build") {
+		t.Errorf("expected go build failure, got %v", err)
+	}
+}
+
+func TestVerifyCommands(t *testing.T) {
+	t.Parallel()
+	if got := verifyCommands(false, false); len(got) != 0 {
+		t.Errorf("both off: got %v", got)
+	}
+	if got := verifyCommands(true, false); len(got) != 1 || got[0][0] != "build" {
+		t.Errorf("build only: got %v", got)
+	}
+	if got := verifyCommands(true, true); len(got) != 2 || got[0][0] != "build" || got[1][0] != "test" {
+		t.Errorf("build and test: got %v", got)
+	}
+	if got := verifyCommands(false, true); len(got) != 1 || got[0][0] != "test" {
+		t.Errorf("test only: got %v", got)
+	}
+}
+
+func TestRunVerifyGate(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/x\n\ngo 1.21\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "x.go"), []byte("package x\n\nfunc F() int { return 1 }\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "x_test.go"), []byte("package x\n\nimport \"testing\"\n\nfunc TestF(t *testing.T) {\n\tif F() != 2 {\n\t\tt.Fatal(\"want 2\")\n\t}\n}\n"), 0o644)
+	task := stitchTask{id: "1", worktreeDir: dir}
+
+	if err := New(Config{}).runVerifyGate(task); err != nil {
+		t.Errorf("gate off: %v", err)
+	}
+	if err := New(Config{Cobbler: CobblerConfig{VerifyBuild: true}}).runVerifyGate(task); err != nil {
+		t.Errorf("build should pass: %v", err)
+	}
+	err := New(Config{Cobbler: CobblerConfig{VerifyBuild: true, VerifyTests: true}}).runVerifyGate(task)
+	if err == nil || !strings.Contains(err.Error(), "go test") {
+		t.Errorf("expected go test failure, got %v", err)
+	}
+}
+
+func TestChangedGoFilesAndParseCheck(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "test"},
+	} {
+		if out, err := cmdGit(dir, args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	os.WriteFile(filepath.Join(dir, "keep.go"), []byte("package x\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "edit.go"), []byte("package x\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "gone.go"), []byte("package x\n"), 0o644)
+	for _, args := range [][]string{{"add", "-A"}, {"commit", "-qm", "init"}} {
+		if out, err := cmdGit(dir, args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	os.WriteFile(filepath.Join(dir, "edit.go"), []byte("package x\n\nfunc f() {\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "new.go"), []byte("package x\n\nfunc g() {}\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not go"), 0o644)
+	os.Remove(filepath.Join(dir, "gone.go"))
+
+	files, err := changedGoFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(files, ",") != "edit.go,new.go" {
+		t.Fatalf("changedGoFiles = %v, want [edit.go new.go]", files)
+	}
+
+	err = parseCheckGoFiles(dir, files)
+	if err == nil {
+		t.Fatal("expected parse failure for edit.go")
+	}
+	if !strings.Contains(err.Error(), "edit.go:") || strings.Contains(err.Error(), "new.go") {
+		t.Errorf("error = %v, want only edit.go reported", err)
+	}
+	if err := parseCheckGoFiles(dir, []string{"new.go", "keep.go"}); err != nil {
+		t.Errorf("valid files reported: %v", err)
+	}
+}
+
+func TestEffectiveStitchParseCheck(t *testing.T) {
+	t.Parallel()
+	var c CobblerConfig
+	if !c.effectiveStitchParseCheck() {
+		t.Error("nil StitchParseCheck should default to true")
+	}
+	off := false
+	c.StitchParseCheck = &off
+	if c.effectiveStitchParseCheck() {
+		t.Error("explicit false should disable the parse check")
+	}
+}
+
+func TestStitchRepairPrompt(t *testing.T) {
+	t.Parallel()
+	prompt, err := stitchRepairPrompt("role: stitcher\ntask: do it\n", 2, errors.New("go build ./...: exit status 1\nx.go:3: undefined: y"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Role   string              `yaml:"role"`
+		Task   string              `yaml:"task"`
+		Repair stitchRepairSection `yaml:"repair"`
+	}
+	if err := yaml.Unmarshal([]byte(prompt), &doc); err != nil {
+		t.Fatalf("repair prompt is not valid YAML: %v\n%s", err, prompt)
+	}
+	if doc.Role != "stitcher" || doc.Task != "do it" {
+		t.Errorf("original prompt not preserved: %+v", doc)
+	}
+	if doc.Repair.Attempt != 2 || !strings.Contains(doc.Repair.Failure, "undefined: y") || doc.Repair.Instructions == "" {
+		t.Errorf("repair section = %+v", doc.Repair)
+	}
+}
+
+func TestRepairTask_FixesBuildAndRecordsHistory(t *testing.T) {
+	chdirTemp(t)
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/x\n\ngo 1.21\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "x.go"), []byte("package x\n\nfunc F() int { return \"no\" }\n"), 0o644)
+
+	history := t.TempDir()
+	o := New(Config{Cobbler: CobblerConfig{Mode: ExecutionModeSDK, VerifyBuild: true, MaxStitchRepairs: 1, HistoryDir: history}})
+	var gotPrompt string
+	fake := fakeSdkQuery(assistantMsg("fixed"), resultMsg(5, 7, 0.01))
+	o.sdkQueryFn = func(ctx context.Context, prompt string, opts *claudetypes.ClaudeAgentOptions) (<-chan claudetypes.Message, error) {
+		gotPrompt = prompt
+		os.WriteFile(filepath.Join(dir, "x.go"), []byte("package x\n\nfunc F() int { return 1 }\n"), 0o644)
+		return fake(ctx, prompt, opts)
+	}
+
+	task := stitchTask{id: "9", title: "t", worktreeDir: dir}
+	failure := o.runVerifyGate(task)
+	if failure == nil {
+		t.Fatal("precondition: broken code should fail the gate")
+	}
+	if err := o.repairTask(task, "task: original\n", 1, failure, "", time.Minute, nil); err != nil {
+		t.Fatalf("repairTask: %v", err)
+	}
+	if !strings.Contains(gotPrompt, "task: original") || !strings.Contains(gotPrompt, "verification_failure") {
+		t.Errorf("repair prompt missing task or failure:\n%s", gotPrompt)
+	}
+	entries := loadHistoryStats(history)
+	if len(entries) != 1 || entries[0].Phase != "stitch-repair" || entries[0].Stats.Status != "success" || entries[0].Stats.TaskID != "9" {
+		t.Errorf("history = %+v", entries)
+	}
+}
+
+func TestLoadConfig_DoneChecks(t *testing.T) {
+	t.Parallel()
+	cfg, err := LoadConfig(writeTemp(t, "cobbler:\n  done_checks:\n    code: build_test\n    documentation: none\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Cobbler.DoneChecks["code"] != DoneCheckBuildTest {
+		t.Errorf("done_checks.code = %q, want %q", cfg.Cobbler.DoneChecks["code"], DoneCheckBuildTest)
+	}
+	_, err = LoadConfig(writeTemp(t, "cobbler:\n  done_checks:\n    code: build-test\n"))
+	if err == nil || !strings.Contains(err.Error(), "done_checks.code") {
+		t.Errorf("LoadConfig with unknown done check = %v, want an error naming done_checks.code", err)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// remainingEstimate is the projected cost and time to drain the open
+// backlog, computed by estimateRemaining.
+type remainingEstimate struct {
+	CompletedTasks int     // successful stitches used for the rates
+	CompletedLOC   int     // LOC delta (prod+test) of those stitches
+	CostPerLOC     float64 // USD per changed line
+	SecondsPerLOC  float64 // wall-clock seconds per changed line
+
+	OpenTasks int // open tasks in the backlog
+	LOCLow    int // sum of low per-task estimates
+	LOCHigh   int // sum of high per-task estimates
+
+	CostLow, CostHigh         float64
+	DurationLow, DurationHigh time.Duration
+}
+
+// taskLineEstimate returns the low and high line estimates for a task.
+// An estimated_lines value in the description is used for both bounds;
+// otherwise the configured EstimatedLinesMin/Max range applies.
+func taskLineEstimate(description string, min, max int) (int, int) {
+	var parsed struct {
+		EstimatedLines int `yaml:"estimated_lines"`
+	}
+	if err := yaml.Unmarshal([]byte(description), &parsed); err == nil && parsed.EstimatedLines > 0 {
+		return parsed.EstimatedLines, parsed.EstimatedLines
+	}
+	return min, max
+}
+
+// estimateRemaining derives cost and duration per changed line from the
+// successful stitch entries in history and projects them onto the open
+// tasks' line estimates. Stitches that changed no lines are skipped so
+// they do not distort the rates. When history has no usable entries the
+// rates and projections are zero.
+func estimateRemaining(history []historyStatsEntry, open []cobblerIssue, linesMin, linesMax int) remainingEstimate {
+	var est remainingEstimate
+	var cost float64
+	var seconds int
+	for _, e := range history {
+		if e.Phase != "stitch" || e.Stats.Status != "success" {
+			continue
+		}
+		loc := absInt(e.Stats.LOCAfter.Production-e.Stats.LOCBefore.Production) +
+			absInt(e.Stats.LOCAfter.Test-e.Stats.LOCBefore.Test)
+		if loc == 0 {
+			continue
+		}
+		est.CompletedTasks++
+		est.CompletedLOC += loc
+		cost += e.Stats.CostUSD
+		seconds += e.Stats.DurationS
+	}
+	if est.CompletedLOC > 0 {
+		est.CostPerLOC = cost / float64(est.CompletedLOC)
+		est.SecondsPerLOC = float64(seconds) / float64(est.CompletedLOC)
+	}
+
+	for _, iss := range open {
+		lo, hi := taskLineEstimate(iss.Description, linesMin, linesMax)
+		est.OpenTasks++
+		est.LOCLow += lo
+		est.LOCHigh += hi
+	}
+	est.CostLow = est.CostPerLOC * float64(est.LOCLow)
+	est.CostHigh = est.CostPerLOC * float64(est.LOCHigh)
+	est.DurationLow = time.Duration(est.SecondsPerLOC * float64(est.LOCLow) * float64(time.Second)).Round(time.Second)
+	est.DurationHigh = time.Duration(est.SecondsPerLOC * float64(est.LOCHigh) * float64(time.Second)).Round(time.Second)
+	return est
+}
+
+// absInt returns the absolute value of n.
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// EstimateRemaining projects the cost and wall-clock time needed to drain
+// the current generation's open backlog. Rates (cost and seconds per
+// changed line) come from the generation's successful stitches in the
+// history directory; each open task contributes its estimated_lines, or
+// the configured EstimatedLinesMin..EstimatedLinesMax range when the
+// description has none. Accuracy improves as more tasks complete.
+func (o *Orchestrator) EstimateRemaining() error {
+	branches := o.listGenerationBranches()
+	genBranch := o.cfg.Generation.Branch
+	if genBranch == "" {
+		if len(branches) == 0 {
+			fmt.Println("no active generation branches found")
+			return nil
+		}
+		genBranch = branches[0]
+	}
+
+	repo, err := detectGitHubRepo(".", o.cfg)
+	if err != nil || repo == "" {
+		return fmt.Errorf("detecting GitHub repo: %w", err)
+	}
+	open, err := listOpenCobblerIssues(repo, genBranch)
+	if err != nil {
+		return fmt.Errorf("listing cobbler issues for %s: %w", genBranch, err)
+	}
+
+	est := estimateRemaining(loadHistoryStats(o.historyDir()), open,
+		o.cfg.Cobbler.EstimatedLinesMin, o.cfg.Cobbler.EstimatedLinesMax)
+
+	fmt.Printf("generation %s: %d open task(s), %d-%d estimated lines\n",
+		genBranch, est.OpenTasks, est.LOCLow, est.LOCHigh)
+	if est.CompletedTasks == 0 {
+		fmt.Println("no completed stitches with LOC changes in history; cannot project cost yet")
+		return nil
+	}
+	fmt.Printf("history: %d completed task(s), %d lines, $%.4f/line, %.1fs/line\n",
+		est.CompletedTasks, est.CompletedLOC, est.CostPerLOC, est.SecondsPerLOC)
+	fmt.Printf("remaining: $%.2f-$%.2f, %s-%s\n",
+		est.CostLow, est.CostHigh, est.DurationLow, est.DurationHigh)
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaskLineEstimate(t *testing.T) {
+	t.Parallel()
+	if lo, hi := taskLineEstimate("estimated_lines: 120\n", 250, 350); lo != 120 || hi != 120 {
+		t.Errorf("explicit estimate = %d-%d, want 120-120", lo, hi)
+	}
+	if lo, hi := taskLineEstimate("description: x\n", 250, 350); lo != 250 || hi != 350 {
+		t.Errorf("fallback estimate = %d-%d, want 250-350", lo, hi)
+	}
+}
+
+func TestEstimateRemaining(t *testing.T) {
+	t.Parallel()
+	history := []historyStatsEntry{
+		{Phase: "stitch", Stats: HistoryStats{Status: "success", CostUSD: 1.0, DurationS: 100,
+			LOCBefore: LocSnapshot{Production: 0}, LOCAfter: LocSnapshot{Production: 80, Test: 20}}},
+		{Phase: "stitch", Stats: HistoryStats{Status: "success", CostUSD: 5.0, DurationS: 500}}, // no LOC change
+		{Phase: "stitch", Stats: HistoryStats{Status: "failed", CostUSD: 9.0, LOCAfter: LocSnapshot{Production: 10}}},
+		{Phase: "measure", Stats: HistoryStats{Status: "success", CostUSD: 2.0}},
+	}
+	open := []cobblerIssue{
+		{Number: 1, Description: "estimated_lines: 200\n"},
+		{Number: 2},
+	}
+	est := estimateRemaining(history, open, 100, 300)
+
+	if est.CompletedTasks != 1 || est.CompletedLOC != 100 {
+		t.Errorf("completed = %d tasks / %d LOC, want 1 / 100", est.CompletedTasks, est.CompletedLOC)
+	}
+	if est.LOCLow != 300 || est.LOCHigh != 500 {
+		t.Errorf("LOC range = %d-%d, want 300-500", est.LOCLow, est.LOCHigh)
+	}
+	if est.CostLow != 3.0 || est.CostHigh != 5.0 {
+		t.Errorf("cost range = %.2f-%.2f, want 3.00-5.00", est.CostLow, est.CostHigh)
+	}
+	if est.DurationLow != 300*time.Second || est.DurationHigh != 500*time.Second {
+		t.Errorf("duration range = %s-%s", est.DurationLow, est.DurationHigh)
+	}
+}
+
+func TestEstimateRemaining_NoHistory(t *testing.T) {
+	t.Parallel()
+	est := estimateRemaining(nil, []cobblerIssue{{Number: 1}}, 250, 350)
+	if est.CostHigh != 0 || est.OpenTasks != 1 || est.LOCHigh != 350 {
+		t.Errorf("unexpected estimate %+v", est)
+	}
+}
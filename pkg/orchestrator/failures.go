@@ -0,0 +1,177 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// historyStatsEntry pairs a parsed HistoryStats file with the timestamp
+// and phase encoded in its filename ({ts}-{phase}-stats.yaml).
+type historyStatsEntry struct {
+	Timestamp string
+	Phase     string
+	Stats     HistoryStats
+}
+
+// loadHistoryStats reads every *-stats.yaml file in dir and returns the
+// parsed entries sorted by timestamp. Unreadable or malformed files are
+// logged and skipped. Returns nil when dir is empty or does not exist.
+func loadHistoryStats(dir string) []historyStatsEntry {
+	if dir == "" {
+		return nil
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, "*-stats.yaml"))
+	if err != nil {
+		logf("loadHistoryStats: glob %s: %v", dir, err)
+		return nil
+	}
+	sort.Strings(matches)
+
+	var entries []historyStatsEntry
+	for _, path := range matches {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logf("loadHistoryStats: read %s: %v", path, err)
+			continue
+		}
+		var stats HistoryStats
+		if err := yaml.Unmarshal(data, &stats); err != nil {
+			logf("loadHistoryStats: parse %s: %v", path, err)
+			continue
+		}
+		ts, phase := splitHistoryStatsName(filepath.Base(path))
+		entries = append(entries, historyStatsEntry{Timestamp: ts, Phase: phase, Stats: stats})
+	}
+	return entries
+}
+
+// splitHistoryStatsName extracts the timestamp and phase from a history
+// stats filename of the form {2006-01-02-15-04-05}-{phase}-stats.yaml.
+func splitHistoryStatsName(name string) (ts, phase string) {
+	base := strings.TrimSuffix(name, "-stats.yaml")
+	const tsLen = len("2006-01-02-15-04-05")
+	if len(base) <= tsLen || base[tsLen] != '-' {
+		return base, ""
+	}
+	return base[:tsLen], base[tsLen+1:]
+}
+
+// failureGroup collects failed invocations whose error messages normalize
+// to the same signature.
+type failureGroup struct {
+	Signature string
+	Count     int
+	Phases    []string
+	Tasks     []string
+}
+
+var (
+	failureHexRe    = regexp.MustCompile(`\b[0-9a-f]{7,40}\b`)
+	failureNumberRe = regexp.MustCompile(`\d+`)
+	failureSpaceRe  = regexp.MustCompile(`\s+`)
+)
+
+// failureSignature normalizes an error message so that failures differing
+// only in numbers, hashes, or whitespace (issue numbers, durations, commit
+// SHAs) fall into the same group. Only the first line is considered.
+func failureSignature(msg string) string {
+	if i := strings.IndexByte(msg, '\n'); i >= 0 {
+		msg = msg[:i]
+	}
+	msg = failureHexRe.ReplaceAllString(msg, "<hash>")
+	msg = failureNumberRe.ReplaceAllString(msg, "N")
+	msg = failureSpaceRe.ReplaceAllString(strings.TrimSpace(msg), " ")
+	if msg == "" {
+		return "(no error message)"
+	}
+	return msg
+}
+
+// groupFailures returns one failureGroup per distinct error signature among
+// entries whose status is "failed", ordered by descending count and then
+// by signature.
+func groupFailures(entries []historyStatsEntry) []failureGroup {
+	bySig := map[string]*failureGroup{}
+	for _, e := range entries {
+		if e.Stats.Status != "failed" {
+			continue
+		}
+		sig := failureSignature(e.Stats.Error)
+		g, ok := bySig[sig]
+		if !ok {
+			g = &failureGroup{Signature: sig}
+			bySig[sig] = g
+		}
+		g.Count++
+		g.Phases = appendUnique(g.Phases, e.Phase)
+		task := e.Stats.TaskID
+		if task == "" {
+			task = e.Stats.Caller
+		}
+		g.Tasks = appendUnique(g.Tasks, task)
+	}
+
+	groups := make([]failureGroup, 0, len(bySig))
+	for _, g := range bySig {
+		groups = append(groups, *g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Signature < groups[j].Signature
+	})
+	return groups
+}
+
+// appendUnique appends s to list unless it is empty or already present.
+func appendUnique(list []string, s string) []string {
+	if s == "" {
+		return list
+	}
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// FailureReport scans the current generation's history directory for
+// failed invocations, groups them by normalized error message, and prints
+// a table of each distinct error with its count, phases, and affected
+// tasks. It is the failure-side complement to GeneratorStats and surfaces
+// systemic problems (recurring merge failures, repeated timeouts) that are
+// hard to see in per-task stats files.
+func (o *Orchestrator) FailureReport() error {
+	entries := loadHistoryStats(o.historyDir())
+	groups := groupFailures(entries)
+	if len(groups) == 0 {
+		fmt.Printf("no failures recorded in %s (%d invocation(s) scanned)\n", o.historyDir(), len(entries))
+		return nil
+	}
+
+	total := 0
+	for _, g := range groups {
+		total += g.Count
+	}
+	fmt.Printf("%d failure(s) across %d invocation(s), %d distinct error(s)\n\n", total, len(entries), len(groups))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Count\tPhase\tTasks\tError")
+	for _, g := range groups {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n",
+			g.Count, strings.Join(g.Phases, ","), strings.Join(g.Tasks, ","), g.Signature)
+	}
+	return w.Flush()
+}
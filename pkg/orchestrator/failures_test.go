@@ -0,0 +1,83 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSplitHistoryStatsName(t *testing.T) {
+	t.Parallel()
+	ts, phase := splitHistoryStatsName("2026-02-10-15-04-05-stitch-stats.yaml")
+	if ts != "2026-02-10-15-04-05" || phase != "stitch" {
+		t.Errorf("got (%q, %q)", ts, phase)
+	}
+	ts, phase = splitHistoryStatsName("odd-stats.yaml")
+	if ts != "odd" || phase != "" {
+		t.Errorf("got (%q, %q) for malformed name", ts, phase)
+	}
+}
+
+func TestFailureSignature_NormalizesNumbersAndHashes(t *testing.T) {
+	t.Parallel()
+	a := failureSignature("merging task/gen-42: exit status 1\nCONFLICT in foo.go")
+	b := failureSignature("merging task/gen-7:  exit status 128")
+	if a != b {
+		t.Errorf("signatures differ: %q vs %q", a, b)
+	}
+	if got := failureSignature("commit abc1234def failed"); got != "commit <hash> failed" {
+		t.Errorf("failureSignature = %q", got)
+	}
+	if got := failureSignature(""); got != "(no error message)" {
+		t.Errorf("failureSignature(\"\") = %q", got)
+	}
+}
+
+func TestGroupFailures(t *testing.T) {
+	t.Parallel()
+	entries := []historyStatsEntry{
+		{Phase: "stitch", Stats: HistoryStats{Status: "failed", TaskID: "10", Error: "claude failure: timeout after 300s"}},
+		{Phase: "stitch", Stats: HistoryStats{Status: "failed", TaskID: "11", Error: "claude failure: timeout after 600s"}},
+		{Phase: "stitch", Stats: HistoryStats{Status: "success", TaskID: "12"}},
+		{Phase: "measure", Stats: HistoryStats{Status: "failed", Caller: "measure", Error: "import failed"}},
+	}
+	groups := groupFailures(entries)
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2: %+v", len(groups), groups)
+	}
+	if groups[0].Count != 2 || len(groups[0].Tasks) != 2 {
+		t.Errorf("first group = %+v, want count 2 with 2 tasks", groups[0])
+	}
+	if groups[1].Tasks[0] != "measure" || groups[1].Phases[0] != "measure" {
+		t.Errorf("second group = %+v, want caller fallback", groups[1])
+	}
+}
+
+func TestLoadHistoryStats(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	data, _ := yaml.Marshal(HistoryStats{Caller: "stitch", Status: "failed", Error: "boom"})
+	os.WriteFile(filepath.Join(dir, "2026-02-10-15-04-05-stitch-stats.yaml"), data, 0o644)
+	os.WriteFile(filepath.Join(dir, "2026-02-10-15-04-06-stitch-stats.yaml"), []byte(":\n\t- bad"), 0o644)
+	os.WriteFile(filepath.Join(dir, "2026-02-10-15-04-05-stitch-prompt.yaml"), []byte("role: x"), 0o644)
+
+	entries := loadHistoryStats(dir)
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	if entries[0].Phase != "stitch" || entries[0].Stats.Error != "boom" {
+		t.Errorf("entry = %+v", entries[0])
+	}
+}
+
+func TestLoadHistoryStats_EmptyDir(t *testing.T) {
+	t.Parallel()
+	if got := loadHistoryStats(""); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+}
@@ -5,8 +5,11 @@ package orchestrator
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"maps"
 	"slices"
@@ -51,9 +54,21 @@ func (o *Orchestrator) GeneratorResume() error {
 	if !gitBranchExists(branch, ".") {
 		return fmt.Errorf("branch does not exist: %s", branch)
 	}
+	// Resuming does not need the base branch, but generator:stop will;
+	// flag a missing one now rather than after hours of stitching (GH-562).
+	base := o.cfg.Generation.BaseBranch
+	if base == "" {
+		base = o.baseBranchAt(branch)
+	}
+	if err := checkBaseBranch(base); err != nil {
+		logf("resume: warning: %v", err)
+	}
 
 	setGeneration(branch)
 	defer clearGeneration()
+	defer o.openCostLedger(branch)()
+	defer o.openStitchCounter(branch, true)()
+	defer o.handleInterrupts()()
 
 	logf("resume: target branch=%s", branch)
 
@@ -64,7 +79,7 @@ func (o *Orchestrator) GeneratorResume() error {
 
 	// Pre-flight cleanup.
 	logf("resume: pre-flight cleanup")
-	wtBase := worktreeBasePath()
+	wtBase := worktreeBasePath(o.cfg.Cobbler.WorktreeDir)
 
 	logf("resume: pruning worktrees")
 	if err := gitWorktreePrune("."); err != nil {
@@ -87,10 +102,25 @@ func (o *Orchestrator) GeneratorResume() error {
 
 	o.cfg.Generation.Branch = branch
 
+	// Continue the MaxStitchIssues budget from where the interrupted run
+	// stopped rather than starting it over (GH-549).
+	if stitched := o.stitched.total(); stitched > 0 {
+		logf("resume: %d task(s) already stitched this generation (max %d)", stitched, o.cfg.Cobbler.MaxStitchIssues)
+	}
+
 	// Drain existing ready issues before starting measure+stitch cycles.
-	logf("resume: draining existing ready issues")
-	if _, err := o.RunStitch(); err != nil {
-		logf("resume: drain stitch warning: %v", err)
+	// The drain is bounded only by what remains of MaxStitchIssues.
+	if limit, ok := stitchLimit(0, o.cfg.Cobbler.MaxStitchIssues, o.stitched.total()); ok {
+		logf("resume: draining existing ready issues (limit=%d)", limit)
+		_, err := o.RunStitchN(limit)
+		if errors.Is(err, errInterrupted) {
+			return err
+		}
+		if err != nil {
+			logf("resume: drain stitch warning: %v", err)
+		}
+	} else {
+		logf("resume: total stitch limit (%d) already reached, skipping drain", o.cfg.Cobbler.MaxStitchIssues)
 	}
 
 	return o.RunCycles("resume")
@@ -103,41 +133,69 @@ func (o *Orchestrator) GeneratorResume() error {
 // (0 = unlimited). Cycles caps the number of stitch+measure rounds
 // (0 = unlimited). MaxConsecutiveZeroLOCCycles stops the loop when stitch
 // produces zero LOC change for N consecutive cycles (default 3), preventing
-// runaway refinement loops on fully-implemented specs.
+// runaway refinement loops on fully-implemented specs. Generation.MaxDuration
+// stops the loop at the first cycle boundary after the deadline (0 = no cap).
+// Generation.MaxCostUSD stops it after the stitch task that brings the
+// generation's Claude spend to the ceiling. SIGINT or SIGTERM resets the
+// task in flight and stops the loop with errInterrupted; a second signal
+// exits immediately. The stitch total is persisted after every task so
+// generator:resume continues it (GH-549).
 func (o *Orchestrator) RunCycles(label string) error {
 	maxZeroLOC := o.cfg.Cobbler.MaxConsecutiveZeroLOCCycles
-	logf("generator %s: starting (stitchTotal=%d stitchPerCycle=%d measure=%d safetyCycles=%d maxZeroLOC=%d)",
-		label, o.cfg.Cobbler.MaxStitchIssues, o.cfg.Cobbler.MaxStitchIssuesPerCycle, o.cfg.Cobbler.MaxMeasureIssues, o.cfg.Generation.Cycles, maxZeroLOC)
-
-	totalStitched := 0
+	maxDuration := o.cfg.GenerationMaxDuration()
+	logf("generator %s: starting (stitchTotal=%d stitchPerCycle=%d measure=%d safetyCycles=%d maxZeroLOC=%d maxDuration=%s)",
+		label, o.cfg.Cobbler.MaxStitchIssues, o.cfg.Cobbler.MaxStitchIssuesPerCycle, o.cfg.Cobbler.MaxMeasureIssues, o.cfg.Generation.Cycles, maxZeroLOC, maxDuration)
+
+	start := time.Now()
+	var deadline time.Time
+	if maxDuration > 0 {
+		deadline = start.Add(maxDuration)
+	}
+	generation := o.cfg.Generation.Branch
+	if generation == "" {
+		generation, _ = gitCurrentBranch(".") // best-effort; "" keeps the ledger in the generation-meta root
+	}
+	defer o.openCostLedger(generation)()
+	defer o.openStitchCounter(generation, false)()
+	defer o.handleInterrupts()()
+	cyclesRun := 0
+	defer func() { o.writeRunSummary(generation, start, cyclesRun) }()
 	consecutiveZeroLOC := 0
 	for cycle := 1; ; cycle++ {
 		if o.cfg.Generation.Cycles > 0 && cycle > o.cfg.Generation.Cycles {
 			logf("generator %s: reached max cycles (%d), stopping", label, o.cfg.Generation.Cycles)
 			break
 		}
+		if deadlinePassed(deadline, time.Now()) {
+			logf("generator %s: reached max duration (%s) after %d cycle(s), stopping", label, maxDuration, cycle-1)
+			break
+		}
+		if o.costBudgetExhausted() {
+			logf("generator %s: reached cost ceiling ($%.2f spent, max $%.2f) after %d cycle(s), stopping",
+				label, o.spend.total(), o.cfg.Generation.MaxCostUSD, cycle-1)
+			break
+		}
 
 		// Determine how many tasks this cycle can stitch.
-		perCycle := o.cfg.Cobbler.MaxStitchIssuesPerCycle
-		if o.cfg.Cobbler.MaxStitchIssues > 0 {
-			remaining := o.cfg.Cobbler.MaxStitchIssues - totalStitched
-			if remaining <= 0 {
-				logf("generator %s: reached total stitch limit (%d), stopping", label, o.cfg.Cobbler.MaxStitchIssues)
-				break
-			}
-			if perCycle == 0 || remaining < perCycle {
-				perCycle = remaining
-			}
+		perCycle, ok := stitchLimit(o.cfg.Cobbler.MaxStitchIssuesPerCycle, o.cfg.Cobbler.MaxStitchIssues, o.stitched.total())
+		if !ok {
+			logf("generator %s: reached total stitch limit (%d), stopping", label, o.cfg.Cobbler.MaxStitchIssues)
+			break
 		}
 
+		cyclesRun = cycle
+
 		// Refresh analysis before each cycle so stitch sees current state.
 		o.RunPreCycleAnalysis()
 
 		// Capture LOC before stitch to detect zero-change cycles.
 		locBefore := o.captureLOC()
-		logf("generator %s: cycle %d — stitch (limit=%d, stitched so far=%d)", label, cycle, perCycle, totalStitched)
-		n, err := o.RunStitchN(perCycle)
-		totalStitched += n
+		logf("generator %s: cycle %d — stitch (limit=%d, stitched so far=%d)", label, cycle, perCycle, o.stitched.total())
+		_, err := o.RunStitchN(perCycle)
+		if o.interrupted() {
+			logf("generator %s: cycle %d — interrupted during stitch (total stitched=%d), stopping", label, cycle, o.stitched.total())
+			return errInterrupted
+		}
 		if err != nil {
 			return fmt.Errorf("cycle %d stitch: %w", cycle, err)
 		}
@@ -164,8 +222,19 @@ func (o *Orchestrator) RunCycles(label string) error {
 			logf("generator %s: cycle %d — auto-advanced release %s", label, cycle, ver)
 		}
 
+		if o.costBudgetExhausted() {
+			logf("generator %s: cycle %d — reached cost ceiling ($%.2f spent, max $%.2f), stopping before measure",
+				label, cycle, o.spend.total(), o.cfg.Generation.MaxCostUSD)
+			break
+		}
+
 		logf("generator %s: cycle %d — measure", label, cycle)
-		if err := o.RunMeasure(); err != nil {
+		err = o.RunMeasure()
+		if o.interrupted() {
+			logf("generator %s: cycle %d — interrupted during measure, stopping", label, cycle)
+			return errInterrupted
+		}
+		if err != nil {
 			return fmt.Errorf("cycle %d measure: %w", cycle, err)
 		}
 
@@ -180,10 +249,16 @@ func (o *Orchestrator) RunCycles(label string) error {
 		logf("generator %s: open issues remain, continuing to cycle %d", label, cycle+1)
 	}
 
-	logf("generator %s: complete (total stitched=%d)", label, totalStitched)
+	logf("generator %s: complete (total stitched=%d, elapsed=%s)", label, o.stitched.total(), time.Since(start).Round(time.Second))
 	return nil
 }
 
+// deadlinePassed reports whether now is at or after deadline. A zero
+// deadline never passes.
+func deadlinePassed(deadline, now time.Time) bool {
+	return !deadline.IsZero() && !now.Before(deadline)
+}
+
 // checkAutoAdvanceRelease detects when the current release's use cases are all
 // done and auto-advances by calling ReleaseUpdate (which marks UCs as
 // "implemented" in road-map.yaml and removes the release from
@@ -273,6 +348,7 @@ func (o *Orchestrator) GeneratorStart() error {
 
 	setGeneration(genName)
 	defer clearGeneration()
+	o.clearStitchCount(genName)
 
 	logf("generator:start: beginning (base branch: %s)", baseBranch)
 
@@ -300,6 +376,12 @@ func (o *Orchestrator) GeneratorStart() error {
 		return fmt.Errorf("recording base branch: %w", err)
 	}
 
+	// Snapshot the resolved prompt templates so this generation's output
+	// stays tied to the prompts that produced it (GH-461).
+	if err := o.snapshotPromptTemplates(genName); err != nil {
+		return fmt.Errorf("snapshotting prompt templates: %w", err)
+	}
+
 	// Ensure bin/ is ignored on the generation branch so compiled binaries
 	// are never staged by git add -A (GH-469).
 	if err := appendToGitignore(".", o.cfg.Project.BinaryDir+"/"); err != nil {
@@ -368,6 +450,57 @@ func (o *Orchestrator) readBaseBranch() string {
 	return branch
 }
 
+// generationMetaDir is the cobbler subdirectory holding per-generation
+// metadata. Each generation gets its own {generationMetaDir}/{name}/ folder
+// so snapshots from different generations can be diffed side by side.
+const generationMetaDir = "generation-meta"
+
+// promptSnapshotPath returns the path of the prompt template snapshot for
+// the given generation and phase ("measure" or "stitch").
+func (o *Orchestrator) promptSnapshotPath(generation, phase string) string {
+	return filepath.Join(o.cfg.Cobbler.Dir, generationMetaDir, generation, phase+"-prompt.yaml")
+}
+
+// snapshotPromptTemplates writes the resolved measure and stitch prompt
+// templates (configured or embedded) into the generation's metadata
+// directory. The files are committed with the generation start commit.
+func (o *Orchestrator) snapshotPromptTemplates(generation string) error {
+	templates := map[string]string{
+		"measure":          orDefault(o.cfg.Cobbler.MeasurePrompt, defaultMeasurePrompt),
+		"spec-maintenance": defaultSpecMaintenancePrompt,
+		"stitch":           orDefault(o.cfg.Cobbler.StitchPrompt, defaultStitchPrompt),
+	}
+	for _, phase := range []string{"measure", "spec-maintenance", "stitch"} {
+		path := o.promptSnapshotPath(generation, phase)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(templates[phase]), 0o644); err != nil {
+			return fmt.Errorf("writing %s: %w", path, err)
+		}
+		logf("generator:start: snapshotted %s prompt to %s", phase, path)
+	}
+	return nil
+}
+
+// resolvePromptTemplate returns the prompt template for phase. When a
+// generation is active and generator:start snapshotted its templates, the
+// snapshot wins so prompt experiments stay reproducible for the lifetime
+// of the generation. Otherwise configured falls back to embedded.
+func (o *Orchestrator) resolvePromptTemplate(phase, configured, embedded string) string {
+	phaseMu.RLock()
+	generation := currentGeneration
+	phaseMu.RUnlock()
+	if generation != "" {
+		path := o.promptSnapshotPath(generation, phase)
+		if data, err := os.ReadFile(path); err == nil && len(data) > 0 {
+			logf("resolvePromptTemplate: using %s snapshot %s", phase, path)
+			return string(data)
+		}
+	}
+	return orDefault(configured, embedded)
+}
+
 // resolveStopTarget returns the branch that generator:stop should merge into.
 // callerBranch is the branch checked out when generator:stop was invoked.
 // genBranch is the generation branch being stopped. recordedBase is the branch
@@ -382,6 +515,27 @@ func resolveStopTarget(callerBranch, genBranch, recordedBase string) string {
 	return recordedBase
 }
 
+// stopTarget returns the merge target for generator:stop: the
+// Generation.BaseBranch override when set, otherwise resolveStopTarget
+// (GH-562).
+func (o *Orchestrator) stopTarget(callerBranch, genBranch, recordedBase string) string {
+	if o.cfg.Generation.BaseBranch != "" {
+		return o.cfg.Generation.BaseBranch
+	}
+	return resolveStopTarget(callerBranch, genBranch, recordedBase)
+}
+
+// checkBaseBranch returns an actionable error when base is not a local
+// branch, so generator:stop fails before tagging or switching branches
+// rather than part-way through the merge (GH-562).
+func checkBaseBranch(base string) error {
+	if gitBranchExists(base, ".") {
+		return nil
+	}
+	return fmt.Errorf("base branch %s does not exist (deleted or renamed during the generation?)\n"+
+		"Recreate it, or set generation.base_branch in configuration.yaml to merge into another branch", base)
+}
+
 // GeneratorStop completes a generation trail and merges it into the base branch.
 // Reads the base branch from .cobbler/base-branch (falls back to "main").
 // Uses Config.GenerationBranch, current branch, or auto-detects.
@@ -412,11 +566,13 @@ func (o *Orchestrator) GeneratorStop() error {
 		return fmt.Errorf("not a generation branch: %s\nSet generation.branch in configuration.yaml", branch)
 	}
 
+	if o.cfg.Generation.DryRun {
+		return o.printStopPlan(branch)
+	}
+
 	setGeneration(branch)
 	defer clearGeneration()
 
-	finishedTag := branch + "-finished"
-
 	logf("generator:stop: beginning")
 
 	// Capture the caller's branch before switching to the generation branch.
@@ -436,105 +592,296 @@ func (o *Orchestrator) GeneratorStop() error {
 
 	// Determine the merge target (GH-523).
 	recordedBase := o.readBaseBranch()
-	baseBranch := resolveStopTarget(callerBranch, branch, recordedBase)
-	if baseBranch != recordedBase {
+	baseBranch := o.stopTarget(callerBranch, branch, recordedBase)
+	if o.cfg.Generation.BaseBranch != "" && baseBranch != recordedBase {
+		logf("generator:stop: generation.base_branch set; using %s as merge target instead of recorded base %s", baseBranch, recordedBase)
+	} else if baseBranch != recordedBase {
 		logf("generator:stop: caller was on %s; using it as merge target instead of recorded base %s", callerBranch, recordedBase)
 	}
-
-	logf("generator:stop: tagging as %s", finishedTag)
-	if err := gitTag(finishedTag, "."); err != nil {
-		return fmt.Errorf("tagging generation: %w", err)
-	}
-
-	// Switch to the base branch.
-	logf("generator:stop: switching to %s", baseBranch)
-	if err := gitCheckout(baseBranch, "."); err != nil {
-		return fmt.Errorf("checking out %s: %w", baseBranch, err)
-	}
-
-	if err := o.mergeGeneration(branch, baseBranch); err != nil {
+	if err := checkBaseBranch(baseBranch); err != nil {
 		return err
 	}
 
-	// Close any open cobbler-gen issues for this generation so they do not
-	// accumulate as orphans after the branch is deleted.
-	if ghRepo, err := detectGitHubRepo(".", o.cfg); err == nil && ghRepo != "" {
-		if err := closeGenerationIssues(ghRepo, branch); err != nil {
-			logf("generator:stop: close issues warning: %v", err)
+	for _, st := range o.stopSteps(branch, baseBranch) {
+		logf("generator:stop: %s", st.desc)
+		if err := st.run(); err != nil {
+			return err
 		}
 	}
 
-	o.cleanupDirs()
-
 	logf("generator:stop: done, work is on %s", baseBranch)
 	return nil
 }
 
-// mergeGeneration resets Go sources, commits the clean state, merges the
-// generation branch into the base branch, tags the result, resets the base
-// branch to specs-only, and deletes the generation branch.
-// When preserve_sources is true the pre-merge source reset and the
-// post-tag cleanGoSources call are both skipped (prd002 R10.2).
-func (o *Orchestrator) mergeGeneration(branch, baseBranch string) error {
-	if o.cfg.Generation.PreserveSources {
-		logf("generator:stop: preserve_sources=true, skipping pre-merge Go source reset on %s", baseBranch)
-	} else {
-		logf("generator:stop: resetting Go sources on %s", baseBranch)
-		_ = o.resetGoSources(branch) // best-effort; merge will overwrite these files
+// stopPlan lists what GeneratorStop would do for a generation branch.
+type stopPlan struct {
+	Branch     string
+	BaseBranch string
+	Steps      []string // operations in the order GeneratorStop runs them
+	Restore    []string // files restoreFromStartTag would bring back
+}
+
+// planGeneratorStop lists the steps GeneratorStop would run for branch,
+// taken from stopSteps, without changing the repository (GH-521). The
+// merge target comes from the branch's recorded base, overridden by the
+// caller's branch or Generation.BaseBranch as in stopTarget.
+func (o *Orchestrator) planGeneratorStop(branch string) (stopPlan, error) {
+	callerBranch, err := gitCurrentBranch(".")
+	if err != nil {
+		return stopPlan{}, fmt.Errorf("getting current branch: %w", err)
 	}
+	recordedBase := o.baseBranchAt(branch)
+	plan := stopPlan{Branch: branch, BaseBranch: o.stopTarget(callerBranch, branch, recordedBase)}
+	if err := checkBaseBranch(plan.BaseBranch); err != nil {
+		return stopPlan{}, err
+	}
+	if callerBranch != branch {
+		plan.Steps = append(plan.Steps, "git checkout "+branch)
+	}
+	for _, st := range o.stopSteps(branch, plan.BaseBranch) {
+		plan.Steps = append(plan.Steps, st.desc)
+	}
+	plan.Restore, err = o.planRestoreFromStartTag(branch+"-start", branch, plan.BaseBranch)
+	if err != nil {
+		logf("generator:stop: restore preview unavailable: %v", err)
+	}
+	return plan, nil
+}
 
-	_ = gitStageAll(".") // best-effort; commit below handles empty index
-	var prepareMsg string
-	if o.cfg.Generation.PreserveSources {
-		prepareMsg = fmt.Sprintf("Prepare %s for generation merge (preserve_sources)\n\nSources preserved. Merging %s.", baseBranch, branch)
-	} else {
-		prepareMsg = fmt.Sprintf("Prepare %s for generation merge: delete Go code\n\nDocumentation preserved for merge. Code will be replaced by %s.", baseBranch, branch)
+// baseBranchAt reads the recorded base branch from the cobbler directory
+// as committed on ref, falling back to "main" like readBaseBranch.
+func (o *Orchestrator) baseBranchAt(ref string) string {
+	path := filepath.ToSlash(filepath.Join(o.cfg.Cobbler.Dir, baseBranchFile))
+	data, err := gitShowFileContent(ref, path, ".")
+	if err != nil {
+		return "main"
 	}
-	if err := gitCommitAllowEmpty(prepareMsg, "."); err != nil {
-		return fmt.Errorf("committing prepare step: %w", err)
+	if branch := strings.TrimSpace(string(data)); branch != "" {
+		return branch
 	}
+	return "main"
+}
 
-	logf("generator:stop: merging into %s", baseBranch)
-	cmd := gitMergeCmd(branch, ".")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("merging %s: %w", branch, err)
+// planRestoreFromStartTag returns the Go files restoreFromStartTag would
+// restore after merging branch into baseBranch: files at startTag outside
+// the magefiles directory that the merged tree would lack. The merged tree
+// is approximated by branch's files, plus baseBranch's when sources are
+// preserved.
+func (o *Orchestrator) planRestoreFromStartTag(startTag, branch, baseBranch string) ([]string, error) {
+	startFiles, err := gitLsTreeFiles(startTag, ".")
+	if err != nil {
+		return nil, fmt.Errorf("listing files at %s: %w", startTag, err)
+	}
+	merged := make(map[string]bool)
+	refs := []string{branch}
+	if o.cfg.Generation.PreserveSources {
+		refs = append(refs, baseBranch)
+	}
+	for _, ref := range refs {
+		files, err := gitLsTreeFiles(ref, ".")
+		if err != nil {
+			return nil, fmt.Errorf("listing files at %s: %w", ref, err)
+		}
+		for _, f := range files {
+			merged[f] = true
+		}
+	}
+	var restore []string
+	for _, path := range startFiles {
+		if !strings.HasSuffix(path, ".go") || strings.HasPrefix(path, o.cfg.Project.MagefilesDir+"/") || merged[path] {
+			continue
+		}
+		restore = append(restore, path)
 	}
+	return restore, nil
+}
 
-	// Restore Go files from earlier generations so the v1 tag captures a
-	// complete snapshot (prd002 R5.9). Runs before tagging.
-	startTag := branch + "-start"
-	if err := o.restoreFromStartTag(startTag); err != nil {
-		logf("generator:stop: restore warning: %v", err)
+// printStopPlan prints the plan for stopping branch (GH-521).
+func (o *Orchestrator) printStopPlan(branch string) error {
+	plan, err := o.planGeneratorStop(branch)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("generator:stop (dry run): would merge %s into %s:\n", plan.Branch, plan.BaseBranch)
+	for i, s := range plan.Steps {
+		fmt.Printf("  %2d. %s\n", i+1, s)
 	}
+	if len(plan.Restore) > 0 {
+		fmt.Printf("files restored from %s-start:\n", plan.Branch)
+		for _, f := range plan.Restore {
+			fmt.Printf("  %s\n", f)
+		}
+	}
+	fmt.Println("version tags are not part of generator:stop; create them with mage tag after the merge")
+	return nil
+}
+
+// stopStep is one operation of generator:stop: desc is what the dry run
+// prints and run performs it.
+type stopStep struct {
+	desc string
+	run  func() error
+}
 
-	mergedTag := branch + "-merged"
-	logf("generator:stop: tagging %s as %s", baseBranch, mergedTag)
-	if err := gitTag(mergedTag, "."); err != nil {
-		return fmt.Errorf("tagging merge: %w", err)
+// gitStep returns a stopStep that runs cmd, described by its command line.
+func gitStep(cmd *exec.Cmd, wrap string) stopStep {
+	return stopStep{
+		desc: strings.Join(cmd.Args, " "),
+		run: func() error {
+			if err := cmd.Run(); err != nil {
+				return fmt.Errorf("%s: %w", wrap, err)
+			}
+			return nil
+		},
 	}
+}
+
+// stopSteps returns the operations GeneratorStop runs once it is on branch
+// and has resolved baseBranch: tag the finished generation, reset Go
+// sources on the base branch and commit the clean state, merge the
+// generation branch, restore files from earlier generations, tag the
+// result, reset the base branch to specs-only, delete the generation
+// branch, close its issues, and remove the cleanup directories. The same
+// list backs the dry run, so the plan cannot drift from what runs.
+// When preserve_sources is true the pre-merge source reset and the
+// post-tag cleanGoSources call are both skipped (prd002 R10.2).
+func (o *Orchestrator) stopSteps(branch, baseBranch string) []stopStep {
+	preserve := o.cfg.Generation.PreserveSources
+	startTag := branch + "-start"
+	steps := []stopStep{
+		gitStep(cmdGit(".", "tag", branch+"-finished"), "tagging generation"),
+		gitStep(cmdGit(".", "checkout", baseBranch), "checking out "+baseBranch),
+	}
+
+	if preserve {
+		steps = append(steps, stopStep{
+			desc: fmt.Sprintf("keep Go sources on %s (preserve_sources)", baseBranch),
+			run: func() error {
+				logf("generator:stop: preserve_sources=true, skipping pre-merge Go source reset on %s", baseBranch)
+				return nil
+			},
+		})
+	} else {
+		steps = append(steps, stopStep{
+			desc: fmt.Sprintf("delete Go sources on %s, re-seed files, and reinitialize go.mod", baseBranch),
+			run: func() error {
+				_ = o.resetGoSources(branch) // best-effort; merge will overwrite these files
+				return nil
+			},
+		})
+	}
+
+	steps = append(steps, stopStep{
+		desc: fmt.Sprintf("git add -A && git commit --allow-empty (prepare %s for generation merge)", baseBranch),
+		run: func() error {
+			_ = gitStageAll(".") // best-effort; commit below handles empty index
+			var prepareMsg string
+			if preserve {
+				prepareMsg = fmt.Sprintf("Prepare %s for generation merge (preserve_sources)\n\nSources preserved. Merging %s.", baseBranch, branch)
+			} else {
+				prepareMsg = fmt.Sprintf("Prepare %s for generation merge: delete Go code\n\nDocumentation preserved for merge. Code will be replaced by %s.", baseBranch, branch)
+			}
+			if err := gitCommitAllowEmpty(prepareMsg, "."); err != nil {
+				return fmt.Errorf("committing prepare step: %w", err)
+			}
+			return nil
+		},
+	})
+
+	merge := gitMergeCmd(branch, ".")
+	merge.Stdout = os.Stdout
+	merge.Stderr = os.Stderr
+	steps = append(steps, gitStep(merge, "merging "+branch),
+		// Restore Go files from earlier generations so the v1 tag captures a
+		// complete snapshot (prd002 R5.9). Runs before tagging.
+		stopStep{
+			desc: fmt.Sprintf("restore Go files from %s missing after the merge, and commit", startTag),
+			run: func() error {
+				if err := o.restoreFromStartTag(startTag); err != nil {
+					logf("generator:stop: restore warning: %v", err)
+				}
+				return nil
+			},
+		},
+		gitStep(cmdGit(".", "tag", branch+"-merged"), "tagging merge"),
+	)
 
 	// Reset base branch to specs-only (prd002 R5.10, R5.11).
 	// Version tagging is handled separately by mage tag (Tag() in tag.go).
 	// Use cleanGoSources (not resetGoSources) to avoid re-seeding files like version.go.
 	// Skip when preserve_sources is true — library repos keep their Go source (prd002 R10.2).
-	if o.cfg.Generation.PreserveSources {
-		logf("generator:stop: preserve_sources=true, skipping post-tag source reset on %s", baseBranch)
+	if preserve {
+		steps = append(steps, stopStep{
+			desc: fmt.Sprintf("keep Go sources on %s (preserve_sources)", baseBranch),
+			run: func() error {
+				logf("generator:stop: preserve_sources=true, skipping post-tag source reset on %s", baseBranch)
+				return nil
+			},
+		})
 	} else {
-		logf("generator:stop: resetting %s to specs-only", baseBranch)
-		o.cleanGoSources()
-	}
-	if err := o.HistoryClean(); err != nil {
-		logf("generator:stop: warning cleaning history: %v", err)
-	}
-	_ = gitStageAll(".")
-	cleanupMsg := fmt.Sprintf("Reset %s to specs-only after v1 tag\n\nGenerated code preserved at version tags. Branch restored to documentation-only state.", baseBranch)
-	_ = gitCommit(cleanupMsg, ".") // best-effort; may be empty if nothing changed
-
-	logf("generator:stop: deleting branch")
-	_ = gitDeleteBranch(branch, ".") // best-effort; branch may already be deleted
-	return nil
+		steps = append(steps, stopStep{
+			desc: fmt.Sprintf("delete Go sources on %s (specs-only reset)", baseBranch),
+			run: func() error {
+				o.cleanGoSources()
+				return nil
+			},
+		})
+	}
+
+	steps = append(steps,
+		stopStep{
+			desc: fmt.Sprintf("clean history directory %s", o.historyDir()),
+			run: func() error {
+				if err := o.HistoryClean(); err != nil {
+					logf("generator:stop: warning cleaning history: %v", err)
+				}
+				return nil
+			},
+		},
+		stopStep{
+			desc: fmt.Sprintf("git add -A && git commit (reset %s to specs-only)", baseBranch),
+			run: func() error {
+				_ = gitStageAll(".")
+				cleanupMsg := fmt.Sprintf("Reset %s to specs-only after v1 tag\n\nGenerated code preserved at version tags. Branch restored to documentation-only state.", baseBranch)
+				_ = gitCommit(cleanupMsg, ".") // best-effort; may be empty if nothing changed
+				return nil
+			},
+		},
+	)
+
+	del := cmdGit(".", "branch", "-d", branch)
+	steps = append(steps,
+		stopStep{
+			desc: strings.Join(del.Args, " "),
+			run: func() error {
+				_ = del.Run() // best-effort; branch may already be deleted
+				return nil
+			},
+		},
+		// Close any open cobbler-gen issues for this generation so they do
+		// not accumulate as orphans after the branch is deleted.
+		stopStep{
+			desc: fmt.Sprintf("close open cobbler issues for %s", branch),
+			run: func() error {
+				if ghRepo, err := detectGitHubRepo(".", o.cfg); err == nil && ghRepo != "" {
+					if err := closeGenerationIssues(ghRepo, branch); err != nil {
+						logf("generator:stop: close issues warning: %v", err)
+					}
+				}
+				return nil
+			},
+		},
+	)
+	if dirs := o.cfg.Generation.CleanupDirs; len(dirs) > 0 {
+		steps = append(steps, stopStep{
+			desc: "remove " + strings.Join(dirs, ", "),
+			run: func() error {
+				o.cleanupDirs()
+				return nil
+			},
+		})
+	}
+	return steps
 }
 
 // restoreFromStartTag restores Go source files that existed on main at the
@@ -715,8 +1062,31 @@ func ensureOnBranch(branch string) error {
 	return gitCheckout(branch, ".")
 }
 
-// GeneratorList shows active branches and past generations.
-func (o *Orchestrator) GeneratorList() error {
+// generationListEntry is one generation reported by GeneratorList.
+type generationListEntry struct {
+	Name      string   `json:"name"`
+	Current   bool     `json:"current"`
+	Active    bool     `json:"active"`
+	Abandoned bool     `json:"abandoned"`
+	Lifecycle []string `json:"lifecycle"`      // tag suffixes present, without the dash
+	Date      string   `json:"date,omitempty"` // RFC 3339; empty for custom names
+}
+
+// generationDate parses the timestamp generator:start puts in generation
+// names (prefix + 2006-01-02-15-04-05) and returns it in RFC 3339 form.
+// Returns "" for names set through Generation.Name or COBBLER_GEN_NAME
+// that carry no timestamp.
+func generationDate(name, prefix string) string {
+	t, err := time.ParseInLocation("2006-01-02-15-04-05", strings.TrimPrefix(name, prefix), time.Local)
+	if err != nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// generationList collects active branches and lifecycle tags into one
+// entry per generation, sorted by name.
+func (o *Orchestrator) generationList() []generationListEntry {
 	branches := o.listGenerationBranches()
 	tags := gitListTags(o.cfg.Generation.Prefix + "*", ".")
 	current, _ := gitCurrentBranch(".")
@@ -734,43 +1104,67 @@ func (o *Orchestrator) GeneratorList() error {
 		nameSet[generationName(t)] = true
 	}
 
-	if len(nameSet) == 0 {
-		fmt.Println("No generations found.")
-		return nil
-	}
-
 	names := make([]string, 0, len(nameSet))
 	for n := range nameSet {
 		names = append(names, n)
 	}
 	slices.Sort(names)
 
+	entries := make([]generationListEntry, 0, len(names))
 	for _, name := range names {
-		isActive := branchSet[name]
-		isAbandoned := tagSet[name+"-abandoned"]
-
-		marker := " "
-		if name == current {
-			marker = "*"
+		e := generationListEntry{
+			Name:      name,
+			Current:   name == current,
+			Active:    branchSet[name],
+			Abandoned: tagSet[name+"-abandoned"],
+			Lifecycle: []string{},
+			Date:      generationDate(name, o.cfg.Generation.Prefix),
 		}
-
-		var lifecycle []string
 		for _, suffix := range tagSuffixes {
 			if tagSet[name+suffix] {
-				lifecycle = append(lifecycle, suffix[1:])
+				e.Lifecycle = append(e.Lifecycle, suffix[1:])
 			}
 		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// GeneratorList shows active branches and past generations. With
+// Generation.OutputJSON set it prints a JSON array of generations instead,
+// one object per generation sorted by name.
+func (o *Orchestrator) GeneratorList() error {
+	entries := o.generationList()
+	if o.cfg.Generation.OutputJSON {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling generation list: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No generations found.")
+		return nil
+	}
+
+	for _, e := range entries {
+		marker := " "
+		if e.Current {
+			marker = "*"
+		}
 
-		if isActive {
-			if len(lifecycle) > 0 {
-				fmt.Printf("%s %s  (active, tags: %s)\n", marker, name, strings.Join(lifecycle, ", "))
+		if e.Active {
+			if len(e.Lifecycle) > 0 {
+				fmt.Printf("%s %s  (active, tags: %s)\n", marker, e.Name, strings.Join(e.Lifecycle, ", "))
 			} else {
-				fmt.Printf("%s %s  (active)\n", marker, name)
+				fmt.Printf("%s %s  (active)\n", marker, e.Name)
 			}
-		} else if isAbandoned {
-			fmt.Printf("%s %s  (abandoned)\n", marker, name)
+		} else if e.Abandoned {
+			fmt.Printf("%s %s  (abandoned)\n", marker, e.Name)
 		} else {
-			fmt.Printf("%s %s  (tags: %s)\n", marker, name, strings.Join(lifecycle, ", "))
+			fmt.Printf("%s %s  (tags: %s)\n", marker, e.Name, strings.Join(e.Lifecycle, ", "))
 		}
 	}
 
@@ -819,7 +1213,7 @@ func (o *Orchestrator) GeneratorReset() error {
 		return fmt.Errorf("switching to %s: %w", baseBranch, err)
 	}
 
-	wtBase := worktreeBasePath()
+	wtBase := worktreeBasePath(o.cfg.Cobbler.WorktreeDir)
 	ghRepo, _ := detectGitHubRepo(".", o.cfg)
 	genBranches := o.listGenerationBranches()
 	if len(genBranches) > 0 {
@@ -859,6 +1253,7 @@ func (o *Orchestrator) GeneratorReset() error {
 		for _, gb := range genBranches {
 			logf("generator:reset: deleting branch %s", gb)
 			_ = gitForceDeleteBranch(gb, ".") // best-effort; branch may be already removed
+			o.clearStitchCount(gb)
 		}
 	}
 
@@ -0,0 +1,130 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// GeneratorStatusReport is the structured state of a generation run
+// reported by GeneratorStatus (GH-520).
+type GeneratorStatusReport struct {
+	Branch        string               `json:"branch"`
+	BaseBranch    string               `json:"base_branch"`
+	Issues        GeneratorIssueCounts `json:"issues"`
+	TasksStitched int                  `json:"tasks_stitched"`
+	Tokens        GeneratorTokenTotals `json:"tokens"`
+	CostUSD       float64              `json:"cost_usd"`
+	RunInProgress bool                 `json:"run_in_progress"`
+}
+
+// GeneratorIssueCounts counts a generation's task issues by state. Ready
+// and InProgress are subsets of Open.
+type GeneratorIssueCounts struct {
+	Open       int `json:"open"`
+	Ready      int `json:"ready"`
+	InProgress int `json:"in_progress"`
+	Closed     int `json:"closed"`
+}
+
+// GeneratorTokenTotals sums token usage over the generation's history
+// stats files.
+type GeneratorTokenTotals struct {
+	Input         int `json:"input"`
+	Output        int `json:"output"`
+	CacheCreation int `json:"cache_creation"`
+	CacheRead     int `json:"cache_read"`
+}
+
+// buildGeneratorStatus derives a status report from the generation's task
+// issues and the history stats entries recorded for branch; entries from
+// other generations, or written before stats carried a generation, are
+// ignored. A run counts as in progress while any task carries the
+// in-progress label.
+func buildGeneratorStatus(branch, baseBranch string, issues []cobblerIssue, history []historyStatsEntry) GeneratorStatusReport {
+	r := GeneratorStatusReport{Branch: branch, BaseBranch: baseBranch}
+	for _, iss := range issues {
+		if iss.State == "closed" {
+			r.Issues.Closed++
+			continue
+		}
+		r.Issues.Open++
+		switch {
+		case hasLabel(iss, cobblerLabelInProgress):
+			r.Issues.InProgress++
+		case hasLabel(iss, cobblerLabelReady):
+			r.Issues.Ready++
+		}
+	}
+	for _, e := range history {
+		if e.Stats.Generation != branch {
+			continue
+		}
+		if e.Phase == "stitch" && e.Stats.Status == "success" {
+			r.TasksStitched++
+		}
+		r.Tokens.Input += e.Stats.Tokens.Input
+		r.Tokens.Output += e.Stats.Tokens.Output
+		r.Tokens.CacheCreation += e.Stats.Tokens.CacheCreation
+		r.Tokens.CacheRead += e.Stats.Tokens.CacheRead
+		r.CostUSD += e.Stats.CostUSD
+	}
+	r.RunInProgress = r.Issues.InProgress > 0
+	return r
+}
+
+// GeneratorStatus reports where the current or configured generation run
+// stands: its base branch as recorded on the generation branch, task issue
+// counts, tasks stitched, and the generation's token and cost totals from
+// the history directory. With
+// Generation.OutputJSON set the report is printed as JSON for CI;
+// otherwise as a short human-readable summary.
+func (o *Orchestrator) GeneratorStatus() error {
+	genBranch := o.cfg.Generation.Branch
+	if genBranch == "" {
+		branches := o.listGenerationBranches()
+		if len(branches) == 0 {
+			return fmt.Errorf("no active generation branches found")
+		}
+		genBranch = branches[0]
+	}
+
+	repo, err := detectGitHubRepo(".", o.cfg)
+	if err != nil || repo == "" {
+		return fmt.Errorf("detecting GitHub repo: %w", err)
+	}
+	issues, err := listAllCobblerIssues(repo, genBranch)
+	if err != nil {
+		return fmt.Errorf("listing cobbler issues for %s: %w", genBranch, err)
+	}
+
+	r := buildGeneratorStatus(genBranch, o.baseBranchAt(genBranch), issues, loadHistoryStats(o.historyDir()))
+	if o.cfg.Generation.OutputJSON {
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling generator status: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	printGeneratorStatus(r)
+	return nil
+}
+
+// printGeneratorStatus writes the human-readable form of a status report.
+func printGeneratorStatus(r GeneratorStatusReport) {
+	state := "idle"
+	if r.RunInProgress {
+		state = "in progress"
+	}
+	fmt.Printf("generation:     %s (base %s)\n", r.Branch, r.BaseBranch)
+	fmt.Printf("run:            %s\n", state)
+	fmt.Printf("issues:         %d open (%d ready, %d in progress), %d closed\n",
+		r.Issues.Open, r.Issues.Ready, r.Issues.InProgress, r.Issues.Closed)
+	fmt.Printf("tasks stitched: %d\n", r.TasksStitched)
+	fmt.Printf("tokens:         %d in, %d out, %d cache write, %d cache read\n",
+		r.Tokens.Input, r.Tokens.Output, r.Tokens.CacheCreation, r.Tokens.CacheRead)
+	fmt.Printf("cost:           $%.2f\n", r.CostUSD)
+}
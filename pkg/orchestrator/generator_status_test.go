@@ -0,0 +1,76 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBuildGeneratorStatus(t *testing.T) {
+	t.Parallel()
+	issues := []cobblerIssue{
+		{Number: 1, State: "closed"},
+		{Number: 2, State: "closed", Labels: []string{"failed"}},
+		{Number: 3, State: "open", Labels: []string{cobblerLabelInProgress}},
+		{Number: 4, State: "open", Labels: []string{cobblerLabelReady}},
+		{Number: 5, State: "open"},
+	}
+	history := []historyStatsEntry{
+		{Phase: "measure", Stats: HistoryStats{Generation: "generation-x", Status: "success", CostUSD: 0.5, Tokens: historyTokens{Input: 100, Output: 10}}},
+		{Phase: "stitch", Stats: HistoryStats{Generation: "generation-x", Status: "success", CostUSD: 1.25, Tokens: historyTokens{Input: 200, Output: 20, CacheRead: 7}}},
+		{Phase: "stitch", Stats: HistoryStats{Generation: "generation-x", Status: "failed", CostUSD: 0.25, Tokens: historyTokens{Input: 50, CacheCreation: 3}}},
+		{Phase: "stitch", Stats: HistoryStats{Generation: "generation-old", Status: "success", CostUSD: 9, Tokens: historyTokens{Input: 900}}},
+		{Phase: "stitch", Stats: HistoryStats{Status: "success", CostUSD: 4, Tokens: historyTokens{Input: 400}}},
+	}
+
+	r := buildGeneratorStatus("generation-x", "main", issues, history)
+	want := GeneratorIssueCounts{Open: 3, Ready: 1, InProgress: 1, Closed: 2}
+	if r.Issues != want {
+		t.Errorf("Issues = %+v, want %+v", r.Issues, want)
+	}
+	if r.TasksStitched != 1 {
+		t.Errorf("TasksStitched = %d, want 1", r.TasksStitched)
+	}
+	if r.Tokens != (GeneratorTokenTotals{Input: 350, Output: 30, CacheCreation: 3, CacheRead: 7}) {
+		t.Errorf("Tokens = %+v", r.Tokens)
+	}
+	if r.CostUSD != 2.0 {
+		t.Errorf("CostUSD = %v, want 2.0", r.CostUSD)
+	}
+	if !r.RunInProgress {
+		t.Error("RunInProgress = false with an in-progress task")
+	}
+	if r.Branch != "generation-x" || r.BaseBranch != "main" {
+		t.Errorf("Branch, BaseBranch = %q, %q", r.Branch, r.BaseBranch)
+	}
+}
+
+func TestBuildGeneratorStatus_IdleWithoutInProgress(t *testing.T) {
+	t.Parallel()
+	r := buildGeneratorStatus("g", "main", []cobblerIssue{{State: "open", Labels: []string{cobblerLabelReady}}}, nil)
+	if r.RunInProgress {
+		t.Error("RunInProgress = true with no in-progress task")
+	}
+}
+
+func TestGeneratorStatusReport_JSONFields(t *testing.T) {
+	t.Parallel()
+	data, err := json.Marshal(GeneratorStatusReport{Branch: "g", Issues: GeneratorIssueCounts{InProgress: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"branch", "base_branch", "issues", "tasks_stitched", "tokens", "cost_usd", "run_in_progress"} {
+		if _, ok := m[key]; !ok {
+			t.Errorf("JSON missing key %q: %s", key, data)
+		}
+	}
+	if got := m["issues"].(map[string]any)["in_progress"]; got != 2.0 {
+		t.Errorf("issues.in_progress = %v, want 2", got)
+	}
+}
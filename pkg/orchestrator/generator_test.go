@@ -4,11 +4,13 @@
 package orchestrator
 
 import (
+	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 // initTestGitRepo creates a bare-minimum git repo in a temp directory,
@@ -185,6 +187,54 @@ func TestWriteReadBaseBranch_RoundTrip(t *testing.T) {
 	}
 }
 
+// --- prompt template snapshots ---
+
+func TestSnapshotPromptTemplates_WritesBothPhases(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	o := &Orchestrator{cfg: Config{Cobbler: CobblerConfig{Dir: dir, StitchPrompt: "role: custom stitch\n"}}}
+
+	if err := o.snapshotPromptTemplates("generation-x"); err != nil {
+		t.Fatalf("snapshotPromptTemplates() error = %v", err)
+	}
+	measure, err := os.ReadFile(o.promptSnapshotPath("generation-x", "measure"))
+	if err != nil {
+		t.Fatalf("reading measure snapshot: %v", err)
+	}
+	if string(measure) != defaultMeasurePrompt {
+		t.Error("measure snapshot should hold the embedded default")
+	}
+	stitch, err := os.ReadFile(o.promptSnapshotPath("generation-x", "stitch"))
+	if err != nil {
+		t.Fatalf("reading stitch snapshot: %v", err)
+	}
+	if string(stitch) != "role: custom stitch\n" {
+		t.Errorf("stitch snapshot = %q, want configured template", stitch)
+	}
+}
+
+func TestResolvePromptTemplate_PrefersSnapshot(t *testing.T) {
+	// Not parallel: sets the package-level generation name.
+	dir := t.TempDir()
+	o := &Orchestrator{cfg: Config{Cobbler: CobblerConfig{Dir: dir}}}
+	path := o.promptSnapshotPath("generation-x", "stitch")
+	os.MkdirAll(filepath.Dir(path), 0o755)
+	os.WriteFile(path, []byte("role: snapshot\n"), 0o644)
+
+	if got := o.resolvePromptTemplate("stitch", "role: configured\n", "role: embedded\n"); got != "role: configured\n" {
+		t.Errorf("without generation: got %q, want configured", got)
+	}
+
+	setGeneration("generation-x")
+	t.Cleanup(clearGeneration)
+	if got := o.resolvePromptTemplate("stitch", "role: configured\n", "role: embedded\n"); got != "role: snapshot\n" {
+		t.Errorf("with generation: got %q, want snapshot", got)
+	}
+	if got := o.resolvePromptTemplate("measure", "", "role: embedded\n"); got != "role: embedded\n" {
+		t.Errorf("missing snapshot: got %q, want embedded", got)
+	}
+}
+
 // --- seedFiles (uses cwd, NOT parallel) ---
 
 func TestSeedFiles_CreatesFiles(t *testing.T) {
@@ -1165,6 +1215,53 @@ func TestGeneratorList_WithBranchAndTags(t *testing.T) {
 	}
 }
 
+func TestGenerationList_Entries(t *testing.T) {
+	initTestGitRepo(t)
+	gitRun(t, "checkout", "-b", "generation-2026-01-01-10-00-00")
+	gitRun(t, "checkout", "main")
+	gitRun(t, "tag", "generation-2026-01-01-10-00-00-start")
+	gitRun(t, "tag", "generation-old-start")
+	gitRun(t, "tag", "generation-old-finished")
+	gitRun(t, "tag", "generation-gone-abandoned")
+
+	o := &Orchestrator{cfg: Config{Generation: GenerationConfig{Prefix: "generation-"}}}
+	entries := o.generationList()
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3: %+v", len(entries), entries)
+	}
+	names := []string{entries[0].Name, entries[1].Name, entries[2].Name}
+	if strings.Join(names, ",") != "generation-2026-01-01-10-00-00,generation-gone,generation-old" {
+		t.Errorf("names = %v, want sorted", names)
+	}
+	active := entries[0]
+	if !active.Active || active.Current || active.Abandoned || strings.Join(active.Lifecycle, ",") != "start" {
+		t.Errorf("active entry = %+v", active)
+	}
+	if !strings.HasPrefix(active.Date, "2026-01-01T10:00:00") {
+		t.Errorf("Date = %q, want 2026-01-01T10:00:00...", active.Date)
+	}
+	if gone := entries[1]; !gone.Abandoned || gone.Active {
+		t.Errorf("abandoned entry = %+v", gone)
+	}
+	if old := entries[2]; strings.Join(old.Lifecycle, ",") != "start,finished" || old.Date != "" {
+		t.Errorf("old entry = %+v", old)
+	}
+}
+
+func TestGenerationList_JSONLifecycleNeverNull(t *testing.T) {
+	initTestGitRepo(t)
+	gitRun(t, "checkout", "-b", "generation-custom")
+	o := &Orchestrator{cfg: Config{Generation: GenerationConfig{Prefix: "generation-"}}}
+	data, err := json.Marshal(o.generationList())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"name":"generation-custom","current":true,"active":true,"abandoned":false,"lifecycle":[]}]`
+	if string(data) != want {
+		t.Errorf("JSON = %s, want %s", data, want)
+	}
+}
+
 // --- restoreFromStartTag (git-dependent, no t.Parallel) ---
 
 func TestRestoreFromStartTag_RestoresMissingGoFiles(t *testing.T) {
@@ -1500,3 +1597,119 @@ releases:
 		t.Error("should not advance when all releases already done")
 	}
 }
+
+func TestDeadlinePassed(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	if deadlinePassed(time.Time{}, now) {
+		t.Error("zero deadline should never pass")
+	}
+	if deadlinePassed(now.Add(time.Minute), now) {
+		t.Error("future deadline should not have passed")
+	}
+	if !deadlinePassed(now, now) {
+		t.Error("deadline equal to now should have passed")
+	}
+	if !deadlinePassed(now.Add(-time.Minute), now) {
+		t.Error("past deadline should have passed")
+	}
+}
+
+// --- generator:stop dry run (GH-521) ---
+
+// setupStopDryRunRepo creates a generation-x branch started from main whose
+// start tag holds a.go and b.go; the generation deleted b.go.
+func setupStopDryRunRepo(t *testing.T) *Orchestrator {
+	t.Helper()
+	dir := initTestGitRepo(t)
+	for _, f := range []string{"a.go", "b.go"} {
+		os.WriteFile(filepath.Join(dir, f), []byte("package x\n"), 0o644)
+	}
+	gitRun(t, "add", "-A")
+	gitRun(t, "commit", "-m", "sources")
+	gitRun(t, "tag", "generation-x-start")
+	gitRun(t, "checkout", "-b", "generation-x")
+	os.MkdirAll(filepath.Join(dir, ".cobbler"), 0o755)
+	os.WriteFile(filepath.Join(dir, ".cobbler", baseBranchFile), []byte("main\n"), 0o644)
+	gitRun(t, "rm", "-q", "b.go")
+	gitRun(t, "add", "-A")
+	gitRun(t, "commit", "-m", "generation work")
+	return &Orchestrator{cfg: Config{
+		Project:    ProjectConfig{MagefilesDir: "magefiles"},
+		Generation: GenerationConfig{Prefix: "generation-", Branch: "generation-x", DryRun: true},
+		Cobbler:    CobblerConfig{Dir: ".cobbler/"},
+	}}
+}
+
+func TestPlanGeneratorStop(t *testing.T) {
+	o := setupStopDryRunRepo(t)
+	plan, err := o.planGeneratorStop("generation-x")
+	if err != nil {
+		t.Fatalf("planGeneratorStop: %v", err)
+	}
+	if plan.BaseBranch != "main" {
+		t.Errorf("BaseBranch = %q, want main", plan.BaseBranch)
+	}
+	if len(plan.Restore) != 1 || plan.Restore[0] != "b.go" {
+		t.Errorf("Restore = %v, want [b.go]", plan.Restore)
+	}
+	steps := strings.Join(plan.Steps, "\n")
+	for _, want := range []string{"git tag generation-x-finished", "git merge generation-x --no-edit", "git tag generation-x-merged", "git branch -d generation-x"} {
+		if !strings.Contains(steps, want) {
+			t.Errorf("steps missing %q:\n%s", want, steps)
+		}
+	}
+	if strings.Contains(steps, "git checkout generation-x") {
+		t.Errorf("plan checks out the branch it is already on:\n%s", steps)
+	}
+}
+
+func TestPlanGeneratorStop_MissingBaseBranch(t *testing.T) {
+	o := setupStopDryRunRepo(t)
+	gitRun(t, "branch", "-D", "main")
+	_, err := o.planGeneratorStop("generation-x")
+	if err == nil || !strings.Contains(err.Error(), "generation.base_branch") {
+		t.Fatalf("planGeneratorStop with deleted base: err = %v, want actionable base branch error", err)
+	}
+}
+
+func TestPlanGeneratorStop_BaseBranchOverride(t *testing.T) {
+	o := setupStopDryRunRepo(t)
+	gitRun(t, "branch", "release", "main")
+	o.cfg.Generation.BaseBranch = "release"
+	plan, err := o.planGeneratorStop("generation-x")
+	if err != nil {
+		t.Fatalf("planGeneratorStop: %v", err)
+	}
+	if plan.BaseBranch != "release" {
+		t.Errorf("BaseBranch = %q, want release", plan.BaseBranch)
+	}
+}
+
+func TestGeneratorStop_MissingBaseBranchFailsBeforeTagging(t *testing.T) {
+	o := setupStopDryRunRepo(t)
+	o.cfg.Generation.DryRun = false
+	gitRun(t, "branch", "-D", "main")
+	if err := o.GeneratorStop(); err == nil || !strings.Contains(err.Error(), "base branch main does not exist") {
+		t.Fatalf("GeneratorStop with deleted base: err = %v, want missing base branch error", err)
+	}
+	if tags := gitListTags("generation-x-*", "."); len(tags) != 1 {
+		t.Errorf("tags = %v, want only the start tag", tags)
+	}
+}
+
+func TestGeneratorStop_DryRunLeavesRepoUnchanged(t *testing.T) {
+	o := setupStopDryRunRepo(t)
+	head, _ := gitRevParseHEAD(".")
+	if err := o.GeneratorStop(); err != nil {
+		t.Fatalf("GeneratorStop dry run: %v", err)
+	}
+	if tags := gitListTags("generation-x-*", "."); len(tags) != 1 {
+		t.Errorf("tags after dry run = %v, want only the start tag", tags)
+	}
+	if current, _ := gitCurrentBranch("."); current != "generation-x" {
+		t.Errorf("current branch = %q, want generation-x", current)
+	}
+	if after, _ := gitRevParseHEAD("."); after != head {
+		t.Errorf("HEAD moved from %s to %s", head, after)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// disallowedToolsFlag is the Claude CLI flag that removes tools, or tool
+// invocations matching a pattern such as Bash(curl:*), from the session.
+const disallowedToolsFlag = "--disallowedTools"
+
+// deniedCommandArgs converts Cobbler.StitchDeniedCommands entries into
+// Claude CLI arguments. Each command prefix becomes a Bash(<cmd>:*) rule
+// passed with its own --disallowedTools flag. Blank entries are skipped.
+func deniedCommandArgs(commands []string) []string {
+	var args []string
+	for _, c := range commands {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		args = append(args, disallowedToolsFlag, "Bash("+c+":*)")
+	}
+	return args
+}
+
+// deniedCommandsFromArgs recovers the denied command prefixes from Claude
+// CLI arguments built by deniedCommandArgs. runClaude uses it to arm the
+// stream scan with the same list the CLI was given.
+func deniedCommandsFromArgs(args []string) []string {
+	var commands []string
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] != disallowedToolsFlag {
+			continue
+		}
+		rule := args[i+1]
+		i++
+		if strings.HasPrefix(rule, "Bash(") && strings.HasSuffix(rule, ":*)") {
+			commands = append(commands, strings.TrimSuffix(strings.TrimPrefix(rule, "Bash("), ":*)"))
+		}
+	}
+	return commands
+}
+
+// commandGuard scans Claude's stream-json output for Bash tool calls whose
+// command matches a denied prefix. It is defense in depth on top of the
+// CLI's own --disallowedTools rules: the first match is recorded and the
+// session is aborted through cancel (GH-467).
+type commandGuard struct {
+	patterns []*regexp.Regexp
+	cancel   func()
+
+	mu        sync.Mutex
+	partial   []byte
+	triggered string
+}
+
+// newCommandGuard returns a guard for the given command prefixes, or nil
+// when the list is empty. A prefix matches at the start of the command or
+// after a shell separator (;, &, |, parenthesis), and must be followed by
+// whitespace or the end of the command.
+func newCommandGuard(commands []string, cancel func()) *commandGuard {
+	var patterns []*regexp.Regexp
+	for _, c := range commands {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		patterns = append(patterns, regexp.MustCompile(`(?:^|[;&|(]\s*)`+regexp.QuoteMeta(c)+`(?:\s|$)`))
+	}
+	if len(patterns) == 0 {
+		return nil
+	}
+	return &commandGuard{patterns: patterns, cancel: cancel}
+}
+
+// denied reports whether command matches any denied prefix.
+func (g *commandGuard) denied(command string) bool {
+	command = strings.TrimSpace(command)
+	for _, re := range g.patterns {
+		if re.MatchString(command) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkToolUse inspects one tool call. When it is a Bash call with a denied
+// command, the command is recorded and the session cancelled. Only the
+// first offending command is kept.
+func (g *commandGuard) checkToolUse(name, command string) {
+	if name != "Bash" || !g.denied(command) {
+		return
+	}
+	g.mu.Lock()
+	first := g.triggered == ""
+	if first {
+		g.triggered = command
+	}
+	g.mu.Unlock()
+	if first {
+		logf("runClaude: denied command %q — cancelling session", command)
+		if g.cancel != nil {
+			g.cancel()
+		}
+	}
+}
+
+// Triggered returns the command that aborted the session, or "".
+func (g *commandGuard) Triggered() string {
+	if g == nil {
+		return ""
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.triggered
+}
+
+// Write implements io.Writer. It splits the stream into lines and checks
+// each assistant tool_use block. It never returns an error so it can sit
+// in an io.MultiWriter next to the output buffer.
+func (g *commandGuard) Write(p []byte) (int, error) {
+	g.partial = append(g.partial, p...)
+	for {
+		idx := bytes.IndexByte(g.partial, '\n')
+		if idx < 0 {
+			break
+		}
+		g.scanLine(g.partial[:idx])
+		g.partial = g.partial[idx+1:]
+	}
+	return len(p), nil
+}
+
+// scanLine parses one stream-json event and checks its Bash tool calls.
+func (g *commandGuard) scanLine(line []byte) {
+	var msg struct {
+		Type    string `json:"type"`
+		Message struct {
+			Content []struct {
+				Type  string `json:"type"`
+				Name  string `json:"name"`
+				Input struct {
+					Command string `json:"command"`
+				} `json:"input"`
+			} `json:"content"`
+		} `json:"message"`
+	}
+	if json.Unmarshal(line, &msg) != nil || msg.Type != "assistant" {
+		return
+	}
+	for _, b := range msg.Message.Content {
+		if b.Type == "tool_use" {
+			g.checkToolUse(b.Name, b.Input.Command)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeniedCommandArgs_RoundTrip(t *testing.T) {
+	t.Parallel()
+	args := deniedCommandArgs([]string{"curl", " ", "rm -rf"})
+	want := []string{"--disallowedTools", "Bash(curl:*)", "--disallowedTools", "Bash(rm -rf:*)"}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("deniedCommandArgs = %v, want %v", args, want)
+	}
+	extra := append([]string{"--model", "opus"}, args...)
+	if got := deniedCommandsFromArgs(extra); !reflect.DeepEqual(got, []string{"curl", "rm -rf"}) {
+		t.Errorf("deniedCommandsFromArgs = %v", got)
+	}
+}
+
+func TestNewCommandGuard_EmptyIsNil(t *testing.T) {
+	t.Parallel()
+	if g := newCommandGuard([]string{"", "  "}, nil); g != nil {
+		t.Errorf("expected nil guard, got %+v", g)
+	}
+	var g *commandGuard
+	if g.Triggered() != "" {
+		t.Error("nil guard should report no trigger")
+	}
+}
+
+func TestCommandGuard_Denied(t *testing.T) {
+	t.Parallel()
+	g := newCommandGuard([]string{"curl", "rm -rf"}, nil)
+	cases := map[string]bool{
+		"curl https://example.com": true,
+		"go test ./... && curl x":  true,
+		"echo hi | curl -d @- x":   true,
+		"rm -rf /tmp/x":            true,
+		"rm -f a.go":               false,
+		"curlew --help":            false,
+		"go build ./...":           false,
+		"grep -r 'curl' pkg/":      false,
+	}
+	for cmd, want := range cases {
+		if got := g.denied(cmd); got != want {
+			t.Errorf("denied(%q) = %v, want %v", cmd, got, want)
+		}
+	}
+}
+
+func TestCommandGuard_WriteCancelsOnDeniedBash(t *testing.T) {
+	t.Parallel()
+	cancelled := 0
+	g := newCommandGuard([]string{"curl"}, func() { cancelled++ })
+	stream := `{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Read","input":{"file_path":"curl"}}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Bash","input":{"command":"curl http://x"}}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Bash","input":{"command":"curl http://y"}}]}}
+`
+	// Write in two chunks to exercise line buffering.
+	g.Write([]byte(stream[:50]))
+	g.Write([]byte(stream[50:]))
+
+	if got := g.Triggered(); got != "curl http://x" {
+		t.Errorf("Triggered = %q, want first offending command", got)
+	}
+	if cancelled != 1 {
+		t.Errorf("cancel called %d times, want 1", cancelled)
+	}
+}
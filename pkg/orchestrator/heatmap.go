@@ -0,0 +1,119 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fileHeatmapFile is the report StitchHeatmap writes to the history
+// directory (GH-553).
+const fileHeatmapFile = "file-heatmap.yaml"
+
+// FileHeat is the change volume of one file summed over every stitch
+// report in a history directory.
+type FileHeat struct {
+	Path       string `yaml:"path"`
+	Edits      int    `yaml:"edits"` // stitch reports that touched the file
+	Insertions int    `yaml:"insertions"`
+	Deletions  int    `yaml:"deletions"`
+}
+
+// Churn returns the total lines changed in the file.
+func (h FileHeat) Churn() int { return h.Insertions + h.Deletions }
+
+// AggregateReports reads every *-stitch-report.yaml in dir and sums
+// insertions, deletions, and edit counts per file path. The result is
+// sorted by descending churn, then descending edits, then path, so the
+// most over-edited files come first. Unreadable or malformed reports are
+// logged and skipped. Returns nil when dir is empty or holds no reports.
+func AggregateReports(dir string) ([]FileHeat, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, "*-stitch-report.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("listing stitch reports in %s: %w", dir, err)
+	}
+	byPath := map[string]*FileHeat{}
+	for _, path := range matches {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logf("AggregateReports: read %s: %v", path, err)
+			continue
+		}
+		var report StitchReport
+		if err := yaml.Unmarshal(data, &report); err != nil {
+			logf("AggregateReports: parse %s: %v", path, err)
+			continue
+		}
+		for _, fc := range report.Files {
+			h, ok := byPath[fc.Path]
+			if !ok {
+				h = &FileHeat{Path: fc.Path}
+				byPath[fc.Path] = h
+			}
+			h.Edits++
+			h.Insertions += fc.Insertions
+			h.Deletions += fc.Deletions
+		}
+	}
+	if len(byPath) == 0 {
+		return nil, nil
+	}
+
+	heat := make([]FileHeat, 0, len(byPath))
+	for _, h := range byPath {
+		heat = append(heat, *h)
+	}
+	sort.Slice(heat, func(i, j int) bool {
+		if heat[i].Churn() != heat[j].Churn() {
+			return heat[i].Churn() > heat[j].Churn()
+		}
+		if heat[i].Edits != heat[j].Edits {
+			return heat[i].Edits > heat[j].Edits
+		}
+		return heat[i].Path < heat[j].Path
+	})
+	return heat, nil
+}
+
+// StitchHeatmap aggregates the stitch reports in the history directory
+// into a per-file change heatmap, writes it to file-heatmap.yaml there,
+// and prints it as a table. Files at the top churn the most across the
+// generation and are candidates for unstable or over-edited modules.
+func (o *Orchestrator) StitchHeatmap() error {
+	dir := o.historyDir()
+	heat, err := AggregateReports(dir)
+	if err != nil {
+		return err
+	}
+	if len(heat) == 0 {
+		fmt.Printf("no stitch reports with file changes in %s\n", dir)
+		return nil
+	}
+
+	data, err := yaml.Marshal(heat)
+	if err != nil {
+		return fmt.Errorf("marshaling heatmap: %w", err)
+	}
+	path := filepath.Join(dir, fileHeatmapFile)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	fmt.Printf("%d file(s) changed, heatmap written to %s\n\n", len(heat), path)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Churn\tEdits\t+\t-\tFile")
+	for _, h := range heat {
+		fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%s\n", h.Churn(), h.Edits, h.Insertions, h.Deletions, h.Path)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestAggregateReports(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	write := func(name string, files ...FileChange) {
+		data, err := yaml.Marshal(StitchReport{TaskID: name, Files: files})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name+"-stitch-report.yaml"), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("2026-01-01-10-00-00",
+		FileChange{Path: "pkg/a.go", Insertions: 10, Deletions: 2},
+		FileChange{Path: "pkg/b.go", Insertions: 5})
+	write("2026-01-01-11-00-00",
+		FileChange{Path: "pkg/a.go", Insertions: 1, Deletions: 1},
+		FileChange{Path: "pkg/c.go", Insertions: 3, Deletions: 2})
+	write("2026-01-01-12-00-00", FileChange{Path: "pkg/b.go", Deletions: 0})
+	os.WriteFile(filepath.Join(dir, "2026-01-01-13-00-00-stitch-report.yaml"), []byte("files: [unclosed"), 0o644)
+	os.WriteFile(filepath.Join(dir, "2026-01-01-13-00-00-stitch-stats.yaml"), []byte("status: success\n"), 0o644)
+
+	got, err := AggregateReports(dir)
+	if err != nil {
+		t.Fatalf("AggregateReports: %v", err)
+	}
+	want := []FileHeat{
+		{Path: "pkg/a.go", Edits: 2, Insertions: 11, Deletions: 3},
+		{Path: "pkg/b.go", Edits: 2, Insertions: 5},
+		{Path: "pkg/c.go", Edits: 1, Insertions: 3, Deletions: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AggregateReports =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestAggregateReports_Empty(t *testing.T) {
+	t.Parallel()
+	for _, dir := range []string{"", t.TempDir()} {
+		got, err := AggregateReports(dir)
+		if err != nil || got != nil {
+			t.Errorf("AggregateReports(%q) = %v, %v; want nil, nil", dir, got, err)
+		}
+	}
+}
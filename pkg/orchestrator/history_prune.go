@@ -0,0 +1,99 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// historyTimestampLayout is the timestamp prefix of every history file
+// name, e.g. 2026-03-01-12-00-00-stitch-log.log.
+const historyTimestampLayout = "2006-01-02-15-04-05"
+
+// HistoryPrune removes old invocations from the history directory. Files
+// are grouped by their timestamp prefix; groups older than maxAge are
+// removed, and of the rest only the newest keepN are kept. A zero maxAge
+// or keepN disables that limit. Files without a timestamp prefix are
+// left alone. When HistoryDir is empty the call is a no-op, consistent
+// with the other history functions (GH-537).
+//
+// Pruning runs only on request (see PruneHistory), never at the start of
+// measure or stitch: stitch model escalation, estimates, generator
+// status, and failure reports read the -stats.yaml files of the current
+// generation.
+func (o *Orchestrator) HistoryPrune(maxAge time.Duration, keepN int) error {
+	dir := o.historyDir()
+	if dir == "" {
+		return nil
+	}
+	groups, files, err := pruneHistory(dir, maxAge, keepN, time.Now())
+	if err != nil {
+		return err
+	}
+	if groups > 0 {
+		logf("historyPrune: removed %d invocation(s) (%d file(s)) from %s", groups, files, dir)
+	}
+	return nil
+}
+
+// PruneHistory applies Cobbler.HistoryRetention to the history directory.
+//
+// Exposed as a mage target (e.g., mage cobbler:pruneHistory).
+func (o *Orchestrator) PruneHistory() error {
+	return o.HistoryPrune(o.cfg.HistoryRetentionMaxAge(), o.cfg.Cobbler.HistoryRetention.KeepN)
+}
+
+// pruneHistory removes the timestamp groups in dir selected by maxAge and
+// keepN relative to now, and returns how many groups and files it removed.
+// A missing dir is a no-op.
+func pruneHistory(dir string, maxAge time.Duration, keepN int, now time.Time) (groups, files int, err error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("reading history dir %s: %w", dir, err)
+	}
+
+	byStamp := make(map[string][]string)
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || len(name) < len(historyTimestampLayout) {
+			continue
+		}
+		prefix := name[:len(historyTimestampLayout)]
+		if _, err := time.ParseInLocation(historyTimestampLayout, prefix, now.Location()); err != nil {
+			continue
+		}
+		byStamp[prefix] = append(byStamp[prefix], name)
+	}
+
+	// The layout sorts lexically in time order; newest first.
+	stamps := make([]string, 0, len(byStamp))
+	for s := range byStamp {
+		stamps = append(stamps, s)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(stamps)))
+
+	for i, s := range stamps {
+		at, _ := time.ParseInLocation(historyTimestampLayout, s, now.Location())
+		expired := maxAge > 0 && now.Sub(at) > maxAge
+		overCount := keepN > 0 && i >= keepN
+		if !expired && !overCount {
+			continue
+		}
+		for _, name := range byStamp[s] {
+			if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+				return groups, files, fmt.Errorf("removing %s: %w", name, err)
+			}
+			files++
+		}
+		groups++
+	}
+	return groups, files, nil
+}
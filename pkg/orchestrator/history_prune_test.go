@@ -0,0 +1,123 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeHistoryGroup writes the files of one invocation at ts.
+func writeHistoryGroup(t *testing.T, dir string, ts time.Time, suffixes ...string) {
+	t.Helper()
+	for _, s := range suffixes {
+		name := ts.Format(historyTimestampLayout) + s
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func historyNames(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestPruneHistory_KeepN(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	for i := range 3 {
+		writeHistoryGroup(t, dir, now.Add(-time.Duration(i)*time.Hour), "-stitch-prompt.yaml", "-stitch-log.log", "-stitch-stats.yaml")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("keep"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	groups, files, err := pruneHistory(dir, 0, 2, now)
+	if err != nil {
+		t.Fatalf("pruneHistory: %v", err)
+	}
+	if groups != 1 || files != 3 {
+		t.Errorf("removed %d group(s), %d file(s); want 1, 3", groups, files)
+	}
+	names := historyNames(t, dir)
+	oldest := now.Add(-2 * time.Hour).Format(historyTimestampLayout)
+	for _, n := range names {
+		if strings.HasPrefix(n, oldest) {
+			t.Errorf("oldest group file %s survived", n)
+		}
+	}
+	if !slices.Contains(names, "notes.txt") {
+		t.Error("file without timestamp prefix was removed")
+	}
+}
+
+func TestPruneHistory_MaxAge(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	writeHistoryGroup(t, dir, now.Add(-48*time.Hour), "-measure-prompt.yaml")
+	writeHistoryGroup(t, dir, now.Add(-time.Hour), "-measure-prompt.yaml")
+
+	groups, _, err := pruneHistory(dir, 24*time.Hour, 0, now)
+	if err != nil {
+		t.Fatalf("pruneHistory: %v", err)
+	}
+	if groups != 1 {
+		t.Errorf("removed %d group(s), want 1", groups)
+	}
+	want := []string{now.Add(-time.Hour).Format(historyTimestampLayout) + "-measure-prompt.yaml"}
+	if got := historyNames(t, dir); !slices.Equal(got, want) {
+		t.Errorf("remaining = %v, want %v", got, want)
+	}
+}
+
+func TestHistoryPrune_NoOpWithoutHistoryDir(t *testing.T) {
+	t.Parallel()
+	o := &Orchestrator{}
+	if err := o.HistoryPrune(time.Hour, 1); err != nil {
+		t.Errorf("HistoryPrune with empty HistoryDir: %v", err)
+	}
+}
+
+func TestHistoryPrune_MissingDir(t *testing.T) {
+	t.Parallel()
+	o := &Orchestrator{cfg: Config{Cobbler: CobblerConfig{HistoryDir: filepath.Join(t.TempDir(), "missing")}}}
+	if err := o.HistoryPrune(0, 1); err != nil {
+		t.Errorf("HistoryPrune on missing dir: %v", err)
+	}
+}
+
+func TestPruneHistory_AppliesRetentionConfig(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	now := time.Now()
+	for i := range 3 {
+		writeHistoryGroup(t, dir, now.Add(-time.Duration(i)*time.Hour), "-stitch-stats.yaml")
+	}
+	o := &Orchestrator{cfg: Config{Cobbler: CobblerConfig{
+		HistoryDir:       dir,
+		HistoryRetention: HistoryRetentionConfig{KeepN: 1},
+	}}}
+	if err := o.PruneHistory(); err != nil {
+		t.Fatalf("PruneHistory: %v", err)
+	}
+	want := []string{now.Format(historyTimestampLayout) + "-stitch-stats.yaml"}
+	if got := historyNames(t, dir); !slices.Equal(got, want) {
+		t.Errorf("remaining = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// errInterrupted is returned by RunCycles, RunStitchN, and RunMeasure when
+// SIGINT or SIGTERM stopped the run early (GH-548).
+var errInterrupted = errors.New("interrupted by signal")
+
+// interruptExitCode is the exit status after a second signal forces the
+// process to quit, following the shell convention of 128+SIGINT.
+const interruptExitCode = 130
+
+// forceExit terminates the process on a second signal. Tests replace it.
+var forceExit = os.Exit
+
+// handleInterrupts turns SIGINT and SIGTERM into a graceful stop for the
+// duration of one run and returns a func that restores default signal
+// handling. The first signal cancels the run context, so the Claude
+// session in flight ends with an error and its task is reset through the
+// normal failure path; the stitch and generator loops then stop at the
+// next boundary instead of leaving orphaned worktrees and in-progress
+// issues for recoverStaleTasks. A second signal exits immediately. When a
+// handler is already installed (generator:resume installs one before
+// RunCycles) it is kept and the returned func does nothing.
+func (o *Orchestrator) handleInterrupts() func() {
+	if o.stop != nil {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go watchInterrupts(sigs, done, cancel)
+	o.stop = ctx
+	return func() {
+		signal.Stop(sigs)
+		close(done)
+		cancel()
+		o.stop = nil
+	}
+}
+
+// watchInterrupts cancels the run on the first signal from sigs and
+// force-quits on the second. It returns when done is closed.
+func watchInterrupts(sigs <-chan os.Signal, done <-chan struct{}, cancel context.CancelFunc) {
+	select {
+	case <-done:
+		return
+	case sig := <-sigs:
+		logf("interrupt: received %s, stopping after the current task is reset (signal again to force quit)", sig)
+		cancel()
+	}
+	select {
+	case <-done:
+	case sig := <-sigs:
+		logf("interrupt: received %s again, forcing exit", sig)
+		forceExit(interruptExitCode)
+	}
+}
+
+// interrupted reports whether a signal has asked the current run to stop.
+func (o *Orchestrator) interrupted() bool {
+	return o.stop != nil && o.stop.Err() != nil
+}
+
+// runContext returns the context Claude invocations derive from: the
+// run's interrupt context while a handler is installed, else Background.
+func (o *Orchestrator) runContext() context.Context {
+	if o.stop != nil {
+		return o.stop
+	}
+	return context.Background()
+}
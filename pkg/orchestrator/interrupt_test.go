@@ -0,0 +1,115 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWatchInterrupts_FirstCancelsSecondForcesExit(t *testing.T) {
+	exited := make(chan int, 1)
+	orig := forceExit
+	forceExit = func(code int) { exited <- code }
+	defer func() { forceExit = orig }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sigs := make(chan os.Signal, 2)
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		watchInterrupts(sigs, done, cancel)
+		close(finished)
+	}()
+
+	sigs <- os.Interrupt
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("first signal did not cancel the run context")
+	}
+	select {
+	case code := <-exited:
+		t.Fatalf("forceExit(%d) after the first signal", code)
+	default:
+	}
+
+	sigs <- os.Interrupt
+	select {
+	case code := <-exited:
+		if code != interruptExitCode {
+			t.Errorf("exit code = %d, want %d", code, interruptExitCode)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("second signal did not force exit")
+	}
+	<-finished
+}
+
+func TestWatchInterrupts_ReturnsWhenDone(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		watchInterrupts(make(chan os.Signal), done, cancel)
+		close(finished)
+	}()
+	close(done)
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("watchInterrupts did not return after done closed")
+	}
+	if ctx.Err() != nil {
+		t.Error("run context cancelled without a signal")
+	}
+}
+
+func TestHandleInterrupts_KeepsOuterHandler(t *testing.T) {
+	o := &Orchestrator{}
+	if o.interrupted() {
+		t.Fatal("interrupted() = true outside a run")
+	}
+	release := o.handleInterrupts()
+	outer := o.stop
+	o.handleInterrupts()()
+	if o.stop != outer {
+		t.Fatal("nested handleInterrupts replaced or cleared the outer handler")
+	}
+	release()
+	if o.stop != nil {
+		t.Error("stop context not cleared after release")
+	}
+	if o.runContext() != context.Background() {
+		t.Error("runContext outside a run should be Background")
+	}
+}
+
+func TestRunWithRateLimitRetry_StopsOnInterrupt(t *testing.T) {
+	orig := rateLimitRetryDelay
+	rateLimitRetryDelay = 0
+	defer func() { rateLimitRetryDelay = orig }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	limited := ClaudeResult{InputTokens: 1, RawOutput: rateLimitedOutput}
+	b := &scriptedBackend{
+		results: []ClaudeResult{limited, limited},
+		errs:    []error{nil, nil},
+	}
+	o := &Orchestrator{cfg: Config{Claude: ClaudeConfig{MaxRateLimitRetries: 3}}, stop: ctx}
+
+	_, err := o.runWithRateLimitRetry(b, "p", time.Minute, nil)
+	if b.calls != 1 {
+		t.Errorf("calls = %d, want 1 (no retry after interrupt)", b.calls)
+	}
+	if !errors.Is(err, errInterrupted) {
+		t.Errorf("err = %v, want errInterrupted", err)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// issueSchemaViolation is one way an issue description departs from the
+// field_specs of the issue-format constitution (GH-556).
+type issueSchemaViolation struct {
+	Field   string // dotted path, e.g. files[1].action
+	Message string
+}
+
+func (v issueSchemaViolation) String() string {
+	return v.Field + ": " + v.Message
+}
+
+// embeddedIssueFormat returns the embedded issue-format constitution
+// parsed into an IssueFormatDoc. It is parsed once; a parse failure
+// yields an empty doc, which validates nothing.
+var embeddedIssueFormat = sync.OnceValue(func() *IssueFormatDoc {
+	var doc IssueFormatDoc
+	if err := yaml.Unmarshal([]byte(issueFormatConstitution), &doc); err != nil {
+		logf("embeddedIssueFormat: parsing issue-format constitution: %v", err)
+	}
+	return &doc
+})
+
+// validateIssueSchema checks a parsed-as-YAML issue description against
+// doc.FieldSpecs: required fields are present, values have the declared
+// type ("string", "integer", "list of strings", "list of mappings"), enum
+// fields hold one of their Values, and list-of-mapping entries satisfy
+// their SubFields. Fields the spec does not name are not checked.
+// Violations are returned in field order; a description that is not a
+// YAML mapping yields a single violation.
+func validateIssueSchema(desc string, doc *IssueFormatDoc) []issueSchemaViolation {
+	var parsed map[string]any
+	if err := yaml.Unmarshal([]byte(desc), &parsed); err != nil {
+		return []issueSchemaViolation{{Field: "(description)", Message: fmt.Sprintf("not a YAML mapping: %v", err)}}
+	}
+	return checkSchemaFields("", parsed, doc.FieldSpecs)
+}
+
+// checkSchemaFields validates the entries of m named by specs, prefixing
+// field paths with prefix.
+func checkSchemaFields(prefix string, m map[string]any, specs map[string]IssueFormatField) []issueSchemaViolation {
+	names := make([]string, 0, len(specs))
+	for name := range specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out []issueSchemaViolation
+	for _, name := range names {
+		spec := specs[name]
+		field := prefix + name
+		v, ok := m[name]
+		if !ok || v == nil {
+			if spec.Required {
+				out = append(out, issueSchemaViolation{field, "required field is missing"})
+			}
+			continue
+		}
+		out = append(out, checkSchemaValue(field, v, spec)...)
+	}
+	return out
+}
+
+// checkSchemaValue validates one value against its field spec.
+func checkSchemaValue(field string, v any, spec IssueFormatField) []issueSchemaViolation {
+	switch spec.Type {
+	case "string":
+		s, ok := v.(string)
+		if !ok {
+			return []issueSchemaViolation{{field, fmt.Sprintf("want string, got %s", yamlKind(v))}}
+		}
+		if len(spec.Values) > 0 && !slices.Contains(spec.Values, s) {
+			return []issueSchemaViolation{{field, fmt.Sprintf("%q is not one of %v", s, spec.Values)}}
+		}
+	case "integer":
+		if _, ok := v.(int); !ok {
+			return []issueSchemaViolation{{field, fmt.Sprintf("want integer, got %s", yamlKind(v))}}
+		}
+	case "list of strings", "list of mappings":
+		items, ok := v.([]any)
+		if !ok {
+			return []issueSchemaViolation{{field, fmt.Sprintf("want %s, got %s", spec.Type, yamlKind(v))}}
+		}
+		var out []issueSchemaViolation
+		for i, item := range items {
+			itemField := fmt.Sprintf("%s[%d]", field, i)
+			if spec.Type == "list of strings" {
+				if _, ok := item.(string); !ok {
+					out = append(out, issueSchemaViolation{itemField, fmt.Sprintf("want string, got %s", yamlKind(item))})
+				}
+				continue
+			}
+			entry, ok := item.(map[string]any)
+			if !ok {
+				out = append(out, issueSchemaViolation{itemField, fmt.Sprintf("want mapping, got %s", yamlKind(item))})
+				continue
+			}
+			out = append(out, checkSchemaFields(itemField+".", entry, spec.SubFields)...)
+		}
+		return out
+	}
+	return nil
+}
+
+// yamlKind names the YAML kind of a decoded value for violation messages.
+func yamlKind(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case int, int64, uint64:
+		return "integer"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "mapping"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// validateIssueSchemas checks each proposed issue's description against
+// the embedded issue-format constitution and returns one message per
+// violation, prefixed with the issue's index and title.
+func validateIssueSchemas(issues []proposedIssue) []string {
+	doc := embeddedIssueFormat()
+	var msgs []string
+	for _, issue := range issues {
+		for _, v := range validateIssueSchema(issue.Description, doc) {
+			msg := fmt.Sprintf("[%d] %q: schema: %s", issue.Index, issue.Title, v)
+			logf("validateIssueSchemas: %s", msg)
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+)
+
+const schemaValidDesc = `deliverable_type: code
+required_reading:
+  - docs/ARCHITECTURE.yaml
+files:
+  - path: pkg/a/a.go
+    action: create
+requirements:
+  - id: R1
+    text: Do the thing
+acceptance_criteria:
+  - id: AC1
+    text: The thing is done
+timeout_sec: 600
+`
+
+func TestValidateIssueSchema_Valid(t *testing.T) {
+	t.Parallel()
+	if got := validateIssueSchema(schemaValidDesc, embeddedIssueFormat()); len(got) != 0 {
+		t.Errorf("validateIssueSchema(valid) = %v, want none", got)
+	}
+}
+
+func TestValidateIssueSchema_Violations(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		desc  string
+		field string
+	}{
+		{"bad enum", strings.Replace(schemaValidDesc, "deliverable_type: code", "deliverable_type: feature", 1), "deliverable_type"},
+		{"missing required", strings.Replace(schemaValidDesc, "deliverable_type: code\n", "", 1), "deliverable_type"},
+		{"wrong type", strings.Replace(schemaValidDesc, "timeout_sec: 600", "timeout_sec: soon", 1), "timeout_sec"},
+		{"scalar for list", strings.Replace(schemaValidDesc, "required_reading:\n  - docs/ARCHITECTURE.yaml", "required_reading: docs/ARCHITECTURE.yaml", 1), "required_reading"},
+		{"sub-field enum", strings.Replace(schemaValidDesc, "action: create", "action: delete", 1), "files[0].action"},
+		{"missing sub-field", strings.Replace(schemaValidDesc, "  - id: R1\n", "  - ", 1), "requirements[0].id"},
+		{"not a mapping", "- just\n- a list\n", "(description)"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := validateIssueSchema(tc.desc, embeddedIssueFormat())
+			if len(got) != 1 || got[0].Field != tc.field {
+				t.Errorf("validateIssueSchema() = %v, want one violation on %s", got, tc.field)
+			}
+		})
+	}
+}
+
+func TestValidateIssueSchema_IgnoresUnknownFields(t *testing.T) {
+	t.Parallel()
+	desc := schemaValidDesc + "extra_notes: anything\n"
+	if got := validateIssueSchema(desc, embeddedIssueFormat()); len(got) != 0 {
+		t.Errorf("validateIssueSchema(unknown field) = %v, want none", got)
+	}
+}
+
+func TestValidateIssueSchemas_PrefixesIssue(t *testing.T) {
+	t.Parallel()
+	issues := []proposedIssue{
+		{Index: 0, Title: "ok", Description: schemaValidDesc},
+		{Index: 1, Title: "bad", Description: strings.Replace(schemaValidDesc, "action: create", "action: delete", 1)},
+	}
+	got := validateIssueSchemas(issues)
+	if len(got) != 1 || !strings.HasPrefix(got[0], `[1] "bad": schema: files[0].action`) {
+		t.Errorf("validateIssueSchemas() = %v, want one message for issue 1", got)
+	}
+}
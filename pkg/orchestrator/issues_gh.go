@@ -7,12 +7,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"hash/fnv"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // cobblerIssue holds the parsed representation of a GitHub issue created by
@@ -23,6 +25,7 @@ type cobblerIssue struct {
 	State       string // "open" or "closed"
 	Index       int    // cobbler_index from front-matter
 	DependsOn   int    // cobbler_depends_on (-1 = no dependency)
+	Priority    int    // cobbler_priority (1 = highest, 0 = unset)
 	Generation  string // cobbler_generation label value
 	Description string // Body text below the front-matter block
 	Labels      []string
@@ -34,13 +37,19 @@ type cobblerFrontMatter struct {
 	Generation string `yaml:"cobbler_generation"`
 	Index      int    `yaml:"cobbler_index"`
 	DependsOn  int    `yaml:"cobbler_depends_on"`
+	Priority   int    `yaml:"cobbler_priority"`
 }
 
 // cobblerLabelReady and cobblerLabelInProgress are the two status labels
-// applied to orchestrator issues during their lifecycle.
+// applied to orchestrator issues during their lifecycle. cobblerLabelReview
+// marks a stitched task whose merge is deferred for human review (GH-465).
+// cobblerLabelMeasureFailed marks a measuring placeholder left open after
+// a failed iteration (GH-492).
 const (
-	cobblerLabelReady      = "cobbler-ready"
-	cobblerLabelInProgress = "cobbler-in-progress"
+	cobblerLabelReady         = "cobbler-ready"
+	cobblerLabelInProgress    = "cobbler-in-progress"
+	cobblerLabelReview        = "cobbler-review"
+	cobblerLabelMeasureFailed = "cobbler-measure-failed"
 )
 
 // cobblerGenLabelPrefix is the prefix for generation-scoped labels.
@@ -69,13 +78,19 @@ func cobblerGenLabel(generation string) string {
 }
 
 // formatIssueFrontMatter formats the YAML front-matter block for an issue body.
-func formatIssueFrontMatter(generation string, index, dependsOn int) string {
-	if dependsOn < 0 {
-		return fmt.Sprintf("---\ncobbler_generation: %s\ncobbler_index: %d\n---\n\n",
-			generation, index)
-	}
-	return fmt.Sprintf("---\ncobbler_generation: %s\ncobbler_index: %d\ncobbler_depends_on: %d\n---\n\n",
-		generation, index, dependsOn)
+// cobbler_depends_on is omitted when dependsOn is negative and
+// cobbler_priority when priority is 0.
+func formatIssueFrontMatter(generation string, index, dependsOn, priority int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "---\ncobbler_generation: %s\ncobbler_index: %d\n", generation, index)
+	if dependsOn >= 0 {
+		fmt.Fprintf(&b, "cobbler_depends_on: %d\n", dependsOn)
+	}
+	if priority > 0 {
+		fmt.Fprintf(&b, "cobbler_priority: %d\n", priority)
+	}
+	b.WriteString("---\n\n")
+	return b.String()
 }
 
 // parseIssueFrontMatter splits a GitHub issue body into its YAML front-matter
@@ -104,6 +119,8 @@ func parseIssueFrontMatter(body string) (cobblerFrontMatter, string) {
 			fmt.Sscanf(strings.TrimSpace(strings.TrimPrefix(line, "cobbler_index:")), "%d", &fm.Index)
 		} else if strings.HasPrefix(line, "cobbler_depends_on:") {
 			fmt.Sscanf(strings.TrimSpace(strings.TrimPrefix(line, "cobbler_depends_on:")), "%d", &fm.DependsOn)
+		} else if strings.HasPrefix(line, "cobbler_priority:") {
+			fmt.Sscanf(strings.TrimSpace(strings.TrimPrefix(line, "cobbler_priority:")), "%d", &fm.Priority)
 		}
 	}
 	return fm, description
@@ -153,6 +170,8 @@ func ensureCobblerLabels(repo string) error {
 	}{
 		{cobblerLabelReady, "0075ca", "Cobbler task ready to be picked by stitch"},
 		{cobblerLabelInProgress, "e4e669", "Cobbler task currently being worked on"},
+		{cobblerLabelReview, "d876e3", "Cobbler task stitched and awaiting review before merge"},
+		{cobblerLabelMeasureFailed, "d73a4a", "Cobbler measure iteration failed; acknowledge and close"},
 	}
 
 	for _, l := range labels {
@@ -208,12 +227,15 @@ func ensureCobblerGenLabel(repo, generation string) error {
 	return nil
 }
 
+// measuringTitlePrefix starts the title of every measuring placeholder.
+const measuringTitlePrefix = "[measuring] "
+
 // createMeasuringPlaceholder creates a transient GitHub issue that signals
 // the measure agent is actively calling Claude for iteration i (1-based).
 // The issue carries no cobbler-ready label so stitch won't pick it up.
 // Callers must call closeMeasuringPlaceholder after the iteration completes.
 func createMeasuringPlaceholder(repo, generation string, iteration int) (int, error) {
-	title := fmt.Sprintf("[measuring] %s task %d", generation, iteration)
+	title := fmt.Sprintf("%s%s task %d", measuringTitlePrefix, generation, iteration)
 	body := fmt.Sprintf("Cobbler measure is calling Claude to propose task %d for generation %s.\n\nThis issue will be closed automatically when measure completes.", iteration, generation)
 	// No cobbler labels: stitch ignores issues without a gen label, and the
 	// placeholder must not appear in the existing-issues context sent to Claude.
@@ -260,13 +282,65 @@ func closeMeasuringPlaceholderWithComment(repo string, number int, comment strin
 	closeMeasuringPlaceholder(repo, number)
 }
 
+// placeholderDisposition says how a measuring placeholder is resolved at
+// the end of an iteration.
+type placeholderDisposition struct {
+	Close   bool   // close the issue
+	Label   string // label to add, or ""
+	Comment string // comment to post first, or ""
+}
+
+// measuringPlaceholderDisposition maps Cobbler.TrackingIssueOnFailure and
+// the iteration outcome to a disposition (GH-492). A successful iteration
+// always closes the placeholder without a comment. A failed one comments
+// and then closes, stays open, or stays open with the failed label;
+// unknown policies fall back to closing.
+func measuringPlaceholderDisposition(policy string, failed bool) placeholderDisposition {
+	if !failed {
+		return placeholderDisposition{Close: true}
+	}
+	switch policy {
+	case TrackingIssueKeepOpen:
+		return placeholderDisposition{
+			Comment: "Measure did not complete; left open for review. Close this issue once the failure is acknowledged.",
+		}
+	case TrackingIssueMarkFailed:
+		return placeholderDisposition{
+			Label:   cobblerLabelMeasureFailed,
+			Comment: "Measure did not complete; marked " + cobblerLabelMeasureFailed + ". Close this issue once the failure is acknowledged.",
+		}
+	}
+	return placeholderDisposition{Close: true, Comment: "Measure did not complete; closed automatically."}
+}
+
+// resolveMeasuringPlaceholder applies measuringPlaceholderDisposition to
+// the placeholder issue. Best-effort: logs and ignores errors.
+func resolveMeasuringPlaceholder(repo string, number int, policy string, failed bool) {
+	d := measuringPlaceholderDisposition(policy, failed)
+	switch {
+	case d.Close && d.Comment != "":
+		closeMeasuringPlaceholderWithComment(repo, number, d.Comment)
+		return
+	case d.Close:
+		closeMeasuringPlaceholder(repo, number)
+		return
+	}
+	commentCobblerIssue(repo, number, d.Comment)
+	if d.Label != "" {
+		if err := addIssueLabel(repo, number, d.Label); err != nil {
+			logf("resolveMeasuringPlaceholder: label #%d warning: %v", number, err)
+		}
+	}
+	logf("resolveMeasuringPlaceholder: left #%d open (tracking_issue_on_failure=%s)", number, policy)
+}
+
 // upgradeMeasuringPlaceholder converts the transient measuring placeholder
 // into the task issue in-place. It edits the placeholder's title and body
 // to match the proposed issue, adds the cobbler-gen label so stitch can
 // pick it up, and links it as a sub-issue of the parent generation issue
 // if the generation name encodes one (GH-578).
 func upgradeMeasuringPlaceholder(repo string, number int, generation string, issue proposedIssue) error {
-	body := formatIssueFrontMatter(generation, issue.Index, issue.Dependency) + issue.Description
+	body := formatIssueFrontMatter(generation, issue.Index, issue.Dependency, issue.Priority) + issue.Description
 	title := "[measure] " + issue.Title
 
 	// Edit title and body in one command.
@@ -296,13 +370,28 @@ func upgradeMeasuringPlaceholder(repo string, number int, generation string, iss
 	return nil
 }
 
+// editCobblerIssueBody rewrites the body of an existing cobbler issue from
+// issue, refreshing the front-matter (e.g. after its dependency changed).
+func editCobblerIssueBody(repo string, number int, generation string, issue proposedIssue) error {
+	body := formatIssueFrontMatter(generation, issue.Index, issue.Dependency, issue.Priority) + issue.Description
+	if err := exec.Command(binGh, "issue", "edit",
+		"--repo", repo,
+		fmt.Sprintf("%d", number),
+		"--body", body,
+	).Run(); err != nil {
+		return fmt.Errorf("gh issue edit #%d: %w", number, err)
+	}
+	logf("editCobblerIssueBody: updated #%d index=%d dep=%d", number, issue.Index, issue.Dependency)
+	return nil
+}
+
 // createCobblerIssue creates a GitHub issue on repo for the given generation
 // and proposedIssue. Returns the GitHub issue number.
 //
 // Note: gh issue create (v2.87.3) does not support --json; it outputs the
 // issue URL (https://github.com/owner/repo/issues/123) on success.
 func createCobblerIssue(repo, generation string, issue proposedIssue) (int, error) {
-	body := formatIssueFrontMatter(generation, issue.Index, issue.Dependency) + issue.Description
+	body := formatIssueFrontMatter(generation, issue.Index, issue.Dependency, issue.Priority) + issue.Description
 	title := "[measure] " + issue.Title
 
 	genLabel := cobblerGenLabel(generation)
@@ -458,6 +547,23 @@ func fetchIssueComments(repo string, number int) ([]string, error) {
 	return bodies, nil
 }
 
+// fetchCobblerIssue returns issue number of repo, whatever its labels or
+// state.
+func fetchCobblerIssue(repo string, number int) (cobblerIssue, error) {
+	out, err := exec.Command(binGh, "api",
+		fmt.Sprintf("repos/%s/issues/%d", repo, number),
+	).Output()
+	if err != nil {
+		return cobblerIssue{}, fmt.Errorf("gh api issue #%d: %w", number, err)
+	}
+	// The endpoint returns one issue object; parse it as a list of one.
+	issues, err := parseCobblerIssuesJSON([]byte("[" + string(out) + "]"))
+	if err != nil {
+		return cobblerIssue{}, err
+	}
+	return issues[0], nil
+}
+
 // parseCobblerIssuesJSON parses the JSON output from the GitHub REST API issues
 // endpoint into a slice of cobblerIssue structs.
 func parseCobblerIssuesJSON(data []byte) ([]cobblerIssue, error) {
@@ -487,6 +593,7 @@ func parseCobblerIssuesJSON(data []byte) ([]cobblerIssue, error) {
 			State:       r.State,
 			Index:       fm.Index,
 			DependsOn:   fm.DependsOn,
+			Priority:    fm.Priority,
 			Generation:  fm.Generation,
 			Description: desc,
 			Labels:      labelNames,
@@ -543,6 +650,11 @@ func promoteReadyIssues(repo, generation string) error {
 	}
 
 	for _, iss := range issues {
+		// Tasks awaiting review are done from stitch's perspective but stay
+		// open (blocking dependents) until AcceptTask merges them.
+		if hasLabel(iss, cobblerLabelReview) {
+			continue
+		}
 		blocked := iss.DependsOn >= 0 && openIndices[iss.DependsOn]
 		currentlyReady := hasLabel(iss, cobblerLabelReady)
 
@@ -559,8 +671,9 @@ func promoteReadyIssues(repo, generation string) error {
 	return nil
 }
 
-// pickReadyIssue promotes ready issues then picks the lowest-numbered
-// cobbler-ready issue, adds cobbler-in-progress, and returns it.
+// pickReadyIssue promotes ready issues then picks the cobbler-ready issue
+// with the best priority, lowest number breaking ties (see
+// selectReadyIssue), adds cobbler-in-progress, and returns it.
 func pickReadyIssue(repo, generation string) (cobblerIssue, error) {
 	if err := promoteReadyIssues(repo, generation); err != nil {
 		return cobblerIssue{}, fmt.Errorf("pickReadyIssue promote: %w", err)
@@ -571,7 +684,71 @@ func pickReadyIssue(repo, generation string) (cobblerIssue, error) {
 		return cobblerIssue{}, fmt.Errorf("pickReadyIssue list: %w", err)
 	}
 
-	// Filter to ready issues and sort by number ascending.
+	picked, ok := selectReadyIssue(issues)
+	if !ok {
+		return cobblerIssue{}, fmt.Errorf("no ready issues for generation %s", generation)
+	}
+	claimIssue(repo, picked.Number)
+	logf("pickReadyIssue: picked #%d %q gen=%s", picked.Number, picked.Title, generation)
+	return picked, nil
+}
+
+// claimIssue marks an issue in progress and no longer ready. Label
+// failures are logged; recoverStaleTasks repairs them on the next run.
+func claimIssue(repo string, number int) {
+	if err := addIssueLabel(repo, number, cobblerLabelInProgress); err != nil {
+		logf("claimIssue: add in-progress label to #%d: %v", number, err)
+	}
+	if err := removeIssueLabel(repo, number, cobblerLabelReady); err != nil {
+		logf("claimIssue: remove ready label from #%d: %v", number, err)
+	}
+}
+
+// claimIssueByID claims the open issue of the generation numbered issueID
+// (an optional leading # is ignored) after checking it with
+// selectStitchableIssue (GH-560).
+func claimIssueByID(repo, generation, issueID string) (cobblerIssue, error) {
+	if err := promoteReadyIssues(repo, generation); err != nil {
+		return cobblerIssue{}, fmt.Errorf("claimIssueByID promote: %w", err)
+	}
+	issues, err := listOpenCobblerIssues(repo, generation)
+	if err != nil {
+		return cobblerIssue{}, fmt.Errorf("claimIssueByID list: %w", err)
+	}
+	picked, err := selectStitchableIssue(issues, issueID)
+	if err != nil {
+		return cobblerIssue{}, err
+	}
+	claimIssue(repo, picked.Number)
+	logf("claimIssueByID: claimed #%d %q gen=%s", picked.Number, picked.Title, generation)
+	return picked, nil
+}
+
+// selectStitchableIssue returns the issue numbered issueID from issues,
+// failing when none matches, when it is already in progress, or when it
+// is not cobbler-ready (its dependencies are still open).
+func selectStitchableIssue(issues []cobblerIssue, issueID string) (cobblerIssue, error) {
+	issueID = strings.TrimPrefix(strings.TrimSpace(issueID), "#")
+	for _, iss := range issues {
+		if fmt.Sprintf("%d", iss.Number) != issueID {
+			continue
+		}
+		if hasLabel(iss, cobblerLabelInProgress) {
+			return cobblerIssue{}, fmt.Errorf("task %s is already in progress", issueID)
+		}
+		if !hasLabel(iss, cobblerLabelReady) {
+			return cobblerIssue{}, fmt.Errorf("task %s is not ready (open dependencies)", issueID)
+		}
+		return iss, nil
+	}
+	return cobblerIssue{}, fmt.Errorf("task %s is not an open cobbler issue", issueID)
+}
+
+// selectReadyIssue returns the issue pickReadyIssue should claim: the
+// cobbler-ready, not in-progress issue with the best cobbler_priority
+// (GH-546), then the lowest number. Issues without a priority come after
+// those with one. Returns false when no issue is ready.
+func selectReadyIssue(issues []cobblerIssue) (cobblerIssue, bool) {
 	var ready []cobblerIssue
 	for _, iss := range issues {
 		if hasLabel(iss, cobblerLabelReady) && !hasLabel(iss, cobblerLabelInProgress) {
@@ -579,19 +756,24 @@ func pickReadyIssue(repo, generation string) (cobblerIssue, error) {
 		}
 	}
 	if len(ready) == 0 {
-		return cobblerIssue{}, fmt.Errorf("no ready issues for generation %s", generation)
+		return cobblerIssue{}, false
 	}
-	sort.Slice(ready, func(i, j int) bool { return ready[i].Number < ready[j].Number })
+	sort.Slice(ready, func(i, j int) bool {
+		if pi, pj := priorityRank(ready[i].Priority), priorityRank(ready[j].Priority); pi != pj {
+			return pi < pj
+		}
+		return ready[i].Number < ready[j].Number
+	})
+	return ready[0], true
+}
 
-	picked := ready[0]
-	if err := addIssueLabel(repo, picked.Number, cobblerLabelInProgress); err != nil {
-		logf("pickReadyIssue: add in-progress label to #%d: %v", picked.Number, err)
-	}
-	if err := removeIssueLabel(repo, picked.Number, cobblerLabelReady); err != nil {
-		logf("pickReadyIssue: remove ready label from #%d: %v", picked.Number, err)
+// priorityRank maps a cobbler_priority to a sort key: 1 first, and 0
+// (unset) after every explicit priority.
+func priorityRank(priority int) int {
+	if priority <= 0 {
+		return math.MaxInt
 	}
-	logf("pickReadyIssue: picked #%d %q gen=%s", picked.Number, picked.Title, generation)
-	return picked, nil
+	return priority
 }
 
 // closeCobblerIssue closes a GitHub issue and re-runs promoteReadyIssues so
@@ -724,7 +906,7 @@ func gcStaleGenerationIssues(repo, generationPrefix string) {
 // listActiveIssuesContext returns a JSON array of ContextIssue objects for all
 // open issues in the generation, suitable for injection into the measure prompt.
 // The JSON format matches what parseIssuesJSON expects.
-func listActiveIssuesContext(repo, generation string) (string, error) {
+func listActiveIssuesContext(repo, generation string, descLimit int) (string, error) {
 	issues, err := listOpenCobblerIssues(repo, generation)
 	if err != nil {
 		return "", fmt.Errorf("listActiveIssuesContext: %w", err)
@@ -733,12 +915,14 @@ func listActiveIssuesContext(repo, generation string) (string, error) {
 		return "", nil
 	}
 	sort.Slice(issues, func(i, j int) bool { return issues[i].Index < issues[j].Index })
-	return issuesContextJSON(issues)
+	return issuesContextJSON(issues, descLimit)
 }
 
 // issuesContextJSON converts a slice of cobblerIssue into the JSON string
-// expected by parseIssuesJSON. Exported for testing.
-func issuesContextJSON(issues []cobblerIssue) (string, error) {
+// expected by parseIssuesJSON. When descLimit is positive each issue's
+// description is included, truncated to descLimit bytes (GH-476).
+// Exported for testing.
+func issuesContextJSON(issues []cobblerIssue, descLimit int) (string, error) {
 	ctx := make([]ContextIssue, len(issues))
 	for i, iss := range issues {
 		status := "backfill"
@@ -752,6 +936,9 @@ func issuesContextJSON(issues []cobblerIssue) (string, error) {
 			Title:  iss.Title,
 			Status: status,
 		}
+		if descLimit > 0 {
+			ctx[i].Description = truncateDescription(strings.TrimSpace(iss.Description), descLimit)
+		}
 	}
 	b, err := json.Marshal(ctx)
 	if err != nil {
@@ -760,6 +947,19 @@ func issuesContextJSON(issues []cobblerIssue) (string, error) {
 	return string(b), nil
 }
 
+// truncateDescription shortens s to at most limit bytes, cutting at a
+// UTF-8 boundary and appending a truncation marker.
+func truncateDescription(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "\n... (truncated)"
+}
+
 // addIssueLabel adds a label to a GitHub issue via the API.
 func addIssueLabel(repo string, number int, label string) error {
 	return exec.Command(binGh, "issue", "edit",
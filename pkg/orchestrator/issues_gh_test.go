@@ -98,7 +98,7 @@ func TestFormatIssueFrontMatter(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 			desc := "Test description content"
-			body := formatIssueFrontMatter(tc.generation, tc.index, tc.dependsOn) + desc
+			body := formatIssueFrontMatter(tc.generation, tc.index, tc.dependsOn, 0) + desc
 			fm, parsedDesc := parseIssueFrontMatter(body)
 
 			if fm.Generation != tc.generation {
@@ -545,7 +545,7 @@ func TestDAGPromotion_AllDepsResolved(t *testing.T) {
 
 func TestIssuesContextJSON_Empty(t *testing.T) {
 	t.Parallel()
-	result, err := issuesContextJSON(nil)
+	result, err := issuesContextJSON(nil, 0)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -561,7 +561,7 @@ func TestIssuesContextJSON_StatusMapping(t *testing.T) {
 		{Number: 11, Title: "Task B", Labels: []string{cobblerLabelInProgress}},
 		{Number: 12, Title: "Task C", Labels: []string{}},
 	}
-	result, err := issuesContextJSON(issues)
+	result, err := issuesContextJSON(issues, 0)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -597,7 +597,7 @@ func TestIssuesContextJSON_ParseableByParseIssuesJSON(t *testing.T) {
 	issues := []cobblerIssue{
 		{Number: 115, Title: "cmd/wc core implementation", Labels: []string{cobblerLabelReady}},
 	}
-	jsonStr, err := issuesContextJSON(issues)
+	jsonStr, err := issuesContextJSON(issues, 0)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -614,6 +614,41 @@ func TestIssuesContextJSON_ParseableByParseIssuesJSON(t *testing.T) {
 	}
 }
 
+// TestIssuesContextJSON_Descriptions verifies descriptions are included and
+// truncated only when a positive limit is given (GH-476).
+func TestIssuesContextJSON_Descriptions(t *testing.T) {
+	t.Parallel()
+	issues := []cobblerIssue{{Number: 7, Title: "t", Description: "deliverable_type: code\nrequirements: many\n"}}
+
+	without, err := issuesContextJSON(issues, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(without, "description") {
+		t.Errorf("description included with limit 0: %s", without)
+	}
+
+	with, err := issuesContextJSON(issues, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed := parseIssuesJSON(with)
+	if len(parsed) != 1 || parsed[0].Description != "deliverabl\n... (truncated)" {
+		t.Errorf("description = %q", parsed[0].Description)
+	}
+}
+
+func TestTruncateDescription_UTF8Boundary(t *testing.T) {
+	t.Parallel()
+	if got := truncateDescription("short", 10); got != "short" {
+		t.Errorf("got %q", got)
+	}
+	// "é" is two bytes; a cut inside it must back off to the rune start.
+	if got := truncateDescription("aé", 2); got != "a\n... (truncated)" {
+		t.Errorf("got %q", got)
+	}
+}
+
 // --- pickReadyIssue label invariant ---
 
 // TestPickReadyIssue_FilterExcludesBothLabels verifies that an issue carrying
@@ -639,6 +674,66 @@ func TestPickReadyIssue_FilterExcludesBothLabels(t *testing.T) {
 	}
 }
 
+// TestSelectReadyIssue_StableAcrossOrderings verifies that the pick does not
+// depend on the order the API returns issues in.
+func TestSelectReadyIssue_StableAcrossOrderings(t *testing.T) {
+	t.Parallel()
+	issues := []cobblerIssue{
+		{Number: 14, Index: 4, Labels: []string{cobblerLabelReady}},
+		{Number: 12, Index: 2, Labels: []string{cobblerLabelReady, cobblerLabelInProgress}},
+		{Number: 13, Index: 3, Labels: []string{cobblerLabelReady}},
+		{Number: 11, Index: 1},
+	}
+	orderings := [][]int{{0, 1, 2, 3}, {3, 2, 1, 0}, {2, 0, 3, 1}, {1, 3, 0, 2}}
+	for run := 0; run < 3; run++ {
+		for _, order := range orderings {
+			shuffled := make([]cobblerIssue, 0, len(order))
+			for _, i := range order {
+				shuffled = append(shuffled, issues[i])
+			}
+			got, ok := selectReadyIssue(shuffled)
+			if !ok || got.Number != 13 {
+				t.Fatalf("order %v: picked #%d (ok=%v), want #13", order, got.Number, ok)
+			}
+		}
+	}
+}
+
+func TestSelectReadyIssue_PrefersPriority(t *testing.T) {
+	t.Parallel()
+	issues := []cobblerIssue{
+		{Number: 10, Labels: []string{cobblerLabelReady}},
+		{Number: 11, Priority: 2, Labels: []string{cobblerLabelReady}},
+		{Number: 12, Priority: 1, Labels: []string{cobblerLabelReady}},
+		{Number: 13, Priority: 1, Labels: []string{cobblerLabelReady}},
+	}
+	if got, _ := selectReadyIssue(issues); got.Number != 12 {
+		t.Errorf("picked #%d, want #12 (priority 1, lowest number)", got.Number)
+	}
+	if got, _ := selectReadyIssue(issues[:2]); got.Number != 11 {
+		t.Errorf("picked #%d, want #11 (a priority beats none)", got.Number)
+	}
+}
+
+func TestFormatIssueFrontMatter_Priority(t *testing.T) {
+	t.Parallel()
+	body := formatIssueFrontMatter("gen", 1, 0, 3) + "desc"
+	fm, _ := parseIssueFrontMatter(body)
+	if fm.Priority != 3 || fm.DependsOn != 0 {
+		t.Errorf("front matter = %+v, want priority 3 and depends_on 0", fm)
+	}
+	if strings.Contains(formatIssueFrontMatter("gen", 1, -1, 0), "cobbler_priority") {
+		t.Error("cobbler_priority written for unset priority")
+	}
+}
+
+func TestSelectReadyIssue_NoneReady(t *testing.T) {
+	t.Parallel()
+	if _, ok := selectReadyIssue([]cobblerIssue{{Number: 1}}); ok {
+		t.Error("expected no ready issue")
+	}
+}
+
 // TestCloseCobblerIssue_FakeRepo_NoOp verifies closeCobblerIssue returns an
 // error (not panic) when the GitHub CLI fails on a fake repo (GH-569).
 func TestCloseCobblerIssue_FakeRepo_NoOp(t *testing.T) {
@@ -677,6 +772,57 @@ func TestCloseMeasuringPlaceholderWithComment_FakeRepo_NoOp(t *testing.T) {
 		"Measure did not complete; closed automatically.") // must not panic
 }
 
+func TestLoadConfig_TrackingIssueOnFailure(t *testing.T) {
+	t.Parallel()
+	cfg, err := LoadConfig(writeTemp(t, "cobbler:\n  tracking_issue_on_failure: failed\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Cobbler.TrackingIssueOnFailure != TrackingIssueMarkFailed {
+		t.Errorf("tracking_issue_on_failure = %q, want %q", cfg.Cobbler.TrackingIssueOnFailure, TrackingIssueMarkFailed)
+	}
+	if _, err := LoadConfig(writeTemp(t, "cobbler:\n  tracking_issue_on_failure: keep_open\n")); err == nil {
+		t.Error("expected an error for an unknown cobbler.tracking_issue_on_failure")
+	}
+}
+
+// TestMeasuringPlaceholderDisposition covers the success and failure paths
+// for each Cobbler.TrackingIssueOnFailure policy (GH-492).
+func TestMeasuringPlaceholderDisposition(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		policy      string
+		failed      bool
+		wantClose   bool
+		wantLabel   string
+		wantComment bool
+	}{
+		{TrackingIssueClose, false, true, "", false},
+		{TrackingIssueClose, true, true, "", true},
+		{TrackingIssueKeepOpen, false, true, "", false},
+		{TrackingIssueKeepOpen, true, false, "", true},
+		{TrackingIssueMarkFailed, false, true, "", false},
+		{TrackingIssueMarkFailed, true, false, cobblerLabelMeasureFailed, true},
+		{"bogus", true, true, "", true},
+	}
+	for _, tt := range tests {
+		d := measuringPlaceholderDisposition(tt.policy, tt.failed)
+		if d.Close != tt.wantClose || d.Label != tt.wantLabel || (d.Comment != "") != tt.wantComment {
+			t.Errorf("policy=%q failed=%v: got %+v, want close=%v label=%q comment=%v",
+				tt.policy, tt.failed, d, tt.wantClose, tt.wantLabel, tt.wantComment)
+		}
+	}
+}
+
+// TestResolveMeasuringPlaceholder_FakeRepo_NoOp verifies every policy is
+// best-effort when the GitHub CLI fails on a fake repo (GH-492).
+func TestResolveMeasuringPlaceholder_FakeRepo_NoOp(t *testing.T) {
+	t.Parallel()
+	for _, policy := range []string{TrackingIssueClose, TrackingIssueKeepOpen, TrackingIssueMarkFailed} {
+		resolveMeasuringPlaceholder("fake/repo-that-does-not-exist", 99999, policy, true) // must not panic
+	}
+}
+
 // TestPlaceholderResolved_DeferIsNoOpOnSuccess verifies that when
 // placeholderResolved is set to true before a defer fires, the defer body
 // does not call closeMeasuringPlaceholderWithComment (GH-747).
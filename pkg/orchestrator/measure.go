@@ -5,11 +5,17 @@ package orchestrator
 
 import (
 	_ "embed"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand/v2"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,6 +25,9 @@ import (
 //go:embed prompts/measure.yaml
 var defaultMeasurePrompt string
 
+//go:embed prompts/measure-spec-maintenance.yaml
+var defaultSpecMaintenancePrompt string
+
 //go:embed constitutions/planning.yaml
 var planningConstitution string
 
@@ -70,7 +79,12 @@ func (o *Orchestrator) RunMeasure() error {
 	logf("starting (iterative, %d issue(s) requested)", o.cfg.Cobbler.MaxMeasureIssues)
 	o.logConfig("measure")
 
-	if err := o.checkClaude(); err != nil {
+	// Load documents and source once per run; iterations only refresh the
+	// issue list (GH-509).
+	o.measureCtx = &projectContextCache{}
+	defer func() { o.measureCtx = nil }()
+
+	if err := o.checkClaude(false); err != nil {
 		return err
 	}
 
@@ -93,23 +107,35 @@ func (o *Orchestrator) RunMeasure() error {
 
 	_ = os.MkdirAll(o.cfg.Cobbler.Dir, 0o755) // best-effort; dir may already exist
 
-	// Resolve the GitHub repo for issue management.
-	repoRoot, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("getting working directory: %w", err)
-	}
-	repo, err := detectGitHubRepo(repoRoot, o.cfg)
-	if err != nil {
-		logf("detectGitHubRepo failed: %v", err)
-		return fmt.Errorf("detecting GitHub repo: %w", err)
-	}
-	logf("using GitHub repo %s for issues", repo)
+	// Resolve the GitHub repo for issue management. Output-only mode
+	// never touches GitHub (GH-545).
+	outputOnly := o.cfg.Cobbler.MeasureOutputOnly
+	var repo string
+	if outputOnly {
+		o.measureOutput = measureOutputPath(o.cfg.Cobbler.Dir, measureStart)
+		defer func() { o.measureOutput = "" }()
+		logf("output-only mode: proposed issues go to %s, not GitHub", o.measureOutput)
+	} else {
+		repoRoot, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting working directory: %w", err)
+		}
+		repo, err = detectGitHubRepo(repoRoot, o.cfg)
+		if err != nil {
+			logf("detectGitHubRepo failed: %v", err)
+			return fmt.Errorf("detecting GitHub repo: %w", err)
+		}
+		logf("using GitHub repo %s for issues", repo)
 
-	// Ensure the cobbler labels and generation label exist on the repo.
-	if err := ensureCobblerLabels(repo); err != nil {
-		logf("ensureCobblerLabels warning: %v", err)
+		// Ensure the cobbler labels and generation label exist on the repo.
+		if err := ensureCobblerLabels(repo); err != nil {
+			logf("ensureCobblerLabels warning: %v", err)
+		}
+		ensureCobblerGenLabel(repo, generation) // nolint: best-effort
+
+		// Close a placeholder left open by a crashed run.
+		o.closeStaleMeasuringPlaceholder(repo, generation)
 	}
-	ensureCobblerGenLabel(repo, generation) // nolint: best-effort
 
 	// Run pre-cycle analysis so the measure prompt sees current project state.
 	o.RunPreCycleAnalysis()
@@ -125,7 +151,7 @@ func (o *Orchestrator) RunMeasure() error {
 	// Schema errors and constitution drift are bugs in the target project's
 	// files; filing them as GitHub issues prevents Claude from proposing them
 	// as measure tasks, which would fail validation and block the cycle.
-	if analysis := loadAnalysisDoc(o.cfg.Cobbler.Dir); analysis != nil && len(analysis.Defects) > 0 {
+	if analysis := loadAnalysisDoc(o.cfg.Cobbler.Dir); !outputOnly && analysis != nil && len(analysis.Defects) > 0 {
 		if targetRepo := resolveTargetRepo(o.cfg); targetRepo != "" {
 			logf("measure: filing %d defect(s) as bug issues in %s", len(analysis.Defects), targetRepo)
 			fileTargetRepoDefects(targetRepo, analysis.Defects)
@@ -144,7 +170,7 @@ func (o *Orchestrator) RunMeasure() error {
 	}
 
 	// Get initial state: open GitHub issues for this generation.
-	existingIssues, _ := listActiveIssuesContext(repo, generation)
+	existingIssues, _ := o.measureExistingIssues(repo, generation)
 	commitSHA, _ := gitRevParseHEAD(".") // empty string on error is acceptable for logging
 
 	logf("existing issues context len=%d, maxMeasureIssues=%d, commit=%s",
@@ -159,16 +185,47 @@ func (o *Orchestrator) RunMeasure() error {
 	// so subsequent calls see existing issues and avoid duplicates.
 	totalIssues := o.cfg.Cobbler.MaxMeasureIssues
 	var allCreatedIDs []string
+	totalDuplicates := 0 // proposed issues skipped as duplicate titles (GH-543)
 	var totalTokens ClaudeResult
 	maxRetries := o.cfg.Cobbler.MaxMeasureRetries
+	// Run-wide retry budget (GH-470): retriesUsed counts retries across all
+	// iterations against MaxMeasureRetriesTotal (0 = unlimited).
+	retryBudget := o.cfg.Cobbler.MaxMeasureRetriesTotal
+	retriesUsed := 0
+	budgetLogged := false
+	// Per-iteration cost ceiling (GH-547): iterCost sums the Claude cost of
+	// the current iteration's attempts against MaxMeasureCostUSD (0 = none).
+	maxIterCost := o.cfg.Cobbler.MaxMeasureCostUSD
+	iterCost := 0.0
+	canRetry := func(iter, attempt int) bool {
+		if attempt >= maxRetries || o.interrupted() {
+			return false
+		}
+		if measureIterationCostCapped(iterCost, maxIterCost) {
+			logf("iteration %d cost-capped: $%.2f spent, max $%.2f, no further retries",
+				iter, iterCost, maxIterCost)
+			return false
+		}
+		if !measureRetryBudgetLeft(retriesUsed, retryBudget) {
+			if !budgetLogged {
+				logf("iteration %d run-wide retry budget exhausted (%d/%d), no further retries this run",
+					iter, retriesUsed, retryBudget)
+				budgetLogged = true
+			}
+			return false
+		}
+		retriesUsed++
+		return true
+	}
 
 	for i := 0; i < totalIssues; i++ {
 		logf("--- iteration %d/%d ---", i+1, totalIssues)
+		iterCost = 0
 
 		// Refresh existing issues from GitHub before each call (except the first,
 		// where we already have them).
 		if i > 0 {
-			refreshed, refreshErr := listActiveIssuesContext(repo, generation)
+			refreshed, refreshErr := o.measureExistingIssues(repo, generation)
 			if refreshErr != nil {
 				logf("measure: warning: refreshing issue list: %v", refreshErr)
 			} else {
@@ -179,17 +236,28 @@ func (o *Orchestrator) RunMeasure() error {
 		// Create a placeholder issue so users can see measure is running Claude.
 		// The placeholder has no cobbler labels and is invisible to stitch and to
 		// the measure context prompt. It is closed after the iteration regardless
-		// of outcome (GH-568). The defer below closes it on any early-return path
-		// (e.g. Claude failure) so it never stays open as an orphan (GH-747).
-		placeholderNum, placeholderErr := createMeasuringPlaceholder(repo, generation, i+1)
-		if placeholderErr != nil {
-			logf("measure: warning: createMeasuringPlaceholder: %v", placeholderErr)
+		// of outcome (GH-568). The defer below resolves it on any early-return
+		// path (e.g. Claude failure) so it never stays open as an orphan
+		// (GH-747), unless TrackingIssueOnFailure keeps it open on purpose
+		// (GH-492). The number is recorded on disk until resolved so the next
+		// run can close it if this one crashes.
+		var placeholderNum int
+		placeholderPath := o.measuringPlaceholderPath(generation)
+		if !outputOnly {
+			var placeholderErr error
+			placeholderNum, placeholderErr = createMeasuringPlaceholder(repo, generation, i+1)
+			if placeholderErr != nil {
+				logf("measure: warning: createMeasuringPlaceholder: %v", placeholderErr)
+			} else {
+				writeMeasuringPlaceholder(placeholderPath, placeholderNum)
+			}
 		}
 		placeholderResolved := false
 		if placeholderNum > 0 {
 			defer func(num int) {
 				if !placeholderResolved {
-					closeMeasuringPlaceholderWithComment(repo, num, "Measure did not complete; closed automatically.")
+					resolveMeasuringPlaceholder(repo, num, o.cfg.Cobbler.TrackingIssueOnFailure, true)
+					clearMeasuringPlaceholder(placeholderPath)
 				}
 			}(placeholderNum)
 		}
@@ -202,8 +270,19 @@ func (o *Orchestrator) RunMeasure() error {
 		// Attempt loop: try Claude + import, retrying on validation failure.
 		for attempt := 0; attempt <= maxRetries; attempt++ {
 			if attempt > 0 {
-				logf("iteration %d retry %d/%d (validation rejected previous output)",
-					i+1, attempt, maxRetries)
+				logf("iteration %d retry %d/%d (validation rejected previous output, run-wide retries used %d)",
+					i+1, attempt, maxRetries, retriesUsed)
+				if delay := measureRetryBackoff(o.cfg.MeasureRetryBackoffBase(), attempt); delay > 0 {
+					logf("iteration %d retry %d backing off %s", i+1, attempt, delay.Round(time.Millisecond))
+					select {
+					case <-time.After(delay):
+					case <-o.runContext().Done():
+					}
+					if o.interrupted() {
+						logf("iteration %d interrupted during retry backoff", i+1)
+						return errInterrupted
+					}
+				}
 			}
 
 			timestamp := time.Now().Format("20060102-150405")
@@ -214,14 +293,17 @@ func (o *Orchestrator) RunMeasure() error {
 			if promptErr != nil {
 				return promptErr
 			}
-			logf("iteration %d prompt built, length=%d bytes", i+1, len(prompt))
+			promptTokens := tokensForBytes([]byte(prompt), o.cfg.Cobbler.ContextCharsPerToken)
+			contextTrimmed := o.lastTrim.removed()
+			logf("iteration %d prompt built, length=%d bytes (~%d tokens)", i+1, len(prompt), promptTokens)
 
 			// Save prompt BEFORE calling Claude so it's on disk even if Claude times out.
 			historyTS := time.Now().Format("2006-01-02-15-04-05")
 			o.saveHistoryPrompt(historyTS, "measure", prompt)
 
 			iterStart := time.Now()
-			tokens, err := o.runClaude(prompt, "", o.cfg.Silence(), "--max-turns", "1")
+			claudeArgs := append([]string{"--max-turns", "1"}, modelArgs(o.cfg.Claude.MeasureModel)...)
+			tokens, err := o.runClaude(prompt, "", o.cfg.Silence(), claudeArgs...)
 			iterDuration := time.Since(iterStart)
 
 			totalTokens.InputTokens += tokens.InputTokens
@@ -229,6 +311,7 @@ func (o *Orchestrator) RunMeasure() error {
 			totalTokens.CacheCreationTokens += tokens.CacheCreationTokens
 			totalTokens.CacheReadTokens += tokens.CacheReadTokens
 			totalTokens.CostUSD += tokens.CostUSD
+			iterCost += tokens.CostUSD
 
 			if err != nil {
 				logf("Claude failed on iteration %d after %s: %v",
@@ -237,6 +320,7 @@ func (o *Orchestrator) RunMeasure() error {
 				o.saveHistoryLog(historyTS, "measure", tokens.RawOutput)
 				o.saveHistoryStats(historyTS, "measure", HistoryStats{
 					Caller:        "measure",
+					Generation:    generation,
 					Status:        "failed",
 					Error:         fmt.Sprintf("claude failure (iteration %d/%d): %v", i+1, totalIssues, err),
 					StartedAt:     iterStart.UTC().Format(time.RFC3339),
@@ -249,7 +333,18 @@ func (o *Orchestrator) RunMeasure() error {
 					SessionID:     tokens.SessionID,
 					LOCBefore:     locBefore,
 					LOCAfter:      o.captureLOC(),
+
+					PromptBytes:          len(prompt),
+					PromptTokensEstimate: promptTokens,
+					ContextTrimmed:       contextTrimmed,
 				})
+				var ce *ClaudeError
+				if errors.As(err, &ce) {
+					logf("iteration %d Claude error kind=%s subtype=%q: %s", i+1, ce.Kind(), ce.Subtype, ce.Message)
+					if ce.Retryable() && canRetry(i+1, attempt) {
+						continue // transient overload; auth and other errors fail the run
+					}
+				}
 				return fmt.Errorf("running Claude (iteration %d/%d): %w", i+1, totalIssues, err)
 			}
 			logf("iteration %d Claude completed in %s", i+1, iterDuration.Round(time.Second))
@@ -258,6 +353,7 @@ func (o *Orchestrator) RunMeasure() error {
 			o.saveHistory(historyTS, tokens.RawOutput, outputFile)
 			o.saveHistoryStats(historyTS, "measure", HistoryStats{
 				Caller:        "measure",
+				Generation:    generation,
 				Status:        "success",
 				StartedAt:     iterStart.UTC().Format(time.RFC3339),
 				Duration:      iterDuration.Round(time.Second).String(),
@@ -269,6 +365,10 @@ func (o *Orchestrator) RunMeasure() error {
 				SessionID:     tokens.SessionID,
 				LOCBefore:     locBefore,
 				LOCAfter:      o.captureLOC(),
+
+				PromptBytes:          len(prompt),
+				PromptTokensEstimate: promptTokens,
+				ContextTrimmed:       contextTrimmed,
 			})
 
 			// Extract YAML from Claude's text output and write to file.
@@ -276,7 +376,7 @@ func (o *Orchestrator) RunMeasure() error {
 			yamlContent, extractErr := extractYAMLBlock(textOutput)
 			if extractErr != nil {
 				logf("iteration %d YAML extraction failed: %v", i+1, extractErr)
-				if attempt < maxRetries {
+				if canRetry(i+1, attempt) {
 					continue // retry
 				}
 				logf("iteration %d retries exhausted, no YAML extracted", i+1)
@@ -290,10 +390,12 @@ func (o *Orchestrator) RunMeasure() error {
 
 			var importErr error
 			var validationErrs []string
-			createdIDs, validationErrs, importErr = o.importIssues(outputFile, repo, generation, placeholderNum)
+			var skipped int
+			createdIDs, skipped, validationErrs, importErr = o.importIssues(outputFile, repo, generation, placeholderNum)
+			totalDuplicates += skipped
 			if importErr != nil {
 				logf("iteration %d import failed: %v", i+1, importErr)
-				if attempt < maxRetries {
+				if canRetry(i+1, attempt) {
 					lastValidationErrors = validationErrs // feed errors back into next prompt
 					_ = os.Remove(outputFile)             // best-effort cleanup before retry
 					continue                              // retry
@@ -301,7 +403,9 @@ func (o *Orchestrator) RunMeasure() error {
 				// Retries exhausted: accept with warning (R5).
 				logf("iteration %d retries exhausted, accepting last result with warnings", i+1)
 				var forceErr error
-				createdIDs, forceErr = o.importIssuesForce(outputFile, repo, generation, placeholderNum)
+				var skipped int
+				createdIDs, skipped, forceErr = o.importIssuesForce(outputFile, repo, generation, placeholderNum)
+				totalDuplicates += skipped
 				if forceErr != nil {
 					logf("iteration %d force import failed: %v", i+1, forceErr)
 				}
@@ -325,7 +429,10 @@ func (o *Orchestrator) RunMeasure() error {
 		// Mark placeholderResolved so the defer registered above is a no-op (GH-747).
 		placeholderResolved = true
 		if placeholderNum > 0 && !placeholderUpgraded {
-			closeMeasuringPlaceholder(repo, placeholderNum)
+			resolveMeasuringPlaceholder(repo, placeholderNum, o.cfg.Cobbler.TrackingIssueOnFailure, false)
+		}
+		if placeholderNum > 0 {
+			clearMeasuringPlaceholder(placeholderPath)
 		}
 
 		// Record invocation metrics on each created issue.
@@ -337,13 +444,87 @@ func (o *Orchestrator) RunMeasure() error {
 		} else if lastOutputFile != "" {
 			os.Remove(lastOutputFile) // nolint: best-effort temp file cleanup
 		}
+
+		// Let the tracker catch up before the next iteration refreshes the
+		// issue list, so it does not miss the issues just created (GH-481).
+		if i < totalIssues-1 && len(createdIDs) > 0 && !outputOnly {
+			o.settleMeasureIteration(repo, generation, createdIDs)
+		}
 	}
 
-	logf("completed %d iteration(s), %d issue(s) created in %s",
-		totalIssues, len(allCreatedIDs), time.Since(measureStart).Round(time.Second))
+	logf("completed %d iteration(s), %d issue(s) created, %d skipped as duplicate(s) in %s",
+		totalIssues, len(allCreatedIDs), totalDuplicates, time.Since(measureStart).Round(time.Second))
+
+	if !outputOnly {
+		o.postMeasurePreview(repo, generation, allCreatedIDs)
+	}
 	return nil
 }
 
+// settleMeasureIteration waits for issues created by a measure iteration
+// to become visible, according to Cobbler.MeasureSettleMode. In delay mode
+// it sleeps MeasureSettleSeconds, returning early on interrupt. In
+// consistent mode it polls the open issue list until every created ID
+// appears, giving up with a warning after MeasureSettleTimeoutSeconds; the
+// next iteration proceeds either way. In count mode importIssues'
+// waitForIssuesVisible is the only wait. A negative MeasureSettleSeconds
+// disables settling.
+func (o *Orchestrator) settleMeasureIteration(repo, generation string, createdIDs []string) {
+	interval := time.Duration(o.cfg.Cobbler.MeasureSettleSeconds) * time.Second
+	if interval <= 0 {
+		return
+	}
+	switch o.cfg.Cobbler.MeasureSettleMode {
+	case MeasureSettleConsistent:
+	case MeasureSettleCount:
+		return
+	default:
+		logf("measure: settling %s before next iteration", interval)
+		select {
+		case <-time.After(interval):
+		case <-o.runContext().Done():
+		}
+		return
+	}
+
+	start := time.Now()
+	deadline := start.Add(time.Duration(o.cfg.Cobbler.MeasureSettleTimeoutSeconds) * time.Second)
+	for {
+		issues, err := listOpenCobblerIssues(repo, generation)
+		if err != nil {
+			logf("measure: settle: listing issues: %v", err)
+		} else if missing := missingIssueIDs(issues, createdIDs); len(missing) == 0 {
+			logf("measure: settle: %d created issue(s) visible after %s",
+				len(createdIDs), time.Since(start).Round(time.Millisecond))
+			return
+		} else if !time.Now().Before(deadline) {
+			logf("measure: settle: warning: issue(s) %v still not listed after %s, continuing",
+				missing, time.Since(start).Round(time.Second))
+			return
+		}
+		if !time.Now().Before(deadline) {
+			return
+		}
+		time.Sleep(interval)
+	}
+}
+
+// missingIssueIDs returns the IDs in want that have no matching issue
+// number in issues, in the order given.
+func missingIssueIDs(issues []cobblerIssue, want []string) []string {
+	seen := make(map[string]bool, len(issues))
+	for _, iss := range issues {
+		seen[fmt.Sprintf("%d", iss.Number)] = true
+	}
+	var missing []string
+	for _, id := range want {
+		if !seen[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
 // truncateSHA returns the first 8 characters of a SHA, or the full
 // string if shorter.
 func truncateSHA(sha string) string {
@@ -354,12 +535,24 @@ func truncateSHA(sha string) string {
 }
 
 func (o *Orchestrator) buildMeasurePrompt(userInput, existingIssues string, limit int, validationErrors ...string) (string, error) {
-	tmpl, err := parsePromptTemplate(orDefault(o.cfg.Cobbler.MeasurePrompt, defaultMeasurePrompt))
+	o.lastTrim = budgetTrim{}
+	var tmplText string
+	if o.cfg.Cobbler.MeasureMode == MeasureModeSpecMaintenance {
+		tmplText = o.resolvePromptTemplate("spec-maintenance", "", defaultSpecMaintenancePrompt)
+		logf("buildMeasurePrompt: measure_mode=%s, using the spec-maintenance prompt", MeasureModeSpecMaintenance)
+	} else {
+		tmplText = o.resolvePromptTemplate("measure", o.cfg.Cobbler.MeasurePrompt, defaultMeasurePrompt)
+	}
+	tmpl, err := parsePromptTemplate(tmplText)
 	if err != nil {
 		return "", fmt.Errorf("measure prompt YAML: %w", err)
 	}
 
-	planningConst := orDefault(o.cfg.Cobbler.PlanningConstitution, planningConstitution)
+	// P9 ranges come from Cobbler.GranularityRules, the same ranges the
+	// validator enforces (GH-542).
+	planningConst := substitutePlaceholders(
+		orDefault(o.cfg.Cobbler.PlanningConstitution, planningConstitution),
+		map[string]string{"granularity_targets": o.cfg.Cobbler.GranularityRules.p9Targets()})
 
 	// Load per-phase context file (prd003 R9.8).
 	measureCtxPath := filepath.Join(o.cfg.Cobbler.Dir, "measure_context.yaml")
@@ -427,11 +620,28 @@ func (o *Orchestrator) buildMeasurePrompt(userInput, existingIssues string, limi
 		}
 	}
 
-	projectCtx, ctxErr := buildProjectContext(existingIssues, o.cfg.Project, phaseCtx)
+	// Measure runs from the repository root, so patterns resolve there.
+	projectCtx, ctxErr := o.measureCtx.build(".", existingIssues, o.cfg.Project, phaseCtx, o.cfg.Cobbler.ExcludeReleases)
 	if ctxErr != nil {
 		logf("buildMeasurePrompt: buildProjectContext error: %v", ctxErr)
 		projectCtx = &ProjectContext{}
 	}
+	if o.cfg.Cobbler.FocusArea != "" {
+		projectCtx.SourceCode = focusSourceFiles(projectCtx.SourceCode, o.cfg.Cobbler.FocusArea)
+	}
+	// Incremental mode keeps files changed since the generation started
+	// and drops unchanged ones first (GH-564).
+	var changedPaths []string
+	if o.cfg.Cobbler.IncrementalSince {
+		if changed, ok := changedSinceStart(currentGeneration); ok {
+			changedPaths = changed
+			logf("buildMeasurePrompt: incremental_since: %d file(s) changed since %s-start", len(changed), currentGeneration)
+		} else {
+			logf("buildMeasurePrompt: incremental_since: no baseline for generation %q, using full context", currentGeneration)
+		}
+	}
+	projectCtx.SourceCode = capSourceFiles(projectCtx.SourceCode, o.cfg.Project.MaxSourceFiles, changedPaths, nil)
+	o.applyContextBudgets(projectCtx, changedPaths, nil)
 
 	placeholders := map[string]string{
 		"limit":            fmt.Sprintf("%d", limit),
@@ -446,7 +656,7 @@ func (o *Orchestrator) buildMeasurePrompt(userInput, existingIssues string, limi
 	var measureContracts []OODPackageContractRef
 	sourceMode := phaseCtx.SourceMode
 	if sourceMode == "headers" || sourceMode == "custom" {
-		contracts, _ := loadOODPromptContext()
+		contracts, _ := loadOODPromptContext(".")
 		if len(contracts) > 0 {
 			measureContracts = contracts
 			logf("buildMeasurePrompt: injecting %d package_contracts (source_mode=%s)", len(contracts), sourceMode)
@@ -476,6 +686,7 @@ func (o *Orchestrator) buildMeasurePrompt(userInput, existingIssues string, limi
 	activeReleases := filterImplementedReleases(o.cfg.Project.Releases)
 	activeRelease := filterImplementedRelease(o.cfg.Project.Release)
 	doc.Constraints += measureReleasesConstraint(activeReleases, activeRelease)
+	doc.Constraints += measureFocusConstraint(o.cfg.Cobbler.FocusArea)
 
 	out, err := yaml.Marshal(&doc)
 	if err != nil {
@@ -487,6 +698,52 @@ func (o *Orchestrator) buildMeasurePrompt(userInput, existingIssues string, limi
 	return string(out), nil
 }
 
+// maxMeasureRetryBackoff caps the delay measureRetryBackoff returns, so a
+// large retry count cannot overflow the doubling or stall a run for hours.
+const maxMeasureRetryBackoff = 5 * time.Minute
+
+// measureRetryBackoff returns the delay before measure retry number retry
+// (1 for the first retry): base doubled per earlier retry plus up to 50%
+// random jitter, as in rateLimitBackoff, capped at maxMeasureRetryBackoff.
+// A zero base or a retry below 1 gives no delay.
+func measureRetryBackoff(base time.Duration, retry int) time.Duration {
+	if base <= 0 || retry < 1 {
+		return 0
+	}
+	d := base
+	for i := 1; i < retry && d < maxMeasureRetryBackoff; i++ {
+		d *= 2
+	}
+	return min(d+rand.N(d/2+1), maxMeasureRetryBackoff)
+}
+
+// changedSinceStart returns the files added, modified, or renamed between
+// the generation's -start tag and the working tree. ok is false when
+// there is no generation, the tag does not exist, or git fails, so the
+// caller falls back to the full context (GH-564).
+func changedSinceStart(generation string) ([]string, bool) {
+	if generation == "" {
+		return nil, false
+	}
+	startTag := generation + "-start"
+	if len(gitListTags(startTag, ".")) == 0 {
+		return nil, false
+	}
+	changes, err := gitDiffNameStatus(startTag, ".")
+	if err != nil {
+		logf("changedSinceStart: diff against %s: %v", startTag, err)
+		return nil, false
+	}
+	changed := []string{}
+	for _, fc := range changes {
+		if fc.Status == "D" {
+			continue
+		}
+		changed = append(changed, fc.Path)
+	}
+	return changed, true
+}
+
 // measureReleasesConstraint returns a hard constraint string to append to the
 // measure prompt when a release scope is configured. Returns "" when no scope
 // is set. Releases (list) takes precedence over Release (single string).
@@ -506,6 +763,40 @@ func measureReleasesConstraint(releases []string, release string) string {
 	return ""
 }
 
+// measureFocusConstraint returns a hard constraint string to append to the
+// measure prompt when a focus area is configured, or "" when none is.
+func measureFocusConstraint(focus string) string {
+	if focus == "" {
+		return ""
+	}
+	return fmt.Sprintf(
+		"\n\nFocus area: You MUST only propose tasks within %s. Do not propose work outside this area.",
+		focus,
+	)
+}
+
+// focusSourceFiles narrows sources to the path named by a focus area
+// (its parenthetical note stripped): files matching it by suffix, as in
+// filterSourceFiles, or lying under it as a directory. When nothing
+// matches, sources is returned unchanged so a mistyped focus does not
+// empty the context.
+func focusSourceFiles(sources []SourceFile, focus string) []SourceFile {
+	path := strings.TrimSuffix(stripParenthetical(focus), "/")
+	var focused []SourceFile
+	for _, sf := range sources {
+		if sourceFileMatchesAny(sf, []string{path}) ||
+			strings.HasPrefix(sf.File, path+"/") || strings.Contains(sf.File, "/"+path+"/") {
+			focused = append(focused, sf)
+		}
+	}
+	if len(focused) == 0 {
+		logf("buildMeasurePrompt: focus area %q matched no source files, keeping all %d", focus, len(sources))
+		return sources
+	}
+	logf("buildMeasurePrompt: focus area %q kept %d of %d source file(s)", focus, len(focused), len(sources))
+	return focused
+}
+
 // filterImplementedReleases returns a copy of releases with any entry whose
 // road-map status is "implemented" or "done" removed. Releases not found in
 // road-map.yaml are kept (unknown status is not treated as implemented).
@@ -558,37 +849,47 @@ type proposedIssue struct {
 	Title       string `yaml:"title"`
 	Description string `yaml:"description"`
 	Dependency  int    `yaml:"dependency"`
+	Priority    int    `yaml:"priority,omitempty"` // set by assignDepthPriorities (GH-546)
 }
 
 // importIssues imports proposed issues from a YAML file into GitHub. It returns
-// the created issue IDs, any validation error strings (for retry feedback), and
-// a non-nil error when validation fails in enforcing mode. ph is the measuring
-// placeholder issue number; when ph > 0 and exactly one issue is proposed, the
-// placeholder is upgraded in-place instead of creating a new issue (GH-578).
-func (o *Orchestrator) importIssues(yamlFile, repo, generation string, ph int) ([]string, []string, error) {
+// the created issue IDs, the number of issues skipped as duplicates of
+// existing titles (GH-543), any validation error strings (for retry
+// feedback), and a non-nil error when validation fails in enforcing mode. ph
+// is the measuring placeholder issue number; when ph > 0 and exactly one
+// issue is proposed, the placeholder is upgraded in-place instead of creating
+// a new issue (GH-578).
+func (o *Orchestrator) importIssues(yamlFile, repo, generation string, ph int) ([]string, int, []string, error) {
 	return o.importIssuesImpl(yamlFile, repo, generation, false, ph)
 }
 
 // importIssuesForce imports issues bypassing enforcing validation. Used when
 // retries are exhausted to accept the last result with warnings (R5). ph is
 // the placeholder number passed through to importIssuesImpl (GH-578).
-func (o *Orchestrator) importIssuesForce(yamlFile, repo, generation string, ph int) ([]string, error) {
-	ids, _, err := o.importIssuesImpl(yamlFile, repo, generation, true, ph)
-	return ids, err
+func (o *Orchestrator) importIssuesForce(yamlFile, repo, generation string, ph int) ([]string, int, error) {
+	ids, skipped, _, err := o.importIssuesImpl(yamlFile, repo, generation, true, ph)
+	return ids, skipped, err
 }
 
-func (o *Orchestrator) importIssuesImpl(yamlFile, repo, generation string, skipEnforcement bool, ph int) ([]string, []string, error) {
+func (o *Orchestrator) importIssuesImpl(yamlFile, repo, generation string, skipEnforcement bool, ph int) ([]string, int, []string, error) {
 	logf("importIssues: reading %s", yamlFile)
 	data, err := os.ReadFile(yamlFile)
 	if err != nil {
-		return nil, nil, fmt.Errorf("reading YAML file: %w", err)
+		return nil, 0, nil, fmt.Errorf("reading YAML file: %w", err)
 	}
 	logf("importIssues: read %d bytes", len(data))
 
+	if o.cfg.Cobbler.MeasureStreamingImport && !o.cfg.Cobbler.MeasureOutputOnly {
+		if o.cfg.Cobbler.MinTaskLines > 0 {
+			logf("importIssues: min_task_lines ignored with streaming import, issues are created one at a time")
+		}
+		return o.streamImportIssues(data, repo, generation, skipEnforcement, ph)
+	}
+
 	var issues []proposedIssue
 	if err := yaml.Unmarshal(data, &issues); err != nil {
 		logf("importIssues: YAML parse error: %v", err)
-		return nil, nil, fmt.Errorf("parsing YAML: %w", err)
+		return nil, 0, nil, fmt.Errorf("parsing YAML: %w", err)
 	}
 
 	logf("importIssues: parsed %d proposed issue(s)", len(issues))
@@ -599,15 +900,65 @@ func (o *Orchestrator) importIssuesImpl(yamlFile, repo, generation string, skipE
 	// Validate proposed issues against P9/P7 rules. Load PRD sub-item
 	// counts so the validator can expand group references (GH-122).
 	subItemCounts := loadPRDSubItemCounts()
-	vr := validateMeasureOutput(issues, o.cfg.Cobbler.MaxRequirementsPerTask, subItemCounts)
+	vr := validateMeasureOutput(issues, o.cfg.Cobbler.MaxRequirementsPerTask, subItemCounts, o.cfg.Cobbler.GranularityRules)
+	vr.Errors = append(vr.Errors, validateDescriptionSize(issues, o.cfg.Cobbler.MaxDescriptionBytes, o.cfg.Cobbler.MaxDescriptionLines)...)
+	if o.cfg.Cobbler.MeasureMode == MeasureModeSpecMaintenance {
+		vr.Errors = append(vr.Errors, validateSpecMaintenanceOutput(issues)...)
+	}
+	// Check descriptions against the issue-format constitution (GH-556).
+	schemaMsgs := validateIssueSchemas(issues)
+	schemaRejects := o.cfg.Cobbler.EnforceIssueSchema && len(schemaMsgs) > 0
+	if o.cfg.Cobbler.EnforceIssueSchema {
+		vr.Errors = append(vr.Errors, schemaMsgs...)
+	} else {
+		vr.Warnings = append(vr.Warnings, schemaMsgs...)
+	}
 	if len(vr.Warnings) > 0 {
 		logf("importIssues: %d warning(s)", len(vr.Warnings))
 	}
-	if vr.HasErrors() && o.cfg.Cobbler.EnforceMeasureValidation && !skipEnforcement {
-		return nil, vr.Errors, fmt.Errorf("measure validation failed (%d error(s)): %s",
+	// A cycle would leave its tasks blocked forever (GH-544).
+	issues, cycleErrs := breakDependencyCycles(issues)
+	vr.Errors = append(vr.Errors, cycleErrs...)
+	if vr.HasErrors() && (o.cfg.Cobbler.EnforceMeasureValidation || schemaRejects) && !skipEnforcement {
+		return nil, 0, vr.Errors, fmt.Errorf("measure validation failed (%d error(s)): %s",
 			len(vr.Errors), strings.Join(vr.Errors, "; "))
 	}
 
+	// Fold tasks below MinTaskLines into their small neighbours (GH-488),
+	// skipping merges that would break the limits validated above.
+	batch := issueIndexSet(issues)
+	fits := func(iss proposedIssue) bool {
+		one := []proposedIssue{iss}
+		return !validateMeasureBatch(one, o.cfg.Cobbler.MaxRequirementsPerTask, subItemCounts, o.cfg.Cobbler.GranularityRules, batch).HasErrors() &&
+			len(validateDescriptionSize(one, o.cfg.Cobbler.MaxDescriptionBytes, o.cfg.Cobbler.MaxDescriptionLines)) == 0
+	}
+	if merged, merges := mergeTinyTasks(issues, o.cfg.Cobbler.MinTaskLines, fits); len(merges) > 0 {
+		for _, m := range merges {
+			logf("importIssues: %s", m)
+		}
+		logf("importIssues: consolidated %d proposed issue(s) into %d", len(issues), len(merged))
+		issues = merged
+	}
+
+	// Skip near-duplicates of open issues and of each other (GH-543).
+	skipped := 0
+	if threshold := o.cfg.Cobbler.DuplicateTitleThreshold; threshold > 0 {
+		issues, skipped = dropDuplicateTitles(issues, o.existingTitles(repo, generation), threshold)
+	}
+
+	// Foundational tasks first: priority follows dependency depth (GH-546).
+	if o.cfg.Cobbler.PriorityByDepth {
+		assignDepthPriorities(issues)
+	}
+
+	// Output-only mode records the proposals and stops (GH-545).
+	if o.cfg.Cobbler.MeasureOutputOnly {
+		ids := appendProposedIssues(o.measureOutput, issues, true)
+		logf("importIssues: %d issue(s) written to %s, %d skipped as duplicate(s)",
+			len(ids), o.measureOutput, skipped)
+		return ids, skipped, nil, nil
+	}
+
 	// Create all issues on GitHub. When a placeholder number is given and exactly
 	// one issue is proposed, upgrade the placeholder in-place instead of creating
 	// a new issue, eliminating the two-issue dance (GH-578).
@@ -639,22 +990,218 @@ func (o *Orchestrator) importIssuesImpl(yamlFile, repo, generation string, skipE
 			logf("importIssues: promoteReadyIssues warning: %v", err)
 		}
 	}
-	logf("importIssues: %d of %d issue(s) imported", len(ids), len(issues))
+	logf("importIssues: %d of %d issue(s) imported, %d skipped as duplicate(s)", len(ids), len(issues), skipped)
 
 	// Append new issues to the persistent measure list.
 	appendMeasureLog(o.cfg.Cobbler.Dir, issues)
 
-	return ids, nil, nil
+	return ids, skipped, nil, nil
+}
+
+// streamImportIssues is the streaming counterpart of importIssuesImpl
+// (GH-463). The top-level YAML list is split into items and each item is
+// parsed, validated, and created on GitHub before the next one is read,
+// so a malformed item does not discard the issues already created.
+// Forward dependency references need no special handling because
+// cobbler_depends_on stores indices that promoteReadyIssues resolves after
+// the whole batch is imported; a deferred link pass clears dependencies on
+// items that failed to parse or were rejected. Returns an error only when
+// nothing was imported, so a partially successful batch is not retried and
+// duplicated.
+func (o *Orchestrator) streamImportIssues(data []byte, repo, generation string, skipEnforcement bool, ph int) ([]string, int, []string, error) {
+	items := splitYAMLSequenceItems(data)
+	logf("streamImportIssues: %d list item(s)", len(items))
+
+	subItemCounts := loadPRDSubItemCounts()
+	batch := make(map[int]bool, len(items))
+	for _, item := range items {
+		if idx, ok := scanItemIndex(item); ok {
+			batch[idx] = true
+		}
+	}
+	type createdIssue struct {
+		number int
+		issue  proposedIssue
+	}
+	var (
+		created       []createdIssue
+		ids           []string
+		allErrs       []string
+		failedIndices = map[int]bool{}
+	)
+	// Duplicate titles are skipped like rejected items so dependents lose
+	// the link (GH-543).
+	threshold := o.cfg.Cobbler.DuplicateTitleThreshold
+	var titles []string
+	if threshold > 0 {
+		titles = existingIssueTitles(repo, generation)
+	}
+	skipped := 0
+	for i, item := range items {
+		var parsed []proposedIssue
+		if err := yaml.Unmarshal([]byte(item), &parsed); err != nil || len(parsed) != 1 {
+			msg := fmt.Sprintf("item %d: could not parse: %v", i, err)
+			logf("streamImportIssues: %s", msg)
+			allErrs = append(allErrs, msg)
+			if idx, ok := scanItemIndex(item); ok {
+				failedIndices[idx] = true
+			}
+			continue
+		}
+		issue := parsed[0]
+
+		vr := validateMeasureBatch([]proposedIssue{issue}, o.cfg.Cobbler.MaxRequirementsPerTask, subItemCounts, o.cfg.Cobbler.GranularityRules, batch)
+		vr.Errors = append(vr.Errors, validateDescriptionSize([]proposedIssue{issue}, o.cfg.Cobbler.MaxDescriptionBytes, o.cfg.Cobbler.MaxDescriptionLines)...)
+		if o.cfg.Cobbler.MeasureMode == MeasureModeSpecMaintenance {
+			vr.Errors = append(vr.Errors, validateSpecMaintenanceOutput([]proposedIssue{issue})...)
+		}
+		schemaMsgs := validateIssueSchemas([]proposedIssue{issue})
+		schemaRejects := o.cfg.Cobbler.EnforceIssueSchema && len(schemaMsgs) > 0
+		if o.cfg.Cobbler.EnforceIssueSchema {
+			vr.Errors = append(vr.Errors, schemaMsgs...)
+		}
+		if vr.HasErrors() && (o.cfg.Cobbler.EnforceMeasureValidation || schemaRejects) && !skipEnforcement {
+			logf("streamImportIssues: rejecting [%d] %q: %s", issue.Index, issue.Title, strings.Join(vr.Errors, "; "))
+			allErrs = append(allErrs, vr.Errors...)
+			failedIndices[issue.Index] = true
+			continue
+		}
+
+		if threshold > 0 {
+			if match, dup := similarTitle(issue.Title, titles, threshold); dup {
+				logf("streamImportIssues: skipping [%d] %q as a duplicate of %q", issue.Index, issue.Title, match)
+				skipped++
+				failedIndices[issue.Index] = true
+				continue
+			}
+		}
+
+		// The first issue upgrades the placeholder in-place (GH-578).
+		var number int
+		if ph > 0 && len(created) == 0 {
+			if err := upgradeMeasuringPlaceholder(repo, ph, generation, issue); err != nil {
+				logf("streamImportIssues: upgradeMeasuringPlaceholder #%d failed, falling back to createCobblerIssue: %v", ph, err)
+			} else {
+				number = ph
+			}
+		}
+		if number == 0 {
+			n, err := createCobblerIssue(repo, generation, issue)
+			if err != nil {
+				logf("streamImportIssues: createCobblerIssue failed for %q: %v", issue.Title, err)
+				allErrs = append(allErrs, fmt.Sprintf("[%d] %q: create failed: %v", issue.Index, issue.Title, err))
+				failedIndices[issue.Index] = true
+				continue
+			}
+			number = n
+		}
+		created = append(created, createdIssue{number: number, issue: issue})
+		titles = append(titles, issue.Title)
+		ids = append(ids, fmt.Sprintf("%d", number))
+		appendMeasureLog(o.cfg.Cobbler.Dir, []proposedIssue{issue})
+	}
+
+	// Deferred link pass: an issue that depends on an item that never made
+	// it to GitHub must not keep a dangling cobbler_depends_on, and
+	// dependency cycles among the created issues are broken (GH-544).
+	createdIssues := make([]proposedIssue, len(created))
+	for i, c := range created {
+		createdIssues[i] = c.issue
+	}
+	acyclic, cycleErrs := breakDependencyCycles(createdIssues)
+	allErrs = append(allErrs, cycleErrs...)
+	for i := range acyclic {
+		if dep := acyclic[i].Dependency; dep >= 0 && failedIndices[dep] {
+			logf("streamImportIssues: clearing dependency of #%d on failed index %d", created[i].number, dep)
+			acyclic[i].Dependency = -1
+		}
+	}
+	if o.cfg.Cobbler.PriorityByDepth {
+		assignDepthPriorities(acyclic)
+	}
+	for i, c := range created {
+		if acyclic[i].Dependency == c.issue.Dependency && acyclic[i].Priority == c.issue.Priority {
+			continue
+		}
+		c.issue.Dependency = acyclic[i].Dependency
+		c.issue.Priority = acyclic[i].Priority
+		if err := editCobblerIssueBody(repo, c.number, generation, c.issue); err != nil {
+			logf("streamImportIssues: editCobblerIssueBody warning for #%d: %v", c.number, err)
+		}
+	}
+
+	if len(ids) > 0 {
+		waitForIssuesVisible(repo, generation, len(ids))
+		if err := promoteReadyIssues(repo, generation); err != nil {
+			logf("streamImportIssues: promoteReadyIssues warning: %v", err)
+		}
+	}
+	logf("streamImportIssues: %d of %d item(s) imported, %d skipped as duplicate(s)", len(ids), len(items), skipped)
+
+	if len(ids) == 0 && len(allErrs) > 0 {
+		return nil, skipped, allErrs, fmt.Errorf("streaming import created no issues (%d error(s)): %s",
+			len(allErrs), strings.Join(allErrs, "; "))
+	}
+	return ids, skipped, allErrs, nil
+}
+
+// splitYAMLSequenceItems splits a top-level YAML block sequence into one
+// single-item sequence per entry, so each entry can be parsed on its own.
+// An entry starts at a line beginning with "- " (or a bare "-") in column
+// zero; leading comments and a document marker are dropped. When no such
+// line exists the whole input is returned as one item.
+func splitYAMLSequenceItems(data []byte) []string {
+	var items []string
+	var cur []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "- ") || line == "-" {
+			if len(cur) > 0 {
+				items = append(items, strings.Join(cur, "\n"))
+			}
+			cur = []string{line}
+			continue
+		}
+		if cur == nil {
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" || trimmed == "---" || strings.HasPrefix(trimmed, "#") {
+				continue
+			}
+		}
+		cur = append(cur, line)
+	}
+	if len(cur) > 0 {
+		items = append(items, strings.Join(cur, "\n"))
+	}
+	return items
+}
+
+// itemIndexPattern matches the index field of a proposed issue list item.
+var itemIndexPattern = regexp.MustCompile(`(?m)^(?:- |  )index:\s*(-?\d+)\s*$`)
+
+// scanItemIndex extracts the index field from an unparseable list item so
+// the deferred link pass can clear dependencies on it.
+func scanItemIndex(item string) (int, bool) {
+	m := itemIndexPattern.FindStringSubmatch(item)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false
+	}
+	return n, true
 }
 
 // issueDescription is the subset of fields parsed from an issue description
 // YAML for advisory validation.
 type issueDescription struct {
-	DeliverableType    string              `yaml:"deliverable_type"`
-	Files              []issueDescFile     `yaml:"files"`
-	Requirements       []issueDescItem     `yaml:"requirements"`
-	AcceptanceCriteria []issueDescItem     `yaml:"acceptance_criteria"`
-	DesignDecisions    []issueDescItem     `yaml:"design_decisions"`
+	DeliverableType    string          `yaml:"deliverable_type"`
+	Files              []issueDescFile `yaml:"files"`
+	Requirements       []issueDescItem `yaml:"requirements"`
+	AcceptanceCriteria []issueDescItem `yaml:"acceptance_criteria"`
+	DesignDecisions    []issueDescItem `yaml:"design_decisions"`
+	TestCases          []issueTestCase `yaml:"test_cases"`
+	EstimatedLines     int             `yaml:"estimated_lines"`
 }
 
 type issueDescFile struct {
@@ -666,6 +1213,13 @@ type issueDescItem struct {
 	Text string `yaml:"text"`
 }
 
+// issueTestCase is an acceptance-test stub proposed by measure (GH-484):
+// the test function name the stitch agent should write and what it checks.
+type issueTestCase struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+}
+
 // validationResult holds the outcome of measure output validation.
 type validationResult struct {
 	Warnings []string // advisory issues (logged but do not block import)
@@ -679,13 +1233,28 @@ func (v validationResult) HasErrors() bool {
 
 // validateMeasureOutput checks proposed issues against P9 granularity ranges
 // and P7 file naming conventions. Returns structured warnings and errors.
-// All issues are logged regardless of enforcing mode. maxReqs is the
-// operator-configured requirement cap (0 = unlimited). subItemCounts maps
-// PRD stems to group IDs to sub-item counts; when a task requirement
-// references a PRD group, the expanded sub-item count is used instead of 1.
-// Expanded-count violations are logged as warnings (best-effort), not errors.
-func validateMeasureOutput(issues []proposedIssue, maxReqs int, subItemCounts map[string]map[string]int) validationResult {
+// All issues are logged regardless of enforcing mode.
+//
+// maxReqs is the operator-configured requirement cap (0 = unlimited).
+// subItemCounts maps PRD stems to group IDs to sub-item counts; when a task
+// requirement references a PRD group, the expanded sub-item count is used
+// instead of 1. Expanded-count violations are logged as warnings
+// (best-effort), not errors. rules holds the P9 ranges per deliverable
+// type; a zero range is not checked (GH-542). Self-dependencies and
+// dependencies on indices outside the batch are errors (GH-464).
+func validateMeasureOutput(issues []proposedIssue, maxReqs int, subItemCounts map[string]map[string]int, rules GranularityRules) validationResult {
+	return validateMeasureBatch(issues, maxReqs, subItemCounts, rules, issueIndexSet(issues))
+}
+
+// validateMeasureBatch is validateMeasureOutput with an explicit set of
+// indices that make up the batch. The streaming importer validates one
+// issue at a time but resolves dependencies against the whole batch.
+func validateMeasureBatch(issues []proposedIssue, maxReqs int, subItemCounts map[string]map[string]int, rules GranularityRules, batch map[int]bool) validationResult {
 	var result validationResult
+	for _, msg := range validateDependencyRefs(issues, batch) {
+		logf("validateMeasureOutput: %s", msg)
+		result.Errors = append(result.Errors, msg)
+	}
 	for _, issue := range issues {
 		var desc issueDescription
 		if err := yaml.Unmarshal([]byte(issue.Description), &desc); err != nil {
@@ -712,33 +1281,38 @@ func validateMeasureOutput(issues []proposedIssue, maxReqs int, subItemCounts ma
 			result.Errors = append(result.Errors, msg)
 		}
 
-		if desc.DeliverableType == "code" {
-			if rCount < 5 || rCount > 8 {
-				msg := fmt.Sprintf("[%d] %q: requirement count %d outside P9 range 5-8", issue.Index, issue.Title, rCount)
-				logf("validateMeasureOutput: %s", msg)
-				result.Errors = append(result.Errors, msg)
-			}
-			if acCount < 5 || acCount > 8 {
-				msg := fmt.Sprintf("[%d] %q: acceptance criteria count %d outside P9 range 5-8", issue.Index, issue.Title, acCount)
-				logf("validateMeasureOutput: %s", msg)
-				result.Errors = append(result.Errors, msg)
-			}
-			if dCount < 3 || dCount > 5 {
-				msg := fmt.Sprintf("[%d] %q: design decision count %d outside P9 range 3-5", issue.Index, issue.Title, dCount)
-				logf("validateMeasureOutput: %s", msg)
-				result.Errors = append(result.Errors, msg)
-			}
-		} else if desc.DeliverableType == "documentation" {
-			if rCount < 2 || rCount > 4 {
-				msg := fmt.Sprintf("[%d] %q: requirement count %d outside P9 doc range 2-4", issue.Index, issue.Title, rCount)
-				logf("validateMeasureOutput: %s", msg)
-				result.Errors = append(result.Errors, msg)
-			}
-			if acCount < 3 || acCount > 5 {
-				msg := fmt.Sprintf("[%d] %q: acceptance criteria count %d outside P9 doc range 3-5", issue.Index, issue.Title, acCount)
-				logf("validateMeasureOutput: %s", msg)
-				result.Errors = append(result.Errors, msg)
+		var ranges DeliverableGranularity
+		rangeLabel := "P9 range"
+		switch desc.DeliverableType {
+		case "code":
+			ranges = rules.Code
+		case "documentation":
+			ranges = rules.Documentation
+			rangeLabel = "P9 doc range"
+		}
+		for _, c := range []struct {
+			what  string
+			count int
+			r     CountRange
+		}{
+			{"requirement count", rCount, ranges.Requirements},
+			{"acceptance criteria count", acCount, ranges.AcceptanceCriteria},
+			{"design decision count", dCount, ranges.DesignDecisions},
+		} {
+			if c.r.isZero() || c.r.contains(c.count) {
+				continue
 			}
+			msg := fmt.Sprintf("[%d] %q: %s %d outside %s %s", issue.Index, issue.Title, c.what, c.count, rangeLabel, c.r)
+			logf("validateMeasureOutput: %s", msg)
+			result.Errors = append(result.Errors, msg)
+		}
+
+		// test_cases is optional and checked loosely: malformed entries
+		// are advisory and never block import (GH-484).
+		for _, msg := range validateTestCases(desc.TestCases) {
+			msg = fmt.Sprintf("[%d] %q: %s", issue.Index, issue.Title, msg)
+			logf("validateMeasureOutput: %s", msg)
+			result.Warnings = append(result.Warnings, msg)
 		}
 
 		// Check for P7 violation: file named after its package.
@@ -758,6 +1332,205 @@ func validateMeasureOutput(issues []proposedIssue, maxReqs int, subItemCounts ma
 	return result
 }
 
+// specDocsDir is the directory spec-maintenance proposals may modify.
+const specDocsDir = "docs/"
+
+// validateSpecMaintenanceOutput returns errors for proposals that break
+// the spec-maintenance contract (GH-551): each must be a documentation
+// deliverable whose files all lie under docs/. The usual P9 documentation
+// ranges are checked by validateMeasureOutput.
+func validateSpecMaintenanceOutput(issues []proposedIssue) []string {
+	var errs []string
+	add := func(issue proposedIssue, format string, args ...any) {
+		msg := fmt.Sprintf("[%d] %q: ", issue.Index, issue.Title) + fmt.Sprintf(format, args...)
+		logf("validateSpecMaintenanceOutput: %s", msg)
+		errs = append(errs, msg)
+	}
+	for _, issue := range issues {
+		var desc issueDescription
+		if err := yaml.Unmarshal([]byte(issue.Description), &desc); err != nil {
+			add(issue, "could not parse description: %v", err)
+			continue
+		}
+		if desc.DeliverableType != "documentation" {
+			add(issue, "deliverable_type %q, spec maintenance allows only documentation", desc.DeliverableType)
+		}
+		if len(desc.Files) == 0 {
+			add(issue, "no files listed, spec maintenance must name the %s documents it updates", specDocsDir)
+		}
+		for _, f := range desc.Files {
+			if p := path.Clean(strings.TrimPrefix(f.Path, "./")); !strings.HasPrefix(p, specDocsDir) {
+				add(issue, "file %s is outside %s", f.Path, specDocsDir)
+			}
+		}
+	}
+	return errs
+}
+
+// validateTestCases returns advisory messages for test_cases entries
+// with no name, names that are not Go test function names, or duplicate
+// names.
+func validateTestCases(cases []issueTestCase) []string {
+	var msgs []string
+	seen := make(map[string]bool, len(cases))
+	for i, tc := range cases {
+		name := strings.TrimSpace(tc.Name)
+		switch {
+		case name == "":
+			msgs = append(msgs, fmt.Sprintf("test_cases[%d] has no name", i))
+			continue
+		case !strings.HasPrefix(name, "Test"):
+			msgs = append(msgs, fmt.Sprintf("test_cases[%d] name %q should start with Test", i, name))
+		}
+		if seen[name] {
+			msgs = append(msgs, fmt.Sprintf("test_cases[%d] duplicates name %q", i, name))
+		}
+		seen[name] = true
+	}
+	return msgs
+}
+
+// issueIndexSet returns the set of indices used by issues.
+func issueIndexSet(issues []proposedIssue) map[int]bool {
+	set := make(map[int]bool, len(issues))
+	for _, issue := range issues {
+		set[issue.Index] = true
+	}
+	return set
+}
+
+// validateDescriptionSize flags proposed issues whose description exceeds
+// maxBytes bytes or maxLines lines (GH-477). A limit of 0 is disabled.
+func validateDescriptionSize(issues []proposedIssue, maxBytes, maxLines int) []string {
+	var errs []string
+	for _, issue := range issues {
+		size := len(issue.Description)
+		lines := strings.Count(strings.TrimRight(issue.Description, "\n"), "\n") + 1
+		if issue.Description == "" {
+			lines = 0
+		}
+		if maxBytes > 0 && size > maxBytes {
+			msg := fmt.Sprintf("[%d] %q: description is %d bytes, max is %d; split the task or tighten the description", issue.Index, issue.Title, size, maxBytes)
+			logf("validateMeasureOutput: %s", msg)
+			errs = append(errs, msg)
+		}
+		if maxLines > 0 && lines > maxLines {
+			msg := fmt.Sprintf("[%d] %q: description is %d lines, max is %d; split the task or tighten the description", issue.Index, issue.Title, lines, maxLines)
+			logf("validateMeasureOutput: %s", msg)
+			errs = append(errs, msg)
+		}
+	}
+	return errs
+}
+
+// validateDependencyRefs flags proposed issues that depend on themselves
+// or on an index not present in batch. Both indicate that Claude confused
+// its own indexing; importing them would yield a broken or no-op
+// dependency graph. A negative dependency means "none" and is always valid.
+func validateDependencyRefs(issues []proposedIssue, batch map[int]bool) []string {
+	var errs []string
+	for _, issue := range issues {
+		switch {
+		case issue.Dependency < 0:
+			continue
+		case issue.Dependency == issue.Index:
+			errs = append(errs, fmt.Sprintf("[%d] %q: depends on itself (dependency %d == index %d)",
+				issue.Index, issue.Title, issue.Dependency, issue.Index))
+		case !batch[issue.Dependency]:
+			errs = append(errs, fmt.Sprintf("[%d] %q: dependency %d does not match any index in the batch",
+				issue.Index, issue.Title, issue.Dependency))
+		}
+	}
+	return errs
+}
+
+// breakDependencyCycles finds cycles in the index -> dependency graph of
+// issues and drops the edge that closes each one, so no task waits on
+// itself forever (GH-544). It returns a copy of issues with those
+// dependencies set to -1 and one validation error per cycle of two or
+// more issues; self-dependencies are already reported by
+// validateDependencyRefs and are only dropped here.
+func breakDependencyCycles(issues []proposedIssue) ([]proposedIssue, []string) {
+	out := append([]proposedIssue(nil), issues...)
+	pos := make(map[int]int, len(out))
+	for i, issue := range out {
+		pos[issue.Index] = i
+	}
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+	state := make(map[int]int, len(out))
+	var errs []string
+	for _, start := range out {
+		var path []int
+		idx := start.Index
+		for state[idx] == unvisited {
+			state[idx] = onPath
+			path = append(path, idx)
+			next := out[pos[idx]].Dependency
+			if _, ok := pos[next]; next < 0 || !ok {
+				break
+			}
+			if state[next] == onPath {
+				closing := &out[pos[idx]]
+				cycle := path[slices.Index(path, next):]
+				logf("breakDependencyCycles: dropping dependency of [%d] on %d (cycle %s)", idx, next, formatCycle(cycle))
+				if len(cycle) > 1 {
+					errs = append(errs, fmt.Sprintf("[%d] %q: dependency %d forms a cycle (%s)",
+						idx, closing.Title, next, formatCycle(cycle)))
+				}
+				closing.Dependency = -1
+				break
+			}
+			idx = next
+		}
+		for _, p := range path {
+			state[p] = done
+		}
+	}
+	return out, errs
+}
+
+// assignDepthPriorities sets each issue's Priority to one plus its depth
+// in the index -> dependency graph of the batch: issues with no dependency
+// in the batch get 1 (highest), their dependents 2, and so on.
+func assignDepthPriorities(issues []proposedIssue) {
+	pos := make(map[int]int, len(issues))
+	for i, issue := range issues {
+		pos[issue.Index] = i
+	}
+	depth := make(map[int]int, len(issues))
+	var depthOf func(idx int, seen map[int]bool) int
+	depthOf = func(idx int, seen map[int]bool) int {
+		if d, ok := depth[idx]; ok {
+			return d
+		}
+		d := 0
+		if dep := issues[pos[idx]].Dependency; dep >= 0 && !seen[dep] {
+			if _, ok := pos[dep]; ok {
+				seen[idx] = true
+				d = depthOf(dep, seen) + 1
+			}
+		}
+		depth[idx] = d
+		return d
+	}
+	for i := range issues {
+		issues[i].Priority = depthOf(issues[i].Index, map[int]bool{}) + 1
+	}
+}
+
+// formatCycle renders cycle indices as "a -> b -> a".
+func formatCycle(cycle []int) string {
+	parts := make([]string, 0, len(cycle)+1)
+	for _, idx := range append(cycle, cycle[0]) {
+		parts = append(parts, strconv.Itoa(idx))
+	}
+	return strings.Join(parts, " -> ")
+}
+
 // prdRefPattern matches PRD requirement references in task requirement text.
 // Examples: "prd003 R2", "prd004-ts R1.3", "prd001-orchestrator-core R5".
 // Group 1 = PRD stem (e.g., "prd003" or "prd004-ts").
@@ -885,26 +1658,153 @@ func (o *Orchestrator) saveHistory(ts string, rawOutput []byte, issuesFile strin
 
 // appendMeasureLog merges newIssues into the persistent measure.yaml list.
 // measure.yaml is a single growing YAML list of all issues proposed across runs.
-func appendMeasureLog(cobblerDir string, newIssues []proposedIssue) {
-	logPath := filepath.Join(cobblerDir, "measure.yaml")
+// It returns the 1-based list positions of newIssues, or nil when the list
+// could not be written.
+func appendMeasureLog(cobblerDir string, newIssues []proposedIssue) []string {
+	return appendProposedIssues(measureLogPath(cobblerDir), newIssues, false)
+}
 
-	var existing []proposedIssue
-	if data, err := os.ReadFile(logPath); err == nil {
-		if err := yaml.Unmarshal(data, &existing); err != nil {
-			logf("appendMeasureLog: could not parse existing list, starting fresh: %v", err)
-			existing = nil
-		}
+// appendProposedIssues appends newIssues to the YAML list at path and
+// returns their 1-based list positions, or nil when the list could not be
+// written. With renumber set, each new issue's index becomes its 0-based
+// list position and dependencies within the batch follow, so batches
+// proposed by separate measure iterations (each indexed from 0) do not
+// collide (GH-545); a dependency outside the batch is cleared.
+func appendProposedIssues(path string, newIssues []proposedIssue, renumber bool) []string {
+	existing := loadProposedIssues(path)
+	if renumber {
+		newIssues = renumberProposedIssues(newIssues, len(existing))
 	}
 
 	combined := append(existing, newIssues...)
 	out, err := yaml.Marshal(combined)
 	if err != nil {
 		logf("appendMeasureLog: marshal failed: %v", err)
-		return
+		return nil
 	}
-	if err := os.WriteFile(logPath, out, 0o644); err != nil {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		logf("appendMeasureLog: creating %s: %v", filepath.Dir(path), err)
+		return nil
+	}
+	if err := os.WriteFile(path, out, 0o644); err != nil {
 		logf("appendMeasureLog: write failed: %v", err)
-		return
+		return nil
+	}
+	logf("appendMeasureLog: %d total issues in %s", len(combined), path)
+	ids := make([]string, len(newIssues))
+	for i := range newIssues {
+		ids[i] = strconv.Itoa(len(existing) + i + 1)
+	}
+	return ids
+}
+
+// renumberProposedIssues returns a copy of issues indexed from offset in
+// list order, with dependencies on issues of the batch remapped and any
+// other dependency set to -1.
+func renumberProposedIssues(issues []proposedIssue, offset int) []proposedIssue {
+	index := make(map[int]int, len(issues))
+	for i, iss := range issues {
+		index[iss.Index] = offset + i
 	}
-	logf("appendMeasureLog: %d total issues in %s", len(combined), logPath)
+	out := make([]proposedIssue, len(issues))
+	for i, iss := range issues {
+		iss.Index = offset + i
+		if dep, ok := index[iss.Dependency]; ok {
+			iss.Dependency = dep
+		} else {
+			iss.Dependency = -1
+		}
+		out[i] = iss
+	}
+	return out
+}
+
+// measureLogPath returns the path of the persistent measure.yaml list.
+func measureLogPath(cobblerDir string) string {
+	return filepath.Join(cobblerDir, "measure.yaml")
+}
+
+// measureOutputDir is the directory, under Cobbler.Dir, holding one
+// proposals file per output-only measure run (GH-545).
+const measureOutputDir = "measure-output"
+
+// measureOutputPath returns the proposals file of the output-only measure
+// run that started at start.
+func measureOutputPath(cobblerDir string, start time.Time) string {
+	return filepath.Join(cobblerDir, measureOutputDir, start.Format("20060102-150405")+".yaml")
+}
+
+// loadProposedIssues returns the issues recorded in the YAML list at
+// path, or nil when it is missing or unparseable.
+func loadProposedIssues(path string) []proposedIssue {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var issues []proposedIssue
+	if err := yaml.Unmarshal(data, &issues); err != nil {
+		logf("loadProposedIssues: could not parse %s: %v", path, err)
+		return nil
+	}
+	return issues
+}
+
+// proposedIssuesContext renders the issues in the YAML list at path as the
+// existing issues JSON of the measure prompt. IDs are 1-based positions in
+// the list and every issue is reported as proposed.
+func proposedIssuesContext(path string, descLimit int) (string, error) {
+	issues := loadProposedIssues(path)
+	if len(issues) == 0 {
+		return "", nil
+	}
+	ctx := make([]ContextIssue, len(issues))
+	for i, iss := range issues {
+		ctx[i] = ContextIssue{ID: strconv.Itoa(i + 1), Title: iss.Title, Status: "proposed"}
+		if descLimit > 0 {
+			ctx[i].Description = truncateDescription(strings.TrimSpace(iss.Description), descLimit)
+		}
+	}
+	b, err := json.Marshal(ctx)
+	if err != nil {
+		return "", fmt.Errorf("proposedIssuesContext: %w", err)
+	}
+	return string(b), nil
+}
+
+// measureExistingIssues returns the existing issues JSON for the measure
+// prompt: the generation's open GitHub issues, or in output-only mode the
+// issues proposed so far by this run (GH-545).
+func (o *Orchestrator) measureExistingIssues(repo, generation string) (string, error) {
+	if o.cfg.Cobbler.MeasureOutputOnly {
+		return proposedIssuesContext(o.measureOutput, o.cfg.Cobbler.issueDescriptionLimit())
+	}
+	return listActiveIssuesContext(repo, generation, o.cfg.Cobbler.issueDescriptionLimit())
+}
+
+// existingTitles returns the titles that proposed issues are checked
+// against for duplicates: the generation's open GitHub issues, or in
+// output-only mode the issues proposed so far by this run.
+func (o *Orchestrator) existingTitles(repo, generation string) []string {
+	if !o.cfg.Cobbler.MeasureOutputOnly {
+		return existingIssueTitles(repo, generation)
+	}
+	var titles []string
+	for _, iss := range loadProposedIssues(o.measureOutput) {
+		titles = append(titles, iss.Title)
+	}
+	return titles
+}
+
+// measureIterationCostCapped reports whether a measure iteration that has
+// spent `spent` USD has reached the per-iteration ceiling
+// (Cobbler.MaxMeasureCostUSD). A ceiling of 0 or less means no cap.
+func measureIterationCostCapped(spent, ceiling float64) bool {
+	return ceiling > 0 && spent >= ceiling
+}
+
+// measureRetryBudgetLeft reports whether a measure run that has used
+// `used` retries may retry again under the run-wide budget
+// (Cobbler.MaxMeasureRetriesTotal). A budget of 0 means unlimited.
+func measureRetryBudgetLeft(used, budget int) bool {
+	return budget <= 0 || used < budget
 }
@@ -0,0 +1,105 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// measuringPlaceholderFile is the per-generation file, under
+// generationMetaDir, holding the number of the measuring placeholder issue
+// that is currently open. It is written when the placeholder is created and
+// removed once the placeholder is resolved, so a leftover file means a
+// previous measure run crashed mid-iteration.
+const measuringPlaceholderFile = "measuring-placeholder"
+
+// staleMeasuringPlaceholderComment is posted on a placeholder left open by
+// a crashed measure run before it is closed.
+const staleMeasuringPlaceholderComment = "Measure crashed before resolving this tracking issue. Closed automatically by the next measure run."
+
+// measuringPlaceholderPath returns the path of the placeholder record for
+// generation.
+func (o *Orchestrator) measuringPlaceholderPath(generation string) string {
+	return filepath.Join(o.cfg.Cobbler.Dir, generationMetaDir, generation, measuringPlaceholderFile)
+}
+
+// readMeasuringPlaceholder returns the issue number recorded at path, or 0
+// when the file is missing or unparsable.
+func readMeasuringPlaceholder(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || n <= 0 {
+		logf("measuringPlaceholder: ignoring unparsable %s", path)
+		return 0
+	}
+	return n
+}
+
+// writeMeasuringPlaceholder records number at path. Failures are logged;
+// a missing record only means a crash would leave the issue for manual
+// cleanup.
+func writeMeasuringPlaceholder(path string, number int) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		logf("measuringPlaceholder: creating %s: %v", filepath.Dir(path), err)
+		return
+	}
+	if err := os.WriteFile(path, []byte(strconv.Itoa(number)+"\n"), 0o644); err != nil {
+		logf("measuringPlaceholder: writing %s: %v", path, err)
+	}
+}
+
+// clearMeasuringPlaceholder removes the placeholder record at path.
+func clearMeasuringPlaceholder(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		logf("measuringPlaceholder: clearing %s: %v", path, err)
+	}
+}
+
+// isMeasuringPlaceholder reports whether iss still looks like an open
+// measuring placeholder of generation: a placeholder title and no cobbler
+// labels. An upgraded placeholder (GH-578) carries the generation label
+// and a task title, so it no longer qualifies.
+func isMeasuringPlaceholder(iss cobblerIssue, generation string) bool {
+	if !strings.EqualFold(iss.State, "open") || !strings.HasPrefix(iss.Title, measuringTitlePrefix+generation+" task ") {
+		return false
+	}
+	for _, l := range iss.Labels {
+		if strings.HasPrefix(l, "cobbler-") {
+			return false
+		}
+	}
+	return true
+}
+
+// closeStaleMeasuringPlaceholder closes the placeholder recorded by a
+// previous measure run that never resolved it, commenting that the run
+// crashed, and clears the record. The issue is closed only while it is
+// still a placeholder; one that was upgraded into a task, closed, or
+// cannot be fetched is left alone (GH-566). Best-effort: gh failures are
+// logged.
+func (o *Orchestrator) closeStaleMeasuringPlaceholder(repo, generation string) {
+	path := o.measuringPlaceholderPath(generation)
+	defer clearMeasuringPlaceholder(path)
+	number := readMeasuringPlaceholder(path)
+	if number == 0 {
+		return
+	}
+	iss, err := fetchCobblerIssue(repo, number)
+	if err != nil {
+		logf("measure: not closing recorded placeholder #%d: %v", number, err)
+		return
+	}
+	if !isMeasuringPlaceholder(iss, generation) {
+		logf("measure: recorded placeholder #%d is no longer an open placeholder (%q), leaving it", number, iss.Title)
+		return
+	}
+	logf("measure: closing placeholder #%d left open by a crashed run", number)
+	closeMeasuringPlaceholderWithComment(repo, number, staleMeasuringPlaceholderComment)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMeasuringPlaceholder_RoundTripAndClear(t *testing.T) {
+	t.Parallel()
+	o := New(Config{Cobbler: CobblerConfig{Dir: t.TempDir()}})
+	path := o.measuringPlaceholderPath("generation-a")
+
+	if got := readMeasuringPlaceholder(path); got != 0 {
+		t.Fatalf("missing record = %d, want 0", got)
+	}
+	writeMeasuringPlaceholder(path, 42)
+	if got := readMeasuringPlaceholder(path); got != 42 {
+		t.Errorf("readMeasuringPlaceholder = %d, want 42", got)
+	}
+	clearMeasuringPlaceholder(path)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("record still present after clear: %v", err)
+	}
+	clearMeasuringPlaceholder(path) // clearing twice is a no-op
+}
+
+func TestReadMeasuringPlaceholder_Unparsable(t *testing.T) {
+	t.Parallel()
+	o := New(Config{Cobbler: CobblerConfig{Dir: t.TempDir()}})
+	path := o.measuringPlaceholderPath("generation-a")
+	writeMeasuringPlaceholder(path, 1)
+	if err := os.WriteFile(path, []byte("not-a-number\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := readMeasuringPlaceholder(path); got != 0 {
+		t.Errorf("unparsable record = %d, want 0", got)
+	}
+}
+
+func TestIsMeasuringPlaceholder(t *testing.T) {
+	t.Parallel()
+	const gen = "generation-a"
+	cases := []struct {
+		name string
+		iss  cobblerIssue
+		want bool
+	}{
+		{"placeholder", cobblerIssue{State: "open", Title: "[measuring] generation-a task 2"}, true},
+		{"upgraded", cobblerIssue{State: "open", Title: "[measure] Add store", Labels: []string{cobblerGenLabel(gen)}}, false},
+		{"labelled", cobblerIssue{State: "open", Title: "[measuring] generation-a task 2", Labels: []string{cobblerLabelReady}}, false},
+		{"closed", cobblerIssue{State: "closed", Title: "[measuring] generation-a task 2"}, false},
+		{"other generation", cobblerIssue{State: "open", Title: "[measuring] generation-b task 2"}, false},
+		{"unrelated labels", cobblerIssue{State: "open", Title: "[measuring] generation-a task 1", Labels: []string{"bug"}}, true},
+	}
+	for _, tc := range cases {
+		if got := isMeasuringPlaceholder(tc.iss, gen); got != tc.want {
+			t.Errorf("%s: isMeasuringPlaceholder = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
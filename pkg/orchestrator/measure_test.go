@@ -4,11 +4,16 @@
 package orchestrator
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -49,14 +54,68 @@ design_decisions:
   - id: D3
     text: d3
 `,
+		Dependency: -1,
 	}}
 
-	vr := validateMeasureOutput(issues, 0, nil)
+	vr := validateMeasureOutput(issues, 0, nil, defaultGranularityRules())
 	if vr.HasErrors() {
 		t.Errorf("expected no errors for valid code task, got: %v", vr.Errors)
 	}
 }
 
+func TestValidateMeasureOutput_CustomGranularityRules(t *testing.T) {
+	t.Parallel()
+	// A code task with 3 requirements, 3 acceptance criteria, 1 design decision.
+	issues := []proposedIssue{{
+		Index: 0,
+		Title: "Small task",
+		Description: `deliverable_type: code
+requirements:
+  - {id: R1, text: r}
+  - {id: R2, text: r}
+  - {id: R3, text: r}
+acceptance_criteria:
+  - {id: AC1, text: a}
+  - {id: AC2, text: a}
+  - {id: AC3, text: a}
+design_decisions:
+  - {id: D1, text: d}
+`,
+		Dependency: -1,
+	}}
+
+	if vr := validateMeasureOutput(issues, 0, nil, defaultGranularityRules()); len(vr.Errors) != 3 {
+		t.Fatalf("default rules: expected 3 errors, got %v", vr.Errors)
+	}
+
+	rules := defaultGranularityRules()
+	rules.Code = DeliverableGranularity{
+		Requirements:       CountRange{Min: 3, Max: 3},
+		AcceptanceCriteria: CountRange{Min: 3},
+		DesignDecisions:    CountRange{Min: 1, Max: 2},
+	}
+	if vr := validateMeasureOutput(issues, 0, nil, rules); vr.HasErrors() {
+		t.Errorf("ranges at the boundaries: unexpected errors %v", vr.Errors)
+	}
+
+	rules.Code.Requirements = CountRange{Min: 4, Max: 8}
+	rules.Code.DesignDecisions = CountRange{Min: 2, Max: 2}
+	vr := validateMeasureOutput(issues, 0, nil, rules)
+	want := []string{
+		`[0] "Small task": requirement count 3 outside P9 range 4-8`,
+		`[0] "Small task": design decision count 1 outside P9 range 2-2`,
+	}
+	if !reflect.DeepEqual(vr.Errors, want) {
+		t.Errorf("ranges one past the boundaries: errors = %v, want %v", vr.Errors, want)
+	}
+
+	// MaxRequirementsPerTask remains a hard cap inside a wider range.
+	rules.Code.Requirements = CountRange{Min: 1, Max: 10}
+	if vr := validateMeasureOutput(issues, 2, nil, rules); len(vr.Errors) != 2 {
+		t.Errorf("max_requirements_per_task=2: expected cap error plus design decision error, got %v", vr.Errors)
+	}
+}
+
 func TestValidateMeasureOutput_CodeP9TooFewRequirements(t *testing.T) {
 	t.Parallel()
 	issues := []proposedIssue{{
@@ -87,9 +146,10 @@ design_decisions:
   - id: D3
     text: d3
 `,
+		Dependency: -1,
 	}}
 
-	vr := validateMeasureOutput(issues, 0, nil)
+	vr := validateMeasureOutput(issues, 0, nil, defaultGranularityRules())
 	if !vr.HasErrors() {
 		t.Error("expected errors for code task with 2 requirements (P9 range 5-8)")
 	}
@@ -144,7 +204,7 @@ design_decisions:
 `,
 	}}
 
-	vr := validateMeasureOutput(issues, 0, nil)
+	vr := validateMeasureOutput(issues, 0, nil, defaultGranularityRules())
 	if !vr.HasErrors() {
 		t.Error("expected errors for code task with 9 requirements (P9 range 5-8)")
 	}
@@ -171,9 +231,10 @@ acceptance_criteria:
   - id: AC3
     text: ac3
 `,
+		Dependency: -1,
 	}}
 
-	vr := validateMeasureOutput(issues, 0, nil)
+	vr := validateMeasureOutput(issues, 0, nil, defaultGranularityRules())
 	if vr.HasErrors() {
 		t.Errorf("expected no errors for valid doc task, got: %v", vr.Errors)
 	}
@@ -206,7 +267,7 @@ acceptance_criteria:
 `,
 	}}
 
-	vr := validateMeasureOutput(issues, 0, nil)
+	vr := validateMeasureOutput(issues, 0, nil, defaultGranularityRules())
 	if !vr.HasErrors() {
 		t.Error("expected errors for doc task with 5 requirements (P9 range 2-4)")
 	}
@@ -252,7 +313,7 @@ design_decisions:
 `,
 	}}
 
-	vr := validateMeasureOutput(issues, 0, nil)
+	vr := validateMeasureOutput(issues, 0, nil, defaultGranularityRules())
 	if !vr.HasErrors() {
 		t.Error("expected errors for file named after package (P7 violation)")
 	}
@@ -268,6 +329,28 @@ design_decisions:
 	}
 }
 
+func TestValidateMeasureOutput_P7IgnoresNonGoFiles(t *testing.T) {
+	t.Parallel()
+	issues := []proposedIssue{{
+		Index: 0,
+		Title: "proto task",
+		Description: `deliverable_type: code
+files:
+  - path: api/api.proto
+  - path: schema/schema.sql
+requirements:
+  - id: R1
+    text: req1
+`,
+	}}
+	vr := validateMeasureOutput(issues, 0, nil, defaultGranularityRules())
+	for _, e := range append(vr.Errors, vr.Warnings...) {
+		if contains(e, "P7 violation") {
+			t.Errorf("non-Go file flagged as P7 violation: %s", e)
+		}
+	}
+}
+
 func TestValidateMeasureOutput_P7NoViolation(t *testing.T) {
 	t.Parallel()
 	issues := []proposedIssue{{
@@ -310,7 +393,7 @@ design_decisions:
 
 	// runner.go in pkg/difftest/ is NOT a P7 violation because
 	// the file name does not match the parent directory name.
-	vr := validateMeasureOutput(issues, 0, nil)
+	vr := validateMeasureOutput(issues, 0, nil, defaultGranularityRules())
 	p7Errors := 0
 	for _, e := range vr.Errors {
 		if contains(e, "P7 violation") {
@@ -330,7 +413,7 @@ func TestValidateMeasureOutput_UnparseableDescription(t *testing.T) {
 		Description: `{{{not valid yaml`,
 	}}
 
-	vr := validateMeasureOutput(issues, 0, nil)
+	vr := validateMeasureOutput(issues, 0, nil, defaultGranularityRules())
 	if len(vr.Warnings) == 0 {
 		t.Error("expected warning for unparseable description")
 	}
@@ -388,7 +471,7 @@ acceptance_criteria:
 		},
 	}
 
-	vr := validateMeasureOutput(issues, 0, nil)
+	vr := validateMeasureOutput(issues, 0, nil, defaultGranularityRules())
 	if !vr.HasErrors() {
 		t.Error("expected errors from invalid second issue")
 	}
@@ -428,7 +511,7 @@ func TestValidateMeasureOutput_MaxReqs_ZeroIsUnlimited(t *testing.T) {
 		Title:       "Huge task",
 		Description: "deliverable_type: code\nrequirements:\n" + reqs,
 	}}
-	vr := validateMeasureOutput(issues, 0, nil)
+	vr := validateMeasureOutput(issues, 0, nil, defaultGranularityRules())
 	for _, e := range vr.Errors {
 		if contains(e, "max is") {
 			t.Errorf("maxReqs=0 should not produce max-requirements error, got: %s", e)
@@ -456,7 +539,7 @@ requirements:
     text: req
 `,
 	}}
-	vr := validateMeasureOutput(issues, 5, nil)
+	vr := validateMeasureOutput(issues, 5, nil, defaultGranularityRules())
 	for _, e := range vr.Errors {
 		if contains(e, "max is") {
 			t.Errorf("5 requirements at maxReqs=5 should not error, got: %s", e)
@@ -486,7 +569,7 @@ requirements:
     text: req
 `,
 	}}
-	vr := validateMeasureOutput(issues, 5, nil)
+	vr := validateMeasureOutput(issues, 5, nil, defaultGranularityRules())
 	found := false
 	for _, e := range vr.Errors {
 		if contains(e, "max is") {
@@ -525,7 +608,7 @@ requirements:
     text: req
 `,
 	}}
-	vr := validateMeasureOutput(issues, 5, nil)
+	vr := validateMeasureOutput(issues, 5, nil, defaultGranularityRules())
 	found := false
 	for _, e := range vr.Errors {
 		if contains(e, "8") && contains(e, "5") && contains(e, "Task Title") {
@@ -655,7 +738,7 @@ design_decisions:
     text: d3
 `,
 	}}
-	vr := validateMeasureOutput(issues, 8, subItems)
+	vr := validateMeasureOutput(issues, 8, subItems, defaultGranularityRules())
 	found := false
 	for _, e := range vr.Errors {
 		if contains(e, "expanded sub-item count") && contains(e, "max is") {
@@ -710,7 +793,7 @@ design_decisions:
 `,
 	}}
 	// expanded = 2+4 = 6, maxReqs = 8 → no expanded-count error.
-	vr := validateMeasureOutput(issues, 8, subItems)
+	vr := validateMeasureOutput(issues, 8, subItems, defaultGranularityRules())
 	for _, e := range vr.Errors {
 		if contains(e, "expanded sub-item count") {
 			t.Errorf("should not error when expanded count under limit, got: %s", e)
@@ -759,7 +842,7 @@ design_decisions:
 `,
 	}}
 	// 5 listed, expanded = 2+4 = 6. maxReqs = 8. Under limit — no error.
-	vr := validateMeasureOutput(issues, 8, subItems)
+	vr := validateMeasureOutput(issues, 8, subItems, defaultGranularityRules())
 	for _, e := range vr.Errors {
 		if contains(e, "expanded sub-item count") {
 			t.Errorf("should not error when expanded count under limit, got: %s", e)
@@ -955,6 +1038,106 @@ func TestAppendMeasureLog_NewFile(t *testing.T) {
 	}
 }
 
+func TestAppendMeasureLog_ReturnsListPositions(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	appendMeasureLog(dir, []proposedIssue{{Index: 0, Title: "A"}})
+	ids := appendMeasureLog(dir, []proposedIssue{{Index: 0, Title: "B"}, {Index: 1, Title: "C"}})
+	if !reflect.DeepEqual(ids, []string{"2", "3"}) {
+		t.Errorf("ids = %v, want [2 3]", ids)
+	}
+}
+
+func TestProposedIssuesContext(t *testing.T) {
+	t.Parallel()
+	path := measureOutputPath(t.TempDir(), time.Now())
+	if got, err := proposedIssuesContext(path, 0); got != "" || err != nil {
+		t.Errorf("proposedIssuesContext without a file = %q, %v", got, err)
+	}
+	appendProposedIssues(path, []proposedIssue{{Title: "Task A", Description: "a long description"}}, true)
+	got, err := proposedIssuesContext(path, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ctx []ContextIssue
+	if err := json.Unmarshal([]byte(got), &ctx); err != nil {
+		t.Fatalf("parsing %q: %v", got, err)
+	}
+	if len(ctx) != 1 || ctx[0].ID != "1" || ctx[0].Title != "Task A" || ctx[0].Status != "proposed" {
+		t.Errorf("context = %+v", ctx)
+	}
+	if !strings.HasPrefix(ctx[0].Description, "a long") || strings.Contains(ctx[0].Description, "description") {
+		t.Errorf("description not truncated: %q", ctx[0].Description)
+	}
+}
+
+func TestImportIssuesImpl_OutputOnlyWritesRunOutput(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	// Proposals from earlier runs neither dedup nor number this run's.
+	appendMeasureLog(dir, []proposedIssue{{Index: 0, Title: "Parse config file"}})
+	output := measureOutputPath(dir, time.Now())
+	appendProposedIssues(output, []proposedIssue{{Index: 0, Title: "Add widget cache", Dependency: -1}}, true)
+
+	yamlFile := filepath.Join(dir, "issues.yaml")
+	data, _ := yaml.Marshal([]proposedIssue{
+		{Index: 0, Title: "Implement widget caching", Dependency: -1},
+		{Index: 1, Title: "Parse config file", Dependency: -1},
+	})
+	os.WriteFile(yamlFile, data, 0o644)
+
+	cfg := Config{}
+	cfg.Cobbler.Dir = dir
+	cfg.Cobbler.MeasureOutputOnly = true
+	o := New(cfg)
+	o.measureOutput = output
+
+	// An empty repo would make any GitHub call fail; output-only makes none.
+	ids, skipped, _, err := o.importIssuesImpl(yamlFile, "", "gen", false, 0)
+	if err != nil {
+		t.Fatalf("importIssuesImpl: %v", err)
+	}
+	if skipped != 1 || !reflect.DeepEqual(ids, []string{"2"}) {
+		t.Errorf("ids = %v skipped = %d, want [2] and 1", ids, skipped)
+	}
+	logged := loadProposedIssues(output)
+	if len(logged) != 2 || logged[1].Title != "Parse config file" || logged[1].Index != 1 {
+		t.Errorf("run output = %+v", logged)
+	}
+	if got := loadProposedIssues(measureLogPath(dir)); len(got) != 1 {
+		t.Errorf("measure.yaml changed in output-only mode: %+v", got)
+	}
+}
+
+func TestAppendProposedIssues_RenumbersAcrossBatches(t *testing.T) {
+	t.Parallel()
+	path := measureOutputPath(t.TempDir(), time.Now())
+	batch := []proposedIssue{
+		{Index: 0, Title: "A", Dependency: -1},
+		{Index: 1, Title: "B", Dependency: 0},
+	}
+	appendProposedIssues(path, batch, true)
+	ids := appendProposedIssues(path, []proposedIssue{
+		{Index: 0, Title: "C", Dependency: -1},
+		{Index: 1, Title: "D", Dependency: 0},
+		{Index: 2, Title: "E", Dependency: 7},
+	}, true)
+	if !reflect.DeepEqual(ids, []string{"3", "4", "5"}) {
+		t.Errorf("ids = %v, want [3 4 5]", ids)
+	}
+	var got []string
+	for _, iss := range loadProposedIssues(path) {
+		got = append(got, fmt.Sprintf("%s:%d->%d", iss.Title, iss.Index, iss.Dependency))
+	}
+	want := []string{"A:0->-1", "B:1->0", "C:2->-1", "D:3->2", "E:4->-1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("issues = %v, want %v", got, want)
+	}
+	if batch[1].Index != 1 {
+		t.Error("renumbering modified the caller's issues")
+	}
+}
+
 func TestAppendMeasureLog_AppendsToExisting(t *testing.T) {
 	t.Parallel()
 	dir := t.TempDir()
@@ -1095,6 +1278,90 @@ func TestBuildMeasurePrompt_DefaultConfig(t *testing.T) {
 	}
 }
 
+func TestSpecMaintenancePromptExample_PassesValidation(t *testing.T) {
+	t.Parallel()
+	tmpl, err := parsePromptTemplate(defaultSpecMaintenancePrompt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, example, ok := strings.Cut(tmpl.OutputFormat, "Example:\n")
+	if !ok {
+		t.Fatal("output_format has no example")
+	}
+	example, _, _ = strings.Cut(example, "\n\n  The description must")
+	var issues []proposedIssue
+	if err := yaml.Unmarshal([]byte(example), &issues); err != nil {
+		t.Fatalf("example does not parse: %v", err)
+	}
+	if len(issues) == 0 {
+		t.Fatal("example has no issues")
+	}
+	vr := validateMeasureOutput(issues, 0, nil, defaultGranularityRules())
+	if len(vr.Errors) > 0 || len(vr.Warnings) > 0 {
+		t.Errorf("example fails validation: errors=%v warnings=%v", vr.Errors, vr.Warnings)
+	}
+}
+
+func TestBuildMeasurePrompt_SpecMaintenanceMode(t *testing.T) {
+	t.Parallel()
+	o := New(Config{})
+	o.cfg.Cobbler.MeasureMode = MeasureModeSpecMaintenance
+	o.cfg.Cobbler.MaxRequirementsPerTask = 4
+
+	prompt, err := o.buildMeasurePrompt("", "", 2)
+	if err != nil {
+		t.Fatalf("buildMeasurePrompt() error = %v", err)
+	}
+	if !strings.Contains(prompt, "maintaining the specification documents") {
+		t.Error("spec-maintenance mode should use the spec-maintenance role")
+	}
+	if strings.Contains(prompt, "software architect planning work") {
+		t.Error("spec-maintenance mode should not use the build measure role")
+	}
+	if !strings.Contains(prompt, "Do NOT exceed 2 tasks") || !strings.Contains(prompt, "at most 4 requirements") {
+		t.Error("spec-maintenance placeholders not substituted")
+	}
+}
+
+func TestValidateSpecMaintenanceOutput(t *testing.T) {
+	t.Parallel()
+	doc := func(deliverable string, paths ...string) string {
+		var b strings.Builder
+		b.WriteString("deliverable_type: " + deliverable + "\nfiles:\n")
+		for _, p := range paths {
+			b.WriteString("  - path: " + p + "\n    action: modify\n")
+		}
+		return b.String()
+	}
+	issues := []proposedIssue{
+		{Index: 0, Title: "Refresh architecture", Description: doc("documentation", "docs/ARCHITECTURE.yaml", "./docs/specs/product-requirements/prd001-core.yaml")},
+		{Index: 1, Title: "Code change", Description: doc("code", "docs/VISION.yaml")},
+		{Index: 2, Title: "Outside docs", Description: doc("documentation", "README.md", "docs/../pkg/x.go")},
+		{Index: 3, Title: "No files", Description: doc("documentation")},
+	}
+	errs := validateSpecMaintenanceOutput(issues)
+	joined := strings.Join(errs, "\n")
+	if strings.Contains(joined, "[0]") {
+		t.Errorf("valid documentation update rejected: %v", errs)
+	}
+	for _, want := range []string{
+		`[1] "Code change": deliverable_type "code"`,
+		`[2] "Outside docs": file README.md is outside docs/`,
+		`[2] "Outside docs": file docs/../pkg/x.go is outside docs/`,
+		`[3] "No files": no files listed`,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("missing error %q in %v", want, errs)
+		}
+	}
+
+	// Spec-maintenance proposals go through the documentation P9 ranges.
+	vr := validateMeasureOutput(issues[:1], 0, nil, defaultGranularityRules())
+	if !strings.Contains(strings.Join(vr.Errors, "\n"), "P9 doc range") {
+		t.Errorf("documentation ranges not applied: %v", vr.Errors)
+	}
+}
+
 func TestBuildMeasurePrompt_PlaceholderSubstitution(t *testing.T) {
 	t.Parallel()
 	o := New(Config{})
@@ -1190,7 +1457,7 @@ func TestBuildMeasurePrompt_GoldenExample(t *testing.T) {
 func TestImportIssuesImpl_NonexistentFile(t *testing.T) {
 	t.Parallel()
 	o := New(Config{})
-	_, _, err := o.importIssuesImpl("/nonexistent/file.yaml", "owner/repo", "gen", false, 0)
+	_, _, _, err := o.importIssuesImpl("/nonexistent/file.yaml", "owner/repo", "gen", false, 0)
 	if err == nil {
 		t.Error("expected error for nonexistent file")
 	}
@@ -1203,7 +1470,7 @@ func TestImportIssuesImpl_InvalidYAML(t *testing.T) {
 	os.WriteFile(yamlFile, []byte("{{{not valid yaml"), 0o644)
 
 	o := New(Config{})
-	_, _, err := o.importIssuesImpl(yamlFile, "owner/repo", "gen", false, 0)
+	_, _, _, err := o.importIssuesImpl(yamlFile, "owner/repo", "gen", false, 0)
 	if err == nil {
 		t.Error("expected error for invalid YAML")
 	}
@@ -1223,7 +1490,7 @@ func TestImportIssuesImpl_EmptyIssueList(t *testing.T) {
 	o := New(cfg)
 
 	// Empty list should not error — no issues to create, no GitHub calls.
-	ids, _, err := o.importIssuesImpl(yamlFile, "owner/repo", "gen", false, 0)
+	ids, _, _, err := o.importIssuesImpl(yamlFile, "owner/repo", "gen", false, 0)
 	if err != nil {
 		t.Fatalf("importIssuesImpl() error = %v", err)
 	}
@@ -1258,7 +1525,7 @@ acceptance_criteria:
 	cfg.Cobbler.EnforceMeasureValidation = true
 	o := New(cfg)
 
-	_, validationErrs, err := o.importIssuesImpl(yamlFile, "owner/repo", "gen", false, 0)
+	_, _, validationErrs, err := o.importIssuesImpl(yamlFile, "owner/repo", "gen", false, 0)
 	if err == nil {
 		t.Error("expected validation error in enforcing mode")
 	}
@@ -1299,7 +1566,7 @@ acceptance_criteria:
 	// skipEnforcement=true should bypass validation errors.
 	// This will fail at createCobblerIssue (no real GitHub), but should NOT
 	// fail at validation.
-	ids, _, err := o.importIssuesImpl(yamlFile, "owner/repo", "gen", true, 0)
+	ids, _, _, err := o.importIssuesImpl(yamlFile, "owner/repo", "gen", true, 0)
 	if err != nil {
 		t.Fatalf("importIssuesImpl() with skipEnforcement should not return validation error, got: %v", err)
 	}
@@ -1329,7 +1596,7 @@ func TestImportIssuesImpl_UpgradePath_PhZero_SingleIssue(t *testing.T) {
 	cfg.Cobbler.Dir = dir
 	o := New(cfg)
 
-	ids, _, err := o.importIssuesImpl(yamlFile, "owner/repo", "gen", false, 0)
+	ids, _, _, err := o.importIssuesImpl(yamlFile, "owner/repo", "gen", false, 0)
 	if err != nil {
 		t.Fatalf("importIssuesImpl() unexpected error: %v", err)
 	}
@@ -1353,7 +1620,7 @@ func TestImportIssuesImpl_UpgradePath_PhPositive_SingleIssue(t *testing.T) {
 	o := New(cfg)
 
 	// ph=99 triggers the upgrade path; both gh calls fail without real GitHub.
-	ids, _, err := o.importIssuesImpl(yamlFile, "owner/repo", "gen", false, 99)
+	ids, _, _, err := o.importIssuesImpl(yamlFile, "owner/repo", "gen", false, 99)
 	if err != nil {
 		t.Fatalf("importIssuesImpl() unexpected error: %v", err)
 	}
@@ -1378,7 +1645,7 @@ func TestImportIssuesImpl_UpgradePath_PhPositive_MultipleIssues(t *testing.T) {
 	o := New(cfg)
 
 	// ph=42 but 2 issues: upgrade path must not be taken.
-	ids, _, err := o.importIssuesImpl(yamlFile, "owner/repo", "gen", false, 42)
+	ids, _, _, err := o.importIssuesImpl(yamlFile, "owner/repo", "gen", false, 42)
 	if err != nil {
 		t.Fatalf("importIssuesImpl() unexpected error: %v", err)
 	}
@@ -1819,6 +2086,110 @@ touchpoints:
 	}
 }
 
+// --- focus area (GH-559) ---
+
+func TestBuildMeasurePrompt_FocusArea(t *testing.T) {
+	_, cleanup := setupContextTestDir(t)
+	defer cleanup()
+
+	os.MkdirAll("pkg/other", 0o755)
+	os.WriteFile("pkg/other/other.go", []byte("package other\n"), 0o644)
+
+	cfg := Config{}
+	cfg.Project.GoSourceDirs = []string{"pkg/"}
+	cfg.Cobbler.FocusArea = "pkg/app (application wiring)"
+	o := New(cfg)
+
+	prompt, err := o.buildMeasurePrompt("", "", 1)
+	if err != nil {
+		t.Fatalf("buildMeasurePrompt() error = %v", err)
+	}
+	if !strings.Contains(prompt, "Focus area: You MUST only propose tasks within pkg/app (application wiring).") {
+		t.Error("prompt missing focus area constraint")
+	}
+	if !strings.Contains(prompt, "pkg/app/main.go") {
+		t.Error("focused source pkg/app/main.go should remain in the prompt")
+	}
+	if strings.Contains(prompt, "pkg/other/other.go") {
+		t.Error("source outside the focus area should be filtered out")
+	}
+}
+
+func TestFocusSourceFiles(t *testing.T) {
+	t.Parallel()
+	sources := []SourceFile{{File: "pkg/a/a.go"}, {File: "pkg/a/b.go"}, {File: "pkg/c/c.go"}}
+	tests := []struct {
+		focus string
+		want  int
+	}{
+		{"pkg/a", 2},
+		{"pkg/a/", 2},
+		{"a/b.go (the b file)", 1},
+		{"pkg/missing", 3},
+	}
+	for _, tc := range tests {
+		if got := focusSourceFiles(sources, tc.focus); len(got) != tc.want {
+			t.Errorf("focusSourceFiles(%q) kept %d files, want %d", tc.focus, len(got), tc.want)
+		}
+	}
+}
+
+// --- measure retry backoff ---
+
+func TestMeasureRetryBackoff(t *testing.T) {
+	t.Parallel()
+	if d := measureRetryBackoff(0, 1); d != 0 {
+		t.Errorf("zero base: got %v, want 0", d)
+	}
+	if d := measureRetryBackoff(time.Second, 0); d != 0 {
+		t.Errorf("first attempt: got %v, want 0", d)
+	}
+	for retry, base := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second} {
+		for range 20 {
+			d := measureRetryBackoff(time.Second, retry)
+			if d < base || d > base+base/2 {
+				t.Fatalf("retry %d: got %v, want within [%v, %v]", retry, d, base, base+base/2)
+			}
+		}
+	}
+	for _, retry := range []int{10, 40, 100} {
+		if d := measureRetryBackoff(time.Second, retry); d != maxMeasureRetryBackoff {
+			t.Errorf("retry %d: got %v, want the %v cap", retry, d, maxMeasureRetryBackoff)
+		}
+	}
+}
+
+// --- incremental measure context (GH-564) ---
+
+func TestChangedSinceStart(t *testing.T) {
+	dir := initTestGitRepo(t)
+	for _, f := range []string{"a.go", "b.go", "c.go"} {
+		os.WriteFile(filepath.Join(dir, f), []byte("package x\n"), 0o644)
+	}
+	gitRun(t, "add", "-A")
+	gitRun(t, "commit", "-m", "sources")
+
+	if _, ok := changedSinceStart("generation-x"); ok {
+		t.Fatal("changedSinceStart without a start tag: want ok=false")
+	}
+	if _, ok := changedSinceStart(""); ok {
+		t.Fatal("changedSinceStart without a generation: want ok=false")
+	}
+
+	gitRun(t, "tag", "generation-x-start")
+	os.WriteFile(filepath.Join(dir, "a.go"), []byte("package x\n\nvar A = 1\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "d.go"), []byte("package x\n"), 0o644)
+	gitRun(t, "rm", "-q", "c.go")
+	gitRun(t, "add", "-A")
+	gitRun(t, "commit", "-m", "generation work")
+
+	got, ok := changedSinceStart("generation-x")
+	slices.Sort(got)
+	if !ok || !slices.Equal(got, []string{"a.go", "d.go"}) {
+		t.Errorf("changedSinceStart = %v, %v; want [a.go d.go], true", got, ok)
+	}
+}
+
 // --- test file exclusion wiring (GH-616) ---
 
 // TestBuildMeasurePrompt_ExcludeTests_DefaultTrue verifies that _test.go files
@@ -1926,3 +2297,433 @@ func TestBuildMeasurePrompt_SourceMode_PhaseCtxWins(t *testing.T) {
 		t.Error("phase context source_mode=full should override config headers mode; body should be in prompt")
 	}
 }
+
+// --- splitYAMLSequenceItems / scanItemIndex (GH-463) ---
+
+func TestSplitYAMLSequenceItems_ParsesEachItem(t *testing.T) {
+	t.Parallel()
+	data := `# proposed issues
+- index: 1
+  title: First
+  description: |
+    - not a new item
+  dependency: -1
+- index: 2
+  title: Second
+  dependency: 1
+`
+	items := splitYAMLSequenceItems([]byte(data))
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2: %q", len(items), items)
+	}
+	for i, item := range items {
+		var parsed []proposedIssue
+		if err := yaml.Unmarshal([]byte(item), &parsed); err != nil || len(parsed) != 1 {
+			t.Fatalf("item %d did not parse: %v", i, err)
+		}
+		if parsed[0].Index != i+1 {
+			t.Errorf("item %d index = %d, want %d", i, parsed[0].Index, i+1)
+		}
+	}
+}
+
+func TestSplitYAMLSequenceItems_MalformedItemIsolated(t *testing.T) {
+	t.Parallel()
+	data := "- index: 1\n  title: Good\n- index: 2\n  title: [unclosed\n- index: 3\n  title: Also good\n"
+	items := splitYAMLSequenceItems([]byte(data))
+	if len(items) != 3 {
+		t.Fatalf("got %d items, want 3", len(items))
+	}
+	var ok int
+	for _, item := range items {
+		var parsed []proposedIssue
+		if yaml.Unmarshal([]byte(item), &parsed) == nil {
+			ok++
+		}
+	}
+	if ok != 2 {
+		t.Errorf("parsed %d items, want 2 (malformed item isolated)", ok)
+	}
+}
+
+func TestScanItemIndex(t *testing.T) {
+	t.Parallel()
+	if n, ok := scanItemIndex("- index: 4\n  title: [broken"); !ok || n != 4 {
+		t.Errorf("scanItemIndex = (%d, %v), want (4, true)", n, ok)
+	}
+	if n, ok := scanItemIndex("- title: x\n  index: 7"); !ok || n != 7 {
+		t.Errorf("scanItemIndex = (%d, %v), want (7, true)", n, ok)
+	}
+	if _, ok := scanItemIndex("- title: no index"); ok {
+		t.Error("scanItemIndex should report no index")
+	}
+}
+
+// --- validateDependencyRefs (GH-464) ---
+
+func TestValidateMeasureOutput_SelfDependency(t *testing.T) {
+	t.Parallel()
+	issues := []proposedIssue{{Index: 2, Title: "Loop", Dependency: 2}}
+	vr := validateMeasureOutput(issues, 0, nil, defaultGranularityRules())
+	found := false
+	for _, e := range vr.Errors {
+		if contains(e, "depends on itself") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected self-dependency error, got: %v", vr.Errors)
+	}
+}
+
+func TestValidateMeasureOutput_DanglingDependency(t *testing.T) {
+	t.Parallel()
+	issues := []proposedIssue{
+		{Index: 0, Title: "Base", Dependency: -1},
+		{Index: 1, Title: "Dangling", Dependency: 5},
+	}
+	errs := validateDependencyRefs(issues, issueIndexSet(issues))
+	if len(errs) != 1 || !contains(errs[0], "dependency 5 does not match any index") {
+		t.Errorf("expected one dangling-dependency error, got: %v", errs)
+	}
+}
+
+func TestValidateDependencyRefs_ValidGraph(t *testing.T) {
+	t.Parallel()
+	issues := []proposedIssue{
+		{Index: 0, Title: "Base", Dependency: -1},
+		{Index: 1, Title: "Next", Dependency: 0},
+	}
+	if errs := validateDependencyRefs(issues, issueIndexSet(issues)); len(errs) != 0 {
+		t.Errorf("expected no errors, got: %v", errs)
+	}
+}
+
+// --- breakDependencyCycles (GH-544) ---
+
+func TestBreakDependencyCycles_SelfDependency(t *testing.T) {
+	t.Parallel()
+	issues := []proposedIssue{
+		{Index: 0, Title: "Base", Dependency: -1},
+		{Index: 1, Title: "Loop", Dependency: 1},
+	}
+	got, errs := breakDependencyCycles(issues)
+	if got[1].Dependency != -1 {
+		t.Errorf("self-dependency not dropped: %+v", got[1])
+	}
+	if len(errs) != 0 {
+		t.Errorf("self-dependency is reported by validateDependencyRefs, got extra errors: %v", errs)
+	}
+	if issues[1].Dependency != 1 {
+		t.Error("breakDependencyCycles modified its input")
+	}
+}
+
+func TestBreakDependencyCycles_TwoNodeCycle(t *testing.T) {
+	t.Parallel()
+	issues := []proposedIssue{
+		{Index: 0, Title: "A", Dependency: 1},
+		{Index: 1, Title: "B", Dependency: 0},
+		{Index: 2, Title: "C", Dependency: 1},
+	}
+	got, errs := breakDependencyCycles(issues)
+	if len(errs) != 1 || !contains(errs[0], "forms a cycle (0 -> 1 -> 0)") {
+		t.Errorf("errs = %v, want one cycle error", errs)
+	}
+	if got[0].Dependency != 1 || got[1].Dependency != -1 || got[2].Dependency != 1 {
+		t.Errorf("dependencies = %d %d %d, want 1 -1 1", got[0].Dependency, got[1].Dependency, got[2].Dependency)
+	}
+}
+
+func TestBreakDependencyCycles_AcyclicUnchanged(t *testing.T) {
+	t.Parallel()
+	issues := []proposedIssue{
+		{Index: 0, Title: "A", Dependency: -1},
+		{Index: 1, Title: "B", Dependency: 0},
+		{Index: 2, Title: "C", Dependency: 1},
+		{Index: 3, Title: "D", Dependency: 9},
+	}
+	got, errs := breakDependencyCycles(issues)
+	if len(errs) != 0 || !reflect.DeepEqual(got, issues) {
+		t.Errorf("acyclic graph changed: %+v, %v", got, errs)
+	}
+}
+
+func TestImportIssuesImpl_CycleRejectedInEnforcingMode(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "issues.yaml")
+	issues := []proposedIssue{
+		{Index: 0, Title: "A", Dependency: 1},
+		{Index: 1, Title: "B", Dependency: 0},
+	}
+	data, _ := yaml.Marshal(issues)
+	os.WriteFile(yamlFile, data, 0o644)
+
+	cfg := Config{}
+	cfg.Cobbler.Dir = dir
+	cfg.Cobbler.EnforceMeasureValidation = true
+	o := New(cfg)
+
+	_, _, validationErrs, err := o.importIssuesImpl(yamlFile, "owner/repo", "gen", false, 0)
+	if err == nil {
+		t.Fatal("expected validation error for dependency cycle in enforcing mode")
+	}
+	found := false
+	for _, e := range validationErrs {
+		if contains(e, "forms a cycle") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("validationErrs = %v, want a cycle error", validationErrs)
+	}
+}
+
+// --- assignDepthPriorities (GH-546) ---
+
+func TestAssignDepthPriorities_ThreeLevelChain(t *testing.T) {
+	t.Parallel()
+	issues := []proposedIssue{
+		{Index: 2, Title: "Feature", Dependency: 1},
+		{Index: 0, Title: "Foundation", Dependency: -1},
+		{Index: 1, Title: "Service", Dependency: 0},
+		{Index: 3, Title: "Standalone", Dependency: 7},
+	}
+	assignDepthPriorities(issues)
+	want := map[int]int{0: 1, 1: 2, 2: 3, 3: 1}
+	for _, issue := range issues {
+		if issue.Priority != want[issue.Index] {
+			t.Errorf("[%d] %s priority = %d, want %d", issue.Index, issue.Title, issue.Priority, want[issue.Index])
+		}
+	}
+}
+
+func TestAssignDepthPriorities_CycleTerminates(t *testing.T) {
+	t.Parallel()
+	issues := []proposedIssue{
+		{Index: 0, Title: "A", Dependency: 1},
+		{Index: 1, Title: "B", Dependency: 0},
+	}
+	assignDepthPriorities(issues) // must not recurse forever
+	for _, issue := range issues {
+		if issue.Priority < 1 {
+			t.Errorf("[%d] priority = %d, want >= 1", issue.Index, issue.Priority)
+		}
+	}
+}
+
+func TestValidateMeasureBatch_ResolvesAgainstBatch(t *testing.T) {
+	t.Parallel()
+	// Streaming import validates one issue at a time against the full batch.
+	issue := []proposedIssue{{Index: 1, Title: "Next", Dependency: 0}}
+	vr := validateMeasureBatch(issue, 0, nil, defaultGranularityRules(), map[int]bool{0: true, 1: true})
+	for _, e := range vr.Errors {
+		if contains(e, "dependency") {
+			t.Errorf("dependency on batch index should be valid, got: %s", e)
+		}
+	}
+}
+
+func TestMeasureRetryBudgetLeft(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		used, budget int
+		want         bool
+	}{
+		{0, 0, true},
+		{100, 0, true},
+		{0, 3, true},
+		{2, 3, true},
+		{3, 3, false},
+		{4, 3, false},
+	}
+	for _, tc := range cases {
+		if got := measureRetryBudgetLeft(tc.used, tc.budget); got != tc.want {
+			t.Errorf("measureRetryBudgetLeft(%d, %d) = %v, want %v", tc.used, tc.budget, got, tc.want)
+		}
+	}
+}
+
+func TestMeasureIterationCostCapped(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		spent, ceiling float64
+		want           bool
+	}{
+		{0, 0, false},
+		{50, 0, false},
+		{0.5, 1, false},
+		{1, 1, true},
+		{1.25, 1, true},
+		{5, -1, false},
+	}
+	for _, tc := range cases {
+		if got := measureIterationCostCapped(tc.spent, tc.ceiling); got != tc.want {
+			t.Errorf("measureIterationCostCapped(%v, %v) = %v, want %v", tc.spent, tc.ceiling, got, tc.want)
+		}
+	}
+}
+
+func TestValidateDescriptionSize_Thresholds(t *testing.T) {
+	t.Parallel()
+	issues := []proposedIssue{
+		{Index: 0, Title: "small", Description: "a\nb\n"},
+		{Index: 1, Title: "wide", Description: strings.Repeat("x", 101)},
+		{Index: 2, Title: "tall", Description: strings.Repeat("l\n", 11)},
+	}
+
+	if errs := validateDescriptionSize(issues, 0, 0); len(errs) != 0 {
+		t.Errorf("disabled limits produced errors: %v", errs)
+	}
+
+	errs := validateDescriptionSize(issues, 100, 10)
+	if len(errs) != 2 {
+		t.Fatalf("got %d errors, want 2: %v", len(errs), errs)
+	}
+	if !contains(errs[0], "101 bytes, max is 100") || !contains(errs[1], "11 lines, max is 10") {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+
+	// Exactly at the limit passes.
+	if errs := validateDescriptionSize(issues[:2], 101, 2); len(errs) != 0 {
+		t.Errorf("at-limit descriptions flagged: %v", errs)
+	}
+}
+
+func TestMissingIssueIDs(t *testing.T) {
+	t.Parallel()
+	issues := []cobblerIssue{{Number: 10}, {Number: 12}}
+	got := missingIssueIDs(issues, []string{"10", "11", "12", "13"})
+	want := []string{"11", "13"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("missingIssueIDs = %v, want %v", got, want)
+	}
+	if got := missingIssueIDs(issues, []string{"12", "10"}); len(got) != 0 {
+		t.Errorf("missingIssueIDs with all visible = %v, want none", got)
+	}
+}
+
+func TestSettleMeasureIteration_DisabledReturnsImmediately(t *testing.T) {
+	t.Parallel()
+	o := &Orchestrator{cfg: Config{Cobbler: CobblerConfig{
+		MeasureSettleMode:    MeasureSettleConsistent,
+		MeasureSettleSeconds: -1,
+	}}}
+	start := time.Now()
+	o.settleMeasureIteration("owner/repo", "gen", []string{"1"})
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("disabled settle took %s, want immediate return", d)
+	}
+}
+
+func TestSettleMeasureIteration_CountModeDoesNotWait(t *testing.T) {
+	t.Parallel()
+	o := &Orchestrator{cfg: Config{Cobbler: CobblerConfig{
+		MeasureSettleMode:    MeasureSettleCount,
+		MeasureSettleSeconds: 2,
+	}}}
+	start := time.Now()
+	o.settleMeasureIteration("owner/repo", "gen", []string{"1"})
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("count-mode settle took %s, want no extra wait", d)
+	}
+}
+
+func TestSettleMeasureIteration_DelayModeStopsOnInterrupt(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	o := &Orchestrator{cfg: Config{Cobbler: CobblerConfig{
+		MeasureSettleMode:    MeasureSettleDelay,
+		MeasureSettleSeconds: 30,
+	}}, stop: ctx}
+	start := time.Now()
+	o.settleMeasureIteration("owner/repo", "gen", []string{"1"})
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("interrupted delay settle took %s, want immediate return", d)
+	}
+}
+
+func TestApplyDefaults_MeasureSettle(t *testing.T) {
+	t.Parallel()
+	cfg := Config{}
+	cfg.applyDefaults()
+	if cfg.Cobbler.MeasureSettleMode != MeasureSettleDelay {
+		t.Errorf("MeasureSettleMode = %q, want %q", cfg.Cobbler.MeasureSettleMode, MeasureSettleDelay)
+	}
+	if cfg.Cobbler.MeasureSettleSeconds != 2 || cfg.Cobbler.MeasureSettleTimeoutSeconds != 30 {
+		t.Errorf("settle seconds = %d/%d, want 2/30",
+			cfg.Cobbler.MeasureSettleSeconds, cfg.Cobbler.MeasureSettleTimeoutSeconds)
+	}
+}
+
+func TestValidateTestCases(t *testing.T) {
+	t.Parallel()
+	cases := []issueTestCase{
+		{Name: "TestGet", Description: "ok"},
+		{Name: ""},
+		{Name: "checkSet"},
+		{Name: "TestGet"},
+	}
+	msgs := validateTestCases(cases)
+	if len(msgs) != 3 {
+		t.Fatalf("validateTestCases = %v, want 3 messages", msgs)
+	}
+	for i, want := range []string{"test_cases[1] has no name", "should start with Test", "duplicates name"} {
+		if !strings.Contains(msgs[i], want) {
+			t.Errorf("msgs[%d] = %q, want it to contain %q", i, msgs[i], want)
+		}
+	}
+}
+
+func TestValidateMeasureOutput_TestCasesAreAdvisory(t *testing.T) {
+	t.Parallel()
+	desc := "deliverable_type: documentation\nrequirements:\n  - id: R1\n    text: a\n  - id: R2\n    text: b\n" +
+		"acceptance_criteria:\n  - id: AC1\n    text: a\n  - id: AC2\n    text: b\n  - id: AC3\n    text: c\n" +
+		"test_cases:\n  - description: missing name\n"
+	issues := []proposedIssue{{Index: 0, Title: "Doc task", Dependency: -1, Description: desc}}
+	result := validateMeasureOutput(issues, 0, nil, defaultGranularityRules())
+	if result.HasErrors() {
+		t.Errorf("malformed test_cases should not be errors, got %v", result.Errors)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "has no name") {
+		t.Errorf("Warnings = %v, want one no-name warning", result.Warnings)
+	}
+}
+
+func TestBuildMeasurePrompt_AppliesContextBudget(t *testing.T) {
+	_, cleanup := setupContextTestDir(t)
+	defer cleanup()
+	big := "package app\n\n// " + strings.Repeat("padding ", 2000) + "\n"
+	if err := os.WriteFile("pkg/app/big.go", []byte(big), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	build := func(budget int) (string, int) {
+		cfg := Config{}
+		cfg.Project.GoSourceDirs = []string{"pkg/app"}
+		cfg.Cobbler.MaxContextBytes = budget
+		cfg.applyDefaults()
+		o := New(cfg)
+		prompt, err := o.buildMeasurePrompt("", "[]", 1)
+		if err != nil {
+			t.Fatalf("buildMeasurePrompt: %v", err)
+		}
+		return prompt, o.lastTrim.removed()
+	}
+	full, removed := build(0)
+	if strings.Count(full, "file: pkg/app/") != 3 {
+		t.Fatalf("unbudgeted prompt has %d source file(s), want 3", strings.Count(full, "file: pkg/app/"))
+	}
+	if removed != 0 {
+		t.Errorf("unbudgeted prompt recorded %d trimmed entries, want 0", removed)
+	}
+	trimmed, removed := build(4000)
+	if strings.Count(trimmed, "file: pkg/app/") >= 3 {
+		t.Error("MaxContextBytes did not trim the measure context without incremental_since")
+	}
+	if removed == 0 {
+		t.Error("budgeted prompt recorded no trimmed entries for its history stats")
+	}
+}
@@ -26,6 +26,30 @@ type sdkQueryFunc func(ctx context.Context, prompt string, opts *claudetypes.Cla
 type Orchestrator struct {
 	cfg        Config
 	sdkQueryFn sdkQueryFunc
+
+	// measureCtx caches the measure project context for the duration of
+	// one RunMeasure (GH-509); nil outside a run.
+	measureCtx *projectContextCache
+
+	// measureOutput is the proposals file of an output-only RunMeasure
+	// (GH-545); "" outside such a run.
+	measureOutput string
+
+	// spend accumulates Claude cost against Generation.MaxCostUSD for
+	// the duration of one generator run (GH-532); nil outside a run.
+	spend *costLedger
+
+	// stitched counts tasks stitched against Cobbler.MaxStitchIssues for
+	// the duration of one generator run (GH-549); nil outside a run.
+	stitched *stitchCounter
+
+	// stop is cancelled by the first SIGINT or SIGTERM during a generator
+	// or stitch run (GH-548); nil outside a run.
+	stop context.Context
+
+	// lastTrim is what the context budget removed while building the
+	// most recent measure or stitch prompt, for its history stats.
+	lastTrim budgetTrim
 }
 
 // New creates an Orchestrator with the given configuration.
@@ -0,0 +1,110 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// postMeasurePreview posts the issues proposed by a measure run as one
+// markdown comment on the configured PR or issue
+// (Cobbler.MeasurePreviewNumber in MeasurePreviewRepo, defaulting to the
+// issues repo). Reviewers see titles, the dependency tree, and full
+// descriptions without leaving GitHub (GH-471). Posting is best-effort:
+// failures are logged and never fail the measure run.
+func (o *Orchestrator) postMeasurePreview(repo, generation string, createdIDs []string) {
+	number := o.cfg.Cobbler.MeasurePreviewNumber
+	if number <= 0 || len(createdIDs) == 0 {
+		return
+	}
+	target := o.cfg.Cobbler.MeasurePreviewRepo
+	if target == "" {
+		target = repo
+	}
+
+	issues, err := listOpenCobblerIssues(repo, generation)
+	if err != nil {
+		logf("postMeasurePreview: listing issues: %v", err)
+		return
+	}
+	created := make(map[string]bool, len(createdIDs))
+	for _, id := range createdIDs {
+		created[id] = true
+	}
+	var proposed []cobblerIssue
+	for _, iss := range issues {
+		if created[fmt.Sprintf("%d", iss.Number)] {
+			proposed = append(proposed, iss)
+		}
+	}
+	if len(proposed) == 0 {
+		logf("postMeasurePreview: none of %d created issue(s) found open, skipping", len(createdIDs))
+		return
+	}
+
+	logf("postMeasurePreview: posting %d proposed issue(s) to %s#%d", len(proposed), target, number)
+	commentCobblerIssue(target, number, formatMeasurePreview(generation, repo, proposed))
+}
+
+// formatMeasurePreview renders proposed issues as a markdown comment: a
+// dependency tree of titles followed by a collapsible description per
+// issue. Issues whose dependency is not among the proposed set are shown
+// as roots with the dependency index noted. Issues caught in a dependency
+// cycle have no root above them; they are listed after the tree and
+// marked as cyclic so none is silently dropped.
+func formatMeasurePreview(generation, repo string, issues []cobblerIssue) string {
+	sorted := append([]cobblerIssue{}, issues...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Index < sorted[j].Index })
+
+	byIndex := make(map[int]bool, len(sorted))
+	for _, iss := range sorted {
+		byIndex[iss.Index] = true
+	}
+	children := issueDependents(sorted)
+	var roots []cobblerIssue
+	for _, iss := range sorted {
+		if iss.DependsOn < 0 || iss.DependsOn == iss.Index || !byIndex[iss.DependsOn] {
+			roots = append(roots, iss)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Measure preview: %d proposed issue(s) for `%s`\n\n", len(sorted), generation)
+	b.WriteString("### Dependency tree\n\n")
+	visited := make(map[int]bool)
+	var walk func(iss cobblerIssue, depth int)
+	walk = func(iss cobblerIssue, depth int) {
+		if visited[iss.Number] {
+			return
+		}
+		visited[iss.Number] = true
+		note := ""
+		if depth == 0 && iss.DependsOn >= 0 && !byIndex[iss.DependsOn] {
+			note = fmt.Sprintf(" (depends on index %d)", iss.DependsOn)
+		}
+		fmt.Fprintf(&b, "%s- %s#%d %s%s\n", strings.Repeat("  ", depth), repo, iss.Number, iss.Title, note)
+		for _, c := range children[iss.Index] {
+			walk(c, depth+1)
+		}
+	}
+	for _, r := range roots {
+		walk(r, 0)
+	}
+	for _, iss := range sorted {
+		if !visited[iss.Number] {
+			fmt.Fprintf(&b, "- %s#%d %s (dependency cycle: depends on index %d)\n",
+				repo, iss.Number, iss.Title, iss.DependsOn)
+			visited[iss.Number] = true
+		}
+	}
+
+	b.WriteString("\n### Descriptions\n")
+	for _, iss := range sorted {
+		fmt.Fprintf(&b, "\n<details><summary>#%d %s</summary>\n\n```yaml\n%s\n```\n\n</details>\n",
+			iss.Number, iss.Title, strings.TrimSpace(iss.Description))
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatMeasurePreview_DependencyTree(t *testing.T) {
+	t.Parallel()
+	issues := []cobblerIssue{
+		{Number: 12, Index: 2, DependsOn: 1, Title: "child", Description: "deliverable_type: code"},
+		{Number: 11, Index: 1, DependsOn: -1, Title: "root"},
+		{Number: 13, Index: 3, DependsOn: 9, Title: "external"},
+	}
+	got := formatMeasurePreview("generation-x", "o/r", issues)
+
+	for _, want := range []string{
+		"3 proposed issue(s) for `generation-x`",
+		"- o/r#11 root\n  - o/r#12 child\n",
+		"- o/r#13 external (depends on index 9)",
+		"<details><summary>#12 child</summary>",
+		"deliverable_type: code",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("preview missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestFormatMeasurePreview_ListsCyclicIssues(t *testing.T) {
+	t.Parallel()
+	issues := []cobblerIssue{
+		{Number: 11, Index: 1, DependsOn: -1, Title: "root"},
+		{Number: 12, Index: 2, DependsOn: 3, Title: "first"},
+		{Number: 13, Index: 3, DependsOn: 2, Title: "second"},
+	}
+	got := formatMeasurePreview("generation-x", "o/r", issues)
+
+	for _, want := range []string{
+		"- o/r#11 root\n",
+		"- o/r#12 first (dependency cycle: depends on index 3)\n",
+		"- o/r#13 second (dependency cycle: depends on index 2)\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("preview missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestPostMeasurePreview_DisabledIsNoop(t *testing.T) {
+	t.Parallel()
+	o := &Orchestrator{}
+	// No gh calls are made when MeasurePreviewNumber is unset.
+	o.postMeasurePreview("o/r", "generation-x", []string{"1"})
+}
@@ -30,17 +30,20 @@ type MeasurePromptDoc struct {
 
 // StitchPromptDoc is the complete stitch prompt as a YAML document.
 type StitchPromptDoc struct {
-	Role                  string                   `yaml:"role"`
-	RepositoryFiles       []string                 `yaml:"repository_files,omitempty"`
-	ProjectContext        *ProjectContext          `yaml:"project_context,omitempty"`
-	Context               string                   `yaml:"context"`
+	Role                  string                  `yaml:"role"`
+	RepositoryFiles       []string                `yaml:"repository_files,omitempty"`
+	ProjectContext        *ProjectContext         `yaml:"project_context,omitempty"`
+	Context               string                  `yaml:"context"`
 	ExecutionConstitution *yaml.Node              `yaml:"execution_constitution,omitempty"`
 	GoStyleConstitution   *yaml.Node              `yaml:"go_style_constitution,omitempty"`
-	Task                  string                   `yaml:"task"`
-	Constraints           string                   `yaml:"constraints"`
-	Description           string                   `yaml:"description"`
-	SharedProtocols       []ArchSharedProtocol     `yaml:"shared_protocols,omitempty"`
-	PackageContracts      []OODPackageContractRef  `yaml:"package_contracts,omitempty"`
+	ObservedConventions   *ObservedConventions    `yaml:"observed_conventions,omitempty"`
+	Task                  string                  `yaml:"task"`
+	Constraints           string                  `yaml:"constraints"`
+	Description           string                  `yaml:"description"`
+	TestCases             []issueTestCase         `yaml:"test_cases,omitempty"`
+	BuildPlan             *StitchBuildPlan        `yaml:"build_plan,omitempty"`
+	SharedProtocols       []ArchSharedProtocol    `yaml:"shared_protocols,omitempty"`
+	PackageContracts      []OODPackageContractRef `yaml:"package_contracts,omitempty"`
 }
 
 // promptTemplate holds the static text fields parsed from a prompt
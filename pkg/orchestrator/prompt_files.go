@@ -50,17 +50,17 @@ func (o *Orchestrator) resolveContextFileEntries() []contextFileEntry {
 	var entries []contextFileEntry
 
 	// Build exclude set (empty map when ContextExclude is unset).
-	excludeSet := resolveFileSet(o.cfg.Project.ContextExclude)
+	excludeSet := resolveFileSet(".", o.cfg.Project.ContextExclude)
 
 	// Resolve doc files: ContextInclude overrides standard patterns.
 	var docFiles []string
 	var docSource string
 	if strings.TrimSpace(o.cfg.Project.ContextInclude) != "" {
-		docFiles = resolveContextSources(o.cfg.Project.ContextInclude)
-		docFiles = ensureTypedDocs(docFiles)
+		docFiles = resolveContextSources(".", o.cfg.Project.ContextInclude)
+		docFiles = ensureTypedDocs(".", docFiles)
 		docSource = "config"
 	} else {
-		docFiles = resolveStandardFiles()
+		docFiles = resolveStandardFiles(".")
 		docSource = "default"
 	}
 
@@ -86,7 +86,7 @@ func (o *Orchestrator) resolveContextFileEntries() []contextFileEntry {
 
 	// Extra context sources from configuration.
 	if strings.TrimSpace(o.cfg.Project.ContextSources) != "" {
-		extras := resolveContextSources(o.cfg.Project.ContextSources)
+		extras := resolveContextSources(".", o.cfg.Project.ContextSources)
 		for _, path := range extras {
 			if seen[path] || excludeSet[path] {
 				continue
@@ -228,6 +228,23 @@ func TestBuildMeasurePrompt_MaxRequirementsPlaceholder(t *testing.T) {
 	}
 }
 
+func TestBuildMeasurePrompt_GranularityTargetsFromConfig(t *testing.T) {
+	cfg := Config{}
+	cfg.Cobbler.GranularityRules.Code.Requirements = CountRange{Min: 2, Max: 3}
+	cfg.applyDefaults()
+	o := New(cfg)
+	prompt, err := o.buildMeasurePrompt("", "[]", 1)
+	if err != nil {
+		t.Fatalf("buildMeasurePrompt: %v", err)
+	}
+	if strings.Contains(prompt, "{granularity_targets}") {
+		t.Error("measure prompt has unsubstituted {granularity_targets} placeholder")
+	}
+	if !strings.Contains(prompt, "Code tasks target 2-3 requirements, 5-8 acceptance criteria") {
+		t.Error("measure prompt does not state the configured code requirement range")
+	}
+}
+
 func TestMeasurePromptNoWriteToolReferences(t *testing.T) {
 	o := New(Config{})
 	prompt, err := o.buildMeasurePrompt("", "[]", 1)
@@ -373,7 +390,7 @@ func TestLoadOODPromptContext_Empty(t *testing.T) {
 	defer os.Chdir(orig)
 	os.MkdirAll("docs/specs/product-requirements", 0o755)
 
-	contracts, protocols := loadOODPromptContext()
+	contracts, protocols := loadOODPromptContext(".")
 	if len(contracts) != 0 {
 		t.Errorf("expected no contracts with no PRD files, got %d", len(contracts))
 	}
@@ -402,7 +419,7 @@ package_contract:
 title: Cmd
 `), 0o644)
 
-	contracts, _ := loadOODPromptContext()
+	contracts, _ := loadOODPromptContext(".")
 	if len(contracts) != 1 {
 		t.Fatalf("expected 1 contract, got %d", len(contracts))
 	}
@@ -435,7 +452,7 @@ shared_protocols:
     pattern: "signal.Notify(...)"
 `), 0o644)
 
-	_, protocols := loadOODPromptContext()
+	_, protocols := loadOODPromptContext(".")
 	if len(protocols) != 1 {
 		t.Fatalf("expected 1 protocol, got %d", len(protocols))
 	}
@@ -459,7 +476,7 @@ package_contract:
   exports: []
 `), 0o644)
 
-	contracts, _ := loadOODPromptContext()
+	contracts, _ := loadOODPromptContext(".")
 	if len(contracts) != 0 {
 		t.Errorf("expected no contracts for empty exports, got %d", len(contracts))
 	}
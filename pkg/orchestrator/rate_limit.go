@@ -0,0 +1,144 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// rateLimitRetryDelay is the backoff before the first rate-limit retry;
+// each later retry doubles it. Tests set it to zero.
+var rateLimitRetryDelay = 30 * time.Second
+
+// maxRateLimitBackoff caps the delay rateLimitBackoff returns, so a large
+// Claude.MaxRateLimitRetries cannot overflow the doubling or stall a run
+// for hours.
+const maxRateLimitBackoff = 10 * time.Minute
+
+// rateLimitMarkers are the substrings, matched case-insensitively, that
+// identify a rate-limit or overload failure in a Claude error message or
+// error result event.
+var rateLimitMarkers = []string{
+	"rate limit",
+	"rate_limit",
+	"overloaded",
+	"api returned 429",
+	"api returned 529",
+}
+
+// runWithRateLimitRetry runs backend with a fresh max-time context per
+// attempt and re-invokes it while the run ends rate limited, up to
+// Claude.MaxRateLimitRetries times (GH-531). Token counts and cost are
+// summed across attempts; RawOutput and the error come from the last
+// attempt so downstream parsing only sees one run's output.
+func (o *Orchestrator) runWithRateLimitRetry(backend ClaudeBackend, prompt string, timeout time.Duration, extraClaudeArgs []string) (ClaudeResult, error) {
+	maxRetries := o.cfg.Claude.MaxRateLimitRetries
+	var total ClaudeResult
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(o.runContext(), timeout)
+		result, err := backend.Run(ctx, prompt, extraClaudeArgs)
+		cancel()
+		total = addClaudeResults(total, result)
+		if o.interrupted() {
+			if err == nil {
+				err = errInterrupted
+			}
+			logf("runClaude: interrupted on attempt %d (err=%v)", attempt+1, err)
+			return total, fmt.Errorf("claude interrupted: %w", err)
+		}
+		if attempt >= maxRetries || !isRateLimited(result.RawOutput, err) {
+			if attempt > 0 {
+				logf("runClaude: finished after %d attempt(s); summed in=%d out=%d cost=$%.4f (err=%v)",
+					attempt+1, total.InputTokens, total.OutputTokens, total.CostUSD, err)
+			}
+			return total, err
+		}
+		delay := rateLimitBackoff(attempt)
+		logf("runClaude: rate limited on attempt %d/%d (err=%v); retrying in %s",
+			attempt+1, maxRetries+1, err, delay.Round(time.Second))
+		select {
+		case <-time.After(delay):
+		case <-o.runContext().Done():
+		}
+		if o.interrupted() {
+			logf("runClaude: interrupted during rate-limit backoff after attempt %d", attempt+1)
+			return total, fmt.Errorf("claude interrupted: %w", errInterrupted)
+		}
+	}
+}
+
+// addClaudeResults adds the usage of next to total and takes the
+// session, raw output, and other per-run fields from next.
+func addClaudeResults(total, next ClaudeResult) ClaudeResult {
+	next.InputTokens += total.InputTokens
+	next.OutputTokens += total.OutputTokens
+	next.CacheCreationTokens += total.CacheCreationTokens
+	next.CacheReadTokens += total.CacheReadTokens
+	next.CostUSD += total.CostUSD
+	next.NumTurns += total.NumTurns
+	next.DurationAPIMs += total.DurationAPIMs
+	return next
+}
+
+// rateLimitBackoff returns the delay before retry attempt+1: the base
+// delay doubled per attempt plus up to 50% random jitter, so parallel
+// workers do not retry in lockstep, capped at maxRateLimitBackoff.
+func rateLimitBackoff(attempt int) time.Duration {
+	d := rateLimitRetryDelay
+	if d <= 0 {
+		return 0
+	}
+	for i := 0; i < attempt && d < maxRateLimitBackoff; i++ {
+		d *= 2
+	}
+	return min(d+rand.N(d/2+1), maxRateLimitBackoff)
+}
+
+// isRateLimited reports whether a Claude run ended on a rate-limit or
+// overload failure: a retryable *ClaudeError, a stream-json result event
+// that is an error whose text names one, or a run error whose message
+// does. Other output lines are not inspected: the informational
+// rate_limit_event stream line precedes many runs that then time out or
+// are killed for unrelated reasons. Successful runs are never treated as
+// rate limited.
+func isRateLimited(rawOutput []byte, err error) bool {
+	var ce *ClaudeError
+	if errors.As(err, &ce) {
+		return ce.Retryable()
+	}
+	lines := bytes.Split(bytes.TrimSpace(rawOutput), []byte("\n"))
+	for i := len(lines) - 1; i >= 0; i-- {
+		var ev struct {
+			Type    string `json:"type"`
+			IsError bool   `json:"is_error"`
+			Result  string `json:"result"`
+		}
+		if json.Unmarshal(lines[i], &ev) != nil || ev.Type != "result" {
+			continue
+		}
+		if ev.IsError && hasRateLimitMarker(ev.Result) {
+			return true
+		}
+		break
+	}
+	return err != nil && hasRateLimitMarker(err.Error())
+}
+
+// hasRateLimitMarker reports whether s contains any rateLimitMarkers.
+func hasRateLimitMarker(s string) bool {
+	s = strings.ToLower(s)
+	for _, m := range rateLimitMarkers {
+		if strings.Contains(s, m) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// scriptedBackend returns one scripted result per Run call.
+type scriptedBackend struct {
+	results []ClaudeResult
+	errs    []error
+	calls   int
+}
+
+func (b *scriptedBackend) Run(ctx context.Context, prompt string, extraArgs []string) (ClaudeResult, error) {
+	i := b.calls
+	b.calls++
+	return b.results[i], b.errs[i]
+}
+
+var rateLimitedOutput = []byte(`{"type":"rate_limit_event"}
+{"type":"result","is_error":true,"result":"API Error: Rate limit reached"}`)
+
+func TestIsRateLimited(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		raw  string
+		err  error
+		want bool
+	}{
+		{"error result", string(rateLimitedOutput), nil, true},
+		{"overloaded result", `{"type":"result","is_error":true,"result":"Overloaded"}`, errors.New("exit status 1"), true},
+		{"successful result mentioning rate limits", `{"type":"result","is_error":false,"result":"added rate limit docs"}`, nil, false},
+		{"exit error with message", "", errors.New("API returned 429: too many requests"), true},
+		{"timeout after rate_limit_event", `{"type":"rate_limit_event"}`, errors.New("claude max time exceeded (5m0s)"), false},
+		{"exit error with non-result final line", "claude: overloaded_error", errors.New("exit status 1"), false},
+		{"timeout", "", errors.New("claude max time exceeded (5m0s)"), false},
+		{"success", `{"type":"result","result":"ok"}`, nil, false},
+	}
+	for _, tc := range cases {
+		if got := isRateLimited([]byte(tc.raw), tc.err); got != tc.want {
+			t.Errorf("%s: isRateLimited = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRunWithRateLimitRetry_SumsTokensAcrossAttempts(t *testing.T) {
+	orig := rateLimitRetryDelay
+	rateLimitRetryDelay = 0
+	defer func() { rateLimitRetryDelay = orig }()
+
+	b := &scriptedBackend{
+		results: []ClaudeResult{
+			{InputTokens: 10, OutputTokens: 1, CostUSD: 0.5, RawOutput: rateLimitedOutput},
+			{InputTokens: 20, OutputTokens: 2, CostUSD: 1, RawOutput: []byte(`{"type":"result","result":"ok"}`)},
+		},
+		errs: []error{errors.New("exit status 1"), nil},
+	}
+	o := &Orchestrator{cfg: Config{Claude: ClaudeConfig{MaxRateLimitRetries: 3}}}
+
+	res, err := o.runWithRateLimitRetry(b, "p", time.Minute, nil)
+	if err != nil {
+		t.Fatalf("runWithRateLimitRetry: %v", err)
+	}
+	if b.calls != 2 {
+		t.Errorf("calls = %d, want 2", b.calls)
+	}
+	if res.InputTokens != 30 || res.OutputTokens != 3 || res.CostUSD != 1.5 {
+		t.Errorf("summed result = %+v, want in=30 out=3 cost=1.5", res)
+	}
+	if string(res.RawOutput) != `{"type":"result","result":"ok"}` {
+		t.Errorf("RawOutput = %q, want last attempt's output", res.RawOutput)
+	}
+}
+
+func TestRunWithRateLimitRetry_StopsAtMaxRetries(t *testing.T) {
+	orig := rateLimitRetryDelay
+	rateLimitRetryDelay = 0
+	defer func() { rateLimitRetryDelay = orig }()
+
+	limited := ClaudeResult{InputTokens: 1, RawOutput: rateLimitedOutput}
+	b := &scriptedBackend{
+		results: []ClaudeResult{limited, limited, limited},
+		errs:    []error{nil, nil, nil},
+	}
+	o := &Orchestrator{cfg: Config{Claude: ClaudeConfig{MaxRateLimitRetries: 1}}}
+
+	res, _ := o.runWithRateLimitRetry(b, "p", time.Minute, nil)
+	if b.calls != 2 {
+		t.Errorf("calls = %d, want 2 (1 retry)", b.calls)
+	}
+	if res.InputTokens != 2 {
+		t.Errorf("InputTokens = %d, want 2", res.InputTokens)
+	}
+}
+
+func TestRateLimitBackoff_GrowsWithJitter(t *testing.T) {
+	orig := rateLimitRetryDelay
+	rateLimitRetryDelay = time.Second
+	defer func() { rateLimitRetryDelay = orig }()
+
+	for attempt, base := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		d := rateLimitBackoff(attempt)
+		if d < base || d > base+base/2 {
+			t.Errorf("attempt %d: backoff %s outside [%s, %s]", attempt, d, base, base+base/2)
+		}
+	}
+}
+
+func TestRateLimitBackoff_Capped(t *testing.T) {
+	orig := rateLimitRetryDelay
+	rateLimitRetryDelay = time.Second
+	defer func() { rateLimitRetryDelay = orig }()
+
+	for _, attempt := range []int{20, 40, 100} {
+		if d := rateLimitBackoff(attempt); d != maxRateLimitBackoff {
+			t.Errorf("attempt %d: got %v, want the %v cap", attempt, d, maxRateLimitBackoff)
+		}
+	}
+}
+
+func TestRunWithRateLimitRetry_InterruptDuringBackoff(t *testing.T) {
+	orig := rateLimitRetryDelay
+	rateLimitRetryDelay = time.Hour
+	defer func() { rateLimitRetryDelay = orig }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	limited := ClaudeResult{InputTokens: 1, RawOutput: rateLimitedOutput}
+	b := &scriptedBackend{results: []ClaudeResult{limited, limited}, errs: []error{nil, nil}}
+	o := &Orchestrator{cfg: Config{Claude: ClaudeConfig{MaxRateLimitRetries: 1}}, stop: ctx}
+
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := o.runWithRateLimitRetry(b, "p", time.Minute, nil)
+	if !errors.Is(err, errInterrupted) {
+		t.Errorf("err = %v, want errInterrupted", err)
+	}
+	if b.calls != 1 {
+		t.Errorf("calls = %d, want 1 (no retry after the interrupt)", b.calls)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("backoff not interrupted: returned after %s", elapsed)
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// replayPrompt is one saved prompt found in a history directory.
+type replayPrompt struct {
+	Timestamp string
+	Phase     string
+	Path      string
+}
+
+// replayResult compares a replayed invocation with the original.
+type replayResult struct {
+	Timestamp  string   `yaml:"timestamp"`
+	Phase      string   `yaml:"phase"`
+	Original   []string `yaml:"original"` // stitch: changed files; measure: issue titles
+	Replay     []string `yaml:"replay"`
+	Divergence float64  `yaml:"divergence"` // Jaccard distance between Original and Replay
+	CostUSD    float64  `yaml:"cost_usd"`
+	Error      string   `yaml:"error,omitempty"`
+}
+
+// listReplayPrompts returns the *-prompt.yaml files in dir in timestamp
+// order with the timestamp and phase parsed from their names.
+func listReplayPrompts(dir string) ([]replayPrompt, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*-prompt.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("listing prompts in %s: %w", dir, err)
+	}
+	sort.Strings(matches)
+	prompts := make([]replayPrompt, 0, len(matches))
+	for _, m := range matches {
+		ts, phase := splitHistoryStatsName(strings.TrimSuffix(filepath.Base(m), "-prompt.yaml") + "-stats.yaml")
+		if phase == "" {
+			logf("listReplayPrompts: skipping %s: unrecognised name", m)
+			continue
+		}
+		prompts = append(prompts, replayPrompt{Timestamp: ts, Phase: phase, Path: m})
+	}
+	return prompts, nil
+}
+
+// jaccardDistance returns 1 - |a∩b|/|a∪b| over the distinct elements of a
+// and b: 0 when they match exactly, 1 when they share nothing. Two empty
+// sets have distance 0.
+func jaccardDistance(a, b []string) float64 {
+	set := make(map[string]int)
+	for _, s := range a {
+		set[s] |= 1
+	}
+	for _, s := range b {
+		set[s] |= 2
+	}
+	if len(set) == 0 {
+		return 0
+	}
+	both := 0
+	for _, v := range set {
+		if v == 3 {
+			both++
+		}
+	}
+	return 1 - float64(both)/float64(len(set))
+}
+
+// changedPaths returns the sorted paths of a FileChange list.
+func changedPaths(files []FileChange) []string {
+	paths := make([]string, 0, len(files))
+	for _, f := range files {
+		paths = append(paths, f.Path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// measureTitlesFromLog extracts proposed issue titles from a saved Claude
+// measure log (stream-json or plain text). Returns nil when no YAML list
+// can be extracted.
+func measureTitlesFromLog(raw []byte) []string {
+	yamlContent, err := extractYAMLBlock(extractTextFromStreamJSON(raw))
+	if err != nil {
+		return nil
+	}
+	var issues []proposedIssue
+	if err := yaml.Unmarshal(yamlContent, &issues); err != nil {
+		return nil
+	}
+	titles := make([]string, 0, len(issues))
+	for _, iss := range issues {
+		titles = append(titles, iss.Title)
+	}
+	sort.Strings(titles)
+	return titles
+}
+
+// originalReplayOutcome loads what the original invocation produced: the
+// changed files from the stitch report, or the proposed titles from the
+// measure log.
+func originalReplayOutcome(historyDir string, p replayPrompt) []string {
+	switch p.Phase {
+	case "stitch":
+		data, err := os.ReadFile(filepath.Join(historyDir, p.Timestamp+"-stitch-report.yaml"))
+		if err != nil {
+			return nil
+		}
+		var report StitchReport
+		if err := yaml.Unmarshal(data, &report); err != nil {
+			logf("replay: parse stitch report %s: %v", p.Timestamp, err)
+			return nil
+		}
+		return changedPaths(report.Files)
+	case "measure":
+		data, err := os.ReadFile(filepath.Join(historyDir, p.Timestamp+"-measure-log.log"))
+		if err != nil {
+			return nil
+		}
+		return measureTitlesFromLog(data)
+	}
+	return nil
+}
+
+// ReplayGeneration re-runs every prompt saved in historyDir (default: the
+// configured history directory) through runClaude in timestamp order and
+// reports how far each replay diverges from the original. Each prompt runs
+// in a throwaway detached worktree of the current HEAD, so nothing is
+// committed or merged. Stitch replays compare changed file sets against
+// the original stitch report; measure replays compare proposed issue
+// titles against the original log. Replay logs and a replay-report.yaml
+// are written to a replay-<timestamp> directory inside historyDir.
+//
+// Replays start from the current HEAD rather than each prompt's original
+// base, so divergence combines model nondeterminism with any drift in the
+// tree since the generation ran.
+func (o *Orchestrator) ReplayGeneration(historyDir string) error {
+	if historyDir == "" {
+		historyDir = o.historyDir()
+	}
+	prompts, err := listReplayPrompts(historyDir)
+	if err != nil {
+		return err
+	}
+	if len(prompts) == 0 {
+		fmt.Printf("no saved prompts in %s\n", historyDir)
+		return nil
+	}
+	if err := o.checkClaude(false); err != nil {
+		return err
+	}
+
+	outDir := filepath.Join(historyDir, "replay-"+time.Now().Format("2006-01-02-15-04-05"))
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return fmt.Errorf("creating replay directory: %w", err)
+	}
+	logf("replay: %d prompt(s) from %s, output in %s", len(prompts), historyDir, outDir)
+
+	var results []replayResult
+	for i, p := range prompts {
+		logf("replay: [%d/%d] %s %s", i+1, len(prompts), p.Timestamp, p.Phase)
+		res := o.replayOne(historyDir, outDir, p)
+		results = append(results, res)
+	}
+
+	if data, err := yaml.Marshal(results); err == nil {
+		if err := os.WriteFile(filepath.Join(outDir, "replay-report.yaml"), data, 0o644); err != nil {
+			logf("replay: write report: %v", err)
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Timestamp\tPhase\tOriginal\tReplay\tDivergence\tCost\tError")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%.2f\t$%.2f\t%s\n",
+			r.Timestamp, r.Phase, len(r.Original), len(r.Replay), r.Divergence, r.CostUSD, r.Error)
+	}
+	return w.Flush()
+}
+
+// replayOne replays a single saved prompt in a throwaway worktree and
+// compares its outcome with the original.
+func (o *Orchestrator) replayOne(historyDir, outDir string, p replayPrompt) replayResult {
+	res := replayResult{Timestamp: p.Timestamp, Phase: p.Phase, Original: originalReplayOutcome(historyDir, p)}
+
+	prompt, err := os.ReadFile(p.Path)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	wt, err := os.MkdirTemp("", "cobbler-replay-*")
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	os.Remove(wt) // git worktree add creates the directory itself
+	if out, err := cmdGit(".", "worktree", "add", "--detach", wt, "HEAD").CombinedOutput(); err != nil {
+		res.Error = fmt.Sprintf("worktree add: %v: %s", err, strings.TrimSpace(string(out)))
+		return res
+	}
+	defer func() {
+		if err := gitWorktreeRemove(wt, "."); err != nil {
+			logf("replay: worktree remove warning for %s: %v", wt, err)
+		}
+	}()
+
+	var extra []string
+	if p.Phase == "measure" {
+		extra = append([]string{"--max-turns", "1"}, modelArgs(o.cfg.Claude.MeasureModel)...)
+	} else {
+		extra = modelArgs(o.cfg.Claude.StitchModel)
+	}
+	result, claudeErr := o.runClaude(string(prompt), wt, o.cfg.Silence(), extra...)
+	res.CostUSD = result.CostUSD
+	if err := os.WriteFile(filepath.Join(outDir, p.Timestamp+"-"+p.Phase+"-log.log"), result.RawOutput, 0o644); err != nil {
+		logf("replay: write log: %v", err)
+	}
+	if claudeErr != nil {
+		res.Error = claudeErr.Error()
+	}
+
+	switch p.Phase {
+	case "stitch":
+		if err := cmdGit(wt, "add", "-A").Run(); err != nil {
+			logf("replay: git add in %s: %v", wt, err)
+		}
+		files, err := gitDiffNameStatus("HEAD", wt)
+		if err != nil {
+			logf("replay: diff in %s: %v", wt, err)
+		}
+		res.Replay = changedPaths(files)
+	case "measure":
+		res.Replay = measureTitlesFromLog(result.RawOutput)
+	}
+	res.Divergence = jaccardDistance(res.Original, res.Replay)
+	return res
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestJaccardDistance(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		a, b []string
+		want float64
+	}{
+		{nil, nil, 0},
+		{[]string{"a", "b"}, []string{"b", "a"}, 0},
+		{[]string{"a"}, []string{"b"}, 1},
+		{[]string{"a", "b", "c"}, []string{"b", "c", "d"}, 0.5},
+	}
+	for _, tc := range cases {
+		if got := jaccardDistance(tc.a, tc.b); got != tc.want {
+			t.Errorf("jaccardDistance(%v, %v) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestListReplayPrompts_OrderAndPhase(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	for _, name := range []string{
+		"2026-02-10-15-05-00-stitch-prompt.yaml",
+		"2026-02-10-15-04-00-measure-prompt.yaml",
+		"2026-02-10-15-04-00-measure-stats.yaml",
+		"odd-prompt.yaml",
+	} {
+		os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o644)
+	}
+	got, err := listReplayPrompts(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Phase != "measure" || got[1].Phase != "stitch" {
+		t.Errorf("listReplayPrompts = %+v", got)
+	}
+}
+
+func TestOriginalReplayOutcome_Stitch(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	data, _ := yaml.Marshal(StitchReport{Files: []FileChange{{Path: "b.go"}, {Path: "a.go"}}})
+	os.WriteFile(filepath.Join(dir, "2026-02-10-15-05-00-stitch-report.yaml"), data, 0o644)
+
+	got := originalReplayOutcome(dir, replayPrompt{Timestamp: "2026-02-10-15-05-00", Phase: "stitch"})
+	if !reflect.DeepEqual(got, []string{"a.go", "b.go"}) {
+		t.Errorf("originalReplayOutcome = %v", got)
+	}
+}
+
+func TestMeasureTitlesFromLog(t *testing.T) {
+	t.Parallel()
+	raw := []byte("```yaml\n- index: 0\n  title: Second\n- index: 1\n  title: First\n```\n")
+	if got := measureTitlesFromLog(raw); !reflect.DeepEqual(got, []string{"First", "Second"}) {
+		t.Errorf("measureTitlesFromLog = %v", got)
+	}
+}
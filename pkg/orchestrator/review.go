@@ -0,0 +1,181 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// reviewBranchName returns the branch a deferred task is parked on while it
+// awaits review. It deliberately does not match taskBranchPattern so that
+// recoverStaleTasks never deletes work that is waiting for a human.
+func reviewBranchName(baseBranch, issueID string) string {
+	return "review/" + baseBranch + "-" + issueID
+}
+
+// reviewBranchPattern returns the glob pattern for listing review branches.
+func reviewBranchPattern(baseBranch string) string {
+	return "review/" + baseBranch + "-*"
+}
+
+// deferTaskMerge parks a committed task for review instead of merging it
+// (Cobbler.DeferMerge, GH-465). It computes the diff of the task branch
+// against baseBranch, then renames the task branch to its review branch.
+// The worktree is left in place so reviewers can inspect and build it.
+func deferTaskMerge(task stitchTask, baseBranch string) (diffStat, []FileChange, error) {
+	diff, err := gitDiffShortstat(baseBranch, task.worktreeDir)
+	if err != nil {
+		logf("deferTaskMerge: warning getting diff shortstat: %v", err)
+	}
+	fileChanges, err := gitDiffNameStatus(baseBranch, task.worktreeDir)
+	if err != nil {
+		logf("deferTaskMerge: warning getting file changes: %v", err)
+	}
+
+	reviewBranch := reviewBranchName(baseBranch, task.id)
+	logf("deferTaskMerge: renaming %s to %s", task.branchName, reviewBranch)
+	if err := gitRenameBranch(task.branchName, reviewBranch, "."); err != nil {
+		return diff, fileChanges, fmt.Errorf("renaming %s to %s: %w", task.branchName, reviewBranch, err)
+	}
+	return diff, fileChanges, nil
+}
+
+// markTaskForReview posts the stitch metrics on the task issue and moves it
+// from in-progress to review. The issue stays open so its dependents remain
+// blocked until AcceptTask merges the work.
+func (o *Orchestrator) markTaskForReview(task stitchTask, rec InvocationRecord) {
+	logf("markTaskForReview: #%d %q on %s", task.ghNumber, task.title, task.branchName)
+	comment := fmt.Sprintf(
+		"Stitch completed in %dm %ds and is awaiting review on branch `%s`. LOC delta: %+d prod, %+d test. Cost: $%.2f. Turns: %d. Run `mage cobbler:accept %s` to merge.",
+		rec.DurationS/60, rec.DurationS%60,
+		task.branchName,
+		rec.LOCAfter.Production-rec.LOCBefore.Production,
+		rec.LOCAfter.Test-rec.LOCBefore.Test,
+		rec.Tokens.CostUSD,
+		rec.NumTurns,
+		task.id,
+	)
+	commentCobblerIssue(task.repo, task.ghNumber, comment)
+	if err := addIssueLabel(task.repo, task.ghNumber, cobblerLabelReview); err != nil {
+		logf("markTaskForReview: add review label to #%d: %v", task.ghNumber, err)
+	}
+	if err := removeInProgressLabel(task.repo, task.ghNumber); err != nil {
+		logf("markTaskForReview: remove in-progress label from #%d: %v", task.ghNumber, err)
+	}
+}
+
+// reviewContext resolves the generation branch, switches to it, and returns
+// it together with the GitHub repo and the repository root. Shared by
+// ListForReview and AcceptTask.
+func (o *Orchestrator) reviewContext() (branch, repo, repoRoot string, err error) {
+	branch, err = o.resolveBranch(o.cfg.Generation.Branch)
+	if err != nil {
+		return "", "", "", err
+	}
+	if err := ensureOnBranch(branch); err != nil {
+		return "", "", "", fmt.Errorf("switching to branch: %w", err)
+	}
+	repoRoot, err = os.Getwd()
+	if err != nil {
+		return "", "", "", fmt.Errorf("getting working directory: %w", err)
+	}
+	repo, err = detectGitHubRepo(repoRoot, o.cfg)
+	if err != nil {
+		return "", "", "", fmt.Errorf("detecting GitHub repo: %w", err)
+	}
+	return branch, repo, repoRoot, nil
+}
+
+// ListForReview prints the stitched tasks of the current generation that
+// are awaiting review, with their review branch. Tasks are parked there
+// when Cobbler.DeferMerge is enabled.
+func (o *Orchestrator) ListForReview() error {
+	branch, repo, _, err := o.reviewContext()
+	if err != nil {
+		return err
+	}
+
+	issues, err := listOpenCobblerIssues(repo, branch)
+	if err != nil {
+		return fmt.Errorf("listing issues: %w", err)
+	}
+	var review []cobblerIssue
+	for _, iss := range issues {
+		if hasLabel(iss, cobblerLabelReview) {
+			review = append(review, iss)
+		}
+	}
+	sort.Slice(review, func(i, j int) bool { return review[i].Number < review[j].Number })
+
+	branches := make(map[string]bool)
+	for _, b := range gitListBranches(reviewBranchPattern(branch), ".") {
+		branches[b] = true
+	}
+
+	if len(review) == 0 {
+		fmt.Println("no tasks awaiting review")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Issue\tBranch\tTitle")
+	for _, iss := range review {
+		rb := reviewBranchName(branch, fmt.Sprintf("%d", iss.Number))
+		if !branches[rb] {
+			rb += " (missing)"
+		}
+		fmt.Fprintf(w, "#%d\t%s\t%s\n", iss.Number, rb, strings.TrimPrefix(iss.Title, "[measure] "))
+	}
+	return w.Flush()
+}
+
+// AcceptTask performs the deferred merge for a task awaiting review: it
+// merges the review branch into the generation branch, removes the task
+// worktree and branch, and closes the issue so its dependents become ready.
+// id is the GitHub issue number of the task.
+func (o *Orchestrator) AcceptTask(id string) error {
+	var num int
+	if _, err := fmt.Sscanf(id, "%d", &num); err != nil || num <= 0 {
+		return fmt.Errorf("invalid task id %q: want a GitHub issue number", id)
+	}
+	id = fmt.Sprintf("%d", num)
+
+	branch, repo, repoRoot, err := o.reviewContext()
+	if err != nil {
+		return err
+	}
+	reviewBranch := reviewBranchName(branch, id)
+	if !gitBranchExists(reviewBranch, ".") {
+		return fmt.Errorf("no review branch %s for task %s", reviewBranch, id)
+	}
+
+	logf("acceptTask: merging %s into %s", reviewBranch, branch)
+	if err := mergeBranch(reviewBranch, branch, repoRoot); err != nil {
+		return fmt.Errorf("accepting task %s: %w", id, err)
+	}
+
+	worktreeDir := filepath.Join(worktreeBasePath(o.cfg.Cobbler.WorktreeDir), id)
+	if _, err := os.Stat(worktreeDir); err == nil {
+		if err := gitWorktreeRemove(worktreeDir, "."); err != nil {
+			logf("acceptTask: worktree remove warning for %s: %v", worktreeDir, err)
+		}
+	}
+	if err := gitDeleteBranch(reviewBranch, "."); err != nil {
+		logf("acceptTask: branch delete warning for %s: %v", reviewBranch, err)
+	}
+
+	if err := removeIssueLabel(repo, num, cobblerLabelReview); err != nil {
+		logf("acceptTask: remove review label from #%d: %v", num, err)
+	}
+	commentCobblerIssue(repo, num, fmt.Sprintf("Review accepted; `%s` merged into `%s`.", reviewBranch, branch))
+	if err := closeCobblerIssue(repo, num, branch); err != nil {
+		return fmt.Errorf("closing task %s: %w", id, err)
+	}
+	logf("acceptTask: task %s merged and closed", id)
+	return nil
+}
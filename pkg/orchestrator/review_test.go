@@ -0,0 +1,77 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReviewBranchName_NotMatchedByTaskPattern(t *testing.T) {
+	t.Parallel()
+	rb := reviewBranchName("generation-x", "42")
+	if rb != "review/generation-x-42" {
+		t.Errorf("reviewBranchName = %q", rb)
+	}
+	if matched, _ := filepath.Match(taskBranchPattern("generation-x"), rb); matched {
+		t.Errorf("review branch %q must not match the stale task pattern", rb)
+	}
+	if matched, _ := filepath.Match(reviewBranchPattern("generation-x"), rb); !matched {
+		t.Errorf("review branch %q should match reviewBranchPattern", rb)
+	}
+}
+
+func TestDeferTaskMerge_RenamesBranchAndKeepsWorktree(t *testing.T) {
+	// Not parallel: initTestGitRepo changes the working directory.
+	dir := initTestGitRepo(t)
+	task := stitchTask{
+		id:          "7",
+		branchName:  taskBranchName("main", "7"),
+		worktreeDir: filepath.Join(t.TempDir(), "7"),
+	}
+	if err := createWorktree(task); err != nil {
+		t.Fatalf("createWorktree: %v", err)
+	}
+	os.WriteFile(filepath.Join(task.worktreeDir, "a.go"), []byte("package a\n"), 0o644)
+	for _, args := range [][]string{{"add", "-A"}, {"commit", "-m", "task"}} {
+		if out, err := exec.Command("git", append([]string{"-C", task.worktreeDir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	diff, files, err := deferTaskMerge(task, "main")
+	if err != nil {
+		t.Fatalf("deferTaskMerge: %v", err)
+	}
+	if diff.FilesChanged != 1 || len(files) != 1 {
+		t.Errorf("diff = %+v files = %v, want one changed file", diff, files)
+	}
+	if gitBranchExists(task.branchName, dir) {
+		t.Errorf("task branch %s should have been renamed", task.branchName)
+	}
+	if !gitBranchExists(reviewBranchName("main", "7"), dir) {
+		t.Error("review branch missing")
+	}
+	if _, err := os.Stat(task.worktreeDir); err != nil {
+		t.Errorf("worktree should be kept for review: %v", err)
+	}
+	// The base branch must not contain the task's work.
+	if _, err := os.Stat(filepath.Join(dir, "a.go")); !os.IsNotExist(err) {
+		t.Error("deferred task must not be merged into the base branch")
+	}
+	if out, _ := exec.Command("git", "-C", task.worktreeDir, "rev-parse", "--abbrev-ref", "HEAD").Output(); strings.TrimSpace(string(out)) != reviewBranchName("main", "7") {
+		t.Errorf("worktree HEAD = %q, want review branch", strings.TrimSpace(string(out)))
+	}
+}
+
+func TestAcceptTask_InvalidID(t *testing.T) {
+	t.Parallel()
+	o := &Orchestrator{}
+	if err := o.AcceptTask("abc"); err == nil || !strings.Contains(err.Error(), "invalid task id") {
+		t.Errorf("expected invalid task id error, got %v", err)
+	}
+}
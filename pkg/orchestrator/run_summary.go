@@ -0,0 +1,201 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// runSummaryFile is the report RunCycles writes to the history directory
+// when it finishes (GH-538).
+const runSummaryFile = "run-summary.yaml"
+
+// RunSummary is the consolidated report of one RunCycles invocation. Token,
+// cost, and task figures cover only the history files written during the
+// run.
+type RunSummary struct {
+	GenerationBranch string          `yaml:"generation_branch"`
+	BaseBranch       string          `yaml:"base_branch"`
+	StartedAt        string          `yaml:"started_at"`
+	FinishedAt       string          `yaml:"finished_at"`
+	Cycles           int             `yaml:"cycles"`
+	IssuesMeasured   int             `yaml:"issues_measured"`
+	TasksStitched    int             `yaml:"tasks_stitched"`
+	TasksFailed      int             `yaml:"tasks_failed"`
+	Tokens           historyTokens   `yaml:"tokens"`
+	CostUSD          float64         `yaml:"cost_usd"`
+	LOCDelta         RunLOCDelta     `yaml:"loc_delta"`
+	TaskDurationS    RunDurationDist `yaml:"task_duration_s"`
+
+	// MeasurePromptBytes and StitchPromptBytes track prompt size across
+	// the run, to spot context growth (GH-561).
+	MeasurePromptBytes RunPromptGrowth `yaml:"measure_prompt_bytes"`
+	StitchPromptBytes  RunPromptGrowth `yaml:"stitch_prompt_bytes"`
+}
+
+// RunPromptGrowth summarises the prompt sizes recorded in one phase's
+// history stats, in timestamp order. Entries without a recorded size are
+// ignored. BudgetHits counts the prompts whose context the budget trimmed;
+// a hit on every prompt usually means the budget is too low.
+type RunPromptGrowth struct {
+	First      int `yaml:"first"`
+	Last       int `yaml:"last"`
+	Max        int `yaml:"max"`
+	BudgetHits int `yaml:"budget_hits"`
+}
+
+// add folds one entry's prompt size and context trim count into g.
+func (g *RunPromptGrowth) add(bytes, trimmed int) {
+	if trimmed > 0 {
+		g.BudgetHits++
+	}
+	if bytes <= 0 {
+		return
+	}
+	if g.First == 0 {
+		g.First = bytes
+	}
+	g.Last = bytes
+	g.Max = max(g.Max, bytes)
+}
+
+// RunLOCDelta is the net change in lines of code across successful stitches.
+type RunLOCDelta struct {
+	Production int `yaml:"production"`
+	Test       int `yaml:"test"`
+}
+
+// RunDurationDist holds nearest-rank percentiles of successful stitch
+// durations in seconds.
+type RunDurationDist struct {
+	P50 int `yaml:"p50"`
+	P90 int `yaml:"p90"`
+	Max int `yaml:"max"`
+}
+
+// buildRunSummary aggregates the history entries whose timestamp is at or
+// after since. issuesMeasured is counted separately from the measure
+// issues files because the stats files do not record it.
+func buildRunSummary(entries []historyStatsEntry, since time.Time, issuesMeasured int) RunSummary {
+	cutoff := since.Format(historyTimestampLayout)
+	s := RunSummary{IssuesMeasured: issuesMeasured}
+	var durations []int
+	for _, e := range entries {
+		if e.Timestamp < cutoff {
+			continue
+		}
+		s.Tokens.Input += e.Stats.Tokens.Input
+		s.Tokens.Output += e.Stats.Tokens.Output
+		s.Tokens.CacheCreation += e.Stats.Tokens.CacheCreation
+		s.Tokens.CacheRead += e.Stats.Tokens.CacheRead
+		s.CostUSD += e.Stats.CostUSD
+		switch e.Phase {
+		case "measure":
+			s.MeasurePromptBytes.add(e.Stats.PromptBytes, e.Stats.ContextTrimmed)
+		case "stitch":
+			s.StitchPromptBytes.add(e.Stats.PromptBytes, e.Stats.ContextTrimmed)
+		}
+		if e.Phase != "stitch" {
+			continue
+		}
+		if e.Stats.Status != "success" {
+			s.TasksFailed++
+			continue
+		}
+		s.TasksStitched++
+		s.LOCDelta.Production += e.Stats.LOCAfter.Production - e.Stats.LOCBefore.Production
+		s.LOCDelta.Test += e.Stats.LOCAfter.Test - e.Stats.LOCBefore.Test
+		durations = append(durations, e.Stats.DurationS)
+	}
+	sort.Ints(durations)
+	s.TaskDurationS = RunDurationDist{
+		P50: percentileInt(durations, 50),
+		P90: percentileInt(durations, 90),
+		Max: percentileInt(durations, 100),
+	}
+	return s
+}
+
+// percentileInt returns the nearest-rank p-th percentile of sorted, or 0
+// when sorted is empty.
+func percentileInt(sorted []int, p int) int {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// countMeasuredIssues sums the issues in the {ts}-measure-issues.yaml
+// files in dir whose timestamp is at or after since.
+func countMeasuredIssues(dir string, since time.Time) int {
+	if dir == "" {
+		return 0
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, "*-measure-issues.yaml"))
+	if err != nil {
+		logf("countMeasuredIssues: glob %s: %v", dir, err)
+		return 0
+	}
+	cutoff := since.Format(historyTimestampLayout)
+	total := 0
+	for _, path := range matches {
+		if strings.TrimSuffix(filepath.Base(path), "-measure-issues.yaml") < cutoff {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logf("countMeasuredIssues: read %s: %v", path, err)
+			continue
+		}
+		var issues []proposedIssue
+		if err := yaml.Unmarshal(data, &issues); err != nil {
+			logf("countMeasuredIssues: parse %s: %v", path, err)
+			continue
+		}
+		total += len(issues)
+	}
+	return total
+}
+
+// writeRunSummary writes run-summary.yaml to the history directory,
+// summarising the history written since start. It is best-effort and a
+// no-op when HistoryDir is empty.
+func (o *Orchestrator) writeRunSummary(generation string, start time.Time, cycles int) {
+	dir := o.historyDir()
+	if dir == "" {
+		return
+	}
+	s := buildRunSummary(loadHistoryStats(dir), start, countMeasuredIssues(dir, start))
+	s.GenerationBranch = generation
+	s.BaseBranch = o.readBaseBranch()
+	s.StartedAt = start.UTC().Format(time.RFC3339)
+	s.FinishedAt = time.Now().UTC().Format(time.RFC3339)
+	s.Cycles = cycles
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		logf("writeRunSummary: mkdir %s: %v", dir, err)
+		return
+	}
+	data, err := yaml.Marshal(&s)
+	if err != nil {
+		logf("writeRunSummary: marshal: %v", err)
+		return
+	}
+	path := filepath.Join(dir, runSummaryFile)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		logf("writeRunSummary: write %s: %v", path, err)
+		return
+	}
+	logf("writeRunSummary: saved %s (cycles=%d stitched=%d cost=$%.2f)", path, s.Cycles, s.TasksStitched, s.CostUSD)
+}
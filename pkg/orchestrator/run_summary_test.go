@@ -0,0 +1,127 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestBuildRunSummary_AggregatesEntriesSinceStart(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.Local)
+	stitch := func(ts, status string, dur, prod, test int) historyStatsEntry {
+		return historyStatsEntry{Timestamp: ts, Phase: "stitch", Stats: HistoryStats{
+			Status:    status,
+			DurationS: dur,
+			Tokens:    historyTokens{Input: 10, Output: 2, CacheCreation: 1, CacheRead: 3},
+			CostUSD:   0.5,
+			LOCBefore: LocSnapshot{Production: 100, Test: 50},
+			LOCAfter:  LocSnapshot{Production: 100 + prod, Test: 50 + test},
+
+			PromptBytes: 1000 + dur,
+		}}
+	}
+	entries := []historyStatsEntry{
+		stitch("2026-03-01-09-59-59", "success", 999, 1000, 1000), // before the run
+		{Timestamp: "2026-03-01-10-00-00", Phase: "measure", Stats: HistoryStats{
+			Status: "success", Tokens: historyTokens{Input: 100}, CostUSD: 1, ContextTrimmed: 2,
+		}},
+		stitch("2026-03-01-10-01-00", "success", 30, 20, 10),
+		stitch("2026-03-01-10-02-00", "failed", 5, 0, 0),
+		stitch("2026-03-01-10-03-00", "success", 10, -5, 4),
+		stitch("2026-03-01-10-04-00", "success", 60, 1, 0),
+	}
+
+	s := buildRunSummary(entries, start, 7)
+	if s.IssuesMeasured != 7 || s.TasksStitched != 3 || s.TasksFailed != 1 {
+		t.Errorf("counts = measured %d stitched %d failed %d, want 7/3/1", s.IssuesMeasured, s.TasksStitched, s.TasksFailed)
+	}
+	if want := (historyTokens{Input: 140, Output: 8, CacheCreation: 4, CacheRead: 12}); s.Tokens != want {
+		t.Errorf("Tokens = %+v, want %+v", s.Tokens, want)
+	}
+	if s.CostUSD != 3 {
+		t.Errorf("CostUSD = %v, want 3", s.CostUSD)
+	}
+	if want := (RunLOCDelta{Production: 16, Test: 14}); s.LOCDelta != want {
+		t.Errorf("LOCDelta = %+v, want %+v", s.LOCDelta, want)
+	}
+	if want := (RunDurationDist{P50: 30, P90: 60, Max: 60}); s.TaskDurationS != want {
+		t.Errorf("TaskDurationS = %+v, want %+v", s.TaskDurationS, want)
+	}
+	if want := (RunPromptGrowth{First: 1030, Last: 1060, Max: 1060}); s.StitchPromptBytes != want {
+		t.Errorf("StitchPromptBytes = %+v, want %+v", s.StitchPromptBytes, want)
+	}
+	if want := (RunPromptGrowth{BudgetHits: 1}); s.MeasurePromptBytes != want {
+		t.Errorf("MeasurePromptBytes = %+v, want %+v (no sizes recorded, one budget hit)", s.MeasurePromptBytes, want)
+	}
+}
+
+func TestPercentileInt(t *testing.T) {
+	t.Parallel()
+	if got := percentileInt(nil, 50); got != 0 {
+		t.Errorf("percentileInt(nil) = %d, want 0", got)
+	}
+	sorted := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	for p, want := range map[int]int{0: 1, 50: 5, 90: 9, 100: 10} {
+		if got := percentileInt(sorted, p); got != want {
+			t.Errorf("percentileInt(p=%d) = %d, want %d", p, got, want)
+		}
+	}
+}
+
+func TestWriteRunSummary_WritesReport(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	hist := filepath.Join(dir, "history")
+	if err := os.MkdirAll(hist, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().Add(-time.Minute)
+	ts := time.Now().Format(historyTimestampLayout)
+	stats, _ := yaml.Marshal(HistoryStats{Caller: "stitch", Status: "success", DurationS: 12, CostUSD: 0.25})
+	issues, _ := yaml.Marshal([]proposedIssue{{Title: "a"}, {Title: "b"}})
+	old := start.Add(-time.Hour).Format(historyTimestampLayout)
+	for name, data := range map[string][]byte{
+		ts + "-stitch-stats.yaml":    stats,
+		ts + "-measure-issues.yaml":  issues,
+		old + "-measure-issues.yaml": issues,
+	} {
+		if err := os.WriteFile(filepath.Join(hist, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	o := &Orchestrator{cfg: Config{Cobbler: CobblerConfig{Dir: dir, HistoryDir: "history"}}}
+	o.writeRunSummary("generation-x", start, 2)
+
+	data, err := os.ReadFile(filepath.Join(hist, runSummaryFile))
+	if err != nil {
+		t.Fatalf("reading summary: %v", err)
+	}
+	var s RunSummary
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		t.Fatalf("parsing summary: %v", err)
+	}
+	if s.GenerationBranch != "generation-x" || s.BaseBranch != "main" || s.Cycles != 2 {
+		t.Errorf("branches/cycles = %q %q %d", s.GenerationBranch, s.BaseBranch, s.Cycles)
+	}
+	if s.IssuesMeasured != 2 || s.TasksStitched != 1 || s.CostUSD != 0.25 || s.TaskDurationS.Max != 12 {
+		t.Errorf("summary = %+v", s)
+	}
+}
+
+func TestWriteRunSummary_NoHistoryDirIsNoop(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	o := &Orchestrator{cfg: Config{Cobbler: CobblerConfig{Dir: dir}}}
+	o.writeRunSummary("g", time.Now(), 1)
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("expected no files, got %d", len(entries))
+	}
+}
@@ -35,6 +35,13 @@ const orchestratorModule = "github.com/mesh-intelligence/cobbler-scaffold"
 func (o *Orchestrator) Scaffold(targetDir, orchestratorRoot string) error {
 	logf("scaffold: targetDir=%s orchestratorRoot=%s", targetDir, orchestratorRoot)
 
+	// Detect project structure first so a target without go.mod fails
+	// before anything is written.
+	cfg, err := detectScaffoldConfig(targetDir)
+	if err != nil {
+		return err
+	}
+
 	mageDir := filepath.Join(targetDir, dirMagefiles)
 
 	// 1. Remove existing .go files in magefiles/ (the orchestrator
@@ -56,13 +63,7 @@ func (o *Orchestrator) Scaffold(targetDir, orchestratorRoot string) error {
 	if err := os.MkdirAll(constitutionsDir, 0o755); err != nil {
 		return fmt.Errorf("creating docs/constitutions directory: %w", err)
 	}
-	constitutionFiles := map[string]string{
-		"design.yaml":    designConstitution,
-		"planning.yaml":  planningConstitution,
-		"execution.yaml": executionConstitution,
-		"go-style.yaml":  goStyleConstitution,
-		"testing.yaml":   testingConstitution,
-	}
+	constitutionFiles := scaffoldConstitutionFiles()
 	for _, name := range slices.Sorted(maps.Keys(constitutionFiles)) {
 		p := filepath.Join(constitutionsDir, name)
 		logf("scaffold: writing constitution to %s", p)
@@ -77,10 +78,7 @@ func (o *Orchestrator) Scaffold(targetDir, orchestratorRoot string) error {
 	if err := os.MkdirAll(promptsDir, 0o755); err != nil {
 		return fmt.Errorf("creating docs/prompts directory: %w", err)
 	}
-	promptFiles := map[string]string{
-		"measure.yaml": defaultMeasurePrompt,
-		"stitch.yaml":  defaultStitchPrompt,
-	}
+	promptFiles := scaffoldPromptFiles()
 	for _, name := range slices.Sorted(maps.Keys(promptFiles)) {
 		p := filepath.Join(promptsDir, name)
 		logf("scaffold: writing prompt to %s", p)
@@ -107,49 +105,14 @@ func (o *Orchestrator) Scaffold(targetDir, orchestratorRoot string) error {
 		}
 	}
 
-	// 2. Detect project structure.
-	modulePath, err := detectModulePath(targetDir)
-	if err != nil {
-		return fmt.Errorf("detecting module path: %w", err)
-	}
-	logf("scaffold: detected module_path=%s", modulePath)
-
-	mainPkg := detectMainPackage(targetDir, modulePath)
-	logf("scaffold: detected main_package=%s", mainPkg)
-
-	srcDirs := detectSourceDirs(targetDir)
-	logf("scaffold: detected go_source_dirs=%v", srcDirs)
-
-	binName := detectBinaryName(modulePath)
-	logf("scaffold: detected binary_name=%s", binName)
-
-	// 3. Generate seed files and configuration.yaml in the target root.
-	cfg := DefaultConfig()
-	cfg.Project.ModulePath = modulePath
-	cfg.Project.BinaryName = binName
-	cfg.Project.MainPackage = mainPkg
-	cfg.Project.GoSourceDirs = srcDirs
-	cfg.Cobbler.PlanningConstitution = "docs/constitutions/planning.yaml"
-	cfg.Cobbler.ExecutionConstitution = "docs/constitutions/execution.yaml"
-	cfg.Cobbler.DesignConstitution = "docs/constitutions/design.yaml"
-	cfg.Cobbler.GoStyleConstitution = "docs/constitutions/go-style.yaml"
-	cfg.Cobbler.MeasurePrompt = "docs/prompts/measure.yaml"
-	cfg.Cobbler.StitchPrompt = "docs/prompts/stitch.yaml"
-
-	// When a main package is detected, create a version.go seed template
-	// so that after generator:reset the project has a minimal compilable
-	// binary. The template is stored in magefiles/ and referenced by
-	// seed_files in configuration.yaml.
-	if mainPkg != "" {
-		seedPath, tmplPath, err := scaffoldSeedTemplate(targetDir, modulePath, mainPkg)
-		if err != nil {
+	// 2. Write the seed template for the detected main packages.
+	if len(cfg.Project.SeedFiles) > 0 {
+		if _, _, err := scaffoldSeedTemplate(targetDir, cfg.Project.ModulePath, cfg.Project.MainPackage); err != nil {
 			return fmt.Errorf("creating seed template: %w", err)
 		}
-		cfg.Project.SeedFiles = map[string]string{seedPath: tmplPath}
-		cfg.Project.VersionFile = seedPath
-		logf("scaffold: created seed template %s -> %s", seedPath, tmplPath)
 	}
 
+	// 3. Write configuration.yaml in the target root.
 	cfgPath := filepath.Join(targetDir, DefaultConfigFile)
 	if _, err := os.Stat(cfgPath); os.IsNotExist(err) {
 		logf("scaffold: writing %s", cfgPath)
@@ -174,7 +137,7 @@ func (o *Orchestrator) Scaffold(targetDir, orchestratorRoot string) error {
 						absFile := filepath.Join(targetDir, src)
 						if _, err := os.Stat(absFile); os.IsNotExist(err) {
 							logf("scaffold: re-creating missing seed template %s", src)
-							if _, _, err := scaffoldSeedTemplate(targetDir, modulePath, existCfg.Project.MainPackage); err != nil {
+							if _, _, err := scaffoldSeedTemplate(targetDir, cfg.Project.ModulePath, existCfg.Project.MainPackage); err != nil {
 								return fmt.Errorf("re-creating seed template: %w", err)
 							}
 						}
@@ -190,7 +153,7 @@ func (o *Orchestrator) Scaffold(targetDir, orchestratorRoot string) error {
 	if err != nil {
 		return fmt.Errorf("resolving orchestrator path: %w", err)
 	}
-	if err := scaffoldMageGoMod(mageDir, modulePath, absOrch); err != nil {
+	if err := scaffoldMageGoMod(mageDir, cfg.Project.ModulePath, absOrch); err != nil {
 		return fmt.Errorf("wiring magefiles/go.mod: %w", err)
 	}
 
@@ -220,11 +183,308 @@ func (o *Orchestrator) Scaffold(targetDir, orchestratorRoot string) error {
 	return nil
 }
 
+// detectScaffoldConfig inspects targetDir and returns the configuration
+// Scaffold would write: detected module path, main packages, source
+// directories, binary name, seed files, and constitution and prompt paths.
+// It does not modify targetDir.
+func detectScaffoldConfig(targetDir string) (Config, error) {
+	modulePath, err := detectModulePath(targetDir)
+	if err != nil {
+		return Config{}, fmt.Errorf("detecting module path: %w", err)
+	}
+	logf("scaffold: detected module_path=%s", modulePath)
+
+	mainPkgs := detectMainPackages(targetDir, modulePath)
+	var mainPkg string
+	if len(mainPkgs) > 0 {
+		mainPkg = mainPkgs[0]
+	}
+	logf("scaffold: detected main_package=%s", mainPkg)
+	if len(mainPkgs) > 1 {
+		logf("scaffold: detected %d main packages: %v", len(mainPkgs), mainPkgs)
+	}
+
+	srcDirs := detectSourceDirs(targetDir)
+	logf("scaffold: detected go_source_dirs=%v", srcDirs)
+
+	binName := detectBinaryName(modulePath)
+	logf("scaffold: detected binary_name=%s", binName)
+
+	cfg := DefaultConfig()
+	cfg.Project.ModulePath = modulePath
+	cfg.Project.BinaryName = binName
+	cfg.Project.MainPackage = mainPkg
+	cfg.Project.GoSourceDirs = srcDirs
+	cfg.Cobbler.PlanningConstitution = "docs/constitutions/planning.yaml"
+	cfg.Cobbler.ExecutionConstitution = "docs/constitutions/execution.yaml"
+	cfg.Cobbler.DesignConstitution = "docs/constitutions/design.yaml"
+	cfg.Cobbler.GoStyleConstitution = "docs/constitutions/go-style.yaml"
+	cfg.Cobbler.MeasurePrompt = "docs/prompts/measure.yaml"
+	cfg.Cobbler.StitchPrompt = "docs/prompts/stitch.yaml"
+
+	// When main packages are detected, reference a version.go seed
+	// template so that after generator:reset every binary has a minimal
+	// compilable main. The template lives in magefiles/ and is listed in
+	// seed_files in configuration.yaml.
+	planSeedFiles(modulePath, mainPkgs, &cfg.Project)
+	return cfg, nil
+}
+
+// scaffoldPlan lists what Scaffold would do in a target directory.
+type scaffoldPlan struct {
+	Config     Config
+	Remove     []string // existing magefiles/*.go files Scaffold deletes
+	Create     []string // files Scaffold writes (overwriting any existing copy)
+	GoModEdits []string // commands run in magefiles/ to wire the module
+}
+
+// planScaffold returns the configuration, removals, writes, and go.mod
+// edits Scaffold would perform in targetDir without changing it (GH-526).
+func planScaffold(targetDir string) (scaffoldPlan, error) {
+	cfg, err := detectScaffoldConfig(targetDir)
+	if err != nil {
+		return scaffoldPlan{}, err
+	}
+	plan := scaffoldPlan{Config: cfg}
+
+	mageDir := filepath.Join(targetDir, dirMagefiles)
+	if entries, err := os.ReadDir(mageDir); err == nil {
+		for _, e := range entries {
+			if !e.IsDir() && strings.HasSuffix(e.Name(), ".go") {
+				plan.Remove = append(plan.Remove, filepath.Join(mageDir, e.Name()))
+			}
+		}
+	}
+
+	plan.Create = append(plan.Create, filepath.Join(mageDir, "orchestrator.go"))
+	for _, name := range slices.Sorted(maps.Keys(scaffoldConstitutionFiles())) {
+		plan.Create = append(plan.Create, filepath.Join(targetDir, "docs", "constitutions", name))
+	}
+	for _, name := range slices.Sorted(maps.Keys(scaffoldPromptFiles())) {
+		plan.Create = append(plan.Create, filepath.Join(targetDir, "docs", "prompts", name))
+	}
+	for _, name := range []string{"measure_context.yaml", "stitch_context.yaml"} {
+		plan.Create = append(plan.Create, filepath.Join(targetDir, dirCobbler, name))
+	}
+	if len(cfg.Project.SeedFiles) > 0 {
+		plan.Create = append(plan.Create, filepath.Join(targetDir, dirMagefiles, "version.go.tmpl"))
+	}
+	cfgPath := filepath.Join(targetDir, DefaultConfigFile)
+	if _, err := os.Stat(cfgPath); os.IsNotExist(err) {
+		plan.Create = append(plan.Create, cfgPath)
+	}
+
+	if _, err := os.Stat(filepath.Join(mageDir, "go.mod")); os.IsNotExist(err) {
+		plan.GoModEdits = append(plan.GoModEdits, binGo+" mod init "+cfg.Project.ModulePath+"/magefiles")
+	}
+	plan.GoModEdits = append(plan.GoModEdits,
+		binGo+" mod edit -require "+orchestratorModule+"@<latest published> (or -replace to the local orchestrator)",
+		binGo+" mod tidy",
+	)
+	return plan, nil
+}
+
+// ScaffoldDryRun prints the configuration Scaffold would detect for
+// targetDir, the files it would remove and write, and the go.mod edits it
+// would make, without changing anything (GH-526).
+func (o *Orchestrator) ScaffoldDryRun(targetDir string) error {
+	plan, err := planScaffold(targetDir)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(&plan.Config)
+	if err != nil {
+		return fmt.Errorf("marshalling config: %w", err)
+	}
+	fmt.Printf("scaffold (dry run): detected configuration for %s:\n%s", targetDir, data)
+	if len(plan.Remove) > 0 {
+		fmt.Println("would remove:")
+		for _, p := range plan.Remove {
+			fmt.Printf("  %s\n", p)
+		}
+	}
+	fmt.Println("would write:")
+	for _, p := range plan.Create {
+		fmt.Printf("  %s\n", p)
+	}
+	fmt.Printf("would run in %s:\n", filepath.Join(targetDir, dirMagefiles))
+	for _, c := range plan.GoModEdits {
+		fmt.Printf("  %s\n", c)
+	}
+	return nil
+}
+
+// scaffoldConstitutionFiles returns the constitutions Scaffold writes to
+// docs/constitutions/, keyed by file name.
+func scaffoldConstitutionFiles() map[string]string {
+	return map[string]string{
+		"design.yaml":    designConstitution,
+		"planning.yaml":  planningConstitution,
+		"execution.yaml": executionConstitution,
+		"go-style.yaml":  goStyleConstitution,
+		"testing.yaml":   testingConstitution,
+	}
+}
+
+// scaffoldPromptFiles returns the prompt templates Scaffold writes to
+// docs/prompts/, keyed by file name.
+func scaffoldPromptFiles() map[string]string {
+	return map[string]string{
+		"measure.yaml": defaultMeasurePrompt,
+		"stitch.yaml":  defaultStitchPrompt,
+	}
+}
+
+// scaffoldDocDirs returns the docs/ directories Scaffold populates, keyed
+// by path relative to the target directory, with the embedded defaults
+// written to each.
+func scaffoldDocDirs() map[string]map[string]string {
+	return map[string]map[string]string{
+		filepath.Join("docs", "constitutions"): scaffoldConstitutionFiles(),
+		filepath.Join("docs", "prompts"):       scaffoldPromptFiles(),
+	}
+}
+
+// uninstallPlan lists what Uninstall would do in a target directory.
+type uninstallPlan struct {
+	Remove     []string // existing files and directories to delete
+	GoModEdits []string // commands run in magefiles/ to unwire the module
+	Preserved  []string // docs files kept because they differ from the embedded defaults
+}
+
+// planUninstall inspects targetDir and returns the removals and go.mod
+// edits Uninstall would perform. Paths that do not exist are omitted. A
+// docs directory is listed whole when every file in it matches what
+// Scaffold wrote; otherwise only its unmodified files are listed and the
+// rest are reported as preserved.
+func planUninstall(targetDir string) uninstallPlan {
+	var plan uninstallPlan
+	docDirs := scaffoldDocDirs()
+	for _, p := range []string{
+		filepath.Join(targetDir, dirMagefiles, "orchestrator.go"),
+		filepath.Join(targetDir, "docs", "constitutions"),
+		filepath.Join(targetDir, "docs", "prompts"),
+		filepath.Join(targetDir, dirCobbler),
+		filepath.Join(targetDir, DefaultConfigFile),
+	} {
+		if _, err := os.Stat(p); err != nil {
+			continue
+		}
+		rel, _ := filepath.Rel(targetDir, p)
+		defaults, ok := docDirs[rel]
+		if !ok {
+			plan.Remove = append(plan.Remove, p)
+			continue
+		}
+		unmodified, preserved := splitScaffoldedFiles(p, defaults)
+		if len(preserved) == 0 {
+			plan.Remove = append(plan.Remove, p)
+			continue
+		}
+		for _, name := range unmodified {
+			plan.Remove = append(plan.Remove, filepath.Join(p, name))
+		}
+		for _, name := range preserved {
+			plan.Preserved = append(plan.Preserved, filepath.Join(p, name))
+		}
+	}
+	if _, err := os.Stat(filepath.Join(targetDir, dirMagefiles, "go.mod")); err == nil {
+		plan.GoModEdits = []string{
+			binGo + " mod edit -dropreplace " + orchestratorModule,
+			binGo + " mod tidy",
+		}
+	}
+	return plan
+}
+
+// splitScaffoldedFiles partitions the entries of dir into files that are
+// byte-identical to the default Scaffold wrote and everything else:
+// edited files, files Scaffold never wrote, and subdirectories. Returns
+// nil slices if dir does not exist.
+func splitScaffoldedFiles(dir string, defaults map[string]string) (unmodified, preserved []string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil
+	}
+	for _, e := range entries {
+		def, ok := defaults[e.Name()]
+		if !ok || !e.Type().IsRegular() {
+			preserved = append(preserved, e.Name())
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil || string(data) != def {
+			preserved = append(preserved, e.Name())
+			continue
+		}
+		unmodified = append(unmodified, e.Name())
+	}
+	return unmodified, preserved
+}
+
+// removeUnmodifiedFiles deletes the files in dir that still match the
+// defaults Scaffold wrote, logs each file it keeps, and removes dir only
+// when nothing is left in it. A missing dir is a no-op.
+func removeUnmodifiedFiles(dir string, defaults map[string]string) error {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	unmodified, preserved := splitScaffoldedFiles(dir, defaults)
+	for _, name := range unmodified {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return fmt.Errorf("removing %s: %w", name, err)
+		}
+	}
+	for _, name := range preserved {
+		logf("uninstall: preserved %s (differs from the scaffolded default)", filepath.Join(dir, name))
+	}
+	if len(preserved) > 0 {
+		return nil
+	}
+	if err := os.Remove(dir); err != nil {
+		return err
+	}
+	logf("uninstall: removed %s", dir)
+	return nil
+}
+
+// UninstallDryRun prints what Uninstall would remove from targetDir and
+// the go.mod edits it would make, without changing anything. Constitutions
+// and prompts that differ from the embedded defaults are listed as
+// preserved, since Uninstall leaves them in place.
+func (o *Orchestrator) UninstallDryRun(targetDir string) error {
+	plan := planUninstall(targetDir)
+	if len(plan.Remove) == 0 && len(plan.GoModEdits) == 0 && len(plan.Preserved) == 0 {
+		fmt.Printf("uninstall (dry run): nothing to remove in %s\n", targetDir)
+		return nil
+	}
+	fmt.Printf("uninstall (dry run): would remove from %s:\n", targetDir)
+	for _, p := range plan.Remove {
+		fmt.Printf("  %s\n", p)
+	}
+	if len(plan.GoModEdits) > 0 {
+		fmt.Printf("would run in %s:\n", filepath.Join(targetDir, dirMagefiles))
+		for _, c := range plan.GoModEdits {
+			fmt.Printf("  %s\n", c)
+		}
+	}
+	if len(plan.Preserved) > 0 {
+		fmt.Println("would preserve (differ from the scaffolded defaults):")
+		for _, p := range plan.Preserved {
+			fmt.Printf("  %s\n", p)
+		}
+	}
+	return nil
+}
+
 // Uninstall removes the files added by Scaffold from targetDir:
 // magefiles/orchestrator.go, docs/constitutions/, docs/prompts/,
 // configuration.yaml, and .cobbler/. It also removes the orchestrator replace
 // directive from magefiles/go.mod and runs go mod tidy to clean up unused
-// dependencies.
+// dependencies. In docs/constitutions/ and docs/prompts/ only files that are
+// byte-identical to what Scaffold wrote are deleted; edited or added files
+// are kept and logged, and each directory is removed only once empty. Use
+// UninstallDryRun to preview.
 func (o *Orchestrator) Uninstall(targetDir string) error {
 	logf("uninstall: removing orchestrator files from %s", targetDir)
 
@@ -234,18 +494,13 @@ func (o *Orchestrator) Uninstall(targetDir string) error {
 		return fmt.Errorf("removing orchestrator.go: %w", err)
 	}
 
-	// Remove docs/constitutions/ and docs/prompts/ directories.
-	constitutionsDir := filepath.Join(targetDir, "docs", "constitutions")
-	if err := os.RemoveAll(constitutionsDir); err != nil {
-		return fmt.Errorf("removing docs/constitutions: %w", err)
-	}
-	logf("uninstall: removed %s", constitutionsDir)
-
-	promptsDir := filepath.Join(targetDir, "docs", "prompts")
-	if err := os.RemoveAll(promptsDir); err != nil {
-		return fmt.Errorf("removing docs/prompts: %w", err)
+	// Remove unmodified files from docs/constitutions/ and docs/prompts/.
+	docDirs := scaffoldDocDirs()
+	for _, rel := range slices.Sorted(maps.Keys(docDirs)) {
+		if err := removeUnmodifiedFiles(filepath.Join(targetDir, rel), docDirs[rel]); err != nil {
+			return fmt.Errorf("removing %s: %w", rel, err)
+		}
 	}
-	logf("uninstall: removed %s", promptsDir)
 
 	// Remove .cobbler/ directory written by Scaffold.
 	cobblerDir := filepath.Join(targetDir, dirCobbler)
@@ -356,25 +611,36 @@ func detectModulePath(targetDir string) (string, error) {
 // Returns the module-relative import path of the first main package
 // found, or empty string if none exist.
 func detectMainPackage(targetDir, modulePath string) string {
+	if pkgs := detectMainPackages(targetDir, modulePath); len(pkgs) > 0 {
+		return pkgs[0]
+	}
+	return ""
+}
+
+// detectMainPackages returns the import paths of every cmd/<name>/
+// directory containing main.go, in directory order, followed by cmd
+// itself when cmd/main.go exists (GH-525). Returns nil if none exist.
+func detectMainPackages(targetDir, modulePath string) []string {
 	cmdDir := filepath.Join(targetDir, "cmd")
 	entries, err := os.ReadDir(cmdDir)
 	if err != nil {
-		return ""
+		return nil
 	}
+	var pkgs []string
 	for _, e := range entries {
 		if !e.IsDir() {
 			continue
 		}
 		mainGo := filepath.Join(cmdDir, e.Name(), "main.go")
 		if _, err := os.Stat(mainGo); err == nil {
-			return modulePath + "/cmd/" + e.Name()
+			pkgs = append(pkgs, modulePath+"/cmd/"+e.Name())
 		}
 	}
 	// Check for main.go directly in cmd/.
 	if _, err := os.Stat(filepath.Join(cmdDir, "main.go")); err == nil {
-		return modulePath + "/cmd"
+		pkgs = append(pkgs, modulePath+"/cmd")
 	}
-	return ""
+	return pkgs
 }
 
 // detectSourceDirs returns existing Go source directories in the target.
@@ -403,17 +669,7 @@ func detectBinaryName(modulePath string) string {
 // and returns the destination path (relative to repo root) and the template
 // source path (relative to repo root) for use in seed_files configuration.
 func scaffoldSeedTemplate(targetDir, modulePath, mainPkg string) (destPath, tmplPath string, err error) {
-	// Derive the relative directory for the main package.
-	// e.g. modulePath="github.com/org/repo", mainPkg="github.com/org/repo/cmd/app"
-	// → relDir="cmd/app"
-	relDir := strings.TrimPrefix(mainPkg, modulePath+"/")
-	if relDir == mainPkg {
-		// mainPkg equals modulePath — main is at repo root.
-		relDir = "."
-	}
-
-	destPath = filepath.Join(relDir, "version.go")
-	tmplPath = filepath.Join(dirMagefiles, "version.go.tmpl")
+	destPath, tmplPath = seedTemplatePaths(modulePath, mainPkg)
 
 	tmplContent := `// Copyright (c) 2026 Petar Djukic. All rights reserved.
 // SPDX-License-Identifier: MIT
@@ -433,6 +689,43 @@ const Version = "{{.Version}}"
 	return destPath, tmplPath, nil
 }
 
+// planSeedFiles wires a version.go seed for each main package into
+// project: every seed shares the magefiles/version.go.tmpl template. A
+// single package is recorded in VersionFile as before; several are listed
+// in VersionFiles so Tag updates each of them (GH-525).
+func planSeedFiles(modulePath string, mainPkgs []string, project *ProjectConfig) {
+	if len(mainPkgs) == 0 {
+		return
+	}
+	seeds := make(map[string]string, len(mainPkgs))
+	var versionFiles []string
+	for _, pkg := range mainPkgs {
+		seedPath, tmplPath := seedTemplatePaths(modulePath, pkg)
+		seeds[seedPath] = tmplPath
+		versionFiles = append(versionFiles, seedPath)
+	}
+	project.SeedFiles = seeds
+	if len(versionFiles) == 1 {
+		project.VersionFile = versionFiles[0]
+	} else {
+		project.VersionFiles = versionFiles
+	}
+}
+
+// seedTemplatePaths returns the version.go destination for mainPkg and
+// the shared template path, both relative to the repo root.
+func seedTemplatePaths(modulePath, mainPkg string) (destPath, tmplPath string) {
+	// Derive the relative directory for the main package.
+	// e.g. modulePath="github.com/org/repo", mainPkg="github.com/org/repo/cmd/app"
+	// → relDir="cmd/app"
+	relDir := strings.TrimPrefix(mainPkg, modulePath+"/")
+	if relDir == mainPkg {
+		// mainPkg equals modulePath — main is at repo root.
+		relDir = "."
+	}
+	return filepath.Join(relDir, "version.go"), filepath.Join(dirMagefiles, "version.go.tmpl")
+}
+
 // writeScaffoldConfig marshals cfg as YAML and writes it to path.
 func writeScaffoldConfig(path string, cfg Config) error {
 	data, err := yaml.Marshal(&cfg)
@@ -652,7 +945,8 @@ func goModDownload(module, version string) (string, error) {
 
 // copyDir recursively copies src to dst, making all files writable.
 // The Go module cache is read-only, so this produces a mutable copy.
-func copyDir(src, dst string) error {
+// Directories below src whose name is in skip are not copied.
+func copyDir(src, dst string, skip ...string) error {
 	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -663,6 +957,9 @@ func copyDir(src, dst string) error {
 		}
 		target := filepath.Join(dst, rel)
 		if d.IsDir() {
+			if rel != "." && slices.Contains(skip, d.Name()) {
+				return fs.SkipDir
+			}
 			return os.MkdirAll(target, 0o755)
 		}
 		data, err := os.ReadFile(path)
@@ -673,18 +970,37 @@ func copyDir(src, dst string) error {
 	})
 }
 
-// PrepareTestRepo downloads a Go module at the given version, copies it
-// to a temporary working directory, initializes a fresh git repository,
-// and runs Scaffold. Returns the path to the ready-to-use repo directory.
-// The caller is responsible for removing the parent temp directory when done.
-func (o *Orchestrator) PrepareTestRepo(module, version, orchestratorRoot string) (string, error) {
+// testRepoSource returns the directory PrepareTestRepo copies from and
+// the directory names to leave out of the copy. When module names an
+// existing directory it is used as is and version is ignored, so an
+// unpublished local checkout can be tested (GH-527); its .git and cobbler
+// directories are skipped. Otherwise module@version is fetched from the
+// Go module proxy.
+func testRepoSource(module, version string) (string, []string, error) {
+	if info, err := os.Stat(module); err == nil && info.IsDir() {
+		logf("prepareTestRepo: using local directory %s", module)
+		return module, []string{".git", dirCobbler}, nil
+	}
 	logf("prepareTestRepo: downloading %s@%s", module, version)
-
 	cacheDir, err := goModDownload(module, version)
 	if err != nil {
-		return "", fmt.Errorf("downloading module: %w", err)
+		return "", nil, fmt.Errorf("downloading module: %w", err)
 	}
 	logf("prepareTestRepo: cached at %s", cacheDir)
+	return cacheDir, nil, nil
+}
+
+// PrepareTestRepo copies a Go module to a temporary working directory,
+// initializes a fresh git repository, and runs Scaffold. module is either
+// a module path, downloaded at version from the Go module proxy, or a
+// local directory, copied without its .git and .cobbler directories.
+// Returns the path to the ready-to-use repo directory. The caller is
+// responsible for removing the parent temp directory when done.
+func (o *Orchestrator) PrepareTestRepo(module, version, orchestratorRoot string) (string, error) {
+	srcDir, skip, err := testRepoSource(module, version)
+	if err != nil {
+		return "", err
+	}
 
 	workDir, err := os.MkdirTemp("", "test-clone-*")
 	if err != nil {
@@ -693,7 +1009,7 @@ func (o *Orchestrator) PrepareTestRepo(module, version, orchestratorRoot string)
 	repoDir := filepath.Join(workDir, "repo")
 
 	logf("prepareTestRepo: copying to %s", repoDir)
-	if err := copyDir(cacheDir, repoDir); err != nil {
+	if err := copyDir(srcDir, repoDir, skip...); err != nil {
 		os.RemoveAll(workDir)
 		return "", fmt.Errorf("copying module source: %w", err)
 	}
@@ -4,8 +4,11 @@
 package orchestrator
 
 import (
+	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -119,6 +122,145 @@ func TestDetectMainPackage_CmdDirNoMainGo(t *testing.T) {
 	}
 }
 
+func TestDetectMainPackages_Multiple(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"beta", "alpha", "nomain"} {
+		if err := os.MkdirAll(filepath.Join(dir, "cmd", name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"alpha", "beta"} {
+		if err := os.WriteFile(filepath.Join(dir, "cmd", name, "main.go"), []byte("package main"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := detectMainPackages(dir, "github.com/org/repo")
+	want := []string{"github.com/org/repo/cmd/alpha", "github.com/org/repo/cmd/beta"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if first := detectMainPackage(dir, "github.com/org/repo"); first != want[0] {
+		t.Errorf("detectMainPackage = %q, want %q", first, want[0])
+	}
+}
+
+// --- planSeedFiles ---
+
+func TestPlanSeedFiles_SinglePackageUsesVersionFile(t *testing.T) {
+	t.Parallel()
+	var project ProjectConfig
+	planSeedFiles("github.com/org/repo", []string{"github.com/org/repo/cmd/app"}, &project)
+	want := filepath.Join("cmd", "app", "version.go")
+	if project.VersionFile != want || project.VersionFiles != nil {
+		t.Errorf("VersionFile = %q, VersionFiles = %v; want %q, nil", project.VersionFile, project.VersionFiles, want)
+	}
+	if len(project.SeedFiles) != 1 {
+		t.Errorf("SeedFiles = %v, want one entry", project.SeedFiles)
+	}
+}
+
+func TestPlanSeedFiles_MultiplePackages(t *testing.T) {
+	t.Parallel()
+	var project ProjectConfig
+	pkgs := []string{"github.com/org/repo/cmd/alpha", "github.com/org/repo/cmd/beta"}
+	planSeedFiles("github.com/org/repo", pkgs, &project)
+	want := []string{filepath.Join("cmd", "alpha", "version.go"), filepath.Join("cmd", "beta", "version.go")}
+	if project.VersionFile != "" || !slices.Equal(project.VersionFiles, want) {
+		t.Errorf("VersionFile = %q, VersionFiles = %v; want empty, %v", project.VersionFile, project.VersionFiles, want)
+	}
+	for _, seed := range want {
+		if tmpl := project.SeedFiles[seed]; tmpl != filepath.Join(dirMagefiles, "version.go.tmpl") {
+			t.Errorf("SeedFiles[%s] = %q", seed, tmpl)
+		}
+	}
+}
+
+func TestPlanSeedFiles_NoPackages(t *testing.T) {
+	t.Parallel()
+	var project ProjectConfig
+	planSeedFiles("github.com/org/repo", nil, &project)
+	if project.SeedFiles != nil || project.VersionFile != "" {
+		t.Errorf("project = %+v, want unchanged", project)
+	}
+}
+
+// --- planScaffold (GH-526) ---
+
+func TestPlanScaffold_DoesNotModifyTarget(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                "module github.com/org/tool\n\ngo 1.22\n",
+		"cmd/tool/main.go":      "package main\n",
+		"magefiles/magefile.go": "//go:build mage\n\npackage main\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before := snapshotTree(t, dir)
+
+	plan, err := planScaffold(dir)
+	if err != nil {
+		t.Fatalf("planScaffold: %v", err)
+	}
+	if plan.Config.Project.ModulePath != "github.com/org/tool" || plan.Config.Project.MainPackage != "github.com/org/tool/cmd/tool" {
+		t.Errorf("detected project = %+v", plan.Config.Project)
+	}
+	if plan.Config.Project.VersionFile != filepath.Join("cmd", "tool", "version.go") {
+		t.Errorf("VersionFile = %q", plan.Config.Project.VersionFile)
+	}
+	if !slices.Equal(plan.Remove, []string{filepath.Join(dir, "magefiles", "magefile.go")}) {
+		t.Errorf("Remove = %v", plan.Remove)
+	}
+	for _, want := range []string{
+		filepath.Join(dir, "magefiles", "orchestrator.go"),
+		filepath.Join(dir, "docs", "constitutions", "design.yaml"),
+		filepath.Join(dir, "magefiles", "version.go.tmpl"),
+		filepath.Join(dir, DefaultConfigFile),
+	} {
+		if !slices.Contains(plan.Create, want) {
+			t.Errorf("Create missing %s: %v", want, plan.Create)
+		}
+	}
+	if len(plan.GoModEdits) == 0 || !strings.Contains(plan.GoModEdits[0], "mod init github.com/org/tool/magefiles") {
+		t.Errorf("GoModEdits = %v, want go mod init first", plan.GoModEdits)
+	}
+	if after := snapshotTree(t, dir); !maps.Equal(before, after) {
+		t.Errorf("planScaffold modified the target:\nbefore %v\nafter  %v", before, after)
+	}
+}
+
+func TestPlanScaffold_NoGoMod(t *testing.T) {
+	t.Parallel()
+	if _, err := planScaffold(t.TempDir()); err == nil {
+		t.Error("planScaffold succeeded without go.mod")
+	}
+}
+
+// snapshotTree maps every file under dir to its content.
+func snapshotTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	tree := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		tree[path] = string(data)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree
+}
+
 // --- detectSourceDirs ---
 
 func TestDetectSourceDirs_ReturnsExisting(t *testing.T) {
@@ -396,6 +538,47 @@ func TestCopyDir_EmptySrc(t *testing.T) {
 	}
 }
 
+func TestCopyDir_SkipsNamedDirs(t *testing.T) {
+	t.Parallel()
+	src := t.TempDir()
+	os.MkdirAll(filepath.Join(src, ".git", "objects"), 0o755)
+	os.MkdirAll(filepath.Join(src, ".cobbler"), 0o755)
+	os.MkdirAll(filepath.Join(src, "pkg"), 0o755)
+	os.WriteFile(filepath.Join(src, ".git", "HEAD"), []byte("ref"), 0o644)
+	os.WriteFile(filepath.Join(src, ".cobbler", "base-branch"), []byte("main"), 0o644)
+	os.WriteFile(filepath.Join(src, "pkg", "a.go"), []byte("package pkg"), 0o644)
+
+	dst := filepath.Join(t.TempDir(), "out")
+	if err := copyDir(src, dst, ".git", ".cobbler"); err != nil {
+		t.Fatalf("copyDir: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "pkg", "a.go")); err != nil {
+		t.Errorf("pkg/a.go not copied: %v", err)
+	}
+	for _, skipped := range []string{".git", ".cobbler"} {
+		if _, err := os.Stat(filepath.Join(dst, skipped)); !os.IsNotExist(err) {
+			t.Errorf("%s copied, want skipped", skipped)
+		}
+	}
+}
+
+// --- testRepoSource ---
+
+func TestTestRepoSource_LocalDirectory(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	src, skip, err := testRepoSource(dir, "")
+	if err != nil {
+		t.Fatalf("testRepoSource: %v", err)
+	}
+	if src != dir {
+		t.Errorf("src = %q, want %q", src, dir)
+	}
+	if !slices.Contains(skip, ".git") || !slices.Contains(skip, dirCobbler) {
+		t.Errorf("skip = %v, want .git and %s", skip, dirCobbler)
+	}
+}
+
 // --- clearGenerationBranch ---
 
 func TestClearGenerationBranch_ClearsStaleBranch(t *testing.T) {
@@ -492,3 +675,147 @@ func TestCopyDir_PreservesContent(t *testing.T) {
 		}
 	}
 }
+
+// --- Uninstall preserves customized docs ---
+
+func TestUninstall_PreservesModifiedDocs(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	constDir := filepath.Join(dir, "docs", "constitutions")
+	promptsDir := filepath.Join(dir, "docs", "prompts")
+	for _, d := range []string{constDir, promptsDir} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, content := range scaffoldConstitutionFiles() {
+		if err := os.WriteFile(filepath.Join(constDir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	custom := filepath.Join(constDir, "testing.yaml")
+	if err := os.WriteFile(custom, []byte("custom: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range scaffoldPromptFiles() {
+		if err := os.WriteFile(filepath.Join(promptsDir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	o := &Orchestrator{}
+	if err := o.Uninstall(dir); err != nil {
+		t.Fatalf("Uninstall: %v", err)
+	}
+
+	if got, err := os.ReadFile(custom); err != nil || string(got) != "custom: true\n" {
+		t.Errorf("customized testing.yaml not preserved: %q, %v", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(constDir, "design.yaml")); !os.IsNotExist(err) {
+		t.Errorf("unmodified design.yaml should have been removed, got stat err: %v", err)
+	}
+	if _, err := os.Stat(promptsDir); !os.IsNotExist(err) {
+		t.Errorf("unmodified docs/prompts should have been removed, got stat err: %v", err)
+	}
+}
+
+func TestRemoveUnmodifiedFiles_KeepsAddedFilesAndDir(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte("mine"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := removeUnmodifiedFiles(dir, map[string]string{"a.yaml": "a"}); err != nil {
+		t.Fatalf("removeUnmodifiedFiles: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "a.yaml")); !os.IsNotExist(err) {
+		t.Errorf("a.yaml should have been removed, got stat err: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.md")); err != nil {
+		t.Errorf("notes.md should have been kept: %v", err)
+	}
+}
+
+func TestRemoveUnmodifiedFiles_MissingDir_IsNoOp(t *testing.T) {
+	t.Parallel()
+	if err := removeUnmodifiedFiles(filepath.Join(t.TempDir(), "missing"), scaffoldPromptFiles()); err != nil {
+		t.Errorf("removeUnmodifiedFiles on missing dir should be no-op, got: %v", err)
+	}
+}
+
+// --- Uninstall dry run ---
+
+func TestPlanUninstall_ListsExistingPathsAndCustomizations(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	constDir := filepath.Join(dir, "docs", "constitutions")
+	if err := os.MkdirAll(constDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(constDir, "design.yaml"), []byte(designConstitution), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(constDir, "testing.yaml"), []byte("custom: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, DefaultConfigFile), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	plan := planUninstall(dir)
+
+	want := []string{filepath.Join(constDir, "design.yaml"), filepath.Join(dir, DefaultConfigFile)}
+	if !slices.Equal(plan.Remove, want) {
+		t.Errorf("Remove = %v, want %v", plan.Remove, want)
+	}
+	if len(plan.GoModEdits) != 0 {
+		t.Errorf("GoModEdits = %v, want none without magefiles/go.mod", plan.GoModEdits)
+	}
+	if want := []string{filepath.Join(constDir, "testing.yaml")}; !slices.Equal(plan.Preserved, want) {
+		t.Errorf("Preserved = %v, want %v", plan.Preserved, want)
+	}
+}
+
+func TestPlanUninstall_UnmodifiedDocDirRemovedWhole(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	promptsDir := filepath.Join(dir, "docs", "prompts")
+	if err := os.MkdirAll(promptsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range scaffoldPromptFiles() {
+		if err := os.WriteFile(filepath.Join(promptsDir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plan := planUninstall(dir)
+
+	if !slices.Equal(plan.Remove, []string{promptsDir}) {
+		t.Errorf("Remove = %v, want [%s]", plan.Remove, promptsDir)
+	}
+	if len(plan.Preserved) != 0 {
+		t.Errorf("Preserved = %v, want none", plan.Preserved)
+	}
+}
+
+func TestUninstallDryRun_LeavesFilesInPlace(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, DefaultConfigFile)
+	if err := os.WriteFile(cfgPath, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	o := &Orchestrator{}
+	if err := o.UninstallDryRun(dir); err != nil {
+		t.Fatalf("UninstallDryRun: %v", err)
+	}
+	if _, err := os.Stat(cfgPath); err != nil {
+		t.Errorf("dry run removed %s: %v", cfgPath, err)
+	}
+}
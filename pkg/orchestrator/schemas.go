@@ -0,0 +1,217 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schemaDrift lists the YAML keys in one document that its typed struct
+// does not capture.
+type schemaDrift struct {
+	Path    string
+	Dropped []string
+}
+
+// roundTripDroppedKeys loads path into T, marshals T back to YAML, and
+// returns the dotted key paths present in the original file but absent
+// from the round-tripped copy. Keys whose original value is empty are not
+// reported, since omitempty fields legitimately disappear. Returns nil and
+// no error if the file does not exist.
+func roundTripDroppedKeys[T any](path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var original any
+	if err := yaml.Unmarshal(data, &original); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	var v T
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	out, err := yaml.Marshal(&v)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling %s: %w", path, err)
+	}
+	var roundTrip any
+	if err := yaml.Unmarshal(out, &roundTrip); err != nil {
+		return nil, fmt.Errorf("re-parsing %s: %w", path, err)
+	}
+	dropped := droppedKeys(original, roundTrip, "")
+	sort.Strings(dropped)
+	return dropped, nil
+}
+
+// droppedKeys walks original and reports the dotted path of every mapping
+// key with a non-empty value that is missing from roundTrip. Sequence
+// elements are compared by position and addressed as path[i].
+func droppedKeys(original, roundTrip any, prefix string) []string {
+	var dropped []string
+	switch orig := original.(type) {
+	case map[string]any:
+		rt, _ := roundTrip.(map[string]any)
+		for k, ov := range orig {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			rv, ok := rt[k]
+			if !ok {
+				if !isEmptyYAMLValue(ov) {
+					dropped = append(dropped, key)
+				}
+				continue
+			}
+			dropped = append(dropped, droppedKeys(ov, rv, key)...)
+		}
+	case []any:
+		rt, _ := roundTrip.([]any)
+		for i, ov := range orig {
+			var rv any
+			if i < len(rt) {
+				rv = rt[i]
+			}
+			dropped = append(dropped, droppedKeys(ov, rv, fmt.Sprintf("%s[%d]", prefix, i))...)
+		}
+	}
+	return dropped
+}
+
+// isEmptyYAMLValue reports whether v is a null, zero scalar, or empty
+// collection as produced by yaml.Unmarshal into any.
+func isEmptyYAMLValue(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return t == ""
+	case bool:
+		return !t
+	case int:
+		return t == 0
+	case float64:
+		return t == 0
+	case map[string]any:
+		return len(t) == 0
+	case []any:
+		return len(t) == 0
+	}
+	return false
+}
+
+// verifySchemaFile dispatches path to the typed struct the orchestrator
+// loads it into and returns the keys that struct drops. Files the
+// orchestrator reads as free-form NamedDoc content (spec_aux, extra) have
+// no schema to drift from and are skipped.
+func verifySchemaFile(path, category string) ([]string, error) {
+	switch category {
+	case "vision":
+		return roundTripDroppedKeys[VisionDoc](path)
+	case "architecture":
+		return roundTripDroppedKeys[ArchitectureDoc](path)
+	case "specifications":
+		return roundTripDroppedKeys[SpecificationsDoc](path)
+	case "roadmap":
+		return roundTripDroppedKeys[RoadmapDoc](path)
+	case "prd":
+		return roundTripDroppedKeys[PRDDoc](path)
+	case "use_case":
+		return roundTripDroppedKeys[UseCaseDoc](path)
+	case "test_suite":
+		return roundTripDroppedKeys[TestSuiteDoc](path)
+	case "engineering":
+		return roundTripDroppedKeys[EngineeringDoc](path)
+	case "design":
+		return roundTripDroppedKeys[DesignDoc](path)
+	case "execution":
+		return roundTripDroppedKeys[ExecutionDoc](path)
+	case "go-style":
+		return roundTripDroppedKeys[GoStyleDoc](path)
+	case "issue-format":
+		return roundTripDroppedKeys[IssueFormatDoc](path)
+	case "planning":
+		return roundTripDroppedKeys[PlanningDoc](path)
+	case "semantic-model":
+		return roundTripDroppedKeys[SemanticModelDoc](path)
+	case "testing":
+		return roundTripDroppedKeys[TestingDoc](path)
+	}
+	return nil, nil
+}
+
+// schemaDriftReport round-trips every typed document the orchestrator
+// loads (standard docs, engineering guidelines, and constitutions) and
+// returns the files with dropped keys. Read or parse failures are
+// returned as errors alongside the drift found so far.
+func schemaDriftReport() ([]schemaDrift, []error) {
+	type target struct{ path, category string }
+	var targets []target
+	for _, path := range resolveStandardFiles(".") {
+		targets = append(targets, target{path, classifyContextFile(path)})
+	}
+	for _, dir := range []string{"docs/constitutions", "pkg/orchestrator/constitutions"} {
+		for _, name := range []string{"design", "execution", "go-style", "issue-format", "planning", "semantic-model", "testing"} {
+			targets = append(targets, target{dir + "/" + name + ".yaml", name})
+		}
+	}
+
+	var drift []schemaDrift
+	var errs []error
+	for _, t := range targets {
+		dropped, err := verifySchemaFile(t.path, t.category)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(dropped) > 0 {
+			drift = append(drift, schemaDrift{Path: t.path, Dropped: dropped})
+		}
+	}
+	return drift, errs
+}
+
+// VerifySchemas checks that every typed documentation struct captures all
+// the data in its YAML file. Each document is loaded into its struct,
+// marshalled back, and compared with the file's parsed structure; keys
+// present in the file but lost on the round trip are reported per file.
+// Unlike the strict decode in Analyze, which stops at the first unknown
+// key, this lists every dropped key. Returns an error when any file
+// drops keys or cannot be read.
+func (o *Orchestrator) VerifySchemas() error {
+	drift, errs := schemaDriftReport()
+	for _, err := range errs {
+		fmt.Printf("error: %v\n", err)
+	}
+	if len(drift) == 0 && len(errs) == 0 {
+		fmt.Println("all typed docs round-trip without dropped keys")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "File\tDropped key")
+	total := 0
+	for _, d := range drift {
+		for _, k := range d.Dropped {
+			fmt.Fprintf(w, "%s\t%s\n", d.Path, k)
+		}
+		total += len(d.Dropped)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("schema verification: %d file(s) unreadable, %d key(s) dropped in %d file(s)", len(errs), total, len(drift))
+	}
+	return fmt.Errorf("schema verification: %d key(s) dropped in %d file(s)", total, len(drift))
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDroppedKeys(t *testing.T) {
+	t.Parallel()
+	original := map[string]any{
+		"id":    "prd001",
+		"extra": "lost",
+		"empty": "",
+		"nested": map[string]any{
+			"kept": 1,
+			"gone": []any{"x"},
+		},
+		"items": []any{
+			map[string]any{"name": "a", "note": "lost too"},
+		},
+	}
+	roundTrip := map[string]any{
+		"id":     "prd001",
+		"nested": map[string]any{"kept": 1},
+		"items":  []any{map[string]any{"name": "a"}},
+	}
+	got := droppedKeys(original, roundTrip, "")
+	want := map[string]bool{"extra": true, "nested.gone": true, "items[0].note": true}
+	if len(got) != len(want) {
+		t.Fatalf("droppedKeys = %v, want keys %v", got, want)
+	}
+	for _, k := range got {
+		if !want[k] {
+			t.Errorf("unexpected dropped key %q", k)
+		}
+	}
+}
+
+func TestRoundTripDroppedKeys(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "VISION.yaml")
+	content := "id: v1\ntitle: Vision\nunknown_section: data\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := roundTripDroppedKeys[VisionDoc](path)
+	if err != nil {
+		t.Fatalf("roundTripDroppedKeys: %v", err)
+	}
+	if want := []string{"unknown_section"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("dropped = %v, want %v", got, want)
+	}
+}
+
+func TestRoundTripDroppedKeys_MissingFile(t *testing.T) {
+	t.Parallel()
+	got, err := roundTripDroppedKeys[VisionDoc](filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil || got != nil {
+		t.Errorf("missing file: got %v, %v; want nil, nil", got, err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"unicode"
 
@@ -15,16 +16,30 @@ import (
 )
 
 // StatsRecord holds collected LOC and documentation word counts.
+// ProdLOCByLang and TestLOCByLang break LOC down by language for every
+// Project.SourceExtensions suffix, keyed by the suffix without its dot
+// (e.g. "go", "proto"); the Go* fields keep the Go totals (GH-539).
 type StatsRecord struct {
-	GoProdLOC int            `yaml:"go_loc_prod"`
-	GoTestLOC int            `yaml:"go_loc_test"`
-	GoLOC     int            `yaml:"go_loc"`
-	SpecWords map[string]int `yaml:"spec_words"`
+	GoProdLOC     int            `yaml:"go_loc_prod"`
+	GoTestLOC     int            `yaml:"go_loc_test"`
+	GoLOC         int            `yaml:"go_loc"`
+	ProdLOCByLang map[string]int `yaml:"loc_prod_by_lang,omitempty"`
+	TestLOCByLang map[string]int `yaml:"loc_test_by_lang,omitempty"`
+	SpecWords     map[string]int `yaml:"spec_words"`
 }
 
-// CollectStats gathers Go LOC and documentation word counts.
+// CollectStats gathers LOC for each source extension and documentation
+// word counts. Go is always counted so the Go* totals are populated even
+// when Project.SourceExtensions omits .go. Generated files and paths
+// matching Cobbler.LOCIgnore are not counted (GH-540).
 func (o *Orchestrator) CollectStats() (StatsRecord, error) {
 	var prodLines, testLines int
+	prodByLang := make(map[string]int)
+	testByLang := make(map[string]int)
+	extensions := o.cfg.Project.SourceExtensions
+	if len(extensions) == 0 {
+		extensions = defaultSourceExtensions
+	}
 
 	err := filepath.Walk(".", func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -34,23 +49,50 @@ func (o *Orchestrator) CollectStats() (StatsRecord, error) {
 			if path == "vendor" || path == ".git" || path == o.cfg.Project.BinaryDir {
 				return filepath.SkipDir
 			}
+			// Installed JavaScript dependencies can sit at any depth.
+			if info.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+			if path != "." && matchesLOCIgnore(path, o.cfg.Cobbler.LOCIgnore) {
+				return filepath.SkipDir
+			}
 			return nil
 		}
-		if !strings.HasSuffix(path, ".go") {
+		if matchesLOCIgnore(path, o.cfg.Cobbler.LOCIgnore) {
+			return nil
+		}
+		ext := sourceExtension(path, extensions)
+		if ext == "" && strings.HasSuffix(path, ".go") {
+			ext = ".go"
+		}
+		if ext == "" {
 			return nil
 		}
 		// Skip magefiles — they are build tooling, not project code.
 		if strings.HasPrefix(path, o.cfg.Project.MagefilesDir) {
 			return nil
 		}
+		if isGeneratedFile(path) {
+			return nil
+		}
 		count, countErr := countLines(path)
 		if countErr != nil {
 			return nil
 		}
-		if strings.HasSuffix(path, "_test.go") {
-			testLines += count
-		} else {
-			prodLines += count
+		lang := strings.TrimPrefix(ext, ".")
+		test := isTestSource(path, ext)
+		switch {
+		case test:
+			testByLang[lang] += count
+		default:
+			prodByLang[lang] += count
+		}
+		if ext == ".go" {
+			if test {
+				testLines += count
+			} else {
+				prodLines += count
+			}
 		}
 		return nil
 	})
@@ -59,7 +101,7 @@ func (o *Orchestrator) CollectStats() (StatsRecord, error) {
 	}
 
 	specWords := make(map[string]int)
-	for _, path := range resolveStandardFiles() {
+	for _, path := range resolveStandardFiles(".") {
 		cat := classifyContextFile(path)
 		if cat == "prd" || cat == "use_case" || cat == "test_suite" {
 			words, wordErr := countWordsInFile(path)
@@ -71,14 +113,100 @@ func (o *Orchestrator) CollectStats() (StatsRecord, error) {
 	}
 
 	return StatsRecord{
-		GoProdLOC: prodLines,
-		GoTestLOC: testLines,
-		GoLOC:     prodLines + testLines,
-		SpecWords: specWords,
+		GoProdLOC:     prodLines,
+		GoTestLOC:     testLines,
+		GoLOC:         prodLines + testLines,
+		ProdLOCByLang: prodByLang,
+		TestLOCByLang: testByLang,
+		SpecWords:     specWords,
 	}, nil
 }
 
-// Stats prints Go lines of code and documentation word counts as YAML.
+// sourceExtension returns the longest entry of extensions that path ends
+// with, or "" when none matches.
+func sourceExtension(path string, extensions []string) string {
+	best := ""
+	for _, ext := range extensions {
+		if ext != "" && strings.HasSuffix(path, ext) && len(ext) > len(best) {
+			best = ext
+		}
+	}
+	return best
+}
+
+// isTestSource reports whether path, a source file with suffix ext, is a
+// test file under the usual convention for its language: _test.go for Go;
+// otherwise a stem ending in _test, _spec, Test, or Tests, a test_ prefix,
+// a .test/.spec infix (foo.test.ts), or a tests/__tests__ directory.
+func isTestSource(path, ext string) bool {
+	if ext == ".go" {
+		return strings.HasSuffix(path, "_test.go")
+	}
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(path)), "/") {
+		if dir == "tests" || dir == "__tests__" {
+			return true
+		}
+	}
+	stem := strings.TrimSuffix(filepath.Base(path), ext)
+	if strings.HasPrefix(stem, "test_") {
+		return true
+	}
+	for _, suffix := range []string{"_test", "_spec", ".test", ".spec", "Test", "Tests"} {
+		if strings.HasSuffix(stem, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// generatedHeader matches the standard marker for generated source
+// (https://go.dev/s/generatedcode), in // or # comment syntax.
+var generatedHeader = regexp.MustCompile(`^(//|#) Code generated .* DO NOT EDIT\.$`)
+
+// isGeneratedFile reports whether path carries a generated-code header in
+// the comment block before its first line of code.
+func isGeneratedFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if generatedHeader.MatchString(line) {
+			return true
+		}
+		if !strings.HasPrefix(line, "//") && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return false
+}
+
+// matchesLOCIgnore reports whether path, or one of its parent
+// directories, matches a Cobbler.LOCIgnore glob.
+func matchesLOCIgnore(path string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	path = filepath.Clean(path)
+	for p := path; p != "." && p != string(filepath.Separator); p = filepath.Dir(p) {
+		for _, pattern := range patterns {
+			if ok, _ := filepath.Match(filepath.Clean(pattern), p); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Stats prints lines of code per language and documentation word counts
+// as YAML.
 func (o *Orchestrator) Stats() error {
 	rec, err := o.CollectStats()
 	if err != nil {
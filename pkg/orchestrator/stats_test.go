@@ -47,6 +47,160 @@ func TestCollectStats_CountsGoFiles(t *testing.T) {
 	}
 }
 
+func TestCollectStats_CountsEachSourceExtension(t *testing.T) {
+	// Not parallel: uses os.Chdir which affects all goroutines.
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "tests"), 0o755)
+	os.WriteFile(filepath.Join(dir, "a.go"), []byte("1\n2\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "a_test.go"), []byte("1\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "app.py"), []byte("1\n2\n3\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "test_app.py"), []byte("1\n2\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "tests", "helpers.py"), []byte("1\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("1\n2\n"), 0o644)
+	os.MkdirAll(filepath.Join(dir, "web", "node_modules", "dep"), 0o755)
+	os.WriteFile(filepath.Join(dir, "web", "node_modules", "dep", "index.py"), []byte("skip\nskip\n"), 0o644)
+
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(origDir) })
+
+	o := New(Config{Project: ProjectConfig{SourceExtensions: []string{".py"}}})
+	rec, err := o.CollectStats()
+	if err != nil {
+		t.Fatalf("CollectStats: %v", err)
+	}
+	if rec.GoProdLOC != 2 || rec.GoTestLOC != 1 {
+		t.Errorf("Go LOC = %d/%d, want 2/1 (Go counted even when not listed)", rec.GoProdLOC, rec.GoTestLOC)
+	}
+	if rec.ProdLOCByLang["py"] != 3 || rec.TestLOCByLang["py"] != 3 {
+		t.Errorf("py LOC = %d/%d, want 3/3", rec.ProdLOCByLang["py"], rec.TestLOCByLang["py"])
+	}
+	if rec.ProdLOCByLang["go"] != 2 || rec.TestLOCByLang["go"] != 1 {
+		t.Errorf("go by-lang LOC = %d/%d, want 2/1", rec.ProdLOCByLang["go"], rec.TestLOCByLang["go"])
+	}
+	if _, ok := rec.ProdLOCByLang["txt"]; ok {
+		t.Error("unlisted extension .txt was counted")
+	}
+}
+
+func TestCollectStats_SkipsGeneratedAndIgnoredFiles(t *testing.T) {
+	// Not parallel: uses os.Chdir which affects all goroutines.
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "third_party", "lib"), 0o755)
+	os.MkdirAll(filepath.Join(dir, "mocks"), 0o755)
+	os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\n\nfunc A() {}\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "a.pb.go"), []byte("// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage a\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "third_party", "lib", "x.go"), []byte("package lib\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "mocks", "m.go"), []byte("package mocks\n"), 0o644)
+
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(origDir) })
+
+	o := New(Config{Cobbler: CobblerConfig{LOCIgnore: []string{"third_party", "mocks/*.go"}}})
+	rec, err := o.CollectStats()
+	if err != nil {
+		t.Fatalf("CollectStats: %v", err)
+	}
+	if rec.GoProdLOC != 3 {
+		t.Errorf("GoProdLOC = %d, want 3 (only a.go)", rec.GoProdLOC)
+	}
+}
+
+func TestIsGeneratedFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	cases := map[string]struct {
+		content string
+		want    bool
+	}{
+		"gen.go":     {"// Code generated by mockgen. DO NOT EDIT.\npackage m\n", true},
+		"late.go":    {"// Copyright x\n\n// Code generated by stringer; DO NOT EDIT.\n\npackage m\n", true},
+		"gen.py":     {"# Code generated by protoc. DO NOT EDIT.\nimport x\n", true},
+		"body.go":    {"package m\n\n// Code generated by x. DO NOT EDIT.\n", false},
+		"plain.go":   {"package m\n", false},
+		"inexact.go": {"// Code generated by x. Do not edit.\npackage m\n", false},
+	}
+	for name, tc := range cases {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(tc.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if got := isGeneratedFile(path); got != tc.want {
+			t.Errorf("isGeneratedFile(%s) = %v, want %v", name, got, tc.want)
+		}
+	}
+}
+
+func TestMatchesLOCIgnore(t *testing.T) {
+	t.Parallel()
+	patterns := []string{"third_party", "internal/mocks/*", "*.gen.go"}
+	cases := map[string]bool{
+		"third_party/a/b.go":        true,
+		"internal/mocks/store.go":   true,
+		"internal/mocks/sub/x.go":   true,
+		"api.gen.go":                true,
+		"internal/store/store.go":   false,
+		"pkg/third_party_client.go": false,
+	}
+	for path, want := range cases {
+		if got := matchesLOCIgnore(path, patterns); got != want {
+			t.Errorf("matchesLOCIgnore(%q) = %v, want %v", path, got, want)
+		}
+	}
+	if matchesLOCIgnore("a.go", nil) {
+		t.Error("matchesLOCIgnore with no patterns = true")
+	}
+}
+
+func TestIsTestSource(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		path, ext string
+		want      bool
+	}{
+		{"pkg/a_test.go", ".go", true},
+		{"pkg/a.go", ".go", false},
+		{"tests/integration.go", ".go", false},
+		{"src/test_app.py", ".py", true},
+		{"src/app_test.py", ".py", true},
+		{"src/app.py", ".py", false},
+		{"web/button.test.ts", ".ts", true},
+		{"web/button.spec.ts", ".ts", true},
+		{"web/__tests__/button.ts", ".ts", true},
+		{"src/main/FooTest.java", ".java", true},
+		{"crate/tests/it.rs", ".rs", true},
+		{"crate/src/lib.rs", ".rs", false},
+		{"src/contest.py", ".py", false},
+	}
+	for _, tc := range cases {
+		if got := isTestSource(tc.path, tc.ext); got != tc.want {
+			t.Errorf("isTestSource(%q, %q) = %v, want %v", tc.path, tc.ext, got, tc.want)
+		}
+	}
+}
+
+func TestSourceExtension_PrefersLongestMatch(t *testing.T) {
+	t.Parallel()
+	exts := []string{".ts", ".d.ts", ".go"}
+	if got := sourceExtension("types/x.d.ts", exts); got != ".d.ts" {
+		t.Errorf("sourceExtension = %q, want .d.ts", got)
+	}
+	if got := sourceExtension("README.md", exts); got != "" {
+		t.Errorf("sourceExtension = %q, want empty", got)
+	}
+}
+
 func TestCollectStats_SkipsVendorAndBinaryDir(t *testing.T) {
 	// Not parallel: uses os.Chdir.
 	dir := t.TempDir()
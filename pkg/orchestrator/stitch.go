@@ -4,14 +4,17 @@
 package orchestrator
 
 import (
+	"bytes"
 	_ "embed"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"text/template"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -50,74 +53,22 @@ func (o *Orchestrator) RunStitch() (int, error) {
 func (o *Orchestrator) RunStitchN(limit int) (int, error) {
 	setPhase("stitch")
 	defer clearPhase()
+	defer o.handleInterrupts()()
 	stitchStart := time.Now()
-
-	// Start orchestrator log capture.
-	if hdir := o.historyDir(); hdir != "" {
-		logPath := filepath.Join(hdir,
-			stitchStart.Format("2006-01-02-15-04-05")+"-stitch-orchestrator.log")
-		if err := openLogSink(logPath); err != nil {
-			logf("warning: could not open orchestrator log: %v", err)
-		} else {
-			defer closeLogSink()
-		}
-	}
+	defer o.openStitchLog(stitchStart)()
 
 	logf("starting (limit=%d)", limit)
 	o.logConfig("stitch")
 
-	if err := o.checkClaude(); err != nil {
+	if err := o.Prewarm(); err != nil {
 		return 0, err
 	}
 
-	branch, err := o.resolveBranch(o.cfg.Generation.Branch)
+	env, done, err := o.prepareStitch()
 	if err != nil {
-		logf("resolveBranch failed: %v", err)
 		return 0, err
 	}
-	logf("resolved branch=%s", branch)
-	if currentGeneration == "" {
-		setGeneration(branch)
-		defer clearGeneration()
-	}
-
-	if err := ensureOnBranch(branch); err != nil {
-		logf("ensureOnBranch failed: %v", err)
-		return 0, fmt.Errorf("switching to branch: %w", err)
-	}
-
-	repoRoot, err := os.Getwd()
-	if err != nil {
-		return 0, fmt.Errorf("getting working directory: %w", err)
-	}
-	logf("repoRoot=%s", repoRoot)
-
-	// Resolve GitHub repo and ensure cobbler labels exist.
-	ghRepo, err := detectGitHubRepo(repoRoot, o.cfg)
-	if err != nil {
-		logf("detectGitHubRepo failed: %v", err)
-		return 0, fmt.Errorf("detecting GitHub repo: %w", err)
-	}
-	generation := branch
-	logf("using GitHub repo %s generation %s for issues", ghRepo, generation)
-	if err := ensureCobblerLabels(ghRepo); err != nil {
-		logf("ensureCobblerLabels warning: %v", err)
-	}
-
-	worktreeBase := worktreeBasePath()
-	logf("worktreeBase=%s", worktreeBase)
-
-	baseBranch, err := gitCurrentBranch(".")
-	if err != nil {
-		return 0, fmt.Errorf("getting current branch: %w", err)
-	}
-	logf("baseBranch=%s", baseBranch)
-
-	logf("recovering stale tasks")
-	if err := o.recoverStaleTasks(baseBranch, worktreeBase, ghRepo, generation); err != nil {
-		logf("recovery failed: %v", err)
-		return 0, fmt.Errorf("recovery: %w", err)
-	}
+	defer done()
 
 	totalTasks := 0
 	// failedTaskIDs tracks tasks that returned errTaskReset in this cycle.
@@ -125,13 +76,21 @@ func (o *Orchestrator) RunStitchN(limit int) (int, error) {
 	// so without this set the stitch loop retries the same task indefinitely.
 	failedTaskIDs := map[string]struct{}{}
 	for {
+		if o.interrupted() {
+			logf("interrupted after %d task(s), stopping stitch", totalTasks)
+			return totalTasks, errInterrupted
+		}
 		if limit > 0 && totalTasks >= limit {
 			logf("reached per-cycle limit (%d), pausing for measure", limit)
 			break
 		}
+		if o.costBudgetExhausted() {
+			logf("reached cost ceiling ($%.2f spent, max $%.2f), stopping stitch", o.spend.total(), o.cfg.Generation.MaxCostUSD)
+			break
+		}
 
 		logf("looking for next ready task (completed %d so far)", totalTasks)
-		task, err := pickTask(baseBranch, worktreeBase, ghRepo, generation)
+		task, err := pickTask(env.baseBranch, env.worktreeBase, env.repo, env.generation)
 		if err != nil {
 			logf("no more tasks: %v", err)
 			break
@@ -146,7 +105,11 @@ func (o *Orchestrator) RunStitchN(limit int) (int, error) {
 
 		taskStart := time.Now()
 		logf("executing task %d: id=%s title=%q", totalTasks+1, task.id, task.title)
-		if err := o.doOneTask(task, baseBranch, repoRoot); err != nil {
+		if err := o.doOneTask(task, env.baseBranch, env.repoRoot); err != nil {
+			if errors.Is(err, errTaskReset) && o.interrupted() {
+				logf("task %s was reset on interrupt after %s, stopping stitch", task.id, time.Since(taskStart).Round(time.Second))
+				return totalTasks, errInterrupted
+			}
 			if errors.Is(err, errTaskReset) {
 				logf("task %s was reset after %s, continuing", task.id, time.Since(taskStart).Round(time.Second))
 				failedTaskIDs[task.id] = struct{}{}
@@ -158,12 +121,146 @@ func (o *Orchestrator) RunStitchN(limit int) (int, error) {
 		logf("task %s completed in %s", task.id, time.Since(taskStart).Round(time.Second))
 
 		totalTasks++
+		o.stitched.add()
 	}
 
 	logf("completed %d task(s) in %s", totalTasks, time.Since(stitchStart).Round(time.Second))
 	return totalTasks, nil
 }
 
+// openStitchLog starts capturing the orchestrator log into the history
+// directory for a stitch run that began at start. The returned func stops
+// the capture.
+func (o *Orchestrator) openStitchLog(start time.Time) func() {
+	hdir := o.historyDir()
+	if hdir == "" {
+		return func() {}
+	}
+	logPath := filepath.Join(hdir, start.Format("2006-01-02-15-04-05")+"-stitch-orchestrator.log")
+	if err := openLogSink(logPath); err != nil {
+		logf("warning: could not open orchestrator log: %v", err)
+		return func() {}
+	}
+	return closeLogSink
+}
+
+// stitchEnv is the repository state a stitch run works against.
+type stitchEnv struct {
+	repoRoot     string
+	repo         string // GitHub owner/repo
+	generation   string
+	worktreeBase string
+	baseBranch   string
+}
+
+// prepareStitch resolves and checks out the generation branch, detects the
+// GitHub repo, and recovers stale tasks left by an interrupted run. The
+// returned func clears the generation it set; call it when the run ends.
+func (o *Orchestrator) prepareStitch() (stitchEnv, func(), error) {
+	var env stitchEnv
+	done := func() {}
+	branch, err := o.resolveBranch(o.cfg.Generation.Branch)
+	if err != nil {
+		logf("resolveBranch failed: %v", err)
+		return env, done, err
+	}
+	logf("resolved branch=%s", branch)
+	if currentGeneration == "" {
+		setGeneration(branch)
+		done = clearGeneration
+	}
+	fail := func(err error) (stitchEnv, func(), error) {
+		done()
+		return env, func() {}, err
+	}
+
+	if err := ensureOnBranch(branch); err != nil {
+		logf("ensureOnBranch failed: %v", err)
+		return fail(fmt.Errorf("switching to branch: %w", err))
+	}
+
+	env.repoRoot, err = os.Getwd()
+	if err != nil {
+		return fail(fmt.Errorf("getting working directory: %w", err))
+	}
+	logf("repoRoot=%s", env.repoRoot)
+
+	// Resolve GitHub repo and ensure cobbler labels exist.
+	env.repo, err = detectGitHubRepo(env.repoRoot, o.cfg)
+	if err != nil {
+		logf("detectGitHubRepo failed: %v", err)
+		return fail(fmt.Errorf("detecting GitHub repo: %w", err))
+	}
+	env.generation = branch
+	logf("using GitHub repo %s generation %s for issues", env.repo, env.generation)
+	if err := ensureCobblerLabels(env.repo); err != nil {
+		logf("ensureCobblerLabels warning: %v", err)
+	}
+
+	env.worktreeBase = worktreeBasePath(o.cfg.Cobbler.WorktreeDir)
+	logf("worktreeBase=%s", env.worktreeBase)
+
+	env.baseBranch, err = gitCurrentBranch(".")
+	if err != nil {
+		return fail(fmt.Errorf("getting current branch: %w", err))
+	}
+	logf("baseBranch=%s", env.baseBranch)
+
+	logf("recovering stale tasks")
+	if err := o.recoverStaleTasks(env.baseBranch, env.worktreeBase, env.repo, env.generation); err != nil {
+		logf("recovery failed: %v", err)
+		return fail(fmt.Errorf("recovery: %w", err))
+	}
+	return env, done, nil
+}
+
+// StitchTask stitches the single open task whose issue number is issueID
+// (an optional leading # is ignored) instead of the next ready one. It
+// runs the same branch resolution, stale-task recovery, worktree, merge,
+// and history flow as RunStitchN. The task must be cobbler-ready and not
+// already in progress, and the generation cost ceiling must not be
+// reached (GH-560).
+func (o *Orchestrator) StitchTask(issueID string) error {
+	setPhase("stitch")
+	defer clearPhase()
+	defer o.handleInterrupts()()
+	start := time.Now()
+	defer o.openStitchLog(start)()
+
+	logf("StitchTask: starting (issue=%s)", issueID)
+	o.logConfig("stitch")
+
+	if err := o.Prewarm(); err != nil {
+		return err
+	}
+
+	env, done, err := o.prepareStitch()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	if o.costBudgetExhausted() {
+		return fmt.Errorf("reached cost ceiling ($%.2f spent, max $%.2f), not stitching task %s",
+			o.spend.total(), o.cfg.Generation.MaxCostUSD, issueID)
+	}
+
+	task, err := pickTaskByID(issueID, env.baseBranch, env.worktreeBase, env.repo, env.generation)
+	if err != nil {
+		return err
+	}
+	logf("StitchTask: executing task id=%s title=%q", task.id, task.title)
+	if err := o.doOneTask(task, env.baseBranch, env.repoRoot); err != nil {
+		if errors.Is(err, errTaskReset) && o.interrupted() {
+			return errInterrupted
+		}
+		logf("StitchTask: task %s failed after %s: %v", task.id, time.Since(start).Round(time.Second), err)
+		return fmt.Errorf("executing task %s: %w", task.id, err)
+	}
+	logf("StitchTask: task %s completed in %s", task.id, time.Since(start).Round(time.Second))
+	return nil
+}
+
 // taskBranchName returns the git branch name for a stitch task.
 // Uses "task/<base>-<id>" instead of "<base>/task/<id>" to avoid
 // ref conflicts when the base branch is "main".
@@ -183,9 +280,10 @@ type stitchTask struct {
 	issueType   string
 	branchName  string
 	worktreeDir string
-	ghNumber    int    // GitHub issue number — used for closing/labelling
-	generation  string // generation label value
-	repo        string // GitHub owner/repo
+	ghNumber    int      // GitHub issue number — used for closing/labelling
+	generation  string   // generation label value
+	repo        string   // GitHub owner/repo
+	sparsePaths []string // sparse-checkout cone for the worktree; nil = full checkout
 }
 
 // recoverStaleTasks cleans up task branches and orphaned in_progress issues
@@ -197,6 +295,10 @@ func (o *Orchestrator) recoverStaleTasks(baseBranch, worktreeBase, repo, generat
 	logf("recoverStaleTasks: checking for orphaned in_progress issues")
 	orphanedIssues := resetOrphanedIssues(baseBranch, repo, generation)
 
+	if n := pruneKeptWorktrees(worktreeBase, o.cfg.KeptWorktreeMaxAge(), time.Now()); n > 0 {
+		logf("recoverStaleTasks: pruned %d kept failed worktree(s)", n)
+	}
+
 	logf("recoverStaleTasks: pruning worktrees")
 	if err := gitWorktreePrune("."); err != nil {
 		logf("recoverStaleTasks: worktree prune warning: %v", err)
@@ -291,6 +393,23 @@ func pickTask(baseBranch, worktreeBase, repo, generation string) (stitchTask, er
 		return stitchTask{}, fmt.Errorf("no tasks available")
 	}
 
+	return newStitchTask(iss, baseBranch, worktreeBase, repo, generation), nil
+}
+
+// pickTaskByID claims the open issue numbered issueID for StitchTask,
+// failing when it is not ready or already in progress (GH-560).
+func pickTaskByID(issueID, baseBranch, worktreeBase, repo, generation string) (stitchTask, error) {
+	logf("pickTaskByID: claiming #%s repo=%s generation=%s", strings.TrimPrefix(issueID, "#"), repo, generation)
+	iss, err := claimIssueByID(repo, generation, issueID)
+	if err != nil {
+		return stitchTask{}, err
+	}
+	return newStitchTask(iss, baseBranch, worktreeBase, repo, generation), nil
+}
+
+// newStitchTask builds the stitch task for a claimed issue and logs any
+// description validation warnings.
+func newStitchTask(iss cobblerIssue, baseBranch, worktreeBase, repo, generation string) stitchTask {
 	id := fmt.Sprintf("%d", iss.Number)
 	task := stitchTask{
 		id:          id,
@@ -308,11 +427,85 @@ func pickTask(baseBranch, worktreeBase, repo, generation string) (stitchTask, er
 	if err := validateIssueDescription(task.description); err != nil {
 		logf("pickTask: description validation warning: %v", err)
 	}
+	for _, v := range validateIssueSchema(task.description, embeddedIssueFormat()) {
+		logf("pickTask: description schema warning: %s", v)
+	}
 
 	logf("pickTask: picked #%d id=%s branch=%s worktree=%s", iss.Number, task.id, task.branchName, task.worktreeDir)
 	logf("pickTask: title=%q", task.title)
 	logf("pickTask: descriptionLen=%d", len(task.description))
-	return task, nil
+	return task
+}
+
+// selectPreviewIssue returns the open issue StitchPrompt previews: the
+// one whose number matches taskID (an optional leading # is ignored), or
+// the issue stitch would pick next when taskID is empty (GH-511).
+func selectPreviewIssue(issues []cobblerIssue, taskID string) (cobblerIssue, error) {
+	taskID = strings.TrimPrefix(strings.TrimSpace(taskID), "#")
+	if taskID == "" {
+		iss, ok := selectReadyIssue(issues)
+		if !ok {
+			return cobblerIssue{}, fmt.Errorf("no ready tasks")
+		}
+		return iss, nil
+	}
+	for _, iss := range issues {
+		if fmt.Sprintf("%d", iss.Number) == taskID {
+			return iss, nil
+		}
+	}
+	return cobblerIssue{}, fmt.Errorf("task %s is not an open cobbler issue", taskID)
+}
+
+// StitchPrompt prints the stitch prompt for one task to stdout without
+// creating a worktree, claiming the task, or invoking Claude (GH-511).
+// taskID selects an open task by issue number; when empty, the next ready
+// task is used. The prompt is built from the current checkout with the
+// same required_reading filtering and context budget as a real stitch, so
+// run it on the generation branch to see what Claude would receive.
+func (o *Orchestrator) StitchPrompt(taskID string) error {
+	branch, err := o.resolveBranch(o.cfg.Generation.Branch)
+	if err != nil {
+		return err
+	}
+	if current, err := gitCurrentBranch("."); err == nil && current != branch {
+		logf("StitchPrompt: warning: on %s, generation branch is %s; source reflects the current checkout", current, branch)
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting working directory: %w", err)
+	}
+	repo, err := detectGitHubRepo(cwd, o.cfg)
+	if err != nil {
+		return fmt.Errorf("detecting GitHub repo: %w", err)
+	}
+	issues, err := listOpenCobblerIssues(repo, branch)
+	if err != nil {
+		return fmt.Errorf("listing cobbler issues for %s: %w", branch, err)
+	}
+	iss, err := selectPreviewIssue(issues, taskID)
+	if err != nil {
+		return err
+	}
+
+	id := fmt.Sprintf("%d", iss.Number)
+	prompt, err := o.buildStitchPrompt(stitchTask{
+		id:          id,
+		title:       iss.Title,
+		description: iss.Description,
+		issueType:   "task",
+		branchName:  taskBranchName(branch, id),
+		worktreeDir: cwd,
+		ghNumber:    iss.Number,
+		generation:  branch,
+		repo:        repo,
+	})
+	if err != nil {
+		return fmt.Errorf("building stitch prompt: %w", err)
+	}
+	logf("StitchPrompt: task #%d %q, %d bytes", iss.Number, iss.Title, len(prompt))
+	fmt.Print(prompt)
+	return nil
 }
 
 // parseRequiredReading extracts the required_reading list from a YAML task
@@ -331,6 +524,105 @@ func parseRequiredReading(description string) []string {
 	return parsed.RequiredReading
 }
 
+// missingRequiredReading returns the required_reading entries in
+// description that name a source file (per extensions) which does not
+// exist under dir. Parenthetical notes and line ranges are stripped
+// before the check.
+func missingRequiredReading(dir, description string, extensions []string) []string {
+	var missing []string
+	for _, entry := range parseRequiredReading(description) {
+		clean, _, _ := parseLineRange(stripParenthetical(entry))
+		if !hasSourceExtension(clean, extensions) {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(dir, clean))
+		if err != nil || info.IsDir() {
+			missing = append(missing, clean)
+		}
+	}
+	return missing
+}
+
+// parseTaskTimeout extracts timeout_sec from a YAML task description.
+// Returns 0 if absent, not positive, or unparseable.
+func parseTaskTimeout(description string) int {
+	var parsed struct {
+		TimeoutSec int `yaml:"timeout_sec"`
+	}
+	if err := yaml.Unmarshal([]byte(description), &parsed); err != nil || parsed.TimeoutSec < 0 {
+		return 0
+	}
+	return parsed.TimeoutSec
+}
+
+// taskClaudeTimeout returns the Claude max time for a stitch task and a
+// description of where it came from for the log (GH-517). A task's
+// timeout_sec replaces Claude.MaxTimeSec, clamped to Claude.MaxTaskTimeSec.
+func (o *Orchestrator) taskClaudeTimeout(description string) (time.Duration, string) {
+	requested := parseTaskTimeout(description)
+	if requested == 0 {
+		return o.cfg.ClaudeTimeout(), "max_time_sec"
+	}
+	if max := o.cfg.Claude.MaxTaskTimeSec; max > 0 && requested > max {
+		return time.Duration(max) * time.Second, fmt.Sprintf("timeout_sec=%d clamped to max_task_time_sec", requested)
+	}
+	return time.Duration(requested) * time.Second, "timeout_sec"
+}
+
+// parseTaskFiles extracts the file paths from the files list of a YAML task
+// description. Entries may be plain paths or mappings with a path key, as
+// defined by the issue-format constitution. Returns nil if the field is
+// absent or unparseable.
+func parseTaskFiles(description string) []string {
+	if description == "" {
+		return nil
+	}
+	var parsed struct {
+		Files []any `yaml:"files"`
+	}
+	if err := yaml.Unmarshal([]byte(description), &parsed); err != nil {
+		return nil
+	}
+	var paths []string
+	for _, f := range parsed.Files {
+		switch v := f.(type) {
+		case string:
+			paths = append(paths, v)
+		case map[string]any:
+			if p, ok := v["path"].(string); ok {
+				paths = append(paths, p)
+			}
+		}
+	}
+	return paths
+}
+
+// taskFileDirs returns the sorted directories of the files listed in a
+// task description, skipping files at the repository root.
+func taskFileDirs(description string) []string {
+	seen := map[string]bool{}
+	var dirs []string
+	for _, f := range parseTaskFiles(description) {
+		d := strings.Trim(filepath.ToSlash(filepath.Dir(stripParenthetical(f))), "/")
+		if d == "." || d == "" || seen[d] {
+			continue
+		}
+		seen[d] = true
+		dirs = append(dirs, d)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// stitchModulePath returns the Go module path used to resolve local
+// imports: Project.ModulePath when set, otherwise the go.mod in root.
+func (o *Orchestrator) stitchModulePath(root string) string {
+	if o.cfg.Project.ModulePath != "" {
+		return o.cfg.Project.ModulePath
+	}
+	return goModModulePath(root)
+}
+
 // scopeSourceDirs narrows GoSourceDirs based on the task description's files
 // field (GH-1005). For each configured dir, if the task's files reference a
 // sub-directory two levels deep (e.g. "cmd/cat/main.go" under "cmd/"), only
@@ -418,8 +710,17 @@ func (o *Orchestrator) doOneTask(task stitchTask, baseBranch, repoRoot string) e
 	// The cobbler-in-progress label was added by pickReadyIssue; no separate claim step is needed.
 	logf("doOneTask: task #%d claimed via pickReadyIssue label", task.ghNumber)
 
-	// Create worktree.
+	// Create worktree, sparse when configured for the deliverable type (GH-478).
+	task.sparsePaths = o.worktreeSparsePaths(task)
 	logf("doOneTask: creating worktree for %s", task.id)
+	if err := checkWorktreeLimit(filepath.Dir(task.worktreeDir), task.worktreeDir, o.cfg.Cobbler.MaxWorktrees); err != nil {
+		// Release the claim so the task is ready again once the stale
+		// worktrees are removed.
+		if lerr := removeInProgressLabel(task.repo, task.ghNumber); lerr != nil {
+			logf("doOneTask: WARNING removeInProgressLabel failed for #%d: %v", task.ghNumber, lerr)
+		}
+		return err
+	}
 	wtStart := time.Now()
 	if err := createWorktree(task); err != nil {
 		logf("doOneTask: createWorktree failed after %s: %v", time.Since(wtStart).Round(time.Second), err)
@@ -428,16 +729,48 @@ func (o *Orchestrator) doOneTask(task stitchTask, baseBranch, repoRoot string) e
 	logf("doOneTask: worktree created in %s", time.Since(wtStart).Round(time.Second))
 
 	// Snapshot LOC before Claude.
+	// A sparse worktree only contains part of the tree, so measure LOC
+	// before from the worktree itself to keep the delta consistent.
 	locBefore := o.captureLOC()
+	if len(task.sparsePaths) > 0 {
+		locBefore = o.captureLOCAt(task.worktreeDir)
+	}
 	logf("doOneTask: locBefore prod=%d test=%d", locBefore.Production, locBefore.Test)
 
+	// A wrong required_reading path silently drops context from the
+	// prompt; warn about each one and, when strict, do not stitch (GH-541).
+	missingReading := missingRequiredReading(task.worktreeDir, task.description, o.cfg.Project.SourceExtensions)
+	for _, path := range missingReading {
+		logf("doOneTask: WARNING task %s required_reading %s does not exist in the worktree", task.id, path)
+	}
+	if len(missingReading) > 0 && o.cfg.Cobbler.StrictRequiredReading {
+		reason := fmt.Sprintf("required_reading not found: %s", strings.Join(missingReading, ", "))
+		o.saveHistoryStats(time.Now().Format("2006-01-02-15-04-05"), "stitch", HistoryStats{
+			Caller:                 "stitch",
+			Generation:             task.generation,
+			TaskID:                 task.id,
+			TaskTitle:              task.title,
+			Status:                 "failed",
+			Error:                  reason,
+			StartedAt:              taskStart.UTC().Format(time.RFC3339),
+			Duration:               time.Since(taskStart).Round(time.Second).String(),
+			DurationS:              int(time.Since(taskStart).Seconds()),
+			LOCBefore:              locBefore,
+			MissingRequiredReading: len(missingReading),
+		})
+		o.failTask(task, reason, taskStart)
+		return errTaskReset
+	}
+
 	// Build and run prompt.
 	prompt, promptErr := o.buildStitchPrompt(task)
 	if promptErr != nil {
 		o.failTask(task, "prompt build failure", taskStart)
 		return promptErr
 	}
-	logf("doOneTask: prompt built, length=%d bytes", len(prompt))
+	promptTokens := tokensForBytes([]byte(prompt), o.cfg.Cobbler.ContextCharsPerToken)
+	contextTrimmed := o.lastTrim.removed()
+	logf("doOneTask: prompt built, length=%d bytes (~%d tokens)", len(prompt), promptTokens)
 
 	// Post "started" comment so the issue reflects pickup immediately.
 	commentCobblerIssue(task.repo, task.ghNumber, fmt.Sprintf(
@@ -447,54 +780,110 @@ func (o *Orchestrator) doOneTask(task stitchTask, baseBranch, repoRoot string) e
 	historyTS := time.Now().Format("2006-01-02-15-04-05")
 	o.saveHistoryPrompt(historyTS, "stitch", prompt)
 
-	logf("doOneTask: invoking Claude for task %s", task.id)
+	attempt := o.stitchAttempt(task.id)
+	model := o.stitchModelForAttempt(attempt)
+	claudeArgs := deniedCommandArgs(o.cfg.Cobbler.StitchDeniedCommands)
+	if model != "" && model != o.cfg.Claude.StitchModel {
+		logf("doOneTask: escalating task %s to model %s (attempt %d)", task.id, model, attempt)
+	}
+	claudeArgs = append(claudeArgs, modelArgs(model)...)
+
+	timeout, timeoutSource := o.taskClaudeTimeout(task.description)
+	logf("doOneTask: invoking Claude for task %s (attempt %d, timeout %s from %s)", task.id, attempt, timeout, timeoutSource)
 	claudeStart := time.Now()
-	tokens, claudeErr := o.runClaude(prompt, task.worktreeDir, o.cfg.Silence())
+	tokens, claudeErr := o.runClaudeWithTimeout(prompt, task.worktreeDir, timeout, o.cfg.Silence(), claudeArgs...)
 
 	// Save Claude log immediately — even on failure, partial output is valuable.
 	o.saveHistoryLog(historyTS, "stitch", tokens.RawOutput)
 
+	// taskStats fills the fields every history entry of this invocation
+	// shares; callers add the outcome-specific ones.
+	taskStats := func(status, errMsg string) HistoryStats {
+		elapsed := time.Since(taskStart)
+		return HistoryStats{
+			Caller:     "stitch",
+			Generation: task.generation,
+			TaskID:     task.id,
+			TaskTitle:  task.title,
+			Attempt:    attempt,
+			Model:      model,
+			Status:     status,
+			Error:      errMsg,
+			StartedAt:  claudeStart.UTC().Format(time.RFC3339),
+			Duration:   elapsed.Round(time.Second).String(),
+			DurationS:  int(elapsed.Seconds()),
+			Tokens:     historyTokens{Input: tokens.InputTokens, Output: tokens.OutputTokens, CacheCreation: tokens.CacheCreationTokens, CacheRead: tokens.CacheReadTokens},
+			CostUSD:    tokens.CostUSD,
+			LOCBefore:  locBefore,
+
+			MissingRequiredReading: len(missingReading),
+
+			PromptBytes:          len(prompt),
+			PromptTokensEstimate: promptTokens,
+			ContextTrimmed:       contextTrimmed,
+		}
+	}
+
 	if claudeErr != nil {
 		logf("doOneTask: Claude failed for %s after %s: %v", task.id, time.Since(claudeStart).Round(time.Second), claudeErr)
-		o.saveHistoryStats(historyTS, "stitch", HistoryStats{
-			Caller:    "stitch",
-			TaskID:    task.id,
-			TaskTitle: task.title,
-			Status:    "failed",
-			Error:     fmt.Sprintf("claude failure: %v", claudeErr),
-			StartedAt: claudeStart.UTC().Format(time.RFC3339),
-			Duration:  time.Since(taskStart).Round(time.Second).String(),
-			DurationS: int(time.Since(taskStart).Seconds()),
-			Tokens:    historyTokens{Input: tokens.InputTokens, Output: tokens.OutputTokens, CacheCreation: tokens.CacheCreationTokens, CacheRead: tokens.CacheReadTokens},
-			CostUSD:   tokens.CostUSD,
-			LOCBefore: locBefore,
-		})
-		o.failTask(task, "Claude failure", taskStart)
+		o.saveHistoryStats(historyTS, "stitch", taskStats("failed", o.stitchFailure("claude failure", claudeErr)))
+		reason := "Claude failure"
+		var ce *ClaudeError
+		if errors.As(claudeErr, &ce) {
+			reason = fmt.Sprintf("Claude failure (%s): %s", ce.Kind(), ce.Message)
+			if ce.Kind() == ClaudeErrorAuth {
+				// Every later task would fail the same way; stop stitching
+				// instead of resetting and moving on (GH-533).
+				o.failTask(task, reason, taskStart)
+				return fmt.Errorf("claude authentication failed: %w", claudeErr)
+			}
+		}
+		o.failTask(task, reason, taskStart)
 		return errTaskReset
 	}
 	logf("doOneTask: Claude completed for %s in %s", task.id, time.Since(claudeStart).Round(time.Second))
 
+	// Reject syntactically invalid Go before it is committed (GH-494).
+	if err := o.runParseCheck(task); err != nil {
+		o.saveHistoryStats(historyTS, "stitch", taskStats("failed", fmt.Sprintf("parse check failure: %v", err)))
+		o.failTask(task, "parse check failure", taskStart)
+		return errTaskReset
+	}
+
+	// Normalize formatting before the worktree is verified and committed.
+	o.runFormatOnStitch(task)
+
+	// Build/test gate before the task can reach the base branch (GH-512),
+	// with up to MaxStitchRepairs repair sessions on failure (GH-513).
+	verifyErr := o.runVerifyGate(task)
+	for repair := 1; verifyErr != nil && repair <= o.cfg.Cobbler.MaxStitchRepairs; repair++ {
+		verifyErr = o.repairTask(task, prompt, repair, verifyErr, model, timeout, claudeArgs)
+	}
+	if err := verifyErr; err != nil {
+		o.saveHistoryStats(historyTS, "stitch", taskStats("failed", o.stitchFailure("verify failure", err)))
+		o.failTask(task, "verify failure", taskStart)
+		return errTaskReset
+	}
+
 	// Commit Claude's changes in the worktree. Claude does not run git;
 	// the orchestrator manages all git operations externally.
-	if err := commitWorktreeChanges(task); err != nil {
+	if err := commitWorktreeChanges(task, o.cfg.Cobbler.CommitMessageTemplate); err != nil {
 		logf("doOneTask: worktree commit failed for %s: %v", task.id, err)
-		o.saveHistoryStats(historyTS, "stitch", HistoryStats{
-			Caller:    "stitch",
-			TaskID:    task.id,
-			TaskTitle: task.title,
-			Status:    "failed",
-			Error:     fmt.Sprintf("worktree commit failure: %v", err),
-			StartedAt: claudeStart.UTC().Format(time.RFC3339),
-			Duration:  time.Since(taskStart).Round(time.Second).String(),
-			DurationS: int(time.Since(taskStart).Seconds()),
-			Tokens:    historyTokens{Input: tokens.InputTokens, Output: tokens.OutputTokens, CacheCreation: tokens.CacheCreationTokens, CacheRead: tokens.CacheReadTokens},
-			CostUSD:   tokens.CostUSD,
-			LOCBefore: locBefore,
-		})
+		o.saveHistoryStats(historyTS, "stitch", taskStats("failed", fmt.Sprintf("worktree commit failure: %v", err)))
 		o.failTask(task, "worktree commit failure", taskStart)
 		return errTaskReset
 	}
 
+	// Definition-of-done check for the task's deliverable type (GH-474).
+	doneCheck, doneErr := o.runDoneCheck(task)
+	if doneErr != nil {
+		stats := taskStats("failed", doneErr.Error())
+		stats.DoneCheck = doneCheck
+		o.saveHistoryStats(historyTS, "stitch", stats)
+		o.failTask(task, "done check failure", taskStart)
+		return errTaskReset
+	}
+
 	// Capture locAfter from the worktree before merging. The worktree starts
 	// from the current generation branch state and includes Claude's additions,
 	// so this gives the correct post-task LOC without waiting for the merge.
@@ -519,105 +908,242 @@ func (o *Orchestrator) doOneTask(task stitchTask, baseBranch, repoRoot string) e
 		LOCAfter:  locAfter,
 		NumTurns:  tokens.NumTurns,
 	}
-	if err := appendOutcomeTrailers(task.worktreeDir, trailerRec); err != nil {
+	reqTrailer := requirementsTrailer(o.cfg.Cobbler.RequirementsTrailer, taskRequirementIDs(task.description))
+	if err := appendOutcomeTrailers(task.worktreeDir, trailerRec, reqTrailer); err != nil {
 		logf("doOneTask: outcome trailer warning for %s: %v", task.id, err)
 	}
 
-	// Capture pre-merge HEAD for diffstat.
-	preMergeRef, err := gitRevParseHEAD(".")
-	if err != nil {
-		logf("doOneTask: warning getting pre-merge ref: %v", err)
-	}
-
-	// Merge branch back.
-	logf("doOneTask: merging %s into %s", task.branchName, baseBranch)
-	mergeStart := time.Now()
-	if err := mergeBranch(task.branchName, baseBranch, repoRoot); err != nil {
-		logf("doOneTask: merge failed for %s after %s: %v", task.id, time.Since(mergeStart).Round(time.Second), err)
-		o.saveHistoryStats(historyTS, "stitch", HistoryStats{
-			Caller:    "stitch",
-			TaskID:    task.id,
-			TaskTitle: task.title,
-			Status:    "failed",
-			Error:     fmt.Sprintf("merge failure: %v", err),
-			StartedAt: claudeStart.UTC().Format(time.RFC3339),
-			Duration:  time.Since(taskStart).Round(time.Second).String(),
-			DurationS: int(time.Since(taskStart).Seconds()),
-			Tokens:    historyTokens{Input: tokens.InputTokens, Output: tokens.OutputTokens, CacheCreation: tokens.CacheCreationTokens, CacheRead: tokens.CacheReadTokens},
-			CostUSD:   tokens.CostUSD,
-			LOCBefore: locBefore,
-		})
-		o.failTask(task, "merge failure", taskStart)
-		return errTaskReset
-	}
-	logf("doOneTask: merge completed in %s", time.Since(mergeStart).Round(time.Second))
+	var diff diffStat
+	var fileChanges []FileChange
+	reportStatus := "success"
+	rebased := false
+	if o.cfg.Cobbler.DeferMerge {
+		// Deferred merge (GH-465): leave the committed task branch and its
+		// worktree for human review. AcceptTask performs the merge later.
+		var deferErr error
+		diff, fileChanges, deferErr = deferTaskMerge(task, baseBranch)
+		if deferErr != nil {
+			logf("doOneTask: defer merge failed for %s: %v", task.id, deferErr)
+			o.saveHistoryStats(historyTS, "stitch", taskStats("failed", fmt.Sprintf("defer merge failure: %v", deferErr)))
+			o.failTask(task, "defer merge failure", taskStart)
+			return errTaskReset
+		}
+		task.branchName = reviewBranchName(baseBranch, task.id)
+		reportStatus = "review"
+	} else {
+		// Capture pre-merge HEAD for diffstat.
+		preMergeRef, err := gitRevParseHEAD(".")
+		if err != nil {
+			logf("doOneTask: warning getting pre-merge ref: %v", err)
+		}
 
-	// Capture per-file diff stats.
-	diff, diffErr := gitDiffShortstat(preMergeRef, ".")
-	if diffErr != nil {
-		logf("doOneTask: warning getting diff shortstat: %v", diffErr)
-	}
-	logf("doOneTask: diff files=%d ins=%d del=%d", diff.FilesChanged, diff.Insertions, diff.Deletions)
-	fileChanges, fcErr := gitDiffNameStatus(preMergeRef, ".")
-	if fcErr != nil {
-		logf("doOneTask: warning getting file changes: %v", fcErr)
-	}
-	logf("doOneTask: fileChanges=%d entries", len(fileChanges))
+		// Merge branch back.
+		logf("doOneTask: merging %s into %s", task.branchName, baseBranch)
+		mergeStart := time.Now()
+		verify := func() error { return o.runVerifyGate(task) }
+		rebased, err = mergeTaskWithRebase(task, baseBranch, repoRoot, o.cfg.Cobbler.SquashMerge, o.cfg.Cobbler.CommitMessageTemplate, verify)
+		if err != nil {
+			logf("doOneTask: merge failed for %s after %s: %v", task.id, time.Since(mergeStart).Round(time.Second), err)
+			o.saveHistoryStats(historyTS, "stitch", taskStats("failed", fmt.Sprintf("merge failure: %v", err)))
+			o.failTask(task, "merge failure", taskStart)
+			return errTaskReset
+		}
+		logf("doOneTask: merge completed in %s (rebased=%v)", time.Since(mergeStart).Round(time.Second), rebased)
+
+		// Capture per-file diff stats.
+		var diffErr error
+		diff, diffErr = gitDiffShortstat(preMergeRef, ".")
+		if diffErr != nil {
+			logf("doOneTask: warning getting diff shortstat: %v", diffErr)
+		}
+		logf("doOneTask: diff files=%d ins=%d del=%d", diff.FilesChanged, diff.Insertions, diff.Deletions)
+		var fcErr error
+		fileChanges, fcErr = gitDiffNameStatus(preMergeRef, ".")
+		if fcErr != nil {
+			logf("doOneTask: warning getting file changes: %v", fcErr)
+		}
+		logf("doOneTask: fileChanges=%d entries", len(fileChanges))
 
-	// Cleanup worktree.
-	logf("doOneTask: cleaning up worktree for %s", task.id)
-	cleanupWorktree(task)
+		// Cleanup worktree.
+		logf("doOneTask: cleaning up worktree for %s", task.id)
+		if o.cfg.Cobbler.SquashMerge {
+			cleanupSquashedWorktree(task)
+		} else {
+			cleanupWorktree(task)
+		}
+	}
 
 	// Save stitch stats (log was saved immediately after runClaude).
-	taskDuration := time.Since(taskStart)
-	o.saveHistoryStats(historyTS, "stitch", HistoryStats{
-		Caller:        "stitch",
-		TaskID:        task.id,
-		TaskTitle:     task.title,
-		Status:        "success",
-		StartedAt:     claudeStart.UTC().Format(time.RFC3339),
-		Duration:      taskDuration.Round(time.Second).String(),
-		DurationS:     int(taskDuration.Seconds()),
-		Tokens:        historyTokens{Input: tokens.InputTokens, Output: tokens.OutputTokens, CacheCreation: tokens.CacheCreationTokens, CacheRead: tokens.CacheReadTokens},
-		CostUSD:       tokens.CostUSD,
-		NumTurns:      tokens.NumTurns,
-		DurationAPIMs: tokens.DurationAPIMs,
-		SessionID:     tokens.SessionID,
-		LOCBefore:     locBefore,
-		LOCAfter:      locAfter,
-		Diff:          historyDiff{Files: diff.FilesChanged, Insertions: diff.Insertions, Deletions: diff.Deletions},
-	})
+	stats := taskStats("success", "")
+	stats.DoneCheck = doneCheck
+	stats.NumTurns = tokens.NumTurns
+	stats.DurationAPIMs = tokens.DurationAPIMs
+	stats.SessionID = tokens.SessionID
+	stats.LOCAfter = locAfter
+	stats.Diff = historyDiff{Files: diff.FilesChanged, Insertions: diff.Insertions, Deletions: diff.Deletions}
+	o.saveHistoryStats(historyTS, "stitch", stats)
 
 	// Save stitch report with per-file diffstat.
 	o.saveHistoryReport(historyTS, StitchReport{
 		TaskID:    task.id,
 		TaskTitle: task.title,
-		Status:    "success",
+		Status:    reportStatus,
 		Branch:    task.branchName,
 		Diff:      historyDiff{Files: diff.FilesChanged, Insertions: diff.Insertions, Deletions: diff.Deletions},
 		Files:     fileChanges,
 		LOCBefore: locBefore,
 		LOCAfter:  locAfter,
+		Rebased:   rebased,
 	})
 
 	// Close task with metrics.
 	rec := InvocationRecord{
 		Caller:    "stitch",
 		StartedAt: claudeStart.UTC().Format(time.RFC3339),
-		DurationS: int(taskDuration.Seconds()),
+		DurationS: stats.DurationS,
 		Tokens:    claudeTokens{Input: tokens.InputTokens, Output: tokens.OutputTokens, CacheCreation: tokens.CacheCreationTokens, CacheRead: tokens.CacheReadTokens, CostUSD: tokens.CostUSD},
 		LOCBefore: locBefore,
 		LOCAfter:  locAfter,
 		Diff:      diffRecord{Files: diff.FilesChanged, Insertions: diff.Insertions, Deletions: diff.Deletions},
 		NumTurns:  tokens.NumTurns,
 	}
-	logf("doOneTask: closing task %s", task.id)
-	o.closeStitchTask(task, rec)
+	if o.cfg.Cobbler.DeferMerge {
+		logf("doOneTask: task %s awaiting review on %s", task.id, task.branchName)
+		o.markTaskForReview(task, rec)
+	} else {
+		logf("doOneTask: closing task %s", task.id)
+		o.closeStitchTask(task, rec)
+	}
 
 	logf("doOneTask: task %s finished in %s", task.id, time.Since(taskStart).Round(time.Second))
 	return nil
 }
 
+// escalatingFailures are the stitch failure reasons that count toward
+// model escalation (GH-466): Claude failing, or its work failing the
+// verify gate. Strict required_reading rejections (GH-541), interrupts
+// (GH-548), and parse-check, commit, done-check and merge failures say
+// nothing about the model and are not counted.
+var escalatingFailures = []string{"claude failure", "verify failure"}
+
+// stitchFailure formats the history error for a stitch that failed with
+// err at the given stage. A failure while the run is being interrupted is
+// recorded as an interrupt, since the signal caused it.
+func (o *Orchestrator) stitchFailure(stage string, err error) string {
+	if o.interrupted() {
+		return fmt.Sprintf("interrupted: %v", err)
+	}
+	return fmt.Sprintf("%s: %v", stage, err)
+}
+
+// stitchAttempt returns the 1-based attempt number for the next stitch of
+// taskID: one more than the number of failed stitch invocations recorded
+// for it in the history directory whose reason is one of
+// escalatingFailures.
+func (o *Orchestrator) stitchAttempt(taskID string) int {
+	attempt := 1
+	for _, e := range loadHistoryStats(o.historyDir()) {
+		if e.Phase != "stitch" || e.Stats.TaskID != taskID || e.Stats.Status != "failed" {
+			continue
+		}
+		for _, reason := range escalatingFailures {
+			if strings.HasPrefix(e.Stats.Error, reason+": ") {
+				attempt++
+				break
+			}
+		}
+	}
+	return attempt
+}
+
+// stitchModelForAttempt returns the model to request for the given attempt
+// number: Claude.StitchModel, or "" to use the CLI default when it is
+// unset. A task is escalated to Cobbler.StitchEscalationModel once it has
+// failed StitchEscalationAfter times (GH-466).
+func (o *Orchestrator) stitchModelForAttempt(attempt int) string {
+	if o.cfg.Cobbler.StitchEscalationModel == "" {
+		return o.cfg.Claude.StitchModel
+	}
+	if attempt <= o.cfg.Cobbler.StitchEscalationAfter {
+		return o.cfg.Claude.StitchModel
+	}
+	return o.cfg.Cobbler.StitchEscalationModel
+}
+
+// worktreeSparsePaths returns the sparse-checkout directories for a task
+// from Cobbler.WorktreeSetup (GH-478). The options are selected by the
+// task's deliverable_type; when they enable sparse checkout, the cone is
+// the directories of the task's files plus the configured extra paths.
+// Returns nil (full checkout) when no options apply or when a task file
+// sits at the repository root.
+func (o *Orchestrator) worktreeSparsePaths(task stitchTask) []string {
+	opts, ok := o.cfg.Cobbler.WorktreeSetup[parseDeliverableType(task.description)]
+	if !ok || !opts.Sparse {
+		return nil
+	}
+	seen := map[string]bool{}
+	var paths []string
+	add := func(p string) bool {
+		p = strings.Trim(filepath.ToSlash(filepath.Clean(p)), "/")
+		if p == "." || p == "" {
+			return false
+		}
+		if !seen[p] {
+			seen[p] = true
+			paths = append(paths, p)
+		}
+		return true
+	}
+	for _, f := range parseTaskFiles(task.description) {
+		if !add(filepath.Dir(stripParenthetical(f))) {
+			logf("worktreeSparsePaths: task %s touches the repository root, using full checkout", task.id)
+			return nil
+		}
+	}
+	for _, p := range opts.Paths {
+		add(p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// checkWorktreeLimit counts the worktree directories under base and
+// refuses to add another when max (Cobbler.MaxWorktrees) are already
+// present (GH-483). Stale worktrees from crashed runs count, so a failed
+// cleanup surfaces here instead of as a full disk. target is not counted
+// when it already exists, since createWorktree reuses it. Worktrees
+// parked on a review branch by DeferMerge (GH-465) are not counted
+// either: they wait for a human, not for cleanup. A max of 0 disables
+// the limit; the count is logged either way.
+func checkWorktreeLimit(base, target string, max int) error {
+	entries, err := os.ReadDir(base)
+	if err != nil && !os.IsNotExist(err) {
+		logf("checkWorktreeLimit: reading %s: %v", base, err)
+		return nil
+	}
+	var branches map[string]string
+	if len(entries) > 0 {
+		branches = gitWorktreeBranches(".")
+	}
+	var existing []string
+	for _, e := range entries {
+		// Kept failed worktrees are bounded by age, not by this limit.
+		if !e.IsDir() || e.Name() == keptWorktreesDir || filepath.Join(base, e.Name()) == target {
+			continue
+		}
+		if strings.HasPrefix(branches[e.Name()], "review/") {
+			continue
+		}
+		existing = append(existing, e.Name())
+	}
+	logf("checkWorktreeLimit: %d existing worktree(s) under %s (max %d)", len(existing), base, max)
+	if max > 0 && len(existing) >= max {
+		return fmt.Errorf("worktree limit reached: %d worktree(s) under %s (max_worktrees=%d): %s; remove stale worktrees with git worktree remove before stitching",
+			len(existing), base, max, strings.Join(existing, ", "))
+	}
+	return nil
+}
+
 func createWorktree(task stitchTask) error {
 	logf("createWorktree: dir=%s branch=%s", task.worktreeDir, task.branchName)
 
@@ -636,6 +1162,16 @@ func createWorktree(task stitchTask) error {
 		logf("createWorktree: branch %s already exists", task.branchName)
 	}
 
+	if len(task.sparsePaths) > 0 {
+		logf("createWorktree: adding sparse worktree with paths %v", task.sparsePaths)
+		if err := addSparseWorktree(task.worktreeDir, task.branchName, task.sparsePaths, "."); err != nil {
+			logf("createWorktree: sparse worktree add failed: %v", err)
+			return fmt.Errorf("adding sparse worktree: %w", err)
+		}
+		logf("createWorktree: worktree ready at %s on branch %s", task.worktreeDir, task.branchName)
+		return nil
+	}
+
 	logf("createWorktree: adding worktree")
 	cmd := gitWorktreeAdd(task.worktreeDir, task.branchName, ".")
 	cmd.Stdout = os.Stdout
@@ -650,7 +1186,8 @@ func createWorktree(task stitchTask) error {
 }
 
 func (o *Orchestrator) buildStitchPrompt(task stitchTask) (string, error) {
-	tmpl, err := parsePromptTemplate(orDefault(o.cfg.Cobbler.StitchPrompt, defaultStitchPrompt))
+	o.lastTrim = budgetTrim{}
+	tmpl, err := parsePromptTemplate(o.resolvePromptTemplate("stitch", o.cfg.Cobbler.StitchPrompt, defaultStitchPrompt))
 	if err != nil {
 		return "", fmt.Errorf("stitch prompt YAML: %w", err)
 	}
@@ -658,8 +1195,8 @@ func (o *Orchestrator) buildStitchPrompt(task stitchTask) (string, error) {
 	executionConst := orDefault(o.cfg.Cobbler.ExecutionConstitution, executionConstitution)
 	goStyleConst := orDefault(o.cfg.Cobbler.GoStyleConstitution, goStyleConstitution)
 
-	// Load per-phase context file (prd003 R9.9). Resolved from the
-	// original working directory before chdir to worktree.
+	// Load per-phase context file (prd003 R9.9) from the cobbler
+	// directory of the main checkout, not the worktree.
 	stitchCtxPath := filepath.Join(o.cfg.Cobbler.Dir, "stitch_context.yaml")
 	phaseCtx, phaseErr := loadPhaseContext(stitchCtxPath)
 	if phaseErr != nil {
@@ -673,27 +1210,37 @@ func (o *Orchestrator) buildStitchPrompt(task stitchTask) (string, error) {
 
 	// Build project context from the worktree directory so source code
 	// reflects the latest state after prior stitches have been merged.
+	// Every path is resolved against the worktree, so the working
+	// directory does not matter.
 	// Scope GoSourceDirs to only directories relevant to this task (GH-1005).
 	var projectCtx *ProjectContext
+	var observed *ObservedConventions
 	if task.worktreeDir != "" {
-		orig, err := os.Getwd()
-		if err != nil {
-			return "", fmt.Errorf("buildStitchPrompt: getwd: %w", err)
+		// Task scoping narrows the phase's source dirs when
+		// stitch_context.yaml sets them (GH-555), else the config's.
+		scopedProject := o.cfg.Project
+		scopedPhase := phaseCtx
+		if phaseCtx != nil && phaseCtx.GoSourceDirs != "" {
+			scopedProject.GoSourceDirs = phaseCtx.sourceDirs(nil)
+			pc := *phaseCtx
+			pc.GoSourceDirs = ""
+			scopedPhase = &pc
 		}
-		if err := os.Chdir(task.worktreeDir); err != nil {
-			logf("buildStitchPrompt: chdir to worktree error: %v", err)
+		if scoped := scopeSourceDirs(scopedProject.GoSourceDirs, task.description); len(scoped) > 0 {
+			logf("buildStitchPrompt: scoped go_source_dirs %v -> %v", scopedProject.GoSourceDirs, scoped)
+			scopedProject.GoSourceDirs = scoped
+		}
+		ctx, ctxErr := buildProjectContext(task.worktreeDir, "", scopedProject, scopedPhase, o.cfg.Cobbler.ExcludeReleases)
+		if ctxErr != nil {
+			logf("buildStitchPrompt: buildProjectContext error: %v", ctxErr)
 		} else {
-			defer os.Chdir(orig)
-			scopedProject := o.cfg.Project
-			if scoped := scopeSourceDirs(o.cfg.Project.GoSourceDirs, task.description); len(scoped) > 0 {
-				logf("buildStitchPrompt: scoped go_source_dirs %v -> %v", o.cfg.Project.GoSourceDirs, scoped)
-				scopedProject.GoSourceDirs = scoped
-			}
-			ctx, ctxErr := buildProjectContext("", scopedProject, phaseCtx)
-			if ctxErr != nil {
-				logf("buildStitchPrompt: buildProjectContext error: %v", ctxErr)
-			} else {
-				projectCtx = ctx
+			projectCtx = ctx
+		}
+		if o.cfg.Cobbler.StitchObservedConventions {
+			observed = detectObservedConventions(task.worktreeDir, scopedProject.GoSourceDirs)
+			if observed != nil {
+				logf("buildStitchPrompt: observed %d convention(s) from %d sampled file(s)",
+					len(observed.Conventions), len(observed.SampledFiles))
 			}
 		}
 	}
@@ -705,25 +1252,71 @@ func (o *Orchestrator) buildStitchPrompt(task stitchTask) (string, error) {
 	if projectCtx != nil {
 		requiredReading := parseRequiredReading(task.description)
 		var sourcePaths []string
+		ranges := make(map[string][]lineRange)
+		wholeFile := make(map[string]bool)
 		for _, entry := range requiredReading {
-			clean := stripParenthetical(entry)
-			if strings.HasSuffix(clean, ".go") {
+			clean, r, hasRange := parseLineRange(stripParenthetical(entry))
+			if !hasSourceExtension(clean, o.cfg.Project.SourceExtensions) {
+				continue
+			}
+			if !slices.Contains(sourcePaths, clean) {
 				sourcePaths = append(sourcePaths, clean)
 			}
+			if hasRange {
+				ranges[clean] = append(ranges[clean], r)
+			} else {
+				wholeFile[clean] = true
+			}
+		}
+		// A path also listed without a range is read in full.
+		for path := range wholeFile {
+			delete(ranges, path)
 		}
 		if len(sourcePaths) > 0 {
 			before := len(projectCtx.SourceCode)
 			projectCtx.SourceCode = filterSourceFiles(projectCtx.SourceCode, sourcePaths)
 			logf("buildStitchPrompt: filtered source files %d -> %d (required_reading has %d source paths)",
 				before, len(projectCtx.SourceCode), len(sourcePaths))
+			// Line-range entries (path:start-end) keep only those lines.
+			if len(ranges) > 0 {
+				projectCtx.SourceCode = sliceSourceLines(projectCtx.SourceCode, ranges)
+				logf("buildStitchPrompt: sliced %d source file(s) to required_reading line ranges", len(ranges))
+			}
+		} else if scoped, scope := packageScopeFiles(task.worktreeDir, projectCtx.SourceCode, parseTaskFiles(task.description), o.stitchModulePath(task.worktreeDir)); scope != nil {
+			// Automatic package scope: without required_reading,
+			// keep the packages of the task's files and their local imports.
+			logf("buildStitchPrompt: derived package scope %v from task files, source files %d -> %d",
+				scope, len(projectCtx.SourceCode), len(scoped))
+			projectCtx.SourceCode = scoped
 		} else {
-			logf("buildStitchPrompt: no source paths in required_reading, keeping all %d source files",
+			logf("buildStitchPrompt: no source paths in required_reading or files, keeping all %d source files",
 				len(projectCtx.SourceCode))
 		}
 
+		// Make sure docs the task itself asks for are present even when
+		// the context rules filtered them out (GH-491).
+		includeRequiredDocs(projectCtx, task.worktreeDir, requiredReading)
+
+		// Count-based cap (Project.MaxSourceFiles): keep required files
+		// first, then files in the directories of the task's files.
+		projectCtx.SourceCode = capSourceFiles(projectCtx.SourceCode, o.cfg.Project.MaxSourceFiles, sourcePaths, taskFileDirs(task.description))
+
+		// Blame summaries for required source files (GH-487), added before
+		// the budget so their size counts against it.
+		if o.cfg.Cobbler.IncludeBlame && len(sourcePaths) > 0 {
+			annotateBlame(projectCtx.SourceCode, sourcePaths, o.cfg.Cobbler.BlameMaxLines, task.worktreeDir)
+		}
+
 		// Context budget enforcement: truncate non-required source files
-		// when the serialized context exceeds MaxContextBytes.
-		applyContextBudget(projectCtx, o.cfg.Cobbler.MaxContextBytes, sourcePaths)
+		// when the context exceeds MaxContextTokens (estimated) or, when
+		// no token budget is set, MaxContextBytes.
+		var requiredDocs []string
+		for _, entry := range requiredReading {
+			if doc := requiredDocPath(entry); doc != "" {
+				requiredDocs = append(requiredDocs, doc)
+			}
+		}
+		o.applyContextBudgets(projectCtx, sourcePaths, requiredDocs)
 	}
 
 	taskContext := fmt.Sprintf("Task ID: %s\nType: %s\nTitle: %s",
@@ -734,7 +1327,7 @@ func (o *Orchestrator) buildStitchPrompt(task stitchTask) (string, error) {
 	// Load OOD context: shared_protocols from ARCHITECTURE.yaml and
 	// package_contracts from any PRD that declares them. These give the
 	// agent structured API context for its dependencies.
-	oodContracts, oodProtocols := loadOODPromptContext()
+	oodContracts, oodProtocols := loadOODPromptContext(task.worktreeDir)
 	if len(oodProtocols) > 0 {
 		logf("buildStitchPrompt: injecting %d shared_protocols", len(oodProtocols))
 	}
@@ -742,6 +1335,11 @@ func (o *Orchestrator) buildStitchPrompt(task stitchTask) (string, error) {
 		logf("buildStitchPrompt: injecting %d package_contracts", len(oodContracts))
 	}
 
+	var buildPlan *StitchBuildPlan
+	if o.cfg.Cobbler.StitchBuildPlan {
+		buildPlan = stitchBuildPlan(task)
+	}
+
 	doc := StitchPromptDoc{
 		Role:                  tmpl.Role,
 		RepositoryFiles:       repoFiles,
@@ -749,9 +1347,12 @@ func (o *Orchestrator) buildStitchPrompt(task stitchTask) (string, error) {
 		Context:               taskContext,
 		ExecutionConstitution: parseYAMLNode(executionConst),
 		GoStyleConstitution:   parseYAMLNode(goStyleConst),
+		ObservedConventions:   observed,
 		Task:                  tmpl.Task,
 		Constraints:           tmpl.Constraints,
 		Description:           task.description,
+		TestCases:             parseTaskTestCases(task.description),
+		BuildPlan:             buildPlan,
 		SharedProtocols:       oodProtocols,
 		PackageContracts:      oodContracts,
 	}
@@ -765,6 +1366,57 @@ func (o *Orchestrator) buildStitchPrompt(task stitchTask) (string, error) {
 	return string(out), nil
 }
 
+// taskRequirementIDs returns the requirement IDs covered by a task, in
+// order and without duplicates (GH-489). PRD references cited in a
+// requirement's text (e.g. "prd003 R2.1") are used when present so the
+// IDs match the specs; otherwise the requirement's own id is used.
+// Returns nil when the description has no requirements.
+func taskRequirementIDs(description string) []string {
+	var desc issueDescription
+	if err := yaml.Unmarshal([]byte(description), &desc); err != nil {
+		return nil
+	}
+	var ids []string
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, req := range desc.Requirements {
+		refs := prdRefPattern.FindAllString(req.Text, -1)
+		if len(refs) == 0 {
+			add(strings.TrimSpace(req.ID))
+			continue
+		}
+		for _, ref := range refs {
+			add(strings.Join(strings.Fields(ref), " "))
+		}
+	}
+	return ids
+}
+
+// parseTaskTestCases extracts the test_cases stubs from a YAML task
+// description so the stitch prompt can list them next to the task
+// (GH-484). Entries without a name are dropped. Returns nil if absent or
+// unparseable.
+func parseTaskTestCases(description string) []issueTestCase {
+	var parsed struct {
+		TestCases []issueTestCase `yaml:"test_cases"`
+	}
+	if err := yaml.Unmarshal([]byte(description), &parsed); err != nil {
+		return nil
+	}
+	var cases []issueTestCase
+	for _, tc := range parsed.TestCases {
+		if strings.TrimSpace(tc.Name) != "" {
+			cases = append(cases, tc)
+		}
+	}
+	return cases
+}
+
 func mergeBranch(branchName, baseBranch, repoRoot string) error {
 	logf("mergeBranch: %s into %s (repoRoot=%s)", branchName, baseBranch, repoRoot)
 
@@ -789,6 +1441,98 @@ func mergeBranch(branchName, baseBranch, repoRoot string) error {
 	return nil
 }
 
+// squashMergeTask merges the task branch into baseBranch with git merge
+// --squash and commits the result as a single commit with the message
+// from taskCommitMessage (GH-518). The trailers of the task branch tip, which
+// appendOutcomeTrailers wrote, are copied onto that commit. A branch that
+// adds nothing to baseBranch produces no commit. A conflicted squash is
+// reset before the error is returned.
+func squashMergeTask(task stitchTask, baseBranch, repoRoot, msgTemplate string) error {
+	logf("squashMergeTask: %s into %s (repoRoot=%s)", task.branchName, baseBranch, repoRoot)
+	if err := gitCheckout(baseBranch, repoRoot); err != nil {
+		return fmt.Errorf("checking out %s: %w", baseBranch, err)
+	}
+	if out, err := gitMergeSquash(task.branchName, repoRoot); err != nil {
+		if resetErr := gitResetMerge(repoRoot); resetErr != nil {
+			logf("squashMergeTask: reset --merge warning: %v", resetErr)
+		}
+		return fmt.Errorf("squash merging %s: %w: %s", task.branchName, err, tailLines(string(out), 5))
+	}
+	if cmdGit(repoRoot, "diff", "--cached", "--quiet").Run() == nil {
+		logf("squashMergeTask: %s has no changes to commit", task.branchName)
+		return nil
+	}
+
+	subject := taskCommitMessage(task, msgTemplate)
+	msg := subject
+	trailers, err := gitCommitTrailers(task.branchName, repoRoot)
+	if err != nil {
+		logf("squashMergeTask: reading trailers of %s: %v", task.branchName, err)
+	}
+	if trailers != "" {
+		msg += "\n\n" + trailers
+	}
+	if out, err := cmdGit(repoRoot, "commit", "--no-verify", "-m", msg).CombinedOutput(); err != nil {
+		if resetErr := gitResetMerge(repoRoot); resetErr != nil {
+			logf("squashMergeTask: reset --merge warning: %v", resetErr)
+		}
+		return fmt.Errorf("committing squash of %s: %w\n%s", task.branchName, err, out)
+	}
+	logf("squashMergeTask: committed %q", subject)
+	return nil
+}
+
+// mergeTaskWithRebase merges the task branch into baseBranch and, when the
+// merge fails, aborts it, rebases the task branch onto baseBranch in the
+// task worktree, and merges again (GH-515). This recovers tasks whose
+// conflict is only superficial, such as an earlier task landing an
+// identical edit. With squash set, both merges use squashMergeTask with
+// msgTemplate. The rebased branch is new code, so verify (when non-nil)
+// runs again before the second merge.
+// Returns whether a rebase was needed; an error means the rebase itself
+// conflicted (it is aborted, leaving the branch as it was), verify failed
+// on the rebased branch, or the second merge failed.
+func mergeTaskWithRebase(task stitchTask, baseBranch, repoRoot string, squash bool, msgTemplate string, verify func() error) (bool, error) {
+	merge := func() error { return mergeBranch(task.branchName, baseBranch, repoRoot) }
+	abort := gitMergeAbort
+	if squash {
+		merge = func() error { return squashMergeTask(task, baseBranch, repoRoot, msgTemplate) }
+		abort = gitResetMerge
+	}
+
+	mergeErr := merge()
+	if mergeErr == nil {
+		return false, nil
+	}
+	if err := abort(repoRoot); err != nil {
+		logf("mergeTaskWithRebase: merge --abort warning: %v", err)
+	}
+
+	logf("mergeTaskWithRebase: rebasing %s onto %s in %s", task.branchName, baseBranch, task.worktreeDir)
+	if out, err := gitRebase(baseBranch, task.worktreeDir); err != nil {
+		if abortErr := gitRebaseAbort(task.worktreeDir); abortErr != nil {
+			logf("mergeTaskWithRebase: rebase --abort warning: %v", abortErr)
+		}
+		return true, fmt.Errorf("%w; rebase onto %s also failed: %v: %s",
+			mergeErr, baseBranch, err, tailLines(string(out), 5))
+	}
+
+	if verify != nil {
+		if err := verify(); err != nil {
+			return true, fmt.Errorf("verify after rebase onto %s: %w", baseBranch, err)
+		}
+	}
+
+	logf("mergeTaskWithRebase: rebase succeeded, merging %s again", task.branchName)
+	if err := merge(); err != nil {
+		if abortErr := abort(repoRoot); abortErr != nil {
+			logf("mergeTaskWithRebase: merge --abort warning: %v", abortErr)
+		}
+		return true, fmt.Errorf("merge after rebase: %w", err)
+	}
+	return true, nil
+}
+
 // cleanGoBinaries removes untracked executable files with no extension from
 // dir before staging. Go binaries produced by `go build ./cmd/<name>/` land
 // in the working directory as extensionless executables; this prevents them
@@ -824,10 +1568,38 @@ func cleanGoBinaries(dir string) {
 	logf("cleanGoBinaries: removed %d binary file(s)", removed)
 }
 
+// taskCommitMessage renders the commit message for a task from
+// Cobbler.CommitMessageTemplate (GH-519). An empty template, or one that
+// fails to parse, execute, or renders only whitespace, yields
+// "Task <id>: <title>".
+func taskCommitMessage(task stitchTask, msgTemplate string) string {
+	fallback := fmt.Sprintf("Task %s: %s", task.id, task.title)
+	if strings.TrimSpace(msgTemplate) == "" {
+		return fallback
+	}
+	tmpl, err := template.New("commit_message").Parse(msgTemplate)
+	if err != nil {
+		logf("taskCommitMessage: parsing commit message template: %v; using default", err)
+		return fallback
+	}
+	data := CommitMessageData{ID: task.id, Title: task.title, IssueType: task.issueType}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		logf("taskCommitMessage: executing commit message template: %v; using default", err)
+		return fallback
+	}
+	msg := strings.TrimSpace(buf.String())
+	if msg == "" {
+		logf("taskCommitMessage: commit message template rendered empty; using default")
+		return fallback
+	}
+	return msg
+}
+
 // commitWorktreeChanges stages and commits all changes Claude made in the
 // worktree. Claude does not run git commands; the orchestrator handles git
 // externally. Returns nil if there are no changes to commit.
-func commitWorktreeChanges(task stitchTask) error {
+func commitWorktreeChanges(task stitchTask, msgTemplate string) error {
 	logf("commitWorktreeChanges: staging changes in %s", task.worktreeDir)
 
 	// Remove compiled Go binaries before staging so they are not committed.
@@ -847,7 +1619,7 @@ func commitWorktreeChanges(task stitchTask) error {
 		return nil
 	}
 
-	msg := fmt.Sprintf("Task %s: %s", task.id, task.title)
+	msg := taskCommitMessage(task, msgTemplate)
 	logf("commitWorktreeChanges: committing %q", msg)
 	commitCmd := exec.Command(binGit, "commit", "--no-verify", "-m", msg)
 	commitCmd.Dir = task.worktreeDir
@@ -866,6 +1638,17 @@ func (o *Orchestrator) resetTask(task stitchTask, reason string) {
 	if err := removeInProgressLabel(task.repo, task.ghNumber); err != nil {
 		logf("resetTask: WARNING removeInProgressLabel failed for #%d: %v", task.ghNumber, err)
 	}
+	if o.cfg.Cobbler.KeepFailedWorktrees {
+		dir, branch, err := keepFailedWorktree(task, time.Now())
+		if err == nil {
+			logf("resetTask: kept failed worktree for #%d at %s (branch %s)", task.ghNumber, dir, branch)
+			return
+		}
+		if dir != "" {
+			task.worktreeDir = dir
+		}
+		logf("resetTask: could not keep worktree %s, removing it: %v", task.worktreeDir, err)
+	}
 	if !cleanupWorktree(task) {
 		logf("resetTask: skipping force branch delete for %s (worktree not removed)", task.branchName)
 		return
@@ -875,10 +1658,100 @@ func (o *Orchestrator) resetTask(task stitchTask, reason string) {
 	}
 }
 
+// keptWorktreesDir is the directory under the worktree base that holds
+// failed task worktrees kept by Cobbler.KeepFailedWorktrees (GH-516).
+const keptWorktreesDir = "failed"
+
+// keptTimestampLayout suffixes kept worktree and branch names; it is
+// parsed back by pruneKeptWorktrees.
+const keptTimestampLayout = "20060102-150405"
+
+// keepFailedWorktree moves a failed task's worktree to
+// <base>/failed/<id>-<timestamp> and renames its branch from task/... to
+// failed/...-<timestamp>, so the work stays inspectable while the task's
+// own worktree path and branch are free for a retry (GH-516). When the
+// branch cannot be renamed the worktree is moved back and an error is
+// returned; if that move also fails, the returned dir is where the
+// worktree now lives, still on the task branch.
+func keepFailedWorktree(task stitchTask, now time.Time) (string, string, error) {
+	stamp := now.Format(keptTimestampLayout)
+	dir := filepath.Join(filepath.Dir(task.worktreeDir), keptWorktreesDir, task.id+"-"+stamp)
+	branch := "failed/" + strings.TrimPrefix(task.branchName, "task/") + "-" + stamp
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return "", "", fmt.Errorf("creating %s: %w", filepath.Dir(dir), err)
+	}
+	if err := gitWorktreeMove(task.worktreeDir, dir, "."); err != nil {
+		return "", "", fmt.Errorf("moving worktree: %w", err)
+	}
+	if err := gitRenameBranch(task.branchName, branch, "."); err != nil {
+		err = fmt.Errorf("renaming branch %s: %w", task.branchName, err)
+		if mvErr := gitWorktreeMove(dir, task.worktreeDir, "."); mvErr != nil {
+			return dir, "", fmt.Errorf("%w; moving worktree back: %v", err, mvErr)
+		}
+		return "", "", err
+	}
+	return dir, branch, nil
+}
+
+// pruneKeptWorktrees removes kept failed worktrees under worktreeBase
+// whose timestamp is older than maxAge, along with their failed/...
+// branches (GH-516). Returns how many were removed.
+func pruneKeptWorktrees(worktreeBase string, maxAge time.Duration, now time.Time) int {
+	keptBase := filepath.Join(worktreeBase, keptWorktreesDir)
+	entries, err := os.ReadDir(keptBase)
+	if err != nil {
+		return 0
+	}
+	// Kept worktree names are unique, so branches are keyed by base name.
+	branches := gitWorktreeBranches(".")
+
+	removed := 0
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() || len(name) < len(keptTimestampLayout) {
+			continue
+		}
+		stamp, err := time.ParseInLocation(keptTimestampLayout, name[len(name)-len(keptTimestampLayout):], now.Location())
+		if err != nil || now.Sub(stamp) < maxAge {
+			continue
+		}
+		dir := filepath.Join(keptBase, name)
+		logf("pruneKeptWorktrees: removing %s (kept since %s)", dir, stamp.Format(time.RFC3339))
+		if err := gitWorktreeRemove(dir, "."); err != nil {
+			logf("pruneKeptWorktrees: worktree remove warning for %s: %v", dir, err)
+			if err := os.RemoveAll(dir); err != nil {
+				logf("pruneKeptWorktrees: remove %s: %v", dir, err)
+				continue
+			}
+		}
+		if b := branches[name]; strings.HasPrefix(b, "failed/") {
+			if err := gitForceDeleteBranch(b, "."); err != nil {
+				logf("pruneKeptWorktrees: branch delete warning for %s: %v", b, err)
+			}
+		}
+		removed++
+	}
+	return removed
+}
+
 // cleanupWorktree removes the worktree and its branch. Returns true if the
 // worktree was removed successfully, false if removal failed (branch is left
 // intact to avoid orphaning the worktree).
 func cleanupWorktree(task stitchTask) bool {
+	return removeTaskWorktree(task, gitDeleteBranch)
+}
+
+// cleanupSquashedWorktree is cleanupWorktree for a task landed by
+// squashMergeTask. The squash commit does not make the task branch an
+// ancestor of the base branch, so git branch -d would refuse and leave a
+// task branch for recoverStaleBranches; the branch is force-deleted instead.
+func cleanupSquashedWorktree(task stitchTask) bool {
+	return removeTaskWorktree(task, gitForceDeleteBranch)
+}
+
+// removeTaskWorktree removes the task worktree and then deletes its branch
+// with deleteBranch. The branch is kept when the worktree removal fails.
+func removeTaskWorktree(task stitchTask, deleteBranch func(name, dir string) error) bool {
 	logf("cleanupWorktree: removing worktree %s", task.worktreeDir)
 	if err := gitWorktreeRemove(task.worktreeDir, "."); err != nil {
 		logf("cleanupWorktree: worktree remove failed, skipping branch delete: %v", err)
@@ -886,7 +1759,7 @@ func cleanupWorktree(task stitchTask) bool {
 	}
 
 	logf("cleanupWorktree: deleting branch %s", task.branchName)
-	if err := gitDeleteBranch(task.branchName, "."); err != nil {
+	if err := deleteBranch(task.branchName, "."); err != nil {
 		logf("cleanupWorktree: branch delete warning: %v", err)
 	}
 
@@ -0,0 +1,122 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// stitchCountFile is the per-generation file, under generationMetaDir,
+// holding the number of tasks stitched so far against
+// Cobbler.MaxStitchIssues. generator:resume continues from it so the total
+// cap spans the interrupted run and the resume (GH-549).
+const stitchCountFile = "stitch-count"
+
+// stitchCountPath returns the path of the stitch counter for generation.
+func (o *Orchestrator) stitchCountPath(generation string) string {
+	return filepath.Join(o.cfg.Cobbler.Dir, generationMetaDir, generation, stitchCountFile)
+}
+
+// stitchCounter is the number of tasks one generation has stitched
+// against Cobbler.MaxStitchIssues. Every add is written through to path,
+// as costLedger does for spend, so an interrupted run resumes with every
+// completed task counted. Methods on a nil counter are no-ops.
+type stitchCounter struct {
+	mu   sync.Mutex
+	path string
+	n    int
+}
+
+// loadStitchCounter reads the count recorded at path. A missing or
+// unparsable file starts the counter at zero.
+func loadStitchCounter(path string) *stitchCounter {
+	c := &stitchCounter{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || n < 0 {
+		logf("stitchCount: ignoring unparsable %s", path)
+		return c
+	}
+	c.n = n
+	return c
+}
+
+// add counts one stitched task and persists the new total. Write
+// failures are logged; the in-memory count stays authoritative for this
+// run.
+func (c *stitchCounter) add() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.n++
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		logf("stitchCount: creating %s: %v", filepath.Dir(c.path), err)
+		return
+	}
+	if err := os.WriteFile(c.path, []byte(strconv.Itoa(c.n)+"\n"), 0o644); err != nil {
+		logf("stitchCount: writing %s: %v", c.path, err)
+	}
+}
+
+// total returns the number of tasks counted so far.
+func (c *stitchCounter) total() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}
+
+// openStitchCounter starts counting stitched tasks for generation and
+// returns a func that stops counting. With resume set the count continues
+// from the persisted total; otherwise the persisted total is cleared and
+// the count starts from zero. When a counter is already open
+// (generator:resume opens one before RunCycles) it is kept and the
+// returned func does nothing.
+func (o *Orchestrator) openStitchCounter(generation string, resume bool) func() {
+	if o.stitched != nil {
+		return func() {}
+	}
+	if !resume {
+		o.clearStitchCount(generation)
+	}
+	o.stitched = loadStitchCounter(o.stitchCountPath(generation))
+	return func() { o.stitched = nil }
+}
+
+// clearStitchCount removes the stitch counter for generation so the next
+// run starts its MaxStitchIssues budget from zero.
+func (o *Orchestrator) clearStitchCount(generation string) {
+	if err := os.Remove(o.stitchCountPath(generation)); err != nil && !os.IsNotExist(err) {
+		logf("stitchCount: clearing %s: %v", generation, err)
+	}
+}
+
+// stitchLimit returns how many tasks the next stitch phase may run given
+// the per-cycle limit, the MaxStitchIssues total (0 = unlimited), and the
+// count already stitched. ok is false when the total is used up. A limit
+// of 0 means unlimited.
+func stitchLimit(perCycle, maxTotal, stitched int) (limit int, ok bool) {
+	if maxTotal <= 0 {
+		return perCycle, true
+	}
+	remaining := maxTotal - stitched
+	if remaining <= 0 {
+		return 0, false
+	}
+	if perCycle == 0 || remaining < perCycle {
+		return remaining, true
+	}
+	return perCycle, true
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStitchLimit(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		perCycle, maxTotal, stitched int
+		wantLimit                    int
+		wantOK                       bool
+	}{
+		{0, 0, 100, 0, true},
+		{3, 0, 100, 3, true},
+		{3, 10, 0, 3, true},
+		{3, 10, 8, 2, true},
+		{0, 10, 8, 2, true},
+		{3, 10, 10, 0, false},
+		{3, 10, 12, 0, false},
+	}
+	for _, tc := range cases {
+		limit, ok := stitchLimit(tc.perCycle, tc.maxTotal, tc.stitched)
+		if limit != tc.wantLimit || ok != tc.wantOK {
+			t.Errorf("stitchLimit(%d, %d, %d) = (%d, %v), want (%d, %v)",
+				tc.perCycle, tc.maxTotal, tc.stitched, limit, ok, tc.wantLimit, tc.wantOK)
+		}
+	}
+}
+
+func TestStitchCounter_RoundTripAndClear(t *testing.T) {
+	t.Parallel()
+	o := New(Config{Cobbler: CobblerConfig{Dir: t.TempDir()}})
+	path := o.stitchCountPath("generation-a")
+
+	c := loadStitchCounter(path)
+	if got := c.total(); got != 0 {
+		t.Fatalf("missing counter = %d, want 0", got)
+	}
+	for range 4 {
+		c.add()
+	}
+	if got := loadStitchCounter(path).total(); got != 4 {
+		t.Errorf("reloaded count = %d, want 4", got)
+	}
+	o.clearStitchCount("generation-a")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("counter still present after clear: %v", err)
+	}
+	o.clearStitchCount("generation-a") // clearing a missing counter is a no-op
+
+	var nilCounter *stitchCounter
+	nilCounter.add()
+	if got := nilCounter.total(); got != 0 {
+		t.Errorf("nil counter total = %d, want 0", got)
+	}
+}
+
+func TestLoadStitchCounter_Unparsable(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), stitchCountFile)
+	if err := os.WriteFile(path, []byte("lots\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := loadStitchCounter(path).total(); got != 0 {
+		t.Errorf("count = %d, want 0", got)
+	}
+}
+
+func TestOpenStitchCounter(t *testing.T) {
+	t.Parallel()
+	o := New(Config{Cobbler: CobblerConfig{Dir: t.TempDir()}})
+	path := o.stitchCountPath("generation-a")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(path, []byte("3\n"), 0o644)
+
+	closeResume := o.openStitchCounter("generation-a", true)
+	if got := o.stitched.total(); got != 3 {
+		t.Errorf("resumed count = %d, want 3", got)
+	}
+	// RunCycles inside a resume keeps the resume's counter.
+	o.openStitchCounter("generation-a", false)()
+	if got := o.stitched.total(); got != 3 {
+		t.Errorf("count after nested open = %d, want 3", got)
+	}
+	closeResume()
+	if o.stitched != nil {
+		t.Fatal("counter still open after close")
+	}
+
+	defer o.openStitchCounter("generation-a", false)()
+	if got := o.stitched.total(); got != 0 {
+		t.Errorf("fresh run count = %d, want 0", got)
+	}
+}
+
+// TestStitchCounter_InterruptAndResume simulates a run with
+// MaxStitchIssues=5 that is interrupted in the middle of its second stitch
+// phase after 3 completed tasks: the resume sees all 3 and may stitch only
+// the remaining 2.
+func TestStitchCounter_InterruptAndResume(t *testing.T) {
+	t.Parallel()
+	const perCycle, maxTotal = 2, 5
+	o := New(Config{Cobbler: CobblerConfig{Dir: t.TempDir()}})
+
+	// Original run: cycle 1 stitches 2, cycle 2 stitches 1 and is
+	// interrupted before its phase returns.
+	run := o.openStitchCounter("generation-a", false)
+	for _, n := range []int{2, 1} {
+		limit, ok := stitchLimit(perCycle, maxTotal, o.stitched.total())
+		if !ok || n > limit {
+			t.Fatalf("run: limit=%d ok=%v for %d more", limit, ok, n)
+		}
+		for range n {
+			o.stitched.add()
+		}
+	}
+	run()
+
+	// Resume: continue from the persisted count and stitch every task allowed.
+	defer o.openStitchCounter("generation-a", true)()
+	if got := o.stitched.total(); got != 3 {
+		t.Fatalf("resumed count = %d, want 3", got)
+	}
+	ranOnResume := 0
+	for {
+		limit, ok := stitchLimit(perCycle, maxTotal, o.stitched.total())
+		if !ok {
+			break
+		}
+		for range limit {
+			o.stitched.add()
+		}
+		ranOnResume += limit
+	}
+	if ranOnResume != 2 {
+		t.Errorf("resume stitched %d task(s), want 2", ranOnResume)
+	}
+	if got := loadStitchCounter(o.stitchCountPath("generation-a")).total(); got != maxTotal {
+		t.Errorf("final count = %d, want %d", got, maxTotal)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+)
+
+// Formatter binaries used by runFormatOnStitch. gofmt ships with Go and
+// is the default; goimports also sorts, adds, and prunes imports and is
+// used only when Cobbler.FormatWithGoimports opts in (GH-567).
+const (
+	binGoimports = "goimports"
+	binGofmt     = "gofmt"
+)
+
+// goFormatter returns the formatter to run: goimports when useGoimports
+// is set and it is on PATH, else gofmt, or "" when gofmt is not installed
+// either.
+func goFormatter(useGoimports bool) string {
+	if useGoimports {
+		if _, err := exec.LookPath(binGoimports); err == nil {
+			return binGoimports
+		}
+		logf("goFormatter: %s not on PATH, falling back to %s", binGoimports, binGofmt)
+	}
+	if _, err := exec.LookPath(binGofmt); err == nil {
+		return binGofmt
+	}
+	return ""
+}
+
+// formatWorktreeGoFiles rewrites files, relative to worktreeDir, in place
+// with formatter. Paths that are absolute or escape the worktree are
+// skipped. Returns the number of files passed to the formatter.
+func formatWorktreeGoFiles(formatter, worktreeDir string, files []string) (int, error) {
+	args := []string{"-w"}
+	for _, f := range files {
+		if !filepath.IsLocal(f) {
+			logf("formatWorktreeGoFiles: skipping %s outside the worktree", f)
+			continue
+		}
+		args = append(args, f)
+	}
+	n := len(args) - 1
+	if n == 0 {
+		return 0, nil
+	}
+	cmd := exec.Command(formatter, args...)
+	cmd.Dir = worktreeDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return 0, fmt.Errorf("%s -w: %w\n%s", formatter, err, out)
+	}
+	return n, nil
+}
+
+// runFormatOnStitch formats the task's new and modified .go files when
+// Cobbler.FormatOnStitch is set. Best-effort: a missing formatter or a
+// formatting failure is logged and the task continues with the files as
+// Claude left them.
+func (o *Orchestrator) runFormatOnStitch(task stitchTask) {
+	if !o.cfg.Cobbler.FormatOnStitch {
+		return
+	}
+	formatter := goFormatter(o.cfg.Cobbler.FormatWithGoimports)
+	if formatter == "" {
+		logf("runFormatOnStitch: %s not on PATH; skipping", binGofmt)
+		return
+	}
+	files, err := changedGoFiles(task.worktreeDir)
+	if err != nil {
+		logf("runFormatOnStitch: task %s: %v; skipping", task.id, err)
+		return
+	}
+	n, err := formatWorktreeGoFiles(formatter, task.worktreeDir, files)
+	if err != nil {
+		logf("runFormatOnStitch: task %s: %v", task.id, err)
+		return
+	}
+	if n > 0 {
+		logf("runFormatOnStitch: task %s: formatted %d .go file(s) with %s", task.id, n, formatter)
+	}
+}
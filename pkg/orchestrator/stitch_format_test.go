@@ -0,0 +1,56 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatWorktreeGoFiles(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath(binGofmt); err != nil {
+		t.Skip("gofmt not on PATH")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "messy.go")
+	if err := os.WriteFile(path, []byte("package x\nfunc f( ) {return}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := formatWorktreeGoFiles(binGofmt, dir, []string{"messy.go", "../outside.go", "/abs.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("formatted %d files, want 1 (paths outside the worktree skipped)", n)
+	}
+	got, _ := os.ReadFile(path)
+	if want := "package x\n\nfunc f() { return }\n"; string(got) != want {
+		t.Errorf("formatted content = %q, want %q", got, want)
+	}
+
+	if n, err := formatWorktreeGoFiles(binGofmt, dir, nil); n != 0 || err != nil {
+		t.Errorf("no files = (%d, %v), want (0, nil)", n, err)
+	}
+}
+
+func TestGoFormatter_DefaultsToGofmt(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath(binGofmt); err != nil {
+		t.Skip("gofmt not on PATH")
+	}
+	if got := goFormatter(false); got != binGofmt {
+		t.Errorf("goFormatter(false) = %q, want %q", got, binGofmt)
+	}
+	want := binGofmt
+	if _, err := exec.LookPath(binGoimports); err == nil {
+		want = binGoimports
+	}
+	if got := goFormatter(true); got != want {
+		t.Errorf("goFormatter(true) = %q, want %q", got, want)
+	}
+}
@@ -4,11 +4,16 @@
 package orchestrator
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestErrTaskReset_MentionsOpen(t *testing.T) {
@@ -301,6 +306,27 @@ func TestTaskBranchName(t *testing.T) {
 
 // --- parseRequiredReading ---
 
+func TestMissingRequiredReading(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "pkg", "a"), 0o755)
+	os.WriteFile(filepath.Join(dir, "pkg", "a", "a.go"), []byte("package a\n"), 0o644)
+	desc := `required_reading:
+  - pkg/a/a.go (the type to extend)
+  - pkg/a/a.go:10-20
+  - pkg/a/typo.go
+  - pkg/a
+  - docs/ARCHITECTURE.yaml
+`
+	got := missingRequiredReading(dir, desc, nil)
+	if !reflect.DeepEqual(got, []string{"pkg/a/typo.go"}) {
+		t.Errorf("missingRequiredReading = %v, want [pkg/a/typo.go]", got)
+	}
+	if got := missingRequiredReading(dir, "title: no reading", nil); got != nil {
+		t.Errorf("missingRequiredReading without required_reading = %v, want nil", got)
+	}
+}
+
 func TestParseRequiredReading_ValidYAML(t *testing.T) {
 	t.Parallel()
 	desc := `required_reading:
@@ -433,7 +459,7 @@ func TestCleanGoBinaries_BinaryNotCommittedAfterClean(t *testing.T) {
 	os.WriteFile(filepath.Join(dir, "wc.go"), []byte("package main\n"), 0o644)
 
 	task := stitchTask{id: "1", title: "wc impl", worktreeDir: dir}
-	if err := commitWorktreeChanges(task); err != nil {
+	if err := commitWorktreeChanges(task, ""); err != nil {
 		t.Fatalf("commitWorktreeChanges() error = %v", err)
 	}
 
@@ -476,7 +502,7 @@ func TestCommitWorktreeChanges_NoChanges(t *testing.T) {
 		worktreeDir: dir,
 	}
 
-	if err := commitWorktreeChanges(task); err != nil {
+	if err := commitWorktreeChanges(task, ""); err != nil {
 		t.Errorf("commitWorktreeChanges() with no changes error = %v", err)
 	}
 }
@@ -505,7 +531,7 @@ func TestCommitWorktreeChanges_WithChanges(t *testing.T) {
 		worktreeDir: dir,
 	}
 
-	if err := commitWorktreeChanges(task); err != nil {
+	if err := commitWorktreeChanges(task, ""); err != nil {
 		t.Fatalf("commitWorktreeChanges() with changes error = %v", err)
 	}
 
@@ -521,6 +547,84 @@ func TestCommitWorktreeChanges_WithChanges(t *testing.T) {
 	}
 }
 
+func TestCommitWorktreeChanges_CommitMessageTemplate(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-b", "main"},
+		{"config", "user.email", "test@test.com"},
+		{"config", "user.name", "Test"},
+		{"config", "commit.gpgsign", "false"},
+		{"commit", "--allow-empty", "-m", "initial"},
+	} {
+		if out, err := cmdGit(dir, args...).CombinedOutput(); err != nil {
+			t.Fatalf("setup %v: %v\n%s", args, err, out)
+		}
+	}
+	os.WriteFile(filepath.Join(dir, "newfile.go"), []byte("package main\n"), 0o644)
+
+	task := stitchTask{id: "7", title: "add parser", issueType: "task", worktreeDir: dir}
+	if err := commitWorktreeChanges(task, "feat({{.IssueType}}): {{.Title}} (#{{.ID}})"); err != nil {
+		t.Fatalf("commitWorktreeChanges() error = %v", err)
+	}
+	out, err := cmdGit(dir, "log", "-1", "--format=%s").Output()
+	if err != nil {
+		t.Fatalf("git log: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "feat(task): add parser (#7)" {
+		t.Errorf("commit subject = %q, want %q", got, "feat(task): add parser (#7)")
+	}
+}
+
+func TestTaskCommitMessage(t *testing.T) {
+	t.Parallel()
+	task := stitchTask{id: "12", title: "wire config", issueType: "task"}
+	cases := []struct {
+		name, tmpl, want string
+	}{
+		{"empty", "", "Task 12: wire config"},
+		{"custom", "chore: {{.Title}}\n\nRefs: task {{.ID}}", "chore: wire config\n\nRefs: task 12"},
+		{"parse error", "feat: {{.Title", "Task 12: wire config"},
+		{"execute error", "feat: {{.Missing}}", "Task 12: wire config"},
+		{"blank output", "{{if false}}x{{end}}", "Task 12: wire config"},
+	}
+	for _, tc := range cases {
+		if got := taskCommitMessage(task, tc.tmpl); got != tc.want {
+			t.Errorf("%s: taskCommitMessage(%q) = %q, want %q", tc.name, tc.tmpl, got, tc.want)
+		}
+	}
+}
+
+func TestCleanupSquashedWorktree_DeletesUnmergedBranch(t *testing.T) {
+	dir := initTestGitRepo(t)
+
+	branchName := "task/main-squashed"
+	gitRun(t, "branch", branchName)
+	worktreeDir := filepath.Join(dir+"-worktrees", "squashed")
+	if err := os.MkdirAll(filepath.Dir(worktreeDir), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, "worktree", "add", worktreeDir, branchName)
+	if err := os.WriteFile(filepath.Join(worktreeDir, "task.txt"), []byte("task\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, "-C", worktreeDir, "add", "-A")
+	gitRun(t, "-C", worktreeDir, "commit", "--no-verify", "-m", "task work")
+
+	// A squash merge lands the change without making the branch an ancestor.
+	task := stitchTask{id: "squashed", branchName: branchName, worktreeDir: worktreeDir}
+	if err := squashMergeTask(task, "main", ".", ""); err != nil {
+		t.Fatalf("squashMergeTask: %v", err)
+	}
+
+	if !cleanupSquashedWorktree(task) {
+		t.Fatal("cleanupSquashedWorktree should return true for successful removal")
+	}
+	if gitBranchExists(branchName, "") {
+		t.Errorf("branch %q should have been deleted after a squash merge", branchName)
+	}
+}
+
 // --- createWorktree ---
 
 func TestCreateWorktree_CreatesWorktreeAndBranch(t *testing.T) {
@@ -575,6 +679,76 @@ func TestBuildStitchPrompt_RequiredReadingFilter(t *testing.T) {
 	}
 }
 
+func TestTaskClaudeTimeout(t *testing.T) {
+	t.Parallel()
+	o := New(Config{Claude: ClaudeConfig{MaxTimeSec: 300, MaxTaskTimeSec: 1800}})
+	cases := []struct {
+		description string
+		want        time.Duration
+		source      string
+	}{
+		{"deliverable_type: code\n", 300 * time.Second, "max_time_sec"},
+		{"timeout_sec: 900\n", 900 * time.Second, "timeout_sec"},
+		{"timeout_sec: 7200\n", 1800 * time.Second, "clamped"},
+		{"timeout_sec: -5\n", 300 * time.Second, "max_time_sec"},
+		{"timeout_sec: soon\n", 300 * time.Second, "max_time_sec"},
+	}
+	for _, tc := range cases {
+		got, source := o.taskClaudeTimeout(tc.description)
+		if got != tc.want || !strings.Contains(source, tc.source) {
+			t.Errorf("taskClaudeTimeout(%q) = %s, %q; want %s, %q", tc.description, got, source, tc.want, tc.source)
+		}
+	}
+}
+
+func TestSelectPreviewIssue(t *testing.T) {
+	t.Parallel()
+	issues := []cobblerIssue{
+		{Number: 14, Labels: []string{cobblerLabelReady}},
+		{Number: 12, Labels: []string{cobblerLabelReady, cobblerLabelInProgress}},
+		{Number: 13, Labels: []string{cobblerLabelReady}},
+		{Number: 11},
+	}
+	cases := []struct {
+		id   string
+		want int
+	}{
+		{"", 13},
+		{"11", 11},
+		{"#12", 12},
+		{" 14 ", 14},
+	}
+	for _, tc := range cases {
+		got, err := selectPreviewIssue(issues, tc.id)
+		if err != nil || got.Number != tc.want {
+			t.Errorf("selectPreviewIssue(%q) = #%d, %v; want #%d", tc.id, got.Number, err, tc.want)
+		}
+	}
+	if _, err := selectPreviewIssue(issues, "99"); err == nil {
+		t.Error("expected error for unknown task")
+	}
+	if _, err := selectPreviewIssue([]cobblerIssue{{Number: 1}}, ""); err == nil {
+		t.Error("expected error when no task is ready")
+	}
+}
+
+func TestSelectStitchableIssue(t *testing.T) {
+	t.Parallel()
+	issues := []cobblerIssue{
+		{Number: 11, Labels: []string{cobblerLabelReady}},
+		{Number: 12, Labels: []string{cobblerLabelReady, cobblerLabelInProgress}},
+		{Number: 13},
+	}
+	if got, err := selectStitchableIssue(issues, "#11"); err != nil || got.Number != 11 {
+		t.Errorf("selectStitchableIssue(#11) = #%d, %v; want #11", got.Number, err)
+	}
+	for _, id := range []string{"12", "13", "99"} {
+		if _, err := selectStitchableIssue(issues, id); err == nil {
+			t.Errorf("selectStitchableIssue(%s): expected error", id)
+		}
+	}
+}
+
 // gitRun executes a git command in the current working directory and
 // fails the test on error. Tests that call initTestGitRepo (which
 // changes cwd to the temp git repo) use this to run git commands.
@@ -663,6 +837,205 @@ func TestMergeBranch_MergeConflict(t *testing.T) {
 	exec.Command("git", "merge", "--abort").Run()
 }
 
+// --- mergeTaskWithRebase ---
+
+// setupRebaseTask creates a worktree on branch task/1 from main and commits
+// each content in turn to shared.txt there. main must already have
+// shared.txt.
+func setupRebaseTask(t *testing.T, dir string, contents ...string) stitchTask {
+	t.Helper()
+	wt := filepath.Join(t.TempDir(), "wt")
+	gitRun(t, "worktree", "add", "-b", "task/1", wt, "main")
+	for i, c := range contents {
+		if err := os.WriteFile(filepath.Join(wt, "shared.txt"), []byte(c), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		for _, args := range [][]string{{"add", "-A"}, {"commit", "--no-verify", "-m", fmt.Sprintf("task change %d", i+1)}} {
+			if out, err := cmdGit(wt, args...).CombinedOutput(); err != nil {
+				t.Fatalf("git %v: %v\n%s", args, err, out)
+			}
+		}
+	}
+	return stitchTask{id: "1", branchName: "task/1", worktreeDir: wt}
+}
+
+func commitShared(t *testing.T, dir, content, msg string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "shared.txt"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, "add", "-A")
+	gitRun(t, "commit", "--no-verify", "-m", msg)
+}
+
+func TestMergeTaskWithRebase_CleanMerge(t *testing.T) {
+	dir := initTestGitRepo(t)
+	commitShared(t, dir, "original\n", "add shared")
+	task := setupRebaseTask(t, dir, "task version\n")
+
+	rebased, err := mergeTaskWithRebase(task, "main", dir, false, "", nil)
+	if err != nil || rebased {
+		t.Fatalf("mergeTaskWithRebase = %v, %v; want false, nil", rebased, err)
+	}
+}
+
+func TestMergeTaskWithRebase_RecoversWithRebase(t *testing.T) {
+	dir := initTestGitRepo(t)
+	commitShared(t, dir, "original\n", "add shared")
+	// The task first makes the same edit an earlier task lands on main,
+	// then builds on it. A merge conflicts; a rebase drops the duplicate.
+	task := setupRebaseTask(t, dir, "X\n", "X\nY\n")
+	commitShared(t, dir, "X\n", "earlier task")
+
+	rebased, err := mergeTaskWithRebase(task, "main", dir, false, "", nil)
+	if err != nil || !rebased {
+		t.Fatalf("mergeTaskWithRebase = %v, %v; want true, nil", rebased, err)
+	}
+	got, _ := os.ReadFile(filepath.Join(dir, "shared.txt"))
+	if string(got) != "X\nY\n" {
+		t.Errorf("shared.txt after merge = %q", got)
+	}
+}
+
+func TestMergeTaskWithRebase_VerifiesRebasedBranch(t *testing.T) {
+	dir := initTestGitRepo(t)
+	commitShared(t, dir, "original\n", "add shared")
+	task := setupRebaseTask(t, dir, "X\n", "X\nY\n")
+	commitShared(t, dir, "X\n", "earlier task")
+	before, err := gitRevParseHEAD(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	verify := func() error { calls++; return fmt.Errorf("build broken") }
+	rebased, err := mergeTaskWithRebase(task, "main", dir, false, "", verify)
+	if err == nil || !rebased || !strings.Contains(err.Error(), "build broken") {
+		t.Fatalf("mergeTaskWithRebase = %v, %v; want true and the verify error", rebased, err)
+	}
+	if calls != 1 {
+		t.Errorf("verify ran %d time(s), want 1", calls)
+	}
+	if after, _ := gitRevParseHEAD(dir); after != before {
+		t.Error("main moved although the rebased branch failed verification")
+	}
+}
+
+func TestMergeTaskWithRebase_RealConflict(t *testing.T) {
+	dir := initTestGitRepo(t)
+	commitShared(t, dir, "original\n", "add shared")
+	task := setupRebaseTask(t, dir, "task version\n")
+	commitShared(t, dir, "main version\n", "conflicting change")
+
+	rebased, err := mergeTaskWithRebase(task, "main", dir, false, "", nil)
+	if err == nil || !rebased {
+		t.Fatalf("mergeTaskWithRebase = %v, %v; want true and an error", rebased, err)
+	}
+	if !strings.Contains(err.Error(), "rebase onto main also failed") {
+		t.Errorf("error = %v", err)
+	}
+	if cmdGit(dir, "rev-parse", "-q", "--verify", "MERGE_HEAD").Run() == nil {
+		t.Error("merge left in progress in the repo")
+	}
+	if out, _ := cmdGit(task.worktreeDir, "status", "--porcelain").Output(); len(out) != 0 {
+		t.Errorf("worktree not clean after rebase abort:\n%s", out)
+	}
+}
+
+// --- keepFailedWorktree / pruneKeptWorktrees ---
+
+func TestKeepFailedWorktree_AndPrune(t *testing.T) {
+	initTestGitRepo(t)
+	base := t.TempDir()
+	task := stitchTask{id: "7", branchName: "task/main-7", worktreeDir: filepath.Join(base, "7")}
+	gitRun(t, "worktree", "add", "-b", task.branchName, task.worktreeDir, "main")
+	if err := os.WriteFile(filepath.Join(task.worktreeDir, "wip.go"), []byte("package x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	failedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	dir, branch, err := keepFailedWorktree(task, failedAt)
+	if err != nil {
+		t.Fatalf("keepFailedWorktree: %v", err)
+	}
+	if want := filepath.Join(base, "failed", "7-20260301-120000"); dir != want {
+		t.Errorf("dir = %s, want %s", dir, want)
+	}
+	if branch != "failed/main-7-20260301-120000" || !gitBranchExists(branch, ".") {
+		t.Errorf("branch = %s (exists=%v)", branch, gitBranchExists(branch, "."))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "wip.go")); err != nil {
+		t.Errorf("uncommitted work not kept: %v", err)
+	}
+	if gitBranchExists(task.branchName, ".") {
+		t.Error("task branch should be free for a retry")
+	}
+	if _, err := os.Stat(task.worktreeDir); !os.IsNotExist(err) {
+		t.Error("task worktree path should be free for a retry")
+	}
+
+	if n := pruneKeptWorktrees(base, 72*time.Hour, failedAt.Add(time.Hour)); n != 0 {
+		t.Errorf("pruned %d young worktree(s)", n)
+	}
+	if n := pruneKeptWorktrees(base, 72*time.Hour, failedAt.Add(73*time.Hour)); n != 1 {
+		t.Errorf("pruned %d, want 1", n)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Error("kept worktree not removed")
+	}
+	if gitBranchExists(branch, ".") {
+		t.Error("kept branch not deleted")
+	}
+}
+
+func TestKeepFailedWorktree_RenameFailureMovesBack(t *testing.T) {
+	initTestGitRepo(t)
+	base := t.TempDir()
+	task := stitchTask{id: "8", branchName: "task/main-8", worktreeDir: filepath.Join(base, "8")}
+	gitRun(t, "worktree", "add", "-b", task.branchName, task.worktreeDir, "main")
+
+	// A branch nested under the target name blocks the rename, which
+	// fails after the worktree is already moved.
+	gitRun(t, "branch", "failed/main-8-20260301-120000/x", "main")
+	dir, branch, err := keepFailedWorktree(task, time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local))
+	if err == nil {
+		t.Fatalf("keepFailedWorktree = (%s, %s, nil), want rename error", dir, branch)
+	}
+	if dir != "" || branch != "" {
+		t.Errorf("dir, branch = %q, %q, want empty after moving back", dir, branch)
+	}
+	if _, err := os.Stat(task.worktreeDir); err != nil {
+		t.Errorf("worktree not moved back to %s: %v", task.worktreeDir, err)
+	}
+	if !cleanupWorktree(task) {
+		t.Error("cleanupWorktree failed on the moved-back worktree")
+	}
+}
+
+func TestCheckWorktreeLimit_IgnoresKeptWorktrees(t *testing.T) {
+	t.Parallel()
+	base := t.TempDir()
+	os.MkdirAll(filepath.Join(base, "1"), 0o755)
+	os.MkdirAll(filepath.Join(base, keptWorktreesDir, "2-20260101-000000"), 0o755)
+	if err := checkWorktreeLimit(base, filepath.Join(base, "3"), 2); err != nil {
+		t.Errorf("kept worktrees counted against the limit: %v", err)
+	}
+}
+
+func TestCheckWorktreeLimit_IgnoresReviewWorktrees(t *testing.T) {
+	dir := initTestGitRepo(t)
+	base := filepath.Join(dir, ".worktrees")
+	os.MkdirAll(filepath.Join(base, "1"), 0o755)
+	gitRun(t, "branch", "review/main-5")
+	gitRun(t, "worktree", "add", filepath.Join(base, "5"), "review/main-5")
+	if err := checkWorktreeLimit(base, filepath.Join(base, "3"), 2); err != nil {
+		t.Errorf("review worktree counted against the limit: %v", err)
+	}
+	if err := checkWorktreeLimit(base, filepath.Join(base, "3"), 1); err == nil {
+		t.Error("stale worktree at max 1 should be refused")
+	}
+}
+
 // --- recoverStaleBranches ---
 
 func TestRecoverStaleBranches_NoBranches(t *testing.T) {
@@ -1069,8 +1442,315 @@ func TestCommitWorktreeChanges_InvalidDir(t *testing.T) {
 		worktreeDir: "/nonexistent/dir/xyz",
 	}
 
-	err := commitWorktreeChanges(task)
+	err := commitWorktreeChanges(task, "")
 	if err == nil {
 		t.Error("expected error for non-existent directory")
 	}
 }
+
+func TestStitchAttempt_CountsFailedHistory(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	o := &Orchestrator{cfg: Config{Cobbler: CobblerConfig{HistoryDir: dir}}}
+	write := func(name string, s HistoryStats) {
+		data, _ := yaml.Marshal(s)
+		os.WriteFile(filepath.Join(dir, name), data, 0o644)
+	}
+	write("2026-02-10-15-04-05-stitch-stats.yaml", HistoryStats{TaskID: "5", Status: "failed", Error: "claude failure: exit status 1"})
+	write("2026-02-10-15-05-05-stitch-stats.yaml", HistoryStats{TaskID: "5", Status: "failed", Error: "verify failure: go test failed"})
+	write("2026-02-10-15-06-05-stitch-stats.yaml", HistoryStats{TaskID: "6", Status: "failed", Error: "claude failure: exit status 1"})
+	write("2026-02-10-15-07-05-stitch-stats.yaml", HistoryStats{TaskID: "7", Status: "success"})
+	// Failures that do not reflect on the model are not attempts.
+	write("2026-02-10-15-08-05-stitch-stats.yaml", HistoryStats{TaskID: "5", Status: "failed", Error: "required_reading not found: docs/x.yaml"})
+	write("2026-02-10-15-09-05-stitch-stats.yaml", HistoryStats{TaskID: "5", Status: "failed", Error: "interrupted: signal: killed"})
+	write("2026-02-10-15-10-05-stitch-stats.yaml", HistoryStats{TaskID: "5", Status: "failed", Error: "parse check failure: a.go:1: expected package"})
+	write("2026-02-10-15-11-05-stitch-stats.yaml", HistoryStats{TaskID: "5", Status: "failed", Error: "merge failure: conflict"})
+
+	if got := o.stitchAttempt("5"); got != 3 {
+		t.Errorf("stitchAttempt(5) = %d, want 3", got)
+	}
+	if got := o.stitchAttempt("7"); got != 1 {
+		t.Errorf("stitchAttempt(7) = %d, want 1", got)
+	}
+}
+
+func TestStitchModelForAttempt(t *testing.T) {
+	t.Parallel()
+	o := &Orchestrator{cfg: Config{Cobbler: CobblerConfig{StitchEscalationModel: "opus", StitchEscalationAfter: 1}}}
+	if got := o.stitchModelForAttempt(1); got != "" {
+		t.Errorf("first attempt model = %q, want default", got)
+	}
+	if got := o.stitchModelForAttempt(2); got != "opus" {
+		t.Errorf("second attempt model = %q, want opus", got)
+	}
+
+	o.cfg.Cobbler.StitchEscalationModel = ""
+	if got := o.stitchModelForAttempt(5); got != "" {
+		t.Errorf("escalation disabled, got model %q", got)
+	}
+}
+
+func TestStitchModelForAttempt_UsesStitchModel(t *testing.T) {
+	t.Parallel()
+	o := &Orchestrator{cfg: Config{
+		Claude:  ClaudeConfig{StitchModel: "sonnet"},
+		Cobbler: CobblerConfig{StitchEscalationModel: "opus", StitchEscalationAfter: 1},
+	}}
+	if got := o.stitchModelForAttempt(1); got != "sonnet" {
+		t.Errorf("first attempt model = %q, want sonnet", got)
+	}
+	if got := o.stitchModelForAttempt(2); got != "opus" {
+		t.Errorf("escalated attempt model = %q, want opus", got)
+	}
+
+	o.cfg.Cobbler.StitchEscalationModel = ""
+	if got := o.stitchModelForAttempt(5); got != "sonnet" {
+		t.Errorf("escalation disabled, got model %q, want sonnet", got)
+	}
+}
+
+func TestParseTaskFiles_MappingsAndStrings(t *testing.T) {
+	t.Parallel()
+	desc := "files:\n  - path: pkg/a/a.go\n    action: create\n  - pkg/b/b.go\n  - action: delete\n"
+	got := parseTaskFiles(desc)
+	if len(got) != 2 || got[0] != "pkg/a/a.go" || got[1] != "pkg/b/b.go" {
+		t.Errorf("parseTaskFiles = %v", got)
+	}
+	if got := parseTaskFiles("not: [valid"); got != nil {
+		t.Errorf("expected nil for invalid YAML, got %v", got)
+	}
+}
+
+func TestWorktreeSparsePaths(t *testing.T) {
+	t.Parallel()
+	o := &Orchestrator{cfg: Config{Cobbler: CobblerConfig{WorktreeSetup: map[string]WorktreeOptions{
+		"documentation": {Sparse: true, Paths: []string{"docs/", "magefiles"}},
+		"code":          {Sparse: false},
+	}}}}
+
+	doc := stitchTask{description: "deliverable_type: documentation\nfiles:\n  - path: docs/specs/a.yaml\n  - path: docs/b.yaml\n"}
+	got := o.worktreeSparsePaths(doc)
+	want := []string{"docs", "docs/specs", "magefiles"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("worktreeSparsePaths = %v, want %v", got, want)
+	}
+
+	code := stitchTask{description: "deliverable_type: code\nfiles:\n  - path: pkg/a/a.go\n"}
+	if got := o.worktreeSparsePaths(code); got != nil {
+		t.Errorf("non-sparse type got %v", got)
+	}
+
+	root := stitchTask{description: "deliverable_type: documentation\nfiles:\n  - path: README.md\n"}
+	if got := o.worktreeSparsePaths(root); got != nil {
+		t.Errorf("root file should force full checkout, got %v", got)
+	}
+}
+
+func TestCreateWorktree_Sparse(t *testing.T) {
+	// Not parallel: initTestGitRepo changes the working directory.
+	dir := initTestGitRepo(t)
+	for _, p := range []string{"docs/a.yaml", "pkg/x/x.go"} {
+		os.MkdirAll(filepath.Join(dir, filepath.Dir(p)), 0o755)
+		os.WriteFile(filepath.Join(dir, p), []byte("x\n"), 0o644)
+	}
+	for _, args := range [][]string{{"add", "-A"}, {"commit", "-m", "files"}} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	task := stitchTask{
+		id:          "3",
+		branchName:  taskBranchName("main", "3"),
+		worktreeDir: filepath.Join(t.TempDir(), "3"),
+		sparsePaths: []string{"docs"},
+	}
+	if err := createWorktree(task); err != nil {
+		t.Fatalf("createWorktree: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(task.worktreeDir, "docs", "a.yaml")); err != nil {
+		t.Errorf("sparse path missing: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(task.worktreeDir, "pkg")); !os.IsNotExist(err) {
+		t.Errorf("pkg/ should not be checked out, stat err=%v", err)
+	}
+}
+
+func TestCheckWorktreeLimit(t *testing.T) {
+	t.Parallel()
+	base := t.TempDir()
+	for _, name := range []string{"task-1", "task-2"} {
+		if err := os.Mkdir(filepath.Join(base, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(base, "stray.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkWorktreeLimit(base, filepath.Join(base, "task-3"), 0); err != nil {
+		t.Errorf("max 0 should disable the limit, got %v", err)
+	}
+	if err := checkWorktreeLimit(base, filepath.Join(base, "task-3"), 3); err != nil {
+		t.Errorf("2 existing under max 3 should pass, got %v", err)
+	}
+	if err := checkWorktreeLimit(base, filepath.Join(base, "task-3"), 2); err == nil {
+		t.Error("2 existing at max 2 should be refused")
+	}
+	if err := checkWorktreeLimit(base, filepath.Join(base, "task-2"), 2); err != nil {
+		t.Errorf("reused target should not count against the limit, got %v", err)
+	}
+	if err := checkWorktreeLimit(filepath.Join(base, "missing"), "x", 1); err != nil {
+		t.Errorf("missing base should pass, got %v", err)
+	}
+}
+
+func TestParseTaskTestCases(t *testing.T) {
+	t.Parallel()
+	desc := "deliverable_type: code\ntest_cases:\n  - name: TestArchive\n    description: archived crumbs are hidden\n  - description: no name\n"
+	got := parseTaskTestCases(desc)
+	if len(got) != 1 || got[0].Name != "TestArchive" || got[0].Description != "archived crumbs are hidden" {
+		t.Errorf("parseTaskTestCases = %+v, want one TestArchive entry", got)
+	}
+	if got := parseTaskTestCases("deliverable_type: code\n"); got != nil {
+		t.Errorf("parseTaskTestCases without test_cases = %+v, want nil", got)
+	}
+}
+
+func TestTaskRequirementIDs(t *testing.T) {
+	t.Parallel()
+	desc := `deliverable_type: code
+requirements:
+  - id: R1
+    text: Implement CrumbTable per prd003-crumbs-interface R2.1 and prd003-crumbs-interface R2.2
+  - id: R2
+    text: Add property operations
+  - id: R3
+    text: Keep prd003-crumbs-interface  R2.1 semantics
+`
+	got := taskRequirementIDs(desc)
+	want := []string{"prd003-crumbs-interface R2.1", "prd003-crumbs-interface R2.2", "R2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("taskRequirementIDs = %v, want %v", got, want)
+	}
+	if got := taskRequirementIDs("deliverable_type: code\n"); got != nil {
+		t.Errorf("no requirements: got %v, want nil", got)
+	}
+}
+
+func TestMergeTaskWithRebase_SquashSingleCommitWithTrailers(t *testing.T) {
+	dir := initTestGitRepo(t)
+	commitShared(t, dir, "original\n", "add shared")
+	task := setupRebaseTask(t, dir, "first\n", "first\nsecond\n")
+	task.title = "Extend shared"
+	if err := appendOutcomeTrailers(task.worktreeDir, InvocationRecord{DurationS: 42}); err != nil {
+		t.Fatal(err)
+	}
+	preMergeRef, err := gitRevParseHEAD(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rebased, err := mergeTaskWithRebase(task, "main", dir, true, "", nil)
+	if err != nil || rebased {
+		t.Fatalf("mergeTaskWithRebase = %v, %v; want false, nil", rebased, err)
+	}
+	out, _ := cmdGit(dir, "rev-list", "--count", preMergeRef+"..HEAD").Output()
+	if got := strings.TrimSpace(string(out)); got != "1" {
+		t.Errorf("commits added = %s, want 1", got)
+	}
+	if cmdGit(dir, "rev-parse", "-q", "--verify", "HEAD^2").Run() == nil {
+		t.Error("squash merge produced a merge commit")
+	}
+	msg, _ := cmdGit(dir, "log", "-1", "--format=%B").Output()
+	if !strings.HasPrefix(string(msg), "Task 1: Extend shared\n") {
+		t.Errorf("commit message = %q", msg)
+	}
+	if trailers, _ := gitCommitTrailers("HEAD", dir); !strings.Contains(trailers, "Duration-Seconds: 42") {
+		t.Errorf("trailers = %q, want Duration-Seconds: 42", trailers)
+	}
+
+	files, err := gitDiffNameStatus(preMergeRef, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Path != "shared.txt" || files[0].Insertions != 2 {
+		t.Errorf("file changes = %+v, want shared.txt with 2 insertions", files)
+	}
+}
+
+func TestMergeTaskWithRebase_SquashRecoversWithRebase(t *testing.T) {
+	dir := initTestGitRepo(t)
+	commitShared(t, dir, "original\n", "add shared")
+	task := setupRebaseTask(t, dir, "X\n", "X\nY\n")
+	commitShared(t, dir, "X\n", "earlier task")
+
+	rebased, err := mergeTaskWithRebase(task, "main", dir, true, "", nil)
+	if err != nil || !rebased {
+		t.Fatalf("mergeTaskWithRebase = %v, %v; want true, nil", rebased, err)
+	}
+	got, _ := os.ReadFile(filepath.Join(dir, "shared.txt"))
+	if string(got) != "X\nY\n" {
+		t.Errorf("shared.txt after merge = %q", got)
+	}
+}
+
+func TestMergeTaskWithRebase_SquashRealConflictLeavesCleanTree(t *testing.T) {
+	dir := initTestGitRepo(t)
+	commitShared(t, dir, "original\n", "add shared")
+	task := setupRebaseTask(t, dir, "task version\n")
+	commitShared(t, dir, "main version\n", "conflicting change")
+
+	if _, err := mergeTaskWithRebase(task, "main", dir, true, "", nil); err == nil {
+		t.Fatal("mergeTaskWithRebase succeeded, want conflict error")
+	}
+	if out, _ := cmdGit(dir, "status", "--porcelain").Output(); len(out) != 0 {
+		t.Errorf("repo left dirty after failed squash:\n%s", out)
+	}
+}
+
+func TestBuildStitchPrompt_ObservedConventionsUsePhaseSourceDirs(t *testing.T) {
+	tmp := t.TempDir()
+	for path, src := range map[string]string{
+		"pkg/a/a.go": "package a\n\nfunc f() error { return fmt.Errorf(\"a: %w\", err) }\n",
+		"pkg/b/b.go": "package b\n\nfunc g() error { return fmt.Errorf(\"b: %w\", err) }\n",
+	} {
+		if err := os.MkdirAll(filepath.Join(tmp, filepath.Dir(path)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(tmp, path), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cobblerDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(cobblerDir, "stitch_context.yaml"), []byte("go_source_dirs: |\n  pkg/b/\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := Config{}
+	cfg.Project.GoSourceDirs = []string{"pkg/"}
+	cfg.Cobbler.Dir = cobblerDir
+	cfg.Cobbler.StitchObservedConventions = true
+	task := stitchTask{id: "t1", title: "impl", issueType: "code", worktreeDir: tmp}
+	out, err := New(cfg).buildStitchPrompt(task)
+	if err != nil {
+		t.Fatalf("buildStitchPrompt() unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "pkg/b/b.go") {
+		t.Errorf("observed conventions should sample pkg/b/b.go from the phase go_source_dirs:\n%s", out)
+	}
+	if strings.Contains(out, "pkg/a/a.go") {
+		t.Errorf("observed conventions sampled pkg/a/a.go outside the phase go_source_dirs:\n%s", out)
+	}
+}
+
+func TestTaskFileDirs(t *testing.T) {
+	t.Parallel()
+	desc := "files:\n  - path: pkg/b/b.go\n  - pkg/a/a.go (new)\n  - path: pkg/a/a_test.go\n  - main.go\n"
+	if got, want := taskFileDirs(desc), []string{"pkg/a", "pkg/b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("taskFileDirs = %v, want %v", got, want)
+	}
+	if got := taskFileDirs(""); got != nil {
+		t.Errorf("taskFileDirs(\"\") = %v, want nil", got)
+	}
+}
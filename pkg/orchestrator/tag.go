@@ -46,11 +46,13 @@ func (o *Orchestrator) Tag() error {
 		return fmt.Errorf("creating tag %s: %w", tag, err)
 	}
 
-	// Update the version constant in the version file if configured.
-	if o.cfg.Project.VersionFile != "" {
-		logf("tag: writing version %s to %s", tag, o.cfg.Project.VersionFile)
-		if err := writeVersionConst(o.cfg.Project.VersionFile, tag); err != nil {
-			return fmt.Errorf("tag %s created but version file update failed: %w", tag, err)
+	// Update the version constant in each configured version file.
+	if files := o.cfg.Project.versionFiles(); len(files) > 0 {
+		for _, f := range files {
+			logf("tag: writing version %s to %s", tag, f)
+			if err := writeVersionConst(f, tag); err != nil {
+				return fmt.Errorf("tag %s created but version file update failed for %s: %w", tag, f, err)
+			}
 		}
 		_ = gitStageAll(".") // best-effort; commit below handles empty index
 		if err := gitCommit(fmt.Sprintf("Set version to %s", tag), "."); err != nil {
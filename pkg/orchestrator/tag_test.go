@@ -6,6 +6,7 @@ package orchestrator
 import (
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -194,3 +195,31 @@ func TestTag_VersionFileWriteError(t *testing.T) {
 		t.Errorf("error = %q, want it to mention the tag was created", err.Error())
 	}
 }
+
+func TestTag_UpdatesEveryVersionFile(t *testing.T) {
+	// Not parallel: uses os.Chdir via setupTagRepo.
+	setupTagRepo(t, nil)
+
+	current, err := gitCurrentBranch(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := filepath.Join(t.TempDir(), "version.go")
+	if err := os.WriteFile(first, []byte("package main\n\nconst Version = \"dev\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := Config{}
+	cfg.applyDefaults()
+	cfg.Cobbler.BaseBranch = current
+	cfg.Cobbler.DocTagPrefix = "v0."
+	cfg.Project.VersionFiles = []string{first, "/dev/null/impossible/version.go"}
+
+	err = (&Orchestrator{cfg: cfg}).Tag()
+	if err == nil || !strings.Contains(err.Error(), "/dev/null/impossible/version.go") {
+		t.Fatalf("Tag() error = %v, want failure naming the second version file", err)
+	}
+	if got := readVersionConst(first); !strings.HasPrefix(got, "v0.") {
+		t.Errorf("first version file Version = %q, want the new v0. tag", got)
+	}
+}
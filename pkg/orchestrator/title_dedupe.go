@@ -0,0 +1,117 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import (
+	"strings"
+	"unicode"
+)
+
+// titleStopWords are dropped before comparing titles: articles,
+// prepositions, and the action verbs measure uses interchangeably, so
+// "Add widget cache" and "Implement widget caching" compare equal.
+var titleStopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true,
+	"for": true, "to": true, "of": true, "in": true, "on": true,
+	"with": true, "from": true, "into": true, "by": true,
+	"add": true, "implement": true, "create": true, "introduce": true,
+	"support": true, "build": true, "write": true, "make": true,
+}
+
+// titleTokens returns the set of normalized words in title: lowercased,
+// split on non-alphanumerics, stop words removed, and common English
+// suffixes trimmed so "cache", "caches", and "caching" match.
+func titleTokens(title string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := make(map[string]bool, len(words))
+	for _, w := range words {
+		if titleStopWords[w] {
+			continue
+		}
+		tokens[stemTitleWord(w)] = true
+	}
+	return tokens
+}
+
+// stemTitleWord trims the first matching suffix of ing, ed, es, s, or e
+// when at least three characters remain.
+func stemTitleWord(w string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s", "e"} {
+		if strings.HasSuffix(w, suffix) && len(w)-len(suffix) >= 3 {
+			return strings.TrimSuffix(w, suffix)
+		}
+	}
+	return w
+}
+
+// titleSimilarity returns the Dice coefficient of the normalized token
+// sets of a and b: 1 for the same words, 0 for none in common.
+func titleSimilarity(a, b string) float64 {
+	ta, tb := titleTokens(a), titleTokens(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range ta {
+		if tb[t] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(ta)+len(tb))
+}
+
+// similarTitle returns the first of titles whose similarity to title is at
+// least threshold.
+func similarTitle(title string, titles []string, threshold float64) (string, bool) {
+	for _, t := range titles {
+		if titleSimilarity(title, t) >= threshold {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// dropDuplicateTitles removes proposed issues whose title is at least
+// threshold similar to an existing title or to an earlier issue in the
+// same batch. Issues that depended on a dropped issue lose the dependency.
+// Returns the kept issues and the number dropped.
+func dropDuplicateTitles(issues []proposedIssue, existing []string, threshold float64) ([]proposedIssue, int) {
+	titles := append([]string(nil), existing...)
+	dropped := make(map[int]bool)
+	var kept []proposedIssue
+	for _, issue := range issues {
+		if match, dup := similarTitle(issue.Title, titles, threshold); dup {
+			logf("importIssues: skipping [%d] %q as a duplicate of %q", issue.Index, issue.Title, match)
+			dropped[issue.Index] = true
+			continue
+		}
+		titles = append(titles, issue.Title)
+		kept = append(kept, issue)
+	}
+	for i := range kept {
+		if kept[i].Dependency >= 0 && dropped[kept[i].Dependency] {
+			logf("importIssues: clearing dependency of [%d] on skipped duplicate %d", kept[i].Index, kept[i].Dependency)
+			kept[i].Dependency = -1
+		}
+	}
+	return kept, len(issues) - len(kept)
+}
+
+// existingIssueTitles returns the titles of the generation's open issues
+// for duplicate detection. Listing is best-effort; on failure the check
+// compares only within the batch.
+func existingIssueTitles(repo, generation string) []string {
+	issues, err := listOpenCobblerIssues(repo, generation)
+	if err != nil {
+		logf("importIssues: listing existing issues for duplicate check: %v", err)
+		return nil
+	}
+	titles := make([]string, 0, len(issues))
+	for _, iss := range issues {
+		titles = append(titles, iss.Title)
+	}
+	return titles
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package orchestrator
+
+import "testing"
+
+func TestTitleSimilarity(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		a, b    string
+		atLeast float64
+		below   float64
+	}{
+		{"Add widget cache", "Implement widget caching", 1, 1.01},
+		{"Add widget cache", "add the Widget-Cache", 1, 1.01},
+		{"Add widget cache", "Add widget cache eviction", 0.8, 0.81},
+		{"Add widget cache", "Parse config file", 0, 0.01},
+		{"", "Add widget cache", 0, 0.01},
+	}
+	for _, tc := range cases {
+		got := titleSimilarity(tc.a, tc.b)
+		if got < tc.atLeast || got >= tc.below {
+			t.Errorf("titleSimilarity(%q, %q) = %.2f, want in [%.2f, %.2f)", tc.a, tc.b, got, tc.atLeast, tc.below)
+		}
+	}
+}
+
+func TestDropDuplicateTitles(t *testing.T) {
+	t.Parallel()
+	issues := []proposedIssue{
+		{Index: 0, Title: "Implement widget caching", Dependency: -1},
+		{Index: 1, Title: "Add config parser", Dependency: -1},
+		{Index: 2, Title: "Create the config parser", Dependency: 1},
+		{Index: 3, Title: "Document config parser", Dependency: 0},
+	}
+	kept, skipped := dropDuplicateTitles(issues, []string{"Add widget cache"}, 0.85)
+	if skipped != 2 {
+		t.Errorf("skipped = %d, want 2", skipped)
+	}
+	if len(kept) != 2 || kept[0].Index != 1 || kept[1].Index != 3 {
+		t.Fatalf("kept = %+v, want indices 1 and 3", kept)
+	}
+	if kept[1].Dependency != -1 {
+		t.Errorf("dependency on skipped issue 0 = %d, want cleared to -1", kept[1].Dependency)
+	}
+
+	if _, skipped := dropDuplicateTitles(issues, nil, 1.01); skipped != 0 {
+		t.Errorf("threshold above 1: skipped = %d, want 0", skipped)
+	}
+}
+
+func TestApplyDefaults_DuplicateTitleThreshold(t *testing.T) {
+	t.Parallel()
+	var cfg Config
+	cfg.applyDefaults()
+	if cfg.Cobbler.DuplicateTitleThreshold != 0.85 {
+		t.Errorf("DuplicateTitleThreshold = %v, want 0.85", cfg.Cobbler.DuplicateTitleThreshold)
+	}
+	cfg.Cobbler.DuplicateTitleThreshold = -1
+	cfg.applyDefaults()
+	if cfg.Cobbler.DuplicateTitleThreshold != -1 {
+		t.Errorf("explicit negative threshold overwritten: %v", cfg.Cobbler.DuplicateTitleThreshold)
+	}
+}
@@ -0,0 +1,64 @@
+//go:build benchmark
+
+// Copyright (c) 2026 Petar Djukic. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Benchmarks for source file loading in buildProjectContext (GH-504).
+// Compares the sequential loader (one worker) with the bounded worker
+// pool on a synthetic tree of Go files.
+
+package uc008_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mesh-intelligence/cobbler-scaffold/pkg/orchestrator"
+)
+
+// writeSyntheticTree creates pkgs packages with filesPerPkg Go files of
+// roughly linesPerFile lines each under root.
+func writeSyntheticTree(b *testing.B, root string, pkgs, filesPerPkg, linesPerFile int) {
+	b.Helper()
+	var body strings.Builder
+	for i := 0; i < linesPerFile; i++ {
+		fmt.Fprintf(&body, "var v%d = %d // synthetic line %d\n", i, i, i)
+	}
+	for p := 0; p < pkgs; p++ {
+		dir := filepath.Join(root, fmt.Sprintf("pkg%03d", p))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			b.Fatal(err)
+		}
+		for f := 0; f < filesPerPkg; f++ {
+			content := fmt.Sprintf("package pkg%03d\n\n%s", p, strings.ReplaceAll(body.String(), "var v", fmt.Sprintf("var f%dv", f)))
+			if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("file%03d.go", f)), []byte(content), 0o644); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkLoadSourceFiles(b *testing.B) {
+	root := b.TempDir()
+	writeSyntheticTree(b, root, 50, 40, 200) // 2000 files
+	dirs := []string{root}
+
+	for _, bc := range []struct {
+		name    string
+		workers int
+	}{
+		{"sequential", 1},
+		{"parallel", 0},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if files := orchestrator.LoadSourceFiles(dirs, bc.workers); len(files) != 2000 {
+					b.Fatalf("loaded %d file(s), want 2000", len(files))
+				}
+			}
+		})
+	}
+}